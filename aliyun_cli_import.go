@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// aliyunCLIConfig is the subset of ~/.aliyun/config.json written by the aliyun CLI that maps onto
+// OSS profiles.
+type aliyunCLIConfig struct {
+	Profiles []aliyunCLIProfile `json:"profiles"`
+}
+
+type aliyunCLIProfile struct {
+	Name            string `json:"name"`
+	Mode            string `json:"mode"`
+	AccessKeyID     string `json:"access_key_id"`
+	AccessKeySecret string `json:"access_key_secret"`
+	StsToken        string `json:"sts_token"`
+	RamRoleName     string `json:"ram_role_name"`
+	RamRoleArn      string `json:"ram_role_arn"`
+	RamSessionName  string `json:"ram_session_name"`
+	SourceProfile   string `json:"source_profile"`
+	RegionID        string `json:"region_id"`
+}
+
+// AliyunCLISkipped is a CLI profile that could not be turned into an OSS profile.
+type AliyunCLISkipped struct {
+	Name   string `json:"name"`
+	Mode   string `json:"mode"`
+	Reason string `json:"reason"`
+}
+
+// AliyunCLIImport holds the candidate profiles found in an aliyun CLI config. Nothing is saved;
+// the UI confirms the candidates and saves them with SaveProfile.
+type AliyunCLIImport struct {
+	Profiles []OSSProfile       `json:"profiles"`
+	Skipped  []AliyunCLISkipped `json:"skipped"`
+}
+
+func aliyunCLIProfileConfig(p aliyunCLIProfile) (OSSConfig, error) {
+	config := OSSConfig{Region: normalizeRegion(p.RegionID)}
+	switch p.Mode {
+	case "", "AK":
+		config.AccessKeyID = p.AccessKeyID
+		config.AccessKeySecret = p.AccessKeySecret
+	case "StsToken":
+		config.AccessKeyID = p.AccessKeyID
+		config.AccessKeySecret = p.AccessKeySecret
+		config.SecurityToken = p.StsToken
+	case "RamRoleArn":
+		config.AccessKeyID = p.AccessKeyID
+		config.AccessKeySecret = p.AccessKeySecret
+		config.RoleArn = p.RamRoleArn
+		config.RoleSessionName = p.RamSessionName
+	case "ChainableRamRoleArn":
+		if strings.TrimSpace(p.SourceProfile) == "" {
+			return OSSConfig{}, fmt.Errorf("no source profile is set")
+		}
+		config.RoleArn = p.RamRoleArn
+		config.RoleSessionName = p.RamSessionName
+		config.SourceProfile = p.SourceProfile
+	case "EcsRamRole":
+		config.Provider = credentialProviderECSRAMRole
+		config.ECSRoleName = p.RamRoleName
+	default:
+		return OSSConfig{}, fmt.Errorf("credential mode %s is not supported", p.Mode)
+	}
+
+	if config.Provider == "" && config.SourceProfile == "" {
+		if strings.TrimSpace(config.AccessKeyID) == "" || config.AccessKeySecret == "" {
+			return OSSConfig{}, fmt.Errorf("AccessKey ID or secret is missing")
+		}
+	}
+	if config.RoleArn == "" && (p.Mode == "RamRoleArn" || p.Mode == "ChainableRamRoleArn") {
+		return OSSConfig{}, fmt.Errorf("no role ARN is set")
+	}
+	return config, nil
+}
+
+// ImportFromAliyunCLI reads an aliyun CLI config (~/.aliyun/config.json when path is empty) and
+// maps its profiles to OSS profiles. Profiles using a credential mode this app cannot use are
+// listed in Skipped instead of failing the whole import.
+func (s *OSSService) ImportFromAliyunCLI(path string) (AliyunCLIImport, error) {
+	path = strings.TrimSpace(expandLeadingTilde(path))
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AliyunCLIImport{}, fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		path = filepath.Join(home, ".aliyun", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AliyunCLIImport{}, fmt.Errorf("failed to read aliyun CLI config: %w", err)
+	}
+	var cliConfig aliyunCLIConfig
+	if err := json.Unmarshal(data, &cliConfig); err != nil {
+		return AliyunCLIImport{}, fmt.Errorf("failed to parse aliyun CLI config: %w", err)
+	}
+
+	result := AliyunCLIImport{Profiles: []OSSProfile{}, Skipped: []AliyunCLISkipped{}}
+	for _, p := range cliConfig.Profiles {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			continue
+		}
+		config, err := aliyunCLIProfileConfig(p)
+		if err != nil {
+			result.Skipped = append(result.Skipped, AliyunCLISkipped{Name: name, Mode: p.Mode, Reason: err.Error()})
+			continue
+		}
+		result.Profiles = append(result.Profiles, OSSProfile{Name: name, Config: config})
+	}
+	return result, nil
+}
@@ -2,32 +2,89 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	goruntime "runtime"
 	"strings"
+	"sync"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
 // App struct
 type App struct {
+	// ctxMu guards ctx, which startup sets while callbacks such as a second-instance launch may
+	// already be running on other goroutines.
+	ctxMu      sync.RWMutex
 	ctx        context.Context
 	OSSService *OSSService
+
+	// lastDirectoryMu guards lastDirectory, the folder most recently picked in a directory dialog.
+	lastDirectoryMu sync.Mutex
+	lastDirectory   string
+
+	// pendingLaunchMu guards pendingLaunch, the launch arguments the frontend has not picked up yet.
+	pendingLaunchMu sync.Mutex
+	pendingLaunch   LaunchRequest
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{
+	app := &App{
 		OSSService: NewOSSService(),
 	}
+	workingDirectory, _ := os.Getwd()
+	app.queueLaunchRequest(parseLaunchArgs(os.Args[1:], workingDirectory))
+	return app
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
+	a.ctxMu.Lock()
 	a.ctx = ctx
+	a.ctxMu.Unlock()
 	a.OSSService.SetContext(ctx)
+	go a.OSSService.watchTheme(ctx)
+	go a.OSSService.watchConfig(ctx)
+	// No ossutil runs yet, so every credential file left behind belongs to a run that was killed.
+	a.OSSService.tempFiles.purge(tempPurposeCredentials)
+	go a.OSSService.CleanupTempFiles(tempFilesStartupMaxAge)
+	go a.OSSService.usage.run(ctx)
+}
+
+// runtimeContext returns the context startup saved, or nil before startup has run.
+func (a *App) runtimeContext() context.Context {
+	a.ctxMu.RLock()
+	defer a.ctxMu.RUnlock()
+	return a.ctx
+}
+
+// errWindowNotReady is returned by calls that need the window before startup has run.
+var errWindowNotReady = errors.New("the window is not ready yet")
+
+// windowContext is runtimeContext for the runtime calls that need a window, such as dialogs and
+// the clipboard, which must not be handed a nil context.
+func (a *App) windowContext() (context.Context, error) {
+	ctx := a.runtimeContext()
+	if ctx == nil {
+		return nil, errWindowNotReady
+	}
+	return ctx, nil
+}
+
+// shutdown is called when the app exits. Temp files not held open by edit-in-place go with it, and
+// the usage counters, profile last-used times and session state are written one last time.
+func (a *App) shutdown(ctx context.Context) {
+	a.OSSService.stopMoveJobs()
+	a.OSSService.stopPrefixWatches()
+	a.OSSService.transfers().close()
+	a.OSSService.CleanupTempFiles(0)
+	_ = a.OSSService.usage.flush()
+	_ = a.OSSService.flushProfileUsage()
+	_ = a.OSSService.flushSessionState()
 }
 
 // Greet returns a greeting for the given name
@@ -37,32 +94,98 @@ func (a *App) Greet(name string) string {
 
 // SelectFile opens a file selection dialog
 func (a *App) SelectFile() (string, error) {
-	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+	ctx, err := a.windowContext()
+	if err != nil {
+		return "", err
+	}
+	return runtime.OpenFileDialog(ctx, runtime.OpenDialogOptions{
 		Title: "Select File to Upload",
 	})
 }
 
-// SelectDirectory opens a folder selection dialog
-func (a *App) SelectDirectory(title string) (string, error) {
+func (a *App) openDirectoryDialog(title string, defaultDirectory string) (string, error) {
+	ctx, err := a.windowContext()
+	if err != nil {
+		return "", err
+	}
 	title = strings.TrimSpace(title)
 	if title == "" {
 		title = "Select Folder"
 	}
-	return runtime.OpenDirectoryDialog(a.ctx, runtime.OpenDialogOptions{
+	defaultDirectory = strings.TrimSpace(defaultDirectory)
+	if defaultDirectory == "" {
+		a.lastDirectoryMu.Lock()
+		defaultDirectory = a.lastDirectory
+		a.lastDirectoryMu.Unlock()
+	}
+
+	dir, err := runtime.OpenDirectoryDialog(ctx, runtime.OpenDialogOptions{
 		Title:                title,
+		DefaultDirectory:     expandLeadingTilde(defaultDirectory),
 		CanCreateDirectories: true,
 	})
+	if err != nil || dir == "" {
+		return "", err
+	}
+	a.lastDirectoryMu.Lock()
+	a.lastDirectory = dir
+	a.lastDirectoryMu.Unlock()
+	return dir, nil
+}
+
+// SelectDirectory opens a folder selection dialog for a destination folder. It returns "" when
+// the dialog is cancelled and an error wrapping ErrDirectoryNotWritable when files cannot be
+// created in the chosen folder. An empty defaultDirectory opens the folder picked last time.
+func (a *App) SelectDirectory(title string, defaultDirectory string) (string, error) {
+	dir, err := a.openDirectoryDialog(title, defaultDirectory)
+	if err != nil || dir == "" {
+		return "", err
+	}
+	if err := checkDirWritable(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// SelectSourceDirectory is SelectDirectory for folders that are only read, such as upload
+// sources, so it skips the write check.
+func (a *App) SelectSourceDirectory(title string, defaultDirectory string) (string, error) {
+	return a.openDirectoryDialog(title, defaultDirectory)
 }
 
 // SelectSaveFile opens a save file dialog
 func (a *App) SelectSaveFile(filename string) (string, error) {
-	return runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+	ctx, err := a.windowContext()
+	if err != nil {
+		return "", err
+	}
+	return runtime.SaveFileDialog(ctx, runtime.SaveDialogOptions{
 		Title:                "Save File As",
+		DefaultDirectory:     a.OSSService.defaultDownloadDir(),
 		DefaultFilename:      filename,
 		CanCreateDirectories: true,
 	})
 }
 
+// PickDefaultDownloadDir lets the user choose the default download directory and saves it.
+// It returns "" when the dialog is cancelled.
+func (a *App) PickDefaultDownloadDir() (string, error) {
+	dir, err := a.SelectDirectory("Select Default Download Folder", a.OSSService.defaultDownloadDir())
+	if err != nil || dir == "" {
+		return "", err
+	}
+
+	settings, err := a.OSSService.GetSettings()
+	if err != nil {
+		return "", err
+	}
+	settings.DefaultDownloadDir = dir
+	if err := a.OSSService.SaveSettings(settings); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 // OpenInFinder opens the containing folder in Finder (macOS)
 func (a *App) OpenInFinder(filePath string) error {
 	return exec.Command("open", "-R", filePath).Start()
@@ -84,3 +207,17 @@ func (a *App) OpenFile(filePath string) error {
 		return exec.Command("xdg-open", filePath).Start()
 	}
 }
+
+// OpenLogFolder opens the folder holding the operation log in the OS file manager.
+func (a *App) OpenLogFolder() error {
+	dir := a.OSSService.opLog.dir
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return a.OpenFile(dir)
+}
+
+// GetRecentLogEntries returns up to n of the newest operation log entries, oldest first.
+func (a *App) GetRecentLogEntries(n int) []OperationLogEntry {
+	return a.OSSService.opLog.recentEntries(n)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestShutdownFlushesProfileUsage(t *testing.T) {
+	s := newTestService(t)
+	config := OSSConfig{AccessKeyID: "id", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+	state := appState{Settings: defaultAppSettings(s.configDir), Profiles: []OSSProfile{{Name: "prod", Config: config}}}
+	state.Settings.CredentialStorage = credentialStoragePlaintext
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		t.Fatal(err)
+	}
+
+	s.markProfileUsed(config)
+	app := &App{OSSService: s}
+	app.shutdown(context.Background())
+
+	// The debounced flush would only have run after profileUsageFlushDelay.
+	loaded, err := s.loadAppState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Profiles) != 1 || loaded.Profiles[0].LastUsedAtMs == 0 {
+		t.Fatalf("LastUsedAtMs was not saved on shutdown: %+v", loaded.Profiles)
+	}
+}
+
+func TestWindowCallsBeforeStartupFail(t *testing.T) {
+	app := &App{OSSService: newTestService(t)}
+	calls := map[string]func() error{
+		"SelectFile":            func() error { _, err := app.SelectFile(); return err },
+		"SelectDirectory":       func() error { _, err := app.SelectDirectory("", ""); return err },
+		"SelectSourceDirectory": func() error { _, err := app.SelectSourceDirectory("", ""); return err },
+		"SelectSaveFile":        func() error { _, err := app.SelectSaveFile("a.txt"); return err },
+		"CopyTextToClipboard":   func() error { return app.CopyTextToClipboard("text") },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, errWindowNotReady) {
+			t.Errorf("%s before startup = %v, want errWindowNotReady", name, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// detectOSTheme reads the macOS appearance. AppleInterfaceStyle is only set in dark mode, so a
+// failed read means light.
+func detectOSTheme() (string, bool) {
+	output, err := exec.Command("defaults", "read", "-g", "AppleInterfaceStyle").Output()
+	if err != nil {
+		return themeLight, true
+	}
+	if strings.EqualFold(strings.TrimSpace(string(output)), "dark") {
+		return themeDark, true
+	}
+	return themeLight, true
+}
@@ -0,0 +1,29 @@
+//go:build !darwin && !windows
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// detectOSTheme asks GNOME-compatible desktops through gsettings: the color-scheme key first, then
+// the GTK theme name for desktops that predate it.
+func detectOSTheme() (string, bool) {
+	if output, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "color-scheme").Output(); err == nil {
+		switch strings.Trim(strings.TrimSpace(string(output)), "'") {
+		case "prefer-dark":
+			return themeDark, true
+		case "prefer-light":
+			return themeLight, true
+		}
+	}
+	output, err := exec.Command("gsettings", "get", "org.gnome.desktop.interface", "gtk-theme").Output()
+	if err != nil {
+		return "", false
+	}
+	if strings.Contains(strings.ToLower(string(output)), "dark") {
+		return themeDark, true
+	}
+	return themeLight, true
+}
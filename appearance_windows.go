@@ -0,0 +1,21 @@
+package main
+
+import "golang.org/x/sys/windows/registry"
+
+// detectOSTheme reads the "apps" half of the Windows light/dark setting.
+func detectOSTheme() (string, bool) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`, registry.QUERY_VALUE)
+	if err != nil {
+		return "", false
+	}
+	defer key.Close()
+
+	value, _, err := key.GetIntegerValue("AppsUseLightTheme")
+	if err != nil {
+		return "", false
+	}
+	if value == 0 {
+		return themeDark, true
+	}
+	return themeLight, true
+}
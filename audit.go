@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	auditLogFileName  = "audit.jsonl"
+	auditLogMaxBytes  = 5 << 20
+	auditLogKeepFiles = 10
+
+	defaultAuditQueryLimit = 500
+
+	auditOutcomeCanceled = "canceled"
+)
+
+// AuditEntry records one destructive operation performed through the app.
+type AuditEntry struct {
+	Time      string `json:"time"`
+	Profile   string `json:"profile"`
+	Operation string `json:"operation"`
+	Bucket    string `json:"bucket"`
+	// Target is the oss:// path acted on; moves read "source -> destination".
+	Target string `json:"target"`
+	// ObjectCount is how many objects were deleted, moved or overwritten.
+	ObjectCount int64  `json:"objectCount"`
+	Outcome     string `json:"outcome"`
+	Error       string `json:"error,omitempty"`
+	// PrevHash is the Hash of the entry written before this one. Hash covers every other field,
+	// PrevHash included, so editing or removing a line breaks the chain from there on.
+	PrevHash string `json:"prevHash"`
+	Hash     string `json:"hash"`
+}
+
+// AuditFilter selects audit entries. Zero fields match everything.
+type AuditFilter struct {
+	FromMs     int64    `json:"fromMs,omitempty"`
+	ToMs       int64    `json:"toMs,omitempty"`
+	Bucket     string   `json:"bucket,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+	// Limit bounds the entries returned, newest first; 0 means 500.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditVerification is the result of checking the hash chain of the audit log.
+type AuditVerification struct {
+	Intact  bool `json:"intact"`
+	Entries int  `json:"entries"`
+	// BrokenAt names the first line that does not match the chain, as file:line.
+	BrokenAt string `json:"brokenAt,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// auditLog appends entries to <dir>/audit.jsonl and syncs each one to disk before returning. It
+// rotates like the operation log, keeping more files; the chain runs on across rotations.
+type auditLog struct {
+	mu       sync.Mutex
+	dir      string
+	file     *os.File
+	size     int64
+	lastHash string
+	opened   bool
+}
+
+func newAuditLog(dir string) *auditLog {
+	return &auditLog{dir: dir}
+}
+
+func (l *auditLog) path() string {
+	return filepath.Join(l.dir, auditLogFileName)
+}
+
+// files lists the audit log files from oldest to newest.
+func (l *auditLog) files() []string {
+	base := l.path()
+	var out []string
+	for i := auditLogKeepFiles; i >= 1; i-- {
+		out = append(out, fmt.Sprintf("%s.%d", base, i))
+	}
+	return append(out, base)
+}
+
+func (l *auditLog) ensureOpenLocked() error {
+	if l.opened {
+		return nil
+	}
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return err
+	}
+	files := l.files()
+	for i := len(files) - 1; i >= 0 && l.lastHash == ""; i-- {
+		l.lastHash = lastAuditHash(files[i])
+	}
+
+	file, err := os.OpenFile(l.path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.size = info.Size()
+	l.opened = true
+	return nil
+}
+
+func lastAuditHash(path string) string {
+	last := ""
+	_ = scanAuditFile(path, func(_ int, entry AuditEntry, err error) bool {
+		if err == nil {
+			last = entry.Hash
+		}
+		return true
+	})
+	return last
+}
+
+// scanAuditFile calls fn with every line of path; err is set for lines that do not parse. fn
+// returns false to stop.
+func scanAuditFile(path string, fn func(line int, entry AuditEntry, err error) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry AuditEntry
+		err := json.Unmarshal(scanner.Bytes(), &entry)
+		if !fn(line, entry, err) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func auditEntryHash(entry AuditEntry) string {
+	entry.Hash = ""
+	data, _ := json.Marshal(entry)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *auditLog) rotateLocked() error {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+	base := l.path()
+	os.Remove(fmt.Sprintf("%s.%d", base, auditLogKeepFiles))
+	for i := auditLogKeepFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", base, i), fmt.Sprintf("%s.%d", base, i+1))
+	}
+	if err := os.Rename(base, base+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.OpenFile(base, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	l.size = 0
+	return nil
+}
+
+// append chains entry to the log and writes it to disk.
+func (l *auditLog) append(entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.ensureOpenLocked(); err != nil {
+		return err
+	}
+	entry.PrevHash = l.lastHash
+	entry.Hash = auditEntryHash(entry)
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if l.size+int64(len(line)) > auditLogMaxBytes && l.size > 0 {
+		if err := l.rotateLocked(); err != nil {
+			return err
+		}
+	}
+	if l.file == nil {
+		return errors.New("audit log is not open")
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.lastHash = entry.Hash
+	return nil
+}
+
+// recordAudit writes a destructive operation to the audit log before the operation returns. count
+// is what was affected; err is the operation's outcome. Failing to write is logged, not returned:
+// the operation has already happened.
+func (s *OSSService) recordAudit(config OSSConfig, operation string, bucket string, target string, count int64, err error) {
+	entry := AuditEntry{
+		Time:        time.Now().UTC().Format(time.RFC3339Nano),
+		Profile:     s.resolveTransferProfileName(config),
+		Operation:   operation,
+		Bucket:      strings.TrimSpace(bucket),
+		Target:      s.redact(target),
+		ObjectCount: count,
+		Outcome:     opOutcomeOK,
+	}
+	switch {
+	case errors.Is(err, ErrOperationCanceled):
+		entry.Outcome = auditOutcomeCanceled
+		entry.Error = s.redact(err.Error())
+	case err != nil:
+		entry.Outcome = opOutcomeError
+		entry.Error = s.redact(err.Error())
+	}
+	if writeErr := s.audit.append(entry); writeErr != nil {
+		s.logOperationEvent(logLevelError, "AuditLog", opOutcomeError, operation+" "+entry.Target, writeErr)
+	}
+}
+
+// auditCount is the object count of a single-object operation that ended with err.
+func auditCount(err error) int64 {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
+
+// objectExists tells whether key exists, for auditing writes that replace an object. It answers
+// false when it cannot tell.
+func (s *OSSService) objectExists(config OSSConfig, bucketName string, key string) bool {
+	var exists bool
+	err := s.withBucketRegion(config, bucketName, "IsObjectExist", func(bucket *oss.Bucket) error {
+		var err error
+		exists, err = bucket.IsObjectExist(key)
+		return err
+	})
+	return err == nil && exists
+}
+
+// QueryAuditLog returns the audit entries matching filter, newest first.
+func (s *OSSService) QueryAuditLog(filter AuditFilter) (_ []AuditEntry, err error) {
+	defer s.logOperation("QueryAuditLog", filter.Bucket, "", time.Now(), &err)
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+	operations := make(map[string]struct{}, len(filter.Operations))
+	for _, operation := range filter.Operations {
+		if operation = strings.TrimSpace(operation); operation != "" {
+			operations[strings.ToLower(operation)] = struct{}{}
+		}
+	}
+	bucket := strings.TrimSpace(filter.Bucket)
+
+	s.audit.mu.Lock()
+	defer s.audit.mu.Unlock()
+
+	var matches []AuditEntry
+	for _, path := range s.audit.files() {
+		err := scanAuditFile(path, func(_ int, entry AuditEntry, err error) bool {
+			if err != nil {
+				return true
+			}
+			if bucket != "" && entry.Bucket != bucket {
+				return true
+			}
+			if len(operations) > 0 {
+				if _, ok := operations[strings.ToLower(entry.Operation)]; !ok {
+					return true
+				}
+			}
+			if filter.FromMs > 0 || filter.ToMs > 0 {
+				at, err := time.Parse(time.RFC3339Nano, entry.Time)
+				if err != nil {
+					return true
+				}
+				if filter.FromMs > 0 && at.UnixMilli() < filter.FromMs {
+					return true
+				}
+				if filter.ToMs > 0 && at.UnixMilli() > filter.ToMs {
+					return true
+				}
+			}
+			matches = append(matches, entry)
+			if len(matches) > limit*2 {
+				matches = append([]AuditEntry(nil), matches[len(matches)-limit:]...)
+			}
+			return true
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if len(matches) > limit {
+		matches = matches[len(matches)-limit:]
+	}
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	return matches, nil
+}
+
+// VerifyAuditLog checks the hash chain over every audit log file. The first entry of the oldest file
+// links to an entry that was rotated away and is taken as it is.
+func (s *OSSService) VerifyAuditLog() (_ AuditVerification, err error) {
+	defer s.logOperation("VerifyAuditLog", "", "", time.Now(), &err)
+
+	s.audit.mu.Lock()
+	defer s.audit.mu.Unlock()
+
+	result := AuditVerification{Intact: true}
+	previous, first := "", true
+	for _, path := range s.audit.files() {
+		name := filepath.Base(path)
+		err := scanAuditFile(path, func(line int, entry AuditEntry, err error) bool {
+			switch {
+			case err != nil:
+				result.Message = s.msg(msgAuditLineUnreadable, err.Error())
+			case entry.Hash != auditEntryHash(entry):
+				result.Message = s.msg(msgAuditEntryModified)
+			case !first && entry.PrevHash != previous:
+				result.Message = s.msg(msgAuditChainBroken)
+			default:
+				result.Entries++
+				previous, first = entry.Hash, false
+				return true
+			}
+			result.Intact = false
+			result.BrokenAt = fmt.Sprintf("%s:%d", name, line)
+			return false
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return AuditVerification{}, err
+		}
+		if !result.Intact {
+			return result, nil
+		}
+	}
+	return result, nil
+}
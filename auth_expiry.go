@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ErrCredentialsNeedReauth is returned for a profile whose temporary credentials were rejected as
+// expired; every operation fails fast with it until new credentials are saved.
+var ErrCredentialsNeedReauth = errors.New("temporary credentials have expired; save new credentials for this profile")
+
+var expiredTokenCodes = []string{"SecurityTokenExpired", "InvalidSecurityToken"}
+
+// AuthExpiredEvent is the payload of the "auth:expired" event.
+type AuthExpiredEvent struct {
+	ProfileName string `json:"profileName"`
+}
+
+func isExpiredTokenError(err error) bool {
+	return isOSSErrorCode(err, expiredTokenCodes...)
+}
+
+func ossutilOutputTokenExpired(output []byte) bool {
+	for _, code := range expiredTokenCodes {
+		if bytes.Contains(output, []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// staticTokenKey identifies configs that carry a user-supplied STS token. Tokens obtained by the
+// app (assume-role, instance roles) are renewed automatically and never need re-authentication.
+func staticTokenKey(config OSSConfig) (string, bool) {
+	if config.SecurityToken == "" || strings.TrimSpace(config.RoleArn) != "" {
+		return "", false
+	}
+	if config.Provider != "" && config.Provider != credentialProviderStatic {
+		return "", false
+	}
+	return transferConfigSignature(config), true
+}
+
+// checkNeedsReauth short-circuits operations for a config already known to have expired credentials.
+func (s *OSSService) checkNeedsReauth(config OSSConfig) error {
+	key, ok := staticTokenKey(config)
+	if !ok {
+		return nil
+	}
+	s.authExpiredMu.Lock()
+	profileName, expired := s.authExpired[key]
+	s.authExpiredMu.Unlock()
+	if !expired {
+		return nil
+	}
+	if profileName != "" {
+		return fmt.Errorf("%w (profile %s)", ErrCredentialsNeedReauth, profileName)
+	}
+	return ErrCredentialsNeedReauth
+}
+
+// markNeedsReauth flags config as expired and tells the UI once per expiry.
+func (s *OSSService) markNeedsReauth(config OSSConfig) {
+	key, ok := staticTokenKey(config)
+	if !ok {
+		return
+	}
+	profileName := s.resolveTransferProfileName(config)
+	if profileName == transferProfileAnonymous {
+		profileName = ""
+	}
+
+	s.authExpiredMu.Lock()
+	_, already := s.authExpired[key]
+	s.authExpired[key] = profileName
+	s.authExpiredMu.Unlock()
+	if already {
+		return
+	}
+
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx != nil {
+		runtime.EventsEmit(ctx, "auth:expired", AuthExpiredEvent{ProfileName: profileName})
+	}
+}
+
+// clearNeedsReauth forgets the expiry flag, called when new credentials are saved.
+func (s *OSSService) clearNeedsReauth(config OSSConfig) {
+	s.authExpiredMu.Lock()
+	defer s.authExpiredMu.Unlock()
+	if key, ok := staticTokenKey(config); ok {
+		delete(s.authExpired, key)
+	}
+}
+
+// classifyAuthError turns an SDK error caused by an expired token into ErrCredentialsNeedReauth
+// and marks the config; other errors are returned unchanged.
+func (s *OSSService) classifyAuthError(config OSSConfig, err error) error {
+	if err == nil || !isExpiredTokenError(err) {
+		return err
+	}
+	if _, ok := staticTokenKey(config); !ok {
+		return err
+	}
+	s.markNeedsReauth(config)
+	return fmt.Errorf("%w: %s", ErrCredentialsNeedReauth, s.redact(describeSDKError(err).Error()))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	// maxBatchDownloadLines bounds one pasted list; every line costs a HEAD request.
+	maxBatchDownloadLines = 1000
+
+	batchLineQueued       = "queued"
+	batchLineNotFound     = "notFound"
+	batchLineAccessDenied = "accessDenied"
+	batchLineMalformed    = "malformed"
+	// batchLineFailed covers everything else, such as a network error or a full transfer queue.
+	batchLineFailed = "failed"
+)
+
+// BatchLineResult is what became of one non-empty line of EnqueueDownloadsFromPaths. Line counts
+// from 1 over all pasted lines, blanks and comments included, so it matches what the user sees.
+type BatchLineResult struct {
+	Line       int    `json:"line"`
+	Input      string `json:"input"`
+	Outcome    string `json:"outcome"`
+	TransferID string `json:"transferId,omitempty"`
+	LocalPath  string `json:"localPath,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// BatchEnqueueResult reports every line of a pasted list of oss:// paths.
+type BatchEnqueueResult struct {
+	Lines  []BatchLineResult `json:"lines"`
+	Queued int               `json:"queued"`
+	Failed int               `json:"failed"`
+}
+
+// EnqueueDownloadsFromPaths queues a download for every oss://bucket/key line of rawPaths into
+// destDir. An element may hold several lines. Blank lines and lines starting with "#" are skipped.
+// Every object is checked with a HEAD request first, so a missing object or a bucket the profile
+// cannot read is reported on its line while the other lines are still queued. Files keep the
+// object's base name and get a "name (1).ext" name when it is taken.
+func (s *OSSService) EnqueueDownloadsFromPaths(config OSSConfig, rawPaths []string, destDir string) (_ BatchEnqueueResult, err error) {
+	defer s.logOperation("EnqueueDownloadsFromPaths", "", destDir, time.Now(), &err)
+
+	var lines []string
+	for _, raw := range rawPaths {
+		lines = append(lines, strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")...)
+	}
+	if len(lines) > maxBatchDownloadLines {
+		return BatchEnqueueResult{}, s.errorf(msgBatchTooManyLines, len(lines), maxBatchDownloadLines)
+	}
+
+	destDir = expandLeadingTilde(strings.TrimSpace(destDir))
+	if destDir == "" {
+		return BatchEnqueueResult{}, s.errorf(msgTransferLocalPathEmpty)
+	}
+	if err := ensureWritableDir(destDir); err != nil {
+		return BatchEnqueueResult{}, err
+	}
+
+	result := BatchEnqueueResult{Lines: []BatchLineResult{}}
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := s.enqueueBatchLine(config, line, destDir)
+		entry.Line, entry.Input = i+1, line
+		if entry.Outcome == batchLineQueued {
+			result.Queued++
+		} else {
+			result.Failed++
+		}
+		result.Lines = append(result.Lines, entry)
+	}
+	return result, nil
+}
+
+func (s *OSSService) enqueueBatchLine(config OSSConfig, line string, destDir string) BatchLineResult {
+	bucket, key, ok := parseOssPath(line)
+	if ok {
+		bucket, key = normalizeTransferBucket(bucket), normalizeTransferObjectKey(key)
+	}
+	if !ok || key == "" || strings.HasSuffix(key, "/") || validateObjectRef(bucket, key) != nil {
+		return BatchLineResult{Outcome: batchLineMalformed, Message: s.msg(msgBatchMalformed)}
+	}
+
+	var totalBytes int64
+	err := s.withBucketRegion(config, bucket, "GetObjectMeta", func(bkt *oss.Bucket) error {
+		props, err := bkt.GetObjectDetailedMeta(key)
+		if err == nil {
+			totalBytes, _ = strconv.ParseInt(props.Get("Content-Length"), 10, 64)
+		}
+		return err
+	})
+	switch {
+	case isNotFound(err):
+		return BatchLineResult{Outcome: batchLineNotFound, Message: s.msg(msgBatchNotFound)}
+	case isAccessDenied(err):
+		return BatchLineResult{Outcome: batchLineAccessDenied, Message: s.msg(msgBatchAccessDenied, bucket)}
+	case err != nil:
+		return BatchLineResult{Outcome: batchLineFailed, Message: s.redact(err.Error())}
+	}
+
+	localPath, err := reserveDownloadPath(destDir, localFileName(path.Base(key)))
+	if err != nil {
+		return BatchLineResult{Outcome: batchLineFailed, Message: err.Error()}
+	}
+	id, err := s.EnqueueDownload(config, bucket, key, localPath, totalBytes)
+	if err != nil {
+		os.Remove(localPath)
+		return BatchLineResult{Outcome: batchLineFailed, Message: s.redact(err.Error())}
+	}
+	return BatchLineResult{Outcome: batchLineQueued, TransferID: id, LocalPath: localPath}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// bucketRegionCache remembers the region of buckets that turned out not to be in their profile's
+// region, so later calls go there directly. Bucket names are global, so the cache is shared by
+// all profiles.
+type bucketRegionCache struct {
+	mu      sync.RWMutex
+	regions map[string]string
+}
+
+func newBucketRegionCache() *bucketRegionCache {
+	return &bucketRegionCache{regions: make(map[string]string)}
+}
+
+func (c *bucketRegionCache) get(bucketName string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	region, ok := c.regions[bucketName]
+	return region, ok
+}
+
+func (c *bucketRegionCache) set(bucketName string, region string) {
+	c.mu.Lock()
+	c.regions[bucketName] = region
+	c.mu.Unlock()
+}
+
+// wrongRegionEndpoint reports whether err may come from sending a request to a region the bucket
+// is not in, and the endpoint OSS named in its answer, if any. OSS answers such requests with a
+// 301 or an AccessDenied naming the bucket's endpoint; HEAD requests get a bare 403.
+func wrongRegionEndpoint(err error) (endpoint string, suspect bool) {
+	var serviceErr oss.ServiceError
+	if !errors.As(err, &serviceErr) {
+		return "", false
+	}
+	switch {
+	case serviceErr.StatusCode == http.StatusMovedPermanently || serviceErr.Code == "PermanentRedirect":
+		return serviceErr.Endpoint, true
+	case serviceErr.StatusCode == http.StatusForbidden && serviceErr.Endpoint != "":
+		return serviceErr.Endpoint, true
+	case serviceErr.StatusCode == http.StatusForbidden && serviceErr.Code == "":
+		return "", true
+	}
+	return "", false
+}
+
+// regionFromEndpoint returns the region of a public, internal or dual-stack regional endpoint, e.g.
+// "oss-cn-shanghai.aliyuncs.com" -> "cn-shanghai", or "" for any other host.
+func regionFromEndpoint(endpoint string) string {
+	region, _, _ := parseRegionalEndpoint(endpoint)
+	return region
+}
+
+// addressesByRegion reports whether config reaches OSS through a regional endpoint, the only kind
+// that can be switched to another region. Custom domains, access points and the acceleration
+// endpoint are left as they are.
+func addressesByRegion(config OSSConfig) bool {
+	if config.UseAccelerateEndpoint || normalizeEndpoint(config.CnameDomain) != "" {
+		return false
+	}
+	endpoint := normalizeEndpoint(config.Endpoint)
+	return endpoint == "" || regionFromEndpoint(endpoint) != ""
+}
+
+// configRegion returns the region requests made with config are sent to.
+func configRegion(config OSSConfig) string {
+	if region := regionFromEndpoint(config.Endpoint); region != "" {
+		return region
+	}
+	return normalizeRegion(config.Region)
+}
+
+// configForBucket points config at the region bucketName was last found in.
+func (s *OSSService) configForBucket(config OSSConfig, bucketName string) OSSConfig {
+	region, ok := s.bucketRegions.get(bucketName)
+	if !ok || !addressesByRegion(config) || region == configRegion(config) {
+		return config
+	}
+	config.Region = region
+	if config.Endpoint != "" {
+		// The internal-endpoint setting rewrites this to the region's -internal host.
+		_, _, dualStack := parseRegionalEndpoint(config.Endpoint)
+		config.Endpoint = endpointScheme(config.Endpoint) + "://" + suggestServiceEndpoint(region, false, dualStack)
+	}
+	return config
+}
+
+// sdkBucket opens bucketName with a data client for the region it was last found in.
+func (s *OSSService) sdkBucket(config OSSConfig, bucketName string) (*oss.Bucket, error) {
+	client, err := s.sdkClientFromConfig(s.configForBucket(config, bucketName))
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := client.Bucket(bucketName)
+	if err != nil {
+		return nil, s.errorf(msgOpenBucketFailed, err)
+	}
+	return bucket, nil
+}
+
+// withBucketRegion runs call against bucketName. When OSS answers that the bucket lives in another
+// region, the region is looked up, remembered for later calls and call runs once more there; the
+// redirect is logged so the profile can be fixed. call may therefore run twice.
+func (s *OSSService) withBucketRegion(config OSSConfig, bucketName string, operation string, call func(*oss.Bucket) error) error {
+	bucket, err := s.sdkBucket(config, bucketName)
+	if err != nil {
+		return err
+	}
+	err = call(bucket)
+	endpoint, suspect := wrongRegionEndpoint(err)
+	if !suspect || !addressesByRegion(config) {
+		return err
+	}
+
+	used := configRegion(s.configForBucket(config, bucketName))
+	region := regionFromEndpoint(endpoint)
+	if region == "" {
+		region = s.lookupBucketRegion(config, bucketName)
+	}
+	if region == "" || region == used {
+		return err
+	}
+	s.bucketRegions.set(bucketName, region)
+	s.logOperationEvent(logLevelWarn, operation, opOutcomeRetry, fmt.Sprintf("bucket %s is in region %s, not %s; retrying there. Set the profile's region to %s to avoid the redirect", bucketName, region, used, region), err)
+
+	bucket, bucketErr := s.sdkBucket(config, bucketName)
+	if bucketErr != nil {
+		return err
+	}
+	return call(bucket)
+}
+
+// lookupBucketRegion asks OSS which region bucketName is in, or returns "" when it cannot tell.
+func (s *OSSService) lookupBucketRegion(config OSSConfig, bucketName string) string {
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return ""
+	}
+	var location string
+	err = s.withSDKRetry("GetBucketLocation", func() error {
+		var err error
+		location, err = client.GetBucketLocation(bucketName)
+		return err
+	})
+	if err != nil {
+		return ""
+	}
+	return normalizeRegion(location)
+}
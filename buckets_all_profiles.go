@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// allProfilesListConcurrency bounds how many accounts are listed at once.
+	allProfilesListConcurrency = 4
+	// allProfilesListTimeout is how long one account may take before it is reported as failed.
+	allProfilesListTimeout = 20 * time.Second
+	// allProfilesCacheTTL keeps switching between views from listing every account again.
+	allProfilesCacheTTL = 30 * time.Second
+)
+
+// ProfileBuckets is the buckets one saved profile can see. Error is set instead when the profile
+// could not be listed; the other profiles are still reported.
+type ProfileBuckets struct {
+	Profile string       `json:"profile"`
+	Buckets []BucketInfo `json:"buckets"`
+	Error   string       `json:"error,omitempty"`
+}
+
+type allProfilesBucketsCache struct {
+	mu        sync.Mutex
+	signature [sha256.Size]byte
+	at        time.Time
+	result    []ProfileBuckets
+}
+
+// ListBucketsAllProfiles lists the buckets of every saved profile, a few accounts at a time. A bucket
+// several profiles can see is listed once, under the first of them: the default profile, then the
+// others in saved order. Every bucket names its profile, whose credentials are the ones to use for
+// it. Results are reused for 30 seconds while the profiles stay the same.
+func (s *OSSService) ListBucketsAllProfiles() (_ []ProfileBuckets, err error) {
+	defer s.logOperation("ListBucketsAllProfiles", "", "", time.Now(), &err)
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return nil, err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return nil, err
+	}
+	profiles := state.Profiles
+	ordered := make([]OSSProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.IsDefault {
+			ordered = append(ordered, profile)
+		}
+	}
+	for _, profile := range profiles {
+		if !profile.IsDefault {
+			ordered = append(ordered, profile)
+		}
+	}
+
+	signatures := make([]string, 0, len(ordered))
+	for _, profile := range ordered {
+		signatures = append(signatures, profile.Name+"\x1e"+transferConfigSignature(profile.Config))
+	}
+	signature := sha256.Sum256([]byte(strings.Join(signatures, "\x1d")))
+
+	cache := &s.allProfilesBuckets
+	cache.mu.Lock()
+	if cache.result != nil && cache.signature == signature && time.Since(cache.at) < allProfilesCacheTTL {
+		result := cloneProfileBuckets(cache.result)
+		cache.mu.Unlock()
+		return result, nil
+	}
+	cache.mu.Unlock()
+
+	listed := make([]ProfileBuckets, len(ordered))
+	slots := make(chan struct{}, allProfilesListConcurrency)
+	var wg sync.WaitGroup
+	for i, profile := range ordered {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			listed[i] = s.listProfileBuckets(profile)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{})
+	for i := range listed {
+		buckets := make([]BucketInfo, 0, len(listed[i].Buckets))
+		for _, bucket := range listed[i].Buckets {
+			if _, ok := seen[bucket.Name]; ok {
+				continue
+			}
+			seen[bucket.Name] = struct{}{}
+			bucket.Profile = listed[i].Profile
+			buckets = append(buckets, bucket)
+		}
+		listed[i].Buckets = buckets
+	}
+
+	cache.mu.Lock()
+	cache.signature, cache.at, cache.result = signature, time.Now(), listed
+	cache.mu.Unlock()
+	return cloneProfileBuckets(listed), nil
+}
+
+// listProfileBuckets resolves the secret of one profile and lists its buckets, giving up after
+// allProfilesListTimeout. A listing that times out keeps running in the background until the SDK's
+// own timeouts end it.
+func (s *OSSService) listProfileBuckets(profile OSSProfile) ProfileBuckets {
+	entry := ProfileBuckets{Profile: profile.Name, Buckets: []BucketInfo{}}
+	profile, err := resolveProfileSecret(profile)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	type outcome struct {
+		buckets []BucketInfo
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		buckets, err := s.ListBuckets(profile.Config)
+		done <- outcome{buckets, err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil {
+			entry.Error = s.redact(result.err.Error())
+			return entry
+		}
+		entry.Buckets = result.buckets
+	case <-time.After(allProfilesListTimeout):
+		entry.Error = s.msg(msgAllProfilesTimeout, int(allProfilesListTimeout.Seconds()))
+	}
+	return entry
+}
+
+func cloneProfileBuckets(in []ProfileBuckets) []ProfileBuckets {
+	out := make([]ProfileBuckets, len(in))
+	for i, entry := range in {
+		entry.Buckets = append([]BucketInfo{}, entry.Buckets...)
+		out[i] = entry
+	}
+	return out
+}
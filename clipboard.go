@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CopyTextToClipboard puts text on the system clipboard. It reads the clipboard back and only
+// returns nil once the text is really there, so a success toast never lies.
+func (a *App) CopyTextToClipboard(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return errors.New("nothing to copy")
+	}
+	ctx, err := a.windowContext()
+	if err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	if err := runtime.ClipboardSetText(ctx, text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	current, err := runtime.ClipboardGetText(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	if current != text {
+		return errors.New("failed to copy to clipboard: the clipboard did not accept the text")
+	}
+	return nil
+}
+
+// CopyObjectPath copies the oss://bucket/key path of an object or folder.
+func (a *App) CopyObjectPath(bucket string, key string) error {
+	bucket = strings.TrimSpace(bucket)
+	if err := validateBucketName(bucket); err != nil {
+		return err
+	}
+	return a.CopyTextToClipboard(buildOssPath(bucket, normalizeObjectKey(key)))
+}
+
+// CopyObjectKey copies an object key without its leading slashes.
+func (a *App) CopyObjectKey(key string) error {
+	key = normalizeObjectKey(key)
+	if err := validateObjectKey(key); err != nil {
+		return err
+	}
+	return a.CopyTextToClipboard(key)
+}
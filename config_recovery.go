@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ConfigRecovery reports a config file that could not be parsed and what was loaded instead. It is
+// emitted as "config:recovered"; recoveries during startup happen before the window listens, so
+// they are also kept for GetConfigRecoveries.
+type ConfigRecovery struct {
+	File string `json:"file"`
+	// MovedTo is where the damaged file was kept.
+	MovedTo string `json:"movedTo"`
+	// RestoredFrom is the backup that was loaded instead, or "" when the file started over empty.
+	RestoredFrom string `json:"restoredFrom,omitempty"`
+	Error        string `json:"error"`
+	Message      string `json:"message"`
+	AtMs         int64  `json:"atMs"`
+}
+
+// configFileReader is the signature of readConfigFile, for loaders that do not hold the service.
+type configFileReader func(path string, v any) (bool, error)
+
+// readConfigFile reads the JSON file at path into v, see decodeConfigFile. It returns false and
+// leaves v alone when the file does not exist.
+func (s *OSSService) readConfigFile(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, s.decodeConfigFile(path, data, v)
+}
+
+// decodeConfigFile decodes data, read from path, into v. A torn, empty or garbled file must not
+// lock the user out: it is moved aside as <path>.corrupt-<timestamp> and <path>.bak is loaded and
+// put back in its place when it parses. Otherwise v keeps the defaults it came with and the file
+// starts over. Only failing to move the damaged file aside is an error.
+func (s *OSSService) decodeConfigFile(path string, data []byte, v any) error {
+	parseErr := decodeJSONInto(data, v)
+	if parseErr == nil {
+		return nil
+	}
+
+	aside := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102-150405.000"))
+	if err := os.Rename(path, aside); err != nil {
+		return fmt.Errorf("%s is damaged (%v) and could not be moved aside: %w", path, parseErr, err)
+	}
+	recovery := ConfigRecovery{
+		File:    path,
+		MovedTo: aside,
+		Error:   parseErr.Error(),
+		AtMs:    time.Now().UnixMilli(),
+	}
+
+	backupPath := path + ".bak"
+	if backup, err := os.ReadFile(backupPath); err == nil && decodeJSONInto(backup, v) == nil {
+		recovery.RestoredFrom = backupPath
+		s.noteConfigWrite([]pendingFile{{path: path, data: backup}})
+		if err := writeFileAtomic(path, backup); err != nil {
+			s.logOperationEvent(logLevelWarn, "LoadConfig", opOutcomeError, "restore "+path, err)
+		}
+		recovery.Message = s.msg(msgConfigRestoredBackup, filepath.Base(path), filepath.Base(aside))
+	} else {
+		recovery.Message = s.msg(msgConfigReset, filepath.Base(path), filepath.Base(aside))
+	}
+	s.noteConfigRecovery(recovery)
+	return nil
+}
+
+// decodeJSONInto unmarshals data into v only when all of it parses, so a file that breaks off
+// halfway does not leave v half overwritten.
+func decodeJSONInto(data []byte, v any) error {
+	target := reflect.ValueOf(v).Elem()
+	decoded := reflect.New(target.Type())
+	decoded.Elem().Set(target)
+	if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+		return err
+	}
+	target.Set(decoded.Elem())
+	return nil
+}
+
+func (s *OSSService) noteConfigRecovery(recovery ConfigRecovery) {
+	s.configRecoveriesMu.Lock()
+	s.configRecoveries = append(s.configRecoveries, recovery)
+	s.configRecoveriesMu.Unlock()
+
+	s.logOperationEvent(logLevelWarn, "LoadConfig", opOutcomeError, recovery.Message, fmt.Errorf("%s: %s", recovery.File, recovery.Error))
+
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx != nil {
+		runtime.EventsEmit(ctx, "config:recovered", recovery)
+	}
+}
+
+// GetConfigRecoveries lists the damaged config files recovered since the app started.
+func (s *OSSService) GetConfigRecoveries() []ConfigRecovery {
+	s.configRecoveriesMu.Lock()
+	defer s.configRecoveriesMu.Unlock()
+	return append([]ConfigRecovery{}, s.configRecoveries...)
+}
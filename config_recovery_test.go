@@ -0,0 +1,227 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type recoveryFixture struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// damagedFiles are what a crash or a full disk leaves behind.
+var damagedFiles = map[string]string{
+	"truncated":  `{"name": "saved", "cou`,
+	"empty":      "",
+	"garbage":    "\x00\x00\x7fELF\xff\xfe not json",
+	"wrong type": `["a", "b"]`,
+}
+
+// corruptCopies returns the files path was moved aside to.
+func corruptCopies(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestReadConfigFileRestoresBackup(t *testing.T) {
+	for name, content := range damagedFiles {
+		t.Run(name, func(t *testing.T) {
+			s := newTestService(t)
+			path := filepath.Join(t.TempDir(), "settings.json")
+			backup := `{"name": "backup", "count": 3}`
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path+".bak", []byte(backup), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			v := recoveryFixture{Name: "default"}
+			found, err := s.readConfigFile(path, &v)
+			if err != nil || !found {
+				t.Fatalf("readConfigFile = %v, %v", found, err)
+			}
+			if v != (recoveryFixture{Name: "backup", Count: 3}) {
+				t.Fatalf("loaded %+v, want the backup", v)
+			}
+			if data, _ := os.ReadFile(path); string(data) != backup {
+				t.Fatalf("%s holds %q, want the backup back in place", path, data)
+			}
+			aside := corruptCopies(t, path)
+			if len(aside) != 1 {
+				t.Fatalf("damaged copies = %v, want one", aside)
+			}
+			if data, _ := os.ReadFile(aside[0]); string(data) != content {
+				t.Fatalf("damaged copy holds %q, want %q", data, content)
+			}
+
+			recoveries := s.GetConfigRecoveries()
+			if len(recoveries) != 1 {
+				t.Fatalf("recoveries = %+v", recoveries)
+			}
+			r := recoveries[0]
+			if r.File != path || r.MovedTo != aside[0] || r.RestoredFrom != path+".bak" || r.Error == "" || r.Message == "" || r.AtMs == 0 {
+				t.Fatalf("recovery = %+v", r)
+			}
+		})
+	}
+}
+
+func TestReadConfigFileStartsOverWithoutBackup(t *testing.T) {
+	for name, content := range damagedFiles {
+		t.Run(name, func(t *testing.T) {
+			s := newTestService(t)
+			path := filepath.Join(t.TempDir(), "session.json")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			// A damaged backup is no better than none.
+			if name == "truncated" {
+				if err := os.WriteFile(path+".bak", []byte(`{"name": `), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			v := recoveryFixture{Name: "default", Count: 1}
+			if _, err := s.readConfigFile(path, &v); err != nil {
+				t.Fatal(err)
+			}
+			if v != (recoveryFixture{Name: "default", Count: 1}) {
+				t.Fatalf("loaded %+v, want the defaults untouched", v)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Fatalf("the damaged file is still in place: %v", err)
+			}
+			if len(corruptCopies(t, path)) != 1 {
+				t.Fatal("the damaged file was not kept aside")
+			}
+			recoveries := s.GetConfigRecoveries()
+			if len(recoveries) != 1 || recoveries[0].RestoredFrom != "" || recoveries[0].Message == "" {
+				t.Fatalf("recoveries = %+v", recoveries)
+			}
+		})
+	}
+}
+
+func TestReadConfigFileValidAndMissing(t *testing.T) {
+	s := newTestService(t)
+	dir := t.TempDir()
+
+	v := recoveryFixture{Name: "default"}
+	if found, err := s.readConfigFile(filepath.Join(dir, "missing.json"), &v); found || err != nil || v.Name != "default" {
+		t.Fatalf("missing file = %v, %v, %+v", found, err, v)
+	}
+
+	path := filepath.Join(dir, "ok.json")
+	if err := os.WriteFile(path, []byte(`{"count": 2}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := s.readConfigFile(path, &v); !found || err != nil || v != (recoveryFixture{Name: "default", Count: 2}) {
+		t.Fatalf("valid file = %v, %v, %+v", found, err, v)
+	}
+	if len(corruptCopies(t, path)) != 0 || len(s.GetConfigRecoveries()) != 0 {
+		t.Fatal("a valid file was treated as damaged")
+	}
+}
+
+func TestDecodeJSONIntoLeavesTargetOnError(t *testing.T) {
+	v := recoveryFixture{Name: "default", Count: 1}
+	if err := decodeJSONInto([]byte(`{"count": 5, "name": `), &v); err == nil {
+		t.Fatal("a torn document decoded")
+	}
+	if v != (recoveryFixture{Name: "default", Count: 1}) {
+		t.Fatalf("a torn document half overwrote the target: %+v", v)
+	}
+}
+
+func TestDamagedAppStateStartsOver(t *testing.T) {
+	for name, content := range damagedFiles {
+		t.Run(name, func(t *testing.T) {
+			s := newTestService(t)
+			writeStateFile(t, s, content)
+
+			state, err := s.loadAppStateFromDir(s.configDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(state.Profiles) != 0 || state.Settings.Theme != defaultAppSettings(s.configDir).Theme {
+				t.Fatalf("state = %+v, want the defaults", state)
+			}
+			recoveries := s.GetConfigRecoveries()
+			if len(recoveries) != 1 || recoveries[0].File != s.stateFilePathIn(s.configDir) {
+				t.Fatalf("recoveries = %+v", recoveries)
+			}
+		})
+	}
+}
+
+func TestDamagedSessionStateStartsOver(t *testing.T) {
+	s := newTestService(t)
+	if err := os.MkdirAll(s.configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.sessionStatePath(), []byte(`{"prod": {"bucket": "b`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := s.GetSessionState("prod")
+	if err != nil || state.Valid {
+		t.Fatalf("GetSessionState = %+v, %v", state, err)
+	}
+	if err := s.SaveSessionState("prod", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	recoveries := s.GetConfigRecoveries()
+	if len(recoveries) != 1 || !strings.HasSuffix(recoveries[0].File, sessionStateFileName) {
+		t.Fatalf("recoveries = %+v", recoveries)
+	}
+}
+
+func TestDamagedUsageAndTempManifestAreKeptAside(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	usagePath := filepath.Join(home, ".walioss", usageFileName)
+	manifestPath := filepath.Join(home, ".walioss", tempDirName, tempManifestFileName)
+	for _, path := range []string{usagePath, manifestPath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`[{"date": "2024-`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewOSSService()
+	recovered := map[string]bool{}
+	for _, recovery := range s.GetConfigRecoveries() {
+		recovered[recovery.File] = true
+	}
+	for _, path := range []string{usagePath, manifestPath} {
+		if !recovered[path] {
+			t.Errorf("%s was not reported as recovered: %+v", path, s.GetConfigRecoveries())
+		}
+		if copies := corruptCopies(t, path); len(copies) != 1 {
+			t.Errorf("%s was kept as %v, want one copy", path, copies)
+		}
+	}
+
+	// Saving again writes a fresh file next to the damaged copy.
+	s.usage.add("prod", TransferTypeUpload, 10)
+	if err := s.usage.flush(); err != nil {
+		t.Fatal(err)
+	}
+	writeTempFile(t, s.tempFiles, tempPurposePreview, "preview")
+	for _, path := range []string{usagePath, manifestPath} {
+		if copies := corruptCopies(t, path); len(copies) != 1 {
+			t.Errorf("saving %s lost the damaged copy: %v", path, copies)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// configReloadDebounce lets an editor finish saving (many write temp files, rename and touch the
+// file again) before the config is reloaded once.
+const configReloadDebounce = 300 * time.Millisecond
+
+// ConfigChange is emitted as "config:changed" when config files changed outside the app, e.g.
+// edited by hand, and were reloaded. Error is set when the new content was not applied.
+type ConfigChange struct {
+	Files    []string `json:"files"`
+	Settings bool     `json:"settings"`
+	Profiles bool     `json:"profiles"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// watchedConfigFiles are the files of a config directory reloaded when they change.
+var watchedConfigFiles = []string{appStateFileName, encryptedProfilesFileName}
+
+// noteConfigWrite remembers the content the app writes so the watcher does not take its own saves
+// for external edits. It is called before the write: a checksum that never makes it to disk is
+// merely unused.
+func (s *OSSService) noteConfigWrite(files []pendingFile) {
+	s.configWatchMu.Lock()
+	defer s.configWatchMu.Unlock()
+	for _, file := range files {
+		s.configFileSums[file.path] = sha256.Sum256(file.data)
+	}
+}
+
+// changedConfigFiles returns the watched files in dir whose content differs from what the app last
+// wrote or loaded, and remembers their current content.
+func (s *OSSService) changedConfigFiles(dir string) []string {
+	s.configWatchMu.Lock()
+	defer s.configWatchMu.Unlock()
+	var changed []string
+	for _, name := range watchedConfigFiles {
+		path := filepath.Join(dir, name)
+		var sum [sha256.Size]byte
+		if data, err := os.ReadFile(path); err == nil {
+			sum = sha256.Sum256(data)
+		}
+		if previous, ok := s.configFileSums[path]; ok && previous == sum {
+			continue
+		}
+		s.configFileSums[path] = sum
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+// configStateSums fingerprints the settings and profiles of state, to tell which of them a reload
+// changed.
+func configStateSums(state appState) (settings [sha256.Size]byte, profiles [sha256.Size]byte) {
+	settingsData, _ := json.Marshal(state.Settings)
+	profilesData, _ := json.Marshal(state.Profiles)
+	return sha256.Sum256(settingsData), sha256.Sum256(profilesData)
+}
+
+// watchConfig reloads the config when config.json or profiles.enc change outside the app until ctx
+// ends. It follows the work dir when that moves.
+func (s *OSSService) watchConfig(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.logOperationEvent(logLevelWarn, "WatchConfig", opOutcomeError, "", err)
+		return
+	}
+	defer watcher.Close()
+
+	dir := ""
+	follow := func() {
+		next := s.configDir
+		if next == dir {
+			return
+		}
+		if dir != "" {
+			_ = watcher.Remove(dir)
+		}
+		// Watch the directory rather than the files: saves replace them by renaming a temp file.
+		if err := watcher.Add(next); err != nil {
+			s.logOperationEvent(logLevelWarn, "WatchConfig", opOutcomeError, next, err)
+			dir = ""
+			return
+		}
+		dir = next
+		s.changedConfigFiles(dir)
+		if state, err := s.loadAppStateFromDir(dir); err == nil {
+			settingsSum, profilesSum := configStateSums(state)
+			s.configWatchMu.Lock()
+			s.configSettingsSum, s.configProfilesSum = settingsSum, profilesSum
+			s.configWatchMu.Unlock()
+		}
+	}
+	follow()
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.configDirMoved:
+			follow()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isWatchedConfigEvent(dir, event) {
+				continue
+			}
+			reload = time.After(configReloadDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			s.logOperationEvent(logLevelWarn, "WatchConfig", opOutcomeError, dir, err)
+		case <-reload:
+			reload = nil
+			if change, ok := s.reloadChangedConfig(dir); ok {
+				runtime.EventsEmit(ctx, "config:changed", change)
+			}
+			follow()
+		}
+	}
+}
+
+func isWatchedConfigEvent(dir string, event fsnotify.Event) bool {
+	if dir == "" || filepath.Dir(event.Name) != dir || event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Base(event.Name)
+	for _, watched := range watchedConfigFiles {
+		if name == watched {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadChangedConfig reloads the config of dir if a watched file changed outside the app and
+// applies the settings to the running app. It returns false when nothing changed, including when
+// the change was the app's own save.
+func (s *OSSService) reloadChangedConfig(dir string) (ConfigChange, bool) {
+	changed := s.changedConfigFiles(dir)
+	if len(changed) == 0 {
+		return ConfigChange{}, false
+	}
+	change := ConfigChange{Files: changed}
+	detail := strings.Join(changed, ", ")
+
+	// A file that does not parse is most likely still being edited. It is left alone, instead of
+	// being moved aside the way a damaged file found at startup is, until a later save fixes it.
+	if data, err := os.ReadFile(s.stateFilePathIn(dir)); err == nil && !json.Valid(data) {
+		change.Error = s.msg(msgConfigReloadInvalid, appStateFileName)
+		s.logOperationEvent(logLevelWarn, "ReloadConfig", opOutcomeError, detail, errors.New(change.Error))
+		return change, true
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		change.Error = err.Error()
+		return change, true
+	}
+	state, err := s.loadAppState()
+	unlock()
+	if err != nil {
+		change.Error = err.Error()
+		s.logOperationEvent(logLevelWarn, "ReloadConfig", opOutcomeError, detail, err)
+		return change, true
+	}
+	s.applySettingsRuntime(state.Settings)
+
+	settingsSum, profilesSum := configStateSums(state)
+	s.configWatchMu.Lock()
+	change.Settings = settingsSum != s.configSettingsSum
+	change.Profiles = profilesSum != s.configProfilesSum
+	s.configSettingsSum, s.configProfilesSum = settingsSum, profilesSum
+	s.configWatchMu.Unlock()
+
+	s.logOperationEvent(logLevelInfo, "ReloadConfig", opOutcomeOK, detail, nil)
+	return change, true
+}
+
+// noteConfigDirMoved makes the watcher follow the work dir to its new place.
+func (s *OSSService) noteConfigDirMoved() {
+	select {
+	case s.configDirMoved <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestOwnConfigWritesAreNotReloaded(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, s *OSSService)
+		write func(t *testing.T, s *OSSService)
+	}{
+		{
+			name:  "rename profile",
+			setup: func(t *testing.T, s *OSSService) { seedProfiles(t, s, "prod", "dev") },
+			write: func(t *testing.T, s *OSSService) {
+				if err := s.RenameProfile("dev", "staging"); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "schema migration",
+			setup: func(t *testing.T, s *OSSService) {
+				if err := os.MkdirAll(s.configDir, 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(s.stateFilePathIn(s.configDir), readFixture(t, "config-v1.json"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			write: func(t *testing.T, s *OSSService) {
+				if _, err := s.loadAppState(); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "restore from backup",
+			setup: func(t *testing.T, s *OSSService) {
+				seedProfiles(t, s, "prod")
+				seedProfiles(t, s, "prod", "dev")
+				if err := os.WriteFile(s.stateFilePathIn(s.configDir), []byte(`{"profiles": [`), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			},
+			write: func(t *testing.T, s *OSSService) {
+				if _, err := s.loadAppState(); err != nil {
+					t.Fatal(err)
+				}
+				if len(s.GetConfigRecoveries()) == 0 {
+					t.Fatal("the damaged config.json was not restored")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring.MockInit()
+			s := newTestService(t)
+			tt.setup(t, s)
+			// The watcher starts out knowing what is on disk.
+			s.changedConfigFiles(s.configDir)
+
+			tt.write(t, s)
+			if changed := s.changedConfigFiles(s.configDir); len(changed) != 0 {
+				t.Fatalf("the app's own write looks like an external edit of %v", changed)
+			}
+		})
+	}
+}
+
+func TestExternalConfigEditIsReloaded(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod")
+	s.changedConfigFiles(s.configDir)
+
+	data, err := os.ReadFile(s.stateFilePathIn(s.configDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "cn-hangzhou", "cn-beijing", 1)
+	if err := os.WriteFile(s.stateFilePathIn(s.configDir), []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed := s.changedConfigFiles(s.configDir); len(changed) != 1 || changed[0] != appStateFileName {
+		t.Fatalf("changed files after an external edit = %v, want [%s]", changed, appStateFileName)
+	}
+}
+
+func TestRenameProfileBacksUpConfig(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod", "dev")
+	before, err := os.ReadFile(s.stateFilePathIn(s.configDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RenameProfile("dev", "staging"); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(s.stateBackupPathIn(s.configDir))
+	if err != nil {
+		t.Fatalf("RenameProfile left no config.json.bak: %v", err)
+	}
+	if string(backup) != string(before) {
+		t.Fatalf("config.json.bak after RenameProfile =\n%s\nwant the config from before the rename", backup)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	confirmationTokenTTL = 30 * time.Second
+
+	// destructiveEstimateMaxKeys caps how many objects RequestConfirmationToken counts in a folder.
+	destructiveEstimateMaxKeys = 1000
+)
+
+// Operations that need a token from RequestDestructiveToken or RequestConfirmationToken before the
+// backend applies them. The target of every operation is an oss:// path: the object or folder for
+// object deletes, oss://bucket/ for bucket settings, and inventoryTarget for inventory deletes.
+const (
+	// confirmOpDeleteObject deletes a file, or everything under a folder path ending in "/".
+	confirmOpDeleteObject           = "object:delete"
+	confirmOpClearBucketReferer     = "bucket:referer:clear"
+	confirmOpClearBucketLifecycle   = "bucket:lifecycle:clear"
+	confirmOpDeleteBucketCORS       = "bucket:cors:delete"
+	confirmOpDeleteBucketPolicy     = "bucket:policy:delete"
+	confirmOpDeleteBucketTagging    = "bucket:tagging:delete"
+	confirmOpDeleteBucketEncryption = "bucket:encryption:delete"
+	confirmOpDeleteBucketInventory  = "bucket:inventory:delete"
+)
+
+// bucketConfirmations describes the operations whose target is oss://bucket/.
+var bucketConfirmations = map[string]string{
+	confirmOpClearBucketReferer:     "turn off hotlink protection of %s",
+	confirmOpClearBucketLifecycle:   "delete every lifecycle rule of %s",
+	confirmOpDeleteBucketCORS:       "delete every CORS rule of %s",
+	confirmOpDeleteBucketPolicy:     "delete the bucket policy of %s",
+	confirmOpDeleteBucketTagging:    "delete every tag of %s",
+	confirmOpDeleteBucketEncryption: "delete the default encryption rule of %s",
+}
+
+// inventoryTargetQuery follows the bucket in the target of an inventory delete, the way the
+// inventory ID follows it in the OSS request.
+const inventoryTargetQuery = "?inventoryId="
+
+// inventoryTarget is the confirmation target for deleting one inventory configuration,
+// e.g. "oss://bucket/?inventoryId=daily".
+func inventoryTarget(bucket string, inventoryID string) string {
+	return buildOssPath(bucket, inventoryTargetQuery+inventoryID)
+}
+
+var ErrConfirmationRequired = errors.New("confirmation required")
+
+type confirmationToken struct {
+	operation string
+	target    string
+	expiresAt time.Time
+}
+
+func (s *OSSService) issueConfirmationToken(operation string, target string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate confirmation token failed: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	now := time.Now()
+	s.confirmTokensMu.Lock()
+	for key, entry := range s.confirmTokens {
+		if now.After(entry.expiresAt) {
+			delete(s.confirmTokens, key)
+		}
+	}
+	s.confirmTokens[token] = confirmationToken{
+		operation: operation,
+		target:    target,
+		expiresAt: now.Add(confirmationTokenTTL),
+	}
+	s.confirmTokensMu.Unlock()
+	return token, nil
+}
+
+// consumeConfirmationToken checks that token was issued for exactly this operation and target.
+// Tokens are single use: a token is removed as soon as it is presented, even if it does not match.
+func (s *OSSService) consumeConfirmationToken(token string, operation string, target string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("%w: %s on %s needs a confirmation token", ErrConfirmationRequired, operation, target)
+	}
+
+	s.confirmTokensMu.Lock()
+	entry, ok := s.confirmTokens[token]
+	delete(s.confirmTokens, token)
+	s.confirmTokensMu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: unknown or already used confirmation token", ErrConfirmationRequired)
+	}
+	if time.Now().After(entry.expiresAt) {
+		return fmt.Errorf("%w: confirmation token expired", ErrConfirmationRequired)
+	}
+	if entry.operation != operation || entry.target != target {
+		return fmt.Errorf("%w: confirmation token was issued for %s on %s", ErrConfirmationRequired, entry.operation, entry.target)
+	}
+	return nil
+}
+
+// ConfirmationToken is a one-time confirmation for a destructive call, with a description of what
+// the call will do so the confirmation dialog can show it.
+type ConfirmationToken struct {
+	Token       string `json:"token"`
+	Operation   string `json:"operation"`
+	Target      string `json:"target"`
+	Description string `json:"description"`
+	// EstimatedObjects is how many objects the call removes; 0 for settings changes.
+	EstimatedObjects int `json:"estimatedObjects"`
+	// EstimateComplete is false when there are more objects than were counted.
+	EstimateComplete bool  `json:"estimateComplete"`
+	ExpiresAtMs      int64 `json:"expiresAtMs"`
+}
+
+// requireConfirmationToken consumes token for operation on target unless the settings turned
+// destructive confirmations off.
+func (s *OSSService) requireConfirmationToken(token string, operation string, target string) error {
+	s.destructiveConfirmMu.RLock()
+	skip := s.skipDestructiveConfirm
+	s.destructiveConfirmMu.RUnlock()
+	if skip {
+		return nil
+	}
+	return s.consumeConfirmationToken(token, operation, target)
+}
+
+func (s *OSSService) setSkipDestructiveConfirmation(skip bool) {
+	s.destructiveConfirmMu.Lock()
+	s.skipDestructiveConfirm = skip
+	s.destructiveConfirmMu.Unlock()
+}
+
+// countPrefixObjects counts the objects under prefix, stopping at destructiveEstimateMaxKeys.
+func (s *OSSService) countPrefixObjects(config OSSConfig, bucket string, prefix string) (int, bool, error) {
+	var lor oss.ListObjectsResult
+	err := s.withBucketRegion(config, bucket, "ListObjects", func(bkt *oss.Bucket) error {
+		return s.withSDKRetry("ListObjects", func() error {
+			var err error
+			lor, err = bkt.ListObjects(oss.Prefix(prefix), oss.MaxKeys(destructiveEstimateMaxKeys))
+			return err
+		})
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return len(lor.Objects), !lor.IsTruncated, nil
+}
+
+// RequestDestructiveToken returns a 30 second one-time token that a destructive call requires for
+// operation on target; see the confirmOp constants. Use RequestConfirmationToken instead to also
+// get a description of the call and the number of objects a folder delete removes.
+func (s *OSSService) RequestDestructiveToken(operation string, target string) (_ string, err error) {
+	defer s.logOperation("RequestDestructiveToken", "", target, time.Now(), &err)
+
+	confirmation, err := s.describeConfirmation(nil, operation, target)
+	if err != nil {
+		return "", err
+	}
+	return confirmation.Token, nil
+}
+
+// RequestConfirmationToken is RequestDestructiveToken with a description of what the call will do,
+// for the confirmation dialog to show. For a folder it says how many objects the folder holds.
+func (s *OSSService) RequestConfirmationToken(config OSSConfig, operation string, target string) (_ ConfirmationToken, err error) {
+	defer s.logOperation("RequestConfirmationToken", "", target, time.Now(), &err)
+	return s.describeConfirmation(&config, operation, target)
+}
+
+// describeConfirmation validates target for operation and issues its token. Objects in a folder are
+// only counted when config is given.
+func (s *OSSService) describeConfirmation(config *OSSConfig, operation string, target string) (ConfirmationToken, error) {
+	operation = strings.TrimSpace(operation)
+
+	// Keys are kept byte for byte, trailing spaces included, so the target is not trimmed.
+	bucket, key, ok := parseOssPath(target)
+	if !ok {
+		return ConfirmationToken{}, fmt.Errorf("target must be an oss:// path: %s", target)
+	}
+	target = buildOssPath(bucket, key)
+
+	var description string
+	count, complete := 0, true
+	switch operation {
+	case confirmOpDeleteObject:
+		if key == "" {
+			return ConfirmationToken{}, fmt.Errorf("target must be an oss://bucket/key path: %s", target)
+		}
+		if !strings.HasSuffix(key, "/") {
+			count = 1
+			description = "delete " + target
+			break
+		}
+		if config == nil {
+			complete = false
+			description = "delete every object under " + target
+			break
+		}
+		var err error
+		count, complete, err = s.countPrefixObjects(*config, bucket, key)
+		if err != nil {
+			return ConfirmationToken{}, err
+		}
+		estimate := fmt.Sprintf("%d", count)
+		if !complete {
+			estimate = fmt.Sprintf("more than %d", count)
+		}
+		description = fmt.Sprintf("delete %s objects under %s", estimate, target)
+	case confirmOpDeleteBucketInventory:
+		inventoryID, ok := strings.CutPrefix(key, inventoryTargetQuery)
+		if !ok || strings.TrimSpace(inventoryID) == "" {
+			return ConfirmationToken{}, fmt.Errorf("target must be an oss://bucket/%s<id> path: %s", inventoryTargetQuery, target)
+		}
+		description = fmt.Sprintf("delete inventory configuration %s of %s", inventoryID, bucket)
+	default:
+		format, ok := bucketConfirmations[operation]
+		if !ok {
+			return ConfirmationToken{}, fmt.Errorf("unknown operation: %s", operation)
+		}
+		if key != "" {
+			return ConfirmationToken{}, fmt.Errorf("target must be an oss://bucket/ path: %s", target)
+		}
+		description = fmt.Sprintf(format, bucket)
+	}
+
+	token, err := s.issueConfirmationToken(operation, target)
+	if err != nil {
+		return ConfirmationToken{}, err
+	}
+	return ConfirmationToken{
+		Token:            token,
+		Operation:        operation,
+		Target:           target,
+		Description:      description,
+		EstimatedObjects: count,
+		EstimateComplete: complete,
+		ExpiresAtMs:      time.Now().Add(confirmationTokenTTL).UnixMilli(),
+	}, nil
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeOssutilRm installs an ossutil that logs the path of every rm and returns the log.
+func fakeOssutilRm(t *testing.T, s *OSSService) func() string {
+	t.Helper()
+	logPath := filepath.Join(t.TempDir(), "rm.log")
+	fakeOssutil(t, s, `if [ "$1" = rm ]; then printf '%s\n' "$2" >> '`+logPath+`'; fi`)
+	return func() string {
+		data, _ := os.ReadFile(logPath)
+		return string(data)
+	}
+}
+
+func TestDeleteObjectRequiresToken(t *testing.T) {
+	s := newTestService(t)
+	removed := fakeOssutilRm(t, s)
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+
+	if err := s.DeleteObject(config, "bucket", "a.txt", ""); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("delete without a token = %v, want ErrConfirmationRequired", err)
+	}
+	other, err := s.RequestConfirmationToken(config, confirmOpDeleteObject, "oss://bucket/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteObject(config, "bucket", "a.txt", other.Token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("delete with a token for another object = %v", err)
+	}
+	if got := removed(); got != "" {
+		t.Fatalf("ossutil removed %q without a matching token", got)
+	}
+
+	token, err := s.RequestConfirmationToken(config, confirmOpDeleteObject, "oss://bucket/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Target != "oss://bucket/a.txt" || token.EstimatedObjects != 1 || !token.EstimateComplete || token.Description != "delete oss://bucket/a.txt" {
+		t.Fatalf("token = %+v", token)
+	}
+	if err := s.DeleteObject(config, "bucket", "a.txt", token.Token); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteObject(config, "bucket", "a.txt", token.Token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("a used token was accepted again: %v", err)
+	}
+	if got := removed(); got != "oss://bucket/a.txt\n" {
+		t.Fatalf("ossutil removed %q, want oss://bucket/a.txt once", got)
+	}
+}
+
+func TestDeleteFolderRequiresToken(t *testing.T) {
+	s := newTestService(t)
+	var deletes atomic.Int32
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			deletes.Add(1)
+		}
+		writeListing(w, []string{"dir/a.txt", "dir/b/c.txt"}, nil)
+	})
+
+	token, err := s.RequestConfirmationToken(config, confirmOpDeleteObject, "oss://bucket/dir/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.EstimatedObjects != 2 || !token.EstimateComplete || token.Description != "delete 2 objects under oss://bucket/dir/" {
+		t.Fatalf("token = %+v", token)
+	}
+	for _, bad := range []string{"", "not-a-token"} {
+		if err := s.DeleteObject(config, "bucket", "dir/", bad); !errors.Is(err, ErrConfirmationRequired) {
+			t.Fatalf("folder delete with token %q = %v", bad, err)
+		}
+	}
+	if err := s.DeleteObject(config, "bucket", "other/", token.Token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("folder delete with another folder's token = %v", err)
+	}
+	if got := deletes.Load(); got != 0 {
+		t.Fatalf("%d delete requests were sent without a matching token", got)
+	}
+}
+
+func TestConfirmationTokenExpires(t *testing.T) {
+	s := newTestService(t)
+	token, err := s.issueConfirmationToken(confirmOpDeleteObject, "oss://bucket/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.confirmTokensMu.Lock()
+	entry := s.confirmTokens[token]
+	entry.expiresAt = time.Now().Add(-time.Second)
+	s.confirmTokens[token] = entry
+	s.confirmTokensMu.Unlock()
+
+	err = s.requireConfirmationToken(token, confirmOpDeleteObject, "oss://bucket/a.txt")
+	if !errors.Is(err, ErrConfirmationRequired) || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("an expired token = %v", err)
+	}
+}
+
+func TestConfirmationTokenOperationMustMatch(t *testing.T) {
+	s := newTestService(t)
+	token, err := s.RequestConfirmationToken(OSSConfig{}, confirmOpClearBucketReferer, "oss://bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.requireConfirmationToken(token.Token, confirmOpDeleteObject, "oss://bucket/"); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("a referer token deleted an object: %v", err)
+	}
+}
+
+func TestSkipDestructiveConfirmation(t *testing.T) {
+	s := newTestService(t)
+	removed := fakeOssutilRm(t, s)
+	s.setSkipDestructiveConfirmation(true)
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+	if err := s.DeleteObject(config, "bucket", "a.txt", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := removed(); got != "oss://bucket/a.txt\n" {
+		t.Fatalf("ossutil removed %q", got)
+	}
+}
+
+func TestClearBucketRefererRequiresToken(t *testing.T) {
+	s := newTestService(t)
+	var requests atomic.Int32
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeOSSError(w, http.StatusNotImplemented, "NotImplemented", "")
+	})
+
+	if err := s.SetBucketReferer(config, "bucket", true, nil, ""); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("clearing without a token = %v", err)
+	}
+	token, err := s.RequestConfirmationToken(config, confirmOpClearBucketReferer, "oss://other/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetBucketReferer(config, "bucket", true, nil, token.Token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("clearing with another bucket's token = %v", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("%d requests were sent without a matching token", got)
+	}
+
+	token, err = s.RequestConfirmationToken(config, confirmOpClearBucketReferer, "oss://bucket/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.SetBucketReferer(config, "bucket", true, nil, token.Token)
+	if requests.Load() == 0 {
+		t.Fatal("a matching token did not let the change through")
+	}
+}
+
+func TestRequestConfirmationTokenRejectsBadRequests(t *testing.T) {
+	s := newTestService(t)
+	tests := []struct{ operation, target string }{
+		{"bucket:delete", "oss://bucket/"},
+		{"", "oss://bucket/a.txt"},
+		{confirmOpDeleteObject, "bucket/a.txt"},
+		{confirmOpDeleteObject, "oss://bucket/"},
+		{confirmOpDeleteObject, "oss://"},
+		{confirmOpClearBucketReferer, "oss://bucket/a.txt"},
+		{confirmOpDeleteBucketCORS, "oss://bucket/a.txt"},
+		{confirmOpDeleteBucketInventory, "oss://bucket/"},
+		{confirmOpDeleteBucketInventory, "oss://bucket/?inventoryId="},
+	}
+	for _, tt := range tests {
+		if token, err := s.RequestConfirmationToken(OSSConfig{}, tt.operation, tt.target); err == nil {
+			t.Errorf("RequestConfirmationToken(%q, %q) = %+v, want an error", tt.operation, tt.target, token)
+		}
+	}
+}
+
+func TestRequestDestructiveToken(t *testing.T) {
+	s := newTestService(t)
+	token, err := s.RequestDestructiveToken(confirmOpDeleteObject, "oss://bucket/dir/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.requireConfirmationToken(token, confirmOpDeleteObject, "oss://bucket/dir/"); err != nil {
+		t.Fatalf("the token was not accepted: %v", err)
+	}
+	if _, err := s.RequestDestructiveToken("bucket:delete", "oss://bucket/"); err == nil {
+		t.Error("a token was issued for an unknown operation")
+	}
+}
+
+func TestBucketSettingDeletesRequireToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		target    string
+		call      func(s *OSSService, config OSSConfig, token string) error
+	}{
+		{"DeleteBucketCORS", confirmOpDeleteBucketCORS, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.DeleteBucketCORS(config, "bucket", token)
+		}},
+		{"SetBucketCORS empty", confirmOpDeleteBucketCORS, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.SetBucketCORS(config, "bucket", nil, token)
+		}},
+		{"DeleteBucketPolicy", confirmOpDeleteBucketPolicy, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.DeleteBucketPolicy(config, "bucket", token)
+		}},
+		{"DeleteBucketTagging", confirmOpDeleteBucketTagging, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.DeleteBucketTagging(config, "bucket", token)
+		}},
+		{"SetBucketTagging empty", confirmOpDeleteBucketTagging, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.SetBucketTagging(config, "bucket", nil, token)
+		}},
+		{"DeleteBucketEncryption", confirmOpDeleteBucketEncryption, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.DeleteBucketEncryption(config, "bucket", token)
+		}},
+		{"DeleteBucketInventory", confirmOpDeleteBucketInventory, "oss://bucket/?inventoryId=daily", func(s *OSSService, config OSSConfig, token string) error {
+			return s.DeleteBucketInventory(config, "bucket", "daily", token)
+		}},
+		{"SetBucketLifecycle empty", confirmOpClearBucketLifecycle, "oss://bucket/", func(s *OSSService, config OSSConfig, token string) error {
+			return s.SetBucketLifecycle(config, "bucket", nil, token)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			var requests atomic.Int32
+			config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			if err := tt.call(s, config, ""); !errors.Is(err, ErrConfirmationRequired) {
+				t.Fatalf("without a token = %v", err)
+			}
+			other, err := s.RequestDestructiveToken(tt.operation, "oss://other/"+strings.TrimPrefix(tt.target, "oss://bucket/"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.call(s, config, other); !errors.Is(err, ErrConfirmationRequired) {
+				t.Fatalf("with another bucket's token = %v", err)
+			}
+			if got := requests.Load(); got != 0 {
+				t.Fatalf("%d requests were sent without a matching token", got)
+			}
+
+			token, err := s.RequestDestructiveToken(tt.operation, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.call(s, config, token); err != nil {
+				t.Fatalf("with a matching token = %v", err)
+			}
+			if requests.Load() == 0 {
+				t.Fatal("a matching token did not let the change through")
+			}
+		})
+	}
+}
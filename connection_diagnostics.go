@@ -0,0 +1,183 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Diagnosis codes reported in ConnectionResult.ErrorCode. OSS error codes are passed through as
+// they are; the others classify failures that never got an answer from OSS.
+const (
+	diagInvalidAccessKeyID = "InvalidAccessKeyId"
+	diagSignatureMismatch  = "SignatureDoesNotMatch"
+	diagRequestTimeSkewed  = "RequestTimeTooSkewed"
+	diagAccessDenied       = "AccessDenied"
+	diagNoSuchBucket       = "NoSuchBucket"
+	diagDNSFailure         = "DNSFailure"
+	diagConnectionRefused  = "ConnectionRefused"
+	diagTimeout            = "Timeout"
+	diagTLSFailure         = "TLSFailure"
+	diagProxyFailure       = "ProxyFailure"
+	diagUnknown            = "Unknown"
+)
+
+const (
+	clockSkewProbeTimeout = 5 * time.Second
+	// OSS rejects requests whose time is more than 15 minutes off.
+	maxClockSkew = 15 * time.Minute
+)
+
+var diagnosisHints = map[string]messageID{
+	diagInvalidAccessKeyID: msgHintInvalidAccessKeyID,
+	diagSignatureMismatch:  msgHintSignatureMismatch,
+	diagRequestTimeSkewed:  msgHintRequestTimeSkewed,
+	diagAccessDenied:       msgHintAccessDenied,
+	diagNoSuchBucket:       msgHintNoSuchBucket,
+	diagDNSFailure:         msgHintDNSFailure,
+	diagConnectionRefused:  msgHintConnectionRefused,
+	diagTimeout:            msgHintTimeout,
+	diagTLSFailure:         msgHintTLSFailure,
+	diagProxyFailure:       msgHintProxyFailure,
+}
+
+// ossutil prints the OSS error code as "ErrorCode=..." or "Code: ...".
+var reOssutilErrorCode = regexp.MustCompile(`(?:ErrorCode=|Code:\s*)"?([A-Za-z]+)`)
+
+var reServerTime = regexp.MustCompile(`<ServerTime>([^<]+)</ServerTime>`)
+
+// diagnoseConnectionError classifies why a connection attempt failed.
+func diagnoseConnectionError(err error) string {
+	if err == nil {
+		return ""
+	}
+	code := ossServiceErrorCode(err)
+	if code == "" {
+		if m := reOssutilErrorCode.FindStringSubmatch(err.Error()); m != nil {
+			code = m[1]
+		}
+	}
+	switch code {
+	case diagInvalidAccessKeyID, diagSignatureMismatch, diagRequestTimeSkewed, diagNoSuchBucket:
+		return code
+	case "":
+	default:
+		if isAccessDenied(err) {
+			return diagAccessDenied
+		}
+		return code
+	}
+
+	if isProxyError(err) {
+		return diagProxyFailure
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return diagDNSFailure
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return diagConnectionRefused
+	}
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) {
+		return diagTLSFailure
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return diagTimeout
+	}
+
+	// ossutil only reports these as text.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "no such host"):
+		return diagDNSFailure
+	case strings.Contains(msg, "connection refused"):
+		return diagConnectionRefused
+	case strings.Contains(msg, "x509:") || strings.Contains(msg, "certificate"):
+		return diagTLSFailure
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return diagTimeout
+	}
+	return diagUnknown
+}
+
+// measureClockSkew returns how far the server's clock is ahead of the local one. OSS reports its
+// time in RequestTimeTooSkewed errors; otherwise an anonymous HEAD request to the endpoint is sent
+// and its Date header read. The probe carries no credentials, so it does not verify the certificate.
+func (s *OSSService) measureClockSkew(config OSSConfig, endpoint string, err error) (time.Duration, bool) {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		if m := reServerTime.FindStringSubmatch(serviceErr.RawMessage); m != nil {
+			if serverTime, parseErr := time.Parse(time.RFC3339, strings.TrimSpace(m[1])); parseErr == nil {
+				return time.Until(serverTime), true
+			}
+		}
+	}
+	if endpoint == "" {
+		return 0, false
+	}
+
+	transport := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	if proxy, ok := s.proxyFor(config); ok {
+		if proxyURL, err := proxy.authURL(); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	client := &http.Client{Transport: transport, Timeout: clockSkewProbeTimeout}
+	defer transport.CloseIdleConnections()
+
+	sent := time.Now()
+	resp, err := client.Head(endpoint)
+	if err != nil {
+		return 0, false
+	}
+	resp.Body.Close()
+	received := time.Now()
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	// Date has a one-second resolution; compare it with the middle of the round trip.
+	local := sent.Add(received.Sub(sent) / 2)
+	return serverTime.Sub(local), true
+}
+
+// diagnoseConnection fills the diagnosis fields of a failed result.
+func (s *OSSService) diagnoseConnection(result *ConnectionResult, config OSSConfig, endpoint string, err error) {
+	result.Endpoint = endpoint
+	result.ErrorCode = diagnoseConnectionError(err)
+
+	// Only a server that answered can have a clock to compare with.
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) || result.ErrorCode == diagRequestTimeSkewed {
+		if skew, ok := s.measureClockSkew(config, endpoint, err); ok {
+			seconds := int64(skew.Round(time.Second) / time.Second)
+			result.ClockSkewSec = &seconds
+			if result.ErrorCode == diagSignatureMismatch && (skew > maxClockSkew || skew < -maxClockSkew) {
+				result.ErrorCode = diagRequestTimeSkewed
+			}
+		}
+	}
+	if id, ok := diagnosisHints[result.ErrorCode]; ok {
+		if result.ErrorCode == diagRequestTimeSkewed && result.ClockSkewSec != nil {
+			offBy := *result.ClockSkewSec
+			if offBy < 0 {
+				offBy = -offBy
+			}
+			result.Hint = s.msg(msgHintRequestTimeSkewedBy, offBy)
+		} else {
+			result.Hint = s.msg(id)
+		}
+	}
+}
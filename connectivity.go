@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	networkProbeTimeout  = 2 * time.Second
+	networkProbeMinDelay = 2 * time.Second
+	networkProbeMaxDelay = 30 * time.Second
+)
+
+// ErrOffline is returned without contacting OSS while the connectivity monitor considers the
+// endpoint of a request unreachable.
+var ErrOffline = errors.New("offline: the OSS endpoint cannot be reached, waiting for it to come back")
+
+// networkMonitor tracks which endpoints are reachable. An endpoint is marked offline when a
+// connection to it cannot be made at all, and the monitor then probes it (or its proxy) until a
+// connection succeeds again. Other endpoints are not affected.
+type networkMonitor struct {
+	mu sync.Mutex
+	// probeAddrs maps the host of every SDK endpoint in use to the address probed for it, so a
+	// failed request can be traced back to its endpoint.
+	probeAddrs map[string]string
+	// offline holds a channel per unreachable probe address that is closed once it is back.
+	offline map[string]chan struct{}
+}
+
+// reachable is the closed channel onlineSignal returns for endpoints that are up.
+var reachable = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+func newNetworkMonitor() *networkMonitor {
+	return &networkMonitor{probeAddrs: map[string]string{}, offline: map[string]chan struct{}{}}
+}
+
+// isNetworkError reports failures to reach OSS at all, as opposed to errors OSS answered with.
+func isNetworkError(err error) bool {
+	if err == nil || errors.Is(err, ErrOffline) {
+		return false
+	}
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect") {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH) {
+		return true
+	}
+	// ossutil only reports these as text.
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"no such host", "network is unreachable", "no route to host", "dial tcp", "i/o timeout"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnreachableError reports the network errors that mean an endpoint cannot be reached: the
+// name does not resolve, the connection is refused or there is no route. Timeouts are left out;
+// a slow endpoint is retried rather than marked offline.
+func isUnreachableError(err error) bool {
+	if !isNetworkError(err) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	// ossutil only reports timeouts as text.
+	msg := strings.ToLower(err.Error())
+	return !strings.Contains(msg, "timeout") && !strings.Contains(msg, "timed out")
+}
+
+// probeAddressFor is the address the monitor dials to see whether config's endpoint is reachable:
+// the proxy when there is one, otherwise the endpoint itself.
+func (s *OSSService) probeAddressFor(config OSSConfig, endpoint string) string {
+	if proxy, ok := s.proxyFor(config); ok {
+		if hostPort, err := proxy.hostPort(); err == nil {
+			return hostPort
+		}
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "443"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// ossutilProbeAddr is the probe address of the endpoint ossutil talks to for purpose: the one
+// passed with --endpoint, or the regional endpoint ossutil derives without it.
+func (s *OSSService) ossutilProbeAddr(config OSSConfig, purpose endpointPurpose) string {
+	endpoint, err := endpointURLForConfig(config, purpose)
+	if err != nil {
+		return ""
+	}
+	if endpoint == "" {
+		host := suggestServiceEndpoint(config.Region, config.UseInternalEndpoint, config.UseDualStackEndpoint)
+		if host == "" {
+			return ""
+		}
+		endpoint = "https://" + host
+	}
+	return s.probeAddressFor(config, endpoint)
+}
+
+// sdkProbeAddr is the probe address of the endpoint SDK requests for purpose go to.
+func (s *OSSService) sdkProbeAddr(config OSSConfig, purpose endpointPurpose) string {
+	endpoint, err := sdkEndpointForConfig(config, purpose)
+	if err != nil {
+		return ""
+	}
+	return s.probeAddressFor(config, endpoint)
+}
+
+// rememberProbeTarget records the probe address of an SDK endpoint, which failed requests to the
+// endpoint or to its bucket subdomains are traced back to, and returns it.
+func (s *OSSService) rememberProbeTarget(config OSSConfig, endpoint string) string {
+	addr := s.probeAddressFor(config, endpoint)
+	u, err := url.Parse(endpoint)
+	if addr == "" || err != nil || u.Hostname() == "" {
+		return addr
+	}
+	s.network.mu.Lock()
+	s.network.probeAddrs[strings.ToLower(u.Hostname())] = addr
+	s.network.mu.Unlock()
+	return addr
+}
+
+// probeAddrForError traces a failed SDK request back to the probe address of its endpoint. The
+// SDK reports transport failures as *url.Error, whose URL names the endpoint or, for
+// virtual-hosted requests, a bucket subdomain of it.
+func (s *OSSService) probeAddrForError(err error) string {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return ""
+	}
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return ""
+	}
+	s.network.mu.Lock()
+	defer s.network.mu.Unlock()
+	for host := strings.ToLower(u.Hostname()); host != ""; {
+		if addr, ok := s.network.probeAddrs[host]; ok {
+			return addr
+		}
+		_, host, _ = strings.Cut(host, ".")
+	}
+	return ""
+}
+
+// requireReachable fails fast while addr is offline so listing and metadata calls do not hang.
+func (s *OSSService) requireReachable(addr string) error {
+	select {
+	case <-s.onlineSignal(addr):
+		return nil
+	default:
+		return fmt.Errorf("%w (%s)", ErrOffline, addr)
+	}
+}
+
+// onlineSignal returns a channel that is closed while addr is reachable, for waits that must also
+// be cancelable.
+func (s *OSSService) onlineSignal(addr string) <-chan struct{} {
+	s.network.mu.Lock()
+	defer s.network.mu.Unlock()
+	if ch, ok := s.network.offline[addr]; ok {
+		return ch
+	}
+	return reachable
+}
+
+// noteNetworkFailure marks the endpoint of a failed SDK request offline when err shows it could
+// not be reached. It reports whether err was such a failure, so callers can stop retrying.
+func (s *OSSService) noteNetworkFailure(err error) bool {
+	if !isUnreachableError(err) {
+		return false
+	}
+	s.noteEndpointFailure(s.probeAddrForError(err), err)
+	return true
+}
+
+// noteEndpointFailure is noteNetworkFailure for callers that know the probe address, such as
+// ossutil runs, whose errors are plain text.
+func (s *OSSService) noteEndpointFailure(addr string, err error) bool {
+	if !isUnreachableError(err) {
+		return false
+	}
+	if addr == "" {
+		return true
+	}
+
+	s.network.mu.Lock()
+	if _, down := s.network.offline[addr]; down {
+		s.network.mu.Unlock()
+		return true
+	}
+	s.network.offline[addr] = make(chan struct{})
+	s.network.mu.Unlock()
+
+	s.logOperationEvent(logLevelWarn, "NetworkMonitor", opOutcomeError, "offline: "+addr, err)
+	s.emitNetworkEvent("network:offline", addr, err)
+	go s.probeUntilOnline(s.baseContext(), addr)
+	return true
+}
+
+// probeUntilOnline dials addr with growing delays until it answers, then marks it online. It gives
+// up when ctx ends, e.g. on shutdown, leaving addr offline.
+func (s *OSSService) probeUntilOnline(ctx context.Context, addr string) {
+	delay := networkProbeMinDelay
+	dialer := net.Dialer{Timeout: networkProbeTimeout}
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if delay *= 2; delay > networkProbeMaxDelay {
+			delay = networkProbeMaxDelay
+		}
+	}
+
+	s.network.mu.Lock()
+	close(s.network.offline[addr])
+	delete(s.network.offline, addr)
+	s.network.mu.Unlock()
+
+	s.logOperationEvent(logLevelInfo, "NetworkMonitor", opOutcomeOK, "online: "+addr, nil)
+	s.emitNetworkEvent("network:online", addr, nil)
+}
+
+func (s *OSSService) emitNetworkEvent(name string, addr string, err error) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	payload := map[string]string{"endpoint": addr}
+	if err != nil {
+		payload["reason"] = s.redact(err.Error())
+	}
+	runtime.EventsEmit(ctx, name, payload)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsUnreachableError(t *testing.T) {
+	requestURL := "https://bucket.oss-cn-hangzhou.aliyuncs.com/key"
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dns", &url.Error{Op: "Get", URL: requestURL, Err: &net.DNSError{Err: "no such host", Name: "oss-cn-hangzhou.aliyuncs.com", IsNotFound: true}}, true},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, false},
+		{"refused", &url.Error{Op: "Get", URL: requestURL, Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, true},
+		{"unreachable", fmt.Errorf("list: %w", syscall.ENETUNREACH), true},
+		{"dial timeout", &url.Error{Op: "Get", URL: requestURL, Err: &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}}, false},
+		{"read timeout", &url.Error{Op: "Get", URL: requestURL, Err: timeoutError{}}, false},
+		{"ossutil refused", errors.New("Error: dial tcp 1.2.3.4:443: connect: connection refused"), true},
+		{"ossutil no such host", errors.New("dial tcp: lookup oss-cn-hangzhou.aliyuncs.com: no such host"), true},
+		{"ossutil timeout", errors.New("dial tcp 1.2.3.4:443: i/o timeout"), false},
+		{"service error", oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}, false},
+		{"offline", ErrOffline, false},
+		{"reset", syscall.ECONNRESET, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isUnreachableError(tt.err); got != tt.want {
+				t.Fatalf("isUnreachableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutDoesNotGoOffline(t *testing.T) {
+	s := newTestService(t)
+	addr := s.rememberProbeTarget(OSSConfig{}, "https://oss-cn-hangzhou.aliyuncs.com")
+	err := &url.Error{Op: "Get", URL: "https://bucket.oss-cn-hangzhou.aliyuncs.com/", Err: timeoutError{}}
+	if s.noteNetworkFailure(err) {
+		t.Fatal("a timeout was treated as unreachable")
+	}
+	if err := s.requireReachable(addr); err != nil {
+		t.Fatalf("requireReachable after a timeout: %v", err)
+	}
+}
+
+func TestReachabilityIsPerEndpoint(t *testing.T) {
+	s := newTestService(t)
+	// Port 1 on localhost refuses connections, so the probe keeps the endpoint offline.
+	down := s.rememberProbeTarget(OSSConfig{}, "https://127.0.0.1:1")
+	up := s.rememberProbeTarget(OSSConfig{}, "https://oss-cn-beijing.aliyuncs.com")
+
+	err := &url.Error{Op: "Get", URL: "https://127.0.0.1:1/bucket", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	if !s.noteNetworkFailure(err) {
+		t.Fatal("a refused connection was not treated as unreachable")
+	}
+	if err := s.requireReachable(down); !errors.Is(err, ErrOffline) {
+		t.Fatalf("requireReachable(%s) = %v, want ErrOffline", down, err)
+	}
+	if err := s.requireReachable(up); err != nil {
+		t.Fatalf("an unrelated endpoint went offline: %v", err)
+	}
+	select {
+	case <-s.onlineSignal(down):
+		t.Fatal("onlineSignal of an offline endpoint is closed")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestProbeAddrForErrorMatchesBucketSubdomain(t *testing.T) {
+	s := newTestService(t)
+	hangzhou := s.rememberProbeTarget(OSSConfig{}, "https://oss-cn-hangzhou.aliyuncs.com")
+	s.rememberProbeTarget(OSSConfig{}, "http://minio.local:9000")
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://my-bucket.oss-cn-hangzhou.aliyuncs.com/a/b?x=1", hangzhou},
+		{"https://oss-cn-hangzhou.aliyuncs.com/", hangzhou},
+		{"http://minio.local:9000/bucket", "minio.local:9000"},
+		{"https://oss-cn-shanghai.aliyuncs.com/", ""},
+	}
+	for _, tt := range tests {
+		err := &url.Error{Op: "Get", URL: tt.url, Err: syscall.ECONNREFUSED}
+		if got := s.probeAddrForError(err); got != tt.want {
+			t.Errorf("probeAddrForError(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestProbeUntilOnlineStopsWithContext(t *testing.T) {
+	s := newTestService(t)
+	// Port 1 on localhost refuses connections, so only the context ends the probe.
+	const down = "127.0.0.1:1"
+	s.network.offline[down] = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		s.probeUntilOnline(ctx, down)
+		close(stopped)
+	}()
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("probeUntilOnline kept running after its context ended")
+	}
+	if err := s.requireReachable(down); !errors.Is(err, ErrOffline) {
+		t.Fatalf("requireReachable after the probe gave up = %v, want ErrOffline", err)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	credentialProviderStatic     = "static"
+	credentialProviderEnv        = "env"
+	credentialProviderECSRAMRole = "ecs-ram-role"
+)
+
+const (
+	envOSSAccessKeyID        = "OSS_ACCESS_KEY_ID"
+	envOSSAccessKeySecret    = "OSS_ACCESS_KEY_SECRET"
+	envOSSSessionToken       = "OSS_SESSION_TOKEN"
+	envAliyunAccessKeyID     = "ALIBABA_CLOUD_ACCESS_KEY_ID"
+	envAliyunAccessKeySecret = "ALIBABA_CLOUD_ACCESS_KEY_SECRET"
+	envAliyunSecurityToken   = "ALIBABA_CLOUD_SECURITY_TOKEN"
+)
+
+const (
+	ecsMetadataBaseURL    = "http://100.100.100.200"
+	ecsMetadataCredsPath  = "/latest/meta-data/ram/security-credentials/"
+	ecsMetadataTokenPath  = "/latest/api/token"
+	ecsMetadataTokenTTL   = "21600"
+	ecsMetadataTimeout    = 2 * time.Second
+	ecsCredsRefreshWindow = 5 * time.Minute
+)
+
+func normalizeCredentialProvider(provider string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "", credentialProviderStatic:
+		return credentialProviderStatic, nil
+	case credentialProviderEnv:
+		return credentialProviderEnv, nil
+	case credentialProviderECSRAMRole:
+		return credentialProviderECSRAMRole, nil
+	default:
+		return "", fmt.Errorf("unknown credential provider %q (expected static, env or ecs-ram-role)", provider)
+	}
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// envCredentials reads credentials from the environment, preferring the OSS_* names used by ossutil.
+func envCredentials(config OSSConfig) (OSSConfig, error) {
+	id := firstEnv(envOSSAccessKeyID, envAliyunAccessKeyID)
+	secret := firstEnv(envOSSAccessKeySecret, envAliyunAccessKeySecret)
+	if id == "" || secret == "" {
+		return OSSConfig{}, fmt.Errorf("environment credentials not found: set %s and %s", envOSSAccessKeyID, envOSSAccessKeySecret)
+	}
+	config.AccessKeyID = id
+	config.AccessKeySecret = secret
+	config.SecurityToken = firstEnv(envOSSSessionToken, envAliyunSecurityToken)
+	return config, nil
+}
+
+type ecsRoleCredentialsResponse struct {
+	Code            string `json:"Code"`
+	AccessKeyID     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+func ecsMetadataGet(client *http.Client, path string, token string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, ecsMetadataBaseURL+path, nil)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		req.Header.Set("X-aliyun-ecs-metadata-token", token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata service returned HTTP %d for %s", resp.StatusCode, path)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// ecsMetadataToken asks for a hardened-mode session token; an empty token means the instance only
+// supports the plain mode.
+func ecsMetadataToken(client *http.Client) string {
+	req, err := http.NewRequest(http.MethodPut, ecsMetadataBaseURL+ecsMetadataTokenPath, nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("X-aliyun-ecs-metadata-token-ttl-seconds", ecsMetadataTokenTTL)
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// fetchECSRoleCredentials queries the instance metadata service for the instance RAM role.
+func fetchECSRoleCredentials(roleName string) (stsCredentials, error) {
+	client := &http.Client{Timeout: ecsMetadataTimeout}
+	token := ecsMetadataToken(client)
+
+	if roleName == "" {
+		listing, err := ecsMetadataGet(client, ecsMetadataCredsPath, token)
+		if err != nil {
+			return stsCredentials{}, fmt.Errorf("failed to discover ECS RAM role (is this an ECS instance with a role attached?): %w", err)
+		}
+		roleName = strings.TrimSpace(strings.SplitN(listing, "\n", 2)[0])
+		if roleName == "" {
+			return stsCredentials{}, fmt.Errorf("no RAM role is attached to this ECS instance")
+		}
+	}
+
+	body, err := ecsMetadataGet(client, ecsMetadataCredsPath+roleName, token)
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("failed to fetch credentials of ECS RAM role %s: %w", roleName, err)
+	}
+
+	var parsed ecsRoleCredentialsResponse
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return stsCredentials{}, fmt.Errorf("invalid ECS RAM role credentials: %w", err)
+	}
+	if parsed.Code != "" && parsed.Code != "Success" {
+		return stsCredentials{}, fmt.Errorf("ECS RAM role %s credentials unavailable: %s", roleName, parsed.Code)
+	}
+	expiration, err := time.Parse(time.RFC3339, parsed.Expiration)
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("invalid credential expiration %q: %w", parsed.Expiration, err)
+	}
+	return stsCredentials{
+		AccessKeyID:     parsed.AccessKeyID,
+		AccessKeySecret: parsed.AccessKeySecret,
+		SecurityToken:   parsed.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+func (s *OSSService) ecsRoleCredentials(config OSSConfig) (OSSConfig, error) {
+	roleName := strings.TrimSpace(config.ECSRoleName)
+
+	s.ecsRoleCacheMu.Lock()
+	defer s.ecsRoleCacheMu.Unlock()
+
+	creds, ok := s.ecsRoleCache[roleName]
+	if !ok || time.Until(creds.Expiration) < ecsCredsRefreshWindow {
+		fresh, err := fetchECSRoleCredentials(roleName)
+		if err != nil {
+			delete(s.ecsRoleCache, roleName)
+			return OSSConfig{}, err
+		}
+		creds = fresh
+		s.ecsRoleCache[roleName] = creds
+	}
+
+	config.AccessKeyID = creds.AccessKeyID
+	config.AccessKeySecret = creds.AccessKeySecret
+	config.SecurityToken = creds.SecurityToken
+	return config, nil
+}
+
+// materializeProviderCredentials fills the AccessKey fields of config from its credential provider
+// and reports which provider supplied them.
+func (s *OSSService) materializeProviderCredentials(config OSSConfig) (OSSConfig, string, error) {
+	provider, err := normalizeCredentialProvider(config.Provider)
+	if err != nil {
+		return OSSConfig{}, "", err
+	}
+
+	switch provider {
+	case credentialProviderEnv:
+		resolved, err := envCredentials(config)
+		return resolved, provider, err
+	case credentialProviderECSRAMRole:
+		resolved, err := s.ecsRoleCredentials(config)
+		return resolved, provider, err
+	default:
+		return config, provider, nil
+	}
+}
+
+func describeCredentialSource(source string) string {
+	parts := strings.Split(source, "+")
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		switch part {
+		case credentialProviderStatic:
+			names = append(names, "profile AccessKey")
+		case credentialProviderEnv:
+			names = append(names, "environment variables")
+		case credentialProviderECSRAMRole:
+			names = append(names, "ECS instance RAM role")
+		case credentialSourceAssumeRole:
+			names = append(names, "assumed RAM role")
+		default:
+			names = append(names, part)
+		}
+	}
+	return strings.Join(names, " → ")
+}
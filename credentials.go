@@ -0,0 +1,249 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	stsEndpoint               = "sts.aliyuncs.com"
+	stsAPIVersion             = "2015-04-01"
+	assumeRoleDurationSeconds = 3600
+	// assumeRoleRefreshWindow is how long before expiry cached role credentials are renewed.
+	assumeRoleRefreshWindow = 5 * time.Minute
+	defaultRoleSessionName  = "walioss"
+	stsRequestTimeout       = 15 * time.Second
+)
+
+// stsCredentials are temporary credentials returned by AssumeRole. They are kept in memory only.
+type stsCredentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+type assumeRoleResponse struct {
+	RequestID   string `json:"RequestId"`
+	Code        string `json:"Code"`
+	Message     string `json:"Message"`
+	Credentials struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		AccessKeySecret string `json:"AccessKeySecret"`
+		SecurityToken   string `json:"SecurityToken"`
+		Expiration      string `json:"Expiration"`
+	} `json:"Credentials"`
+}
+
+// stsPercentEncode is the RFC 3986 encoding required by the Aliyun RPC signature.
+func stsPercentEncode(value string) string {
+	encoded := url.QueryEscape(value)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	encoded = strings.ReplaceAll(encoded, "%7E", "~")
+	return encoded
+}
+
+func stsNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// signedSTSQuery builds the signed query string of an STS RPC request.
+func signedSTSQuery(params map[string]string, accessKeySecret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, stsPercentEncode(key)+"="+stsPercentEncode(params[key]))
+	}
+	canonical := strings.Join(pairs, "&")
+
+	stringToSign := "GET&" + stsPercentEncode("/") + "&" + stsPercentEncode(canonical)
+	mac := hmac.New(sha1.New, []byte(accessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return canonical + "&Signature=" + stsPercentEncode(signature)
+}
+
+// callAssumeRole calls STS AssumeRole with the given source credentials.
+func callAssumeRole(source OSSConfig, roleArn string, sessionName string) (stsCredentials, error) {
+	nonce, err := stsNonce()
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	params := map[string]string{
+		"Action":           "AssumeRole",
+		"Version":          stsAPIVersion,
+		"Format":           "JSON",
+		"RoleArn":          roleArn,
+		"RoleSessionName":  sessionName,
+		"DurationSeconds":  fmt.Sprintf("%d", assumeRoleDurationSeconds),
+		"AccessKeyId":      strings.TrimSpace(source.AccessKeyID),
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   nonce,
+		"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	if source.SecurityToken != "" {
+		params["SecurityToken"] = source.SecurityToken
+	}
+
+	requestURL := "https://" + stsEndpoint + "/?" + signedSTSQuery(params, source.AccessKeySecret)
+	client := &http.Client{Timeout: stsRequestTimeout}
+	resp, err := client.Get(requestURL)
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("assume role request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("failed to read assume role response: %w", err)
+	}
+
+	var parsed assumeRoleResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return stsCredentials{}, fmt.Errorf("invalid assume role response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || parsed.Code != "" {
+		return stsCredentials{}, fmt.Errorf("assume role failed: %s: %s (RequestId: %s)", parsed.Code, parsed.Message, parsed.RequestID)
+	}
+
+	expiration, err := time.Parse(time.RFC3339, parsed.Credentials.Expiration)
+	if err != nil {
+		return stsCredentials{}, fmt.Errorf("invalid credential expiration %q: %w", parsed.Credentials.Expiration, err)
+	}
+	return stsCredentials{
+		AccessKeyID:     parsed.Credentials.AccessKeyID,
+		AccessKeySecret: parsed.Credentials.AccessKeySecret,
+		SecurityToken:   parsed.Credentials.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+// roleSourceConfig returns the long-lived credentials used to assume the role of config.
+func (s *OSSService) roleSourceConfig(config OSSConfig) (OSSConfig, error) {
+	sourceName := strings.TrimSpace(config.SourceProfile)
+	if sourceName == "" {
+		if strings.TrimSpace(config.AccessKeyID) == "" || config.AccessKeySecret == "" {
+			return OSSConfig{}, fmt.Errorf("role %s needs source credentials: set a source profile or an AccessKey", config.RoleArn)
+		}
+		s.rememberSecrets(config)
+		return config, nil
+	}
+
+	source, err := s.GetProfile(sourceName)
+	if err != nil {
+		return OSSConfig{}, fmt.Errorf("failed to load source profile %s: %w", sourceName, err)
+	}
+	if strings.TrimSpace(source.Config.RoleArn) != "" {
+		return OSSConfig{}, fmt.Errorf("source profile %s must use an AccessKey, not another role", sourceName)
+	}
+	resolved, _, err := s.materializeProviderCredentials(source.Config)
+	if err != nil {
+		return OSSConfig{}, fmt.Errorf("source profile %s: %w", sourceName, err)
+	}
+	s.rememberSecrets(resolved)
+	return resolved, nil
+}
+
+func assumeRoleCacheKey(source OSSConfig, roleArn string, sessionName string) string {
+	return strings.Join([]string{strings.TrimSpace(source.AccessKeyID), roleArn, sessionName}, "\x1f")
+}
+
+const credentialSourceAssumeRole = "assume-role"
+
+// resolveCredentials turns a config into one that can be handed to the SDK or ossutil.
+func (s *OSSService) resolveCredentials(config OSSConfig) (OSSConfig, error) {
+	resolved, _, err := s.resolveCredentialsWithSource(config)
+	return resolved, err
+}
+
+// resolveCredentialsWithSource materializes credentials from the config's provider, then, for role
+// profiles, swaps them for cached STS credentials that are renewed shortly before they expire. The
+// returned source names the chain that supplied the credentials, e.g. "env+assume-role". Both the
+// input and the resolved secrets are registered for redaction.
+func (s *OSSService) resolveCredentialsWithSource(config OSSConfig) (OSSConfig, string, error) {
+	s.rememberSecrets(config)
+	resolved, source, err := s.resolveCredentialChain(config)
+	if err == nil {
+		s.rememberSecrets(resolved)
+	}
+	return resolved, source, err
+}
+
+func (s *OSSService) resolveCredentialChain(config OSSConfig) (OSSConfig, string, error) {
+	config, source, err := s.materializeProviderCredentials(config)
+	if err != nil {
+		return OSSConfig{}, source, err
+	}
+
+	roleArn := strings.TrimSpace(config.RoleArn)
+	if roleArn == "" {
+		return config, source, nil
+	}
+
+	sessionName := strings.TrimSpace(config.RoleSessionName)
+	if sessionName == "" {
+		sessionName = defaultRoleSessionName
+	}
+
+	roleSource, err := s.roleSourceConfig(config)
+	if err != nil {
+		return OSSConfig{}, source, err
+	}
+	if strings.TrimSpace(config.SourceProfile) != "" {
+		source = credentialProviderStatic
+	}
+	source += "+" + credentialSourceAssumeRole
+
+	cacheKey := assumeRoleCacheKey(roleSource, roleArn, sessionName)
+
+	s.assumeRoleCacheMu.Lock()
+	defer s.assumeRoleCacheMu.Unlock()
+
+	creds, ok := s.assumeRoleCache[cacheKey]
+	if !ok || time.Until(creds.Expiration) < assumeRoleRefreshWindow {
+		fresh, err := callAssumeRole(roleSource, roleArn, sessionName)
+		if err != nil {
+			delete(s.assumeRoleCache, cacheKey)
+			if ok {
+				return OSSConfig{}, source, fmt.Errorf("credentials expired, re-assume failed: %w", err)
+			}
+			return OSSConfig{}, source, err
+		}
+		creds = fresh
+		s.assumeRoleCache[cacheKey] = creds
+	}
+
+	resolved := config
+	resolved.Provider = credentialProviderStatic
+	resolved.AccessKeyID = creds.AccessKeyID
+	resolved.AccessKeySecret = creds.AccessKeySecret
+	resolved.SecurityToken = creds.SecurityToken
+	resolved.RoleArn = ""
+	resolved.RoleSessionName = ""
+	resolved.SourceProfile = ""
+	return resolved, source, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Downloads need this much room to spare on top of their size, for ossutil's checkpoint files and
+// for everything else on the disk that keeps writing meanwhile.
+const diskSpaceSafetyMargin = 100 << 20
+
+// InsufficientDiskSpaceError is returned when a download does not fit on its destination disk.
+type InsufficientDiskSpaceError struct {
+	Path      string
+	NeedBytes int64
+	HaveBytes int64
+}
+
+func (e *InsufficientDiskSpaceError) Error() string {
+	return fmt.Sprintf("insufficient disk space: need %s, have %s on %s", formatByteSize(e.NeedBytes), formatByteSize(e.HaveBytes), e.Path)
+}
+
+func formatByteSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// existingAncestor returns path or its nearest parent that exists, since a download's directory
+// is often only created when the transfer starts.
+func existingAncestor(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// checkDiskSpace fails when totalBytes plus the safety margin does not fit on the filesystem
+// holding dir. The check is skipped when the settings turn it off or free space cannot be read.
+func (s *OSSService) checkDiskSpace(dir string, totalBytes int64) error {
+	if totalBytes <= 0 {
+		return nil
+	}
+	s.diskSpaceCheckMu.RLock()
+	skip := s.skipDiskSpaceCheck
+	s.diskSpaceCheckMu.RUnlock()
+	if skip {
+		return nil
+	}
+
+	probe := existingAncestor(dir)
+	free, err := s.diskFree(probe)
+	if err != nil {
+		s.logOperationEvent(logLevelWarn, "checkDiskSpace", opOutcomeError, probe, err)
+		return nil
+	}
+	need := totalBytes + diskSpaceSafetyMargin
+	if uint64(need) > free {
+		return &InsufficientDiskSpaceError{Path: probe, NeedBytes: need, HaveBytes: int64(free)}
+	}
+	return nil
+}
+
+func (s *OSSService) setSkipDiskSpaceCheck(skip bool) {
+	s.diskSpaceCheckMu.Lock()
+	s.skipDiskSpaceCheck = skip
+	s.diskSpaceCheckMu.Unlock()
+}
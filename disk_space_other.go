@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+func diskFreeBytes(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	// Bavail is what an unprivileged process may use, excluding blocks reserved for root.
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// fakeDiskFree makes s see free bytes on every disk and records the probed paths.
+func fakeDiskFree(s *OSSService, free uint64, err error) *[]string {
+	var probed []string
+	s.diskFree = func(path string) (uint64, error) {
+		probed = append(probed, path)
+		return free, err
+	}
+	return &probed
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		free       uint64
+		probeErr   error
+		totalBytes int64
+		skip       bool
+		wantErr    bool
+	}{
+		{name: "fits", free: 1 << 30, totalBytes: 10 << 20},
+		{name: "fits exactly with the margin", free: 10<<20 + diskSpaceSafetyMargin, totalBytes: 10 << 20},
+		{name: "eaten by the margin", free: 10<<20 + diskSpaceSafetyMargin - 1, totalBytes: 10 << 20, wantErr: true},
+		{name: "too big", free: 1 << 20, totalBytes: 1 << 30, wantErr: true},
+		{name: "unknown size", free: 0, totalBytes: 0},
+		{name: "check turned off", free: 1 << 20, totalBytes: 1 << 30, skip: true},
+		{name: "free space unreadable", probeErr: errors.New("statfs failed"), totalBytes: 1 << 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			fakeDiskFree(s, tt.free, tt.probeErr)
+			s.setSkipDiskSpaceCheck(tt.skip)
+
+			err := s.checkDiskSpace(dir, tt.totalBytes)
+			var spaceErr *InsufficientDiskSpaceError
+			if got := errors.As(err, &spaceErr); got != tt.wantErr {
+				t.Fatalf("checkDiskSpace = %v, want an InsufficientDiskSpaceError: %v", err, tt.wantErr)
+			}
+			if tt.wantErr && (spaceErr.NeedBytes != tt.totalBytes+diskSpaceSafetyMargin || spaceErr.HaveBytes != int64(tt.free) || spaceErr.Path != dir) {
+				t.Errorf("error = %+v", spaceErr)
+			}
+		})
+	}
+}
+
+func TestCheckDiskSpaceProbesExistingAncestor(t *testing.T) {
+	s := newTestService(t)
+	probed := fakeDiskFree(s, 1<<30, nil)
+	root := t.TempDir()
+
+	if err := s.checkDiskSpace(filepath.Join(root, "not", "created", "yet"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if len(*probed) != 1 || (*probed)[0] != root {
+		t.Errorf("probed %v, want %s", *probed, root)
+	}
+}
+
+func TestEnqueueDownloadRefusedWhenDiskIsFull(t *testing.T) {
+	s := newTestService(t)
+	fakeDiskFree(s, 1<<20, nil)
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+
+	_, err := s.EnqueueDownload(config, "bucket", "big.iso", filepath.Join(t.TempDir(), "big.iso"), 4<<30)
+	var spaceErr *InsufficientDiskSpaceError
+	if !errors.As(err, &spaceErr) {
+		t.Fatalf("EnqueueDownload = %v, want an InsufficientDiskSpaceError", err)
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := map[int64]string{
+		0:              "0 B",
+		1023:           "1023 B",
+		1024:           "1.0 KiB",
+		1536:           "1.5 KiB",
+		100 << 20:      "100.0 MiB",
+		5 << 30:        "5.0 GiB",
+		3 << 40:        "3.0 TiB",
+		int64(1) << 62: "4.0 EiB",
+	}
+	for bytes, want := range tests {
+		if got := formatByteSize(bytes); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
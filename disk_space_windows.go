@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+func diskFreeBytes(path string) (uint64, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var freeToCaller, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeToCaller, &total, &totalFree); err != nil {
+		return 0, err
+	}
+	return freeToCaller, nil
+}
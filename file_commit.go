@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pendingFile is one file of a multi-file write.
+type pendingFile struct {
+	path string
+	data []byte
+}
+
+// writeFilesTogether writes every file to a temp sibling first and only then renames them into
+// place. If a rename fails, files already replaced are restored from their previous contents, so
+// readers see either the old set or the new set.
+func writeFilesTogether(files []pendingFile) error {
+	temps := make([]string, 0, len(files))
+	cleanup := func() {
+		for _, tmp := range temps {
+			_ = os.Remove(tmp)
+		}
+	}
+
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.path), 0700); err != nil {
+			cleanup()
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(file.path), "."+filepath.Base(file.path)+".tmp-*")
+		if err != nil {
+			cleanup()
+			return err
+		}
+		temps = append(temps, tmp.Name())
+		if _, err := tmp.Write(file.data); err != nil {
+			tmp.Close()
+			cleanup()
+			return err
+		}
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			cleanup()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			cleanup()
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0600); err != nil {
+			cleanup()
+			return err
+		}
+	}
+
+	type previous struct {
+		path   string
+		data   []byte
+		exists bool
+	}
+	replaced := make([]previous, 0, len(files))
+	for i, file := range files {
+		prev := previous{path: file.path}
+		if data, err := os.ReadFile(file.path); err == nil {
+			prev.data = data
+			prev.exists = true
+		}
+
+		if err := os.Rename(temps[i], file.path); err != nil {
+			for j := len(replaced) - 1; j >= 0; j-- {
+				if replaced[j].exists {
+					_ = os.WriteFile(replaced[j].path, replaced[j].data, 0600)
+				} else {
+					_ = os.Remove(replaced[j].path)
+				}
+			}
+			cleanup()
+			return fmt.Errorf("failed to replace %s: %w", file.path, err)
+		}
+		replaced = append(replaced, prev)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces path via a temp file and rename, so a crash never leaves it half written.
+func writeFileAtomic(path string, data []byte) error {
+	return writeFilesTogether([]pendingFile{{path: path, data: data}})
+}
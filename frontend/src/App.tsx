@@ -7,11 +7,12 @@ import AboutModal from './components/AboutModal';
 import FileBrowser from './components/FileBrowser';
 import TransferModal from './components/TransferModal';
 import { main } from '../wailsjs/go/models';
-import { CheckOssutilInstalled, GetSettings, GetTransferHistory, MoveObject } from '../wailsjs/go/main/OSSService';
-import { GetAppInfo, OpenFile, OpenInFinder } from '../wailsjs/go/main/App';
+import { CancelMoveJob, CancelOperation, CancelQueuedTransfer, CheckOssutilInstalled, ExportIntegrityReportCSV, GetConfigRecoveries, GetEffectiveTheme, GetSessionState, GetSettings, GetTransferHistory, MoveObject, SaveSessionState, UndoLastOperation } from '../wailsjs/go/main/OSSService';
+import { GetAppInfo, OpenFile, OpenInFinder, SelectSaveFile, TakeLaunchRequest } from '../wailsjs/go/main/App';
 import { EventsEmit, EventsOn, OnFileDrop, OnFileDropOff } from '../wailsjs/runtime/runtime';
 import { canReadOssDragPayload, readOssDragPayload } from './ossDrag';
 import { enqueueUploadWithRenamePrompt } from './upload';
+import { lastSegment, parentPrefix, segmentLabel } from './ossPath';
 
 type GlobalView = 'session' | 'settings';
 
@@ -53,7 +54,8 @@ type TransferItem = {
 };
 
 type ToastType = 'success' | 'error' | 'info';
-type Toast = { id: number; type: ToastType; message: string };
+type ToastAction = { label: string; onClick: () => void };
+type Toast = { id: number; type: ToastType; message: string; action?: ToastAction };
 
 type AppInfo = {
   name: string;
@@ -230,18 +232,30 @@ function App() {
     }
   }, [activeTabId, tabs]);
 
-  const handleThemeChange = (newTheme: string) => {
-    setTheme(newTheme);
-    const themeClass = newTheme === 'light' ? 'theme-light' : 'theme-dark';
+  const applyThemeClass = (effectiveTheme: string) => {
+    const themeClass = effectiveTheme === 'light' ? 'theme-light' : 'theme-dark';
     document.body.classList.remove('theme-light', 'theme-dark');
     document.body.classList.add(themeClass);
   };
 
+  const handleThemeChange = (newTheme: string) => {
+    setTheme(newTheme);
+    if (newTheme !== 'system') {
+      applyThemeClass(newTheme);
+      return;
+    }
+    // Preview with the webview's view of the OS until the backend reports the effective theme.
+    applyThemeClass(window.matchMedia?.('(prefers-color-scheme: light)').matches ? 'light' : 'dark');
+  };
+
   useEffect(() => {
     const applySavedTheme = async () => {
       try {
         const settings = await GetSettings();
         handleThemeChange(settings?.theme || 'dark');
+        if (settings?.theme === 'system') {
+          applyThemeClass(await GetEffectiveTheme());
+        }
         setNewTabNameRule(settings?.newTabNameRule === 'newTab' ? 'newTab' : 'folder');
         setFileListViewMode(settings?.fileListViewMode === 'classic' ? 'classic' : 'finder');
       } catch {
@@ -265,12 +279,7 @@ function App() {
     void applySavedTheme();
   }, []);
 
-  const folderNameFromPrefix = (prefix: string) => {
-    const trimmed = (prefix || '').replace(/\/+$/, '');
-    if (!trimmed) return '';
-    const parts = trimmed.split('/').filter(Boolean);
-    return parts[parts.length - 1] || '';
-  };
+  const folderNameFromPrefix = (prefix: string) => (prefix ? segmentLabel(lastSegment(prefix)) : '');
 
   const autoTitleFromLocation = (bucket: string, prefix: string) => {
     if (!bucket) return 'Buckets';
@@ -284,7 +293,7 @@ function App() {
     if (p.startsWith('oss://')) p = p.slice(6);
     p = p.replace(/^\/+/, '');
     if (!p) return { bucket: '', prefix: '' };
-    const parts = p.split('/').filter(Boolean);
+    const parts = p.split('/');
     const bucket = parts[0] || '';
     const prefixPart = parts.slice(1).join('/');
     const prefix = prefixPart ? (prefixPart.endsWith('/') ? prefixPart : `${prefixPart}/`) : '';
@@ -293,15 +302,16 @@ function App() {
 
   const normalizeBucketName = (bucket: string) => bucket.trim().replace(/^\/+/, '').replace(/\/+$/, '');
 
+  // Prefixes are kept byte for byte (see ossPath.ts); only a trailing slash is added.
   const normalizePrefix = (prefix: string) => {
-    let p = (prefix || '').trim().replace(/^\/+/, '');
+    let p = prefix || '';
     if (!p) return '';
     if (!p.endsWith('/')) p += '/';
     return p;
   };
 
   const parseOssObjectPath = (path: string | undefined | null) => {
-    let p = (path || '').trim();
+    let p = (path || '').trimStart();
     if (!p.startsWith('oss://')) return null;
     p = p.slice(6);
     p = p.replace(/^\/+/, '');
@@ -437,9 +447,9 @@ function App() {
     };
   }, [activeTransferProfile, sessionConfig, toTransferItem]);
 
-  const showToast = useCallback((type: ToastType, message: string, timeoutMs = 2600) => {
+  const showToast = useCallback((type: ToastType, message: string, timeoutMs = 2600, action?: ToastAction) => {
     const id = Date.now();
-    setToast({ id, type, message });
+    setToast({ id, type, message, action });
     if (toastTimerRef.current) {
       window.clearTimeout(toastTimerRef.current);
     }
@@ -448,6 +458,27 @@ function App() {
     }, timeoutMs);
   }, []);
 
+  const undoLastMove = useCallback(async () => {
+    try {
+      const result = await UndoLastOperation();
+      if (!result.undone) {
+        const keys = (result.conflicts || []).slice(0, 5).map((c) => `${c.key}: ${c.reason}`).join('\n');
+        showToast('error', [result.message, keys].filter(Boolean).join('\n'), 8000);
+        return;
+      }
+      EventsEmit(
+        'objects:changed',
+        { bucket: result.srcBucket, prefix: parentPrefix(result.srcPrefix || '') },
+        { bucket: result.destBucket, prefix: parentPrefix(result.destPrefix || '') },
+      );
+      showToast('success', result.message || 'Move undone');
+    } catch (err: any) {
+      showToast('error', err?.message || 'Undo failed', 6000);
+    }
+  }, [showToast]);
+
+  const undoAction: ToastAction = { label: 'Undo', onClick: () => void undoLastMove() };
+
   useEffect(() => {
     return () => {
       if (toastTimerRef.current) {
@@ -526,6 +557,132 @@ function App() {
     return () => off();
   }, [activeTransferProfile, sessionConfig, toTransferItem]);
 
+  useEffect(() => {
+    const off = EventsOn('theme:changed', (payload: any) => {
+      if (payload?.theme) {
+        applyThemeClass(payload.theme);
+      }
+    });
+    return () => off();
+  }, []);
+
+  useEffect(() => {
+    // Folder moves run in the background; MoveObject only returns their job ID.
+    const off = EventsOn('move:update', (payload: any) => {
+      if (!payload?.id) return;
+      const source = { bucket: payload.srcBucket, prefix: parentPrefix(payload.srcPrefix || '') };
+      const destination = { bucket: payload.destBucket, prefix: parentPrefix(payload.destPrefix || '') };
+      if (payload.status === 'running') {
+        const moved = `${payload.copied || 0} objects, ${formatBytesCompact(payload.bytes || 0)}`;
+        showToast('info', `Moving ${payload.srcPrefix}: ${moved}`, 60000, {
+          label: 'Cancel',
+          onClick: () => {
+            CancelMoveJob(payload.id).catch(() => {
+              // The move finished meanwhile; its final update reports the outcome.
+            });
+          },
+        });
+        return;
+      }
+      EventsEmit('objects:changed', source, destination);
+      const failed = payload.status === 'error' || (payload.deleteFailed?.length || 0) > 0;
+      const undoable = (payload.copied || 0) > 0;
+      showToast(failed ? 'error' : 'success', payload.message || 'Move finished', failed ? 6000 : 5000, undoable ? undoAction : undefined);
+    });
+    return () => off();
+  }, [showToast, undoLastMove]);
+
+  useEffect(() => {
+    const off = EventsOn('restore:update', (payload: any) => {
+      if (!payload?.id) return;
+      if (payload.status === 'running') {
+        showToast('info', `Restoring ${payload.prefix}: ${payload.requested || 0} requested, ${payload.skipped || 0} skipped`, 60000, {
+          label: 'Cancel',
+          onClick: () => {
+            CancelOperation(payload.id).catch(() => {
+              // The restore finished meanwhile; its final update reports the outcome.
+            });
+          },
+        });
+        return;
+      }
+      const failed = payload.status === 'error' || (payload.failed || 0) > 0;
+      showToast(failed ? 'error' : 'success', payload.message || 'Restore finished', failed ? 6000 : 5000);
+    });
+    return () => off();
+  }, [showToast]);
+
+  useEffect(() => {
+    const off = EventsOn('scan:update', (payload: any) => {
+      if (!payload?.id) return;
+      if (payload.status === 'running') {
+        showToast('info', `Scanning ${payload.prefix}: ${payload.scanned || 0} objects`, 60000, {
+          label: 'Cancel',
+          onClick: () => {
+            CancelOperation(payload.id).catch(() => {
+              // The scan finished meanwhile; its final update reports the outcome.
+            });
+          },
+        });
+        return;
+      }
+      showToast(payload.status === 'error' ? 'error' : 'success', payload.message || 'Scan finished', 10000, {
+        label: 'Export CSV',
+        onClick: async () => {
+          const path = await SelectSaveFile('integrity-report.csv');
+          if (!path) return;
+          ExportIntegrityReportCSV(payload.id, path).catch((err: any) => showToast('error', err?.message || 'Export failed'));
+        },
+      });
+    });
+    return () => off();
+  }, [showToast]);
+
+  useEffect(() => {
+    const offOffline = EventsOn('network:offline', (payload: { endpoint?: string }) => {
+      const target = payload?.endpoint || 'OSS';
+      showToast('error', `Cannot reach ${target}. Transfers to it will wait until it is back.`, 5000);
+    });
+    const offOnline = EventsOn('network:online', (payload: { endpoint?: string }) => {
+      showToast('success', payload?.endpoint ? `${payload.endpoint} is reachable again` : 'Back online');
+    });
+    return () => {
+      offOffline();
+      offOnline();
+    };
+  }, [showToast]);
+
+  useEffect(() => {
+    // Files damaged before the window opened were recovered during startup; show those too.
+    const showRecovery = (recovery: main.ConfigRecovery) => showToast('error', recovery.message, 12000);
+    GetConfigRecoveries()
+      .then((recoveries) => (recoveries || []).forEach(showRecovery))
+      .catch(() => {});
+    const off = EventsOn('config:recovered', (payload: main.ConfigRecovery) => {
+      if (payload?.message) showRecovery(payload);
+    });
+    return () => off();
+  }, [showToast]);
+
+  useEffect(() => {
+    const off = EventsOn('config:changed', (change: any) => {
+      if (change?.error) {
+        showToast('error', change.error, 8000);
+        return;
+      }
+      if (!change?.settings && !change?.profiles) return;
+      showToast('info', `Reloaded ${(change.files || []).join(', ')} after an outside change`);
+      if (!change.settings) return;
+      GetSettings()
+        .then((settings) => {
+          setNewTabNameRule(settings?.newTabNameRule === 'newTab' ? 'newTab' : 'folder');
+          setFileListViewMode(settings?.fileListViewMode === 'classic' ? 'classic' : 'finder');
+        })
+        .catch(() => {});
+    });
+    return () => off();
+  }, [showToast]);
+
   useEffect(() => {
     const off = EventsOn('app:about', () => {
       openAbout();
@@ -564,6 +721,96 @@ function App() {
     return () => OnFileDropOff();
   }, [activeTab?.bucket, activeTab?.prefix, sessionConfig, showToast]);
 
+  // Paths handed over on the command line (or by a second launch) wait here until a session is open.
+  const [pendingLaunch, setPendingLaunch] = useState<main.LaunchRequest | null>(null);
+
+  const openLaunchRequest = useCallback(
+    (request: main.LaunchRequest) => {
+      for (const ossPath of request.ossPaths || []) {
+        const trimmed = ossPath.replace(/^oss:\/\//, '');
+        // Object paths open their parent folder.
+        const folderPath = trimmed.endsWith('/') || !trimmed.includes('/') ? trimmed : trimmed.slice(0, trimmed.lastIndexOf('/') + 1);
+        const loc = parseOssPathLocation(folderPath);
+        if (!loc.bucket) continue;
+        const id = `t${nextTabNumber.current++}`;
+        const title = defaultTabTitleForRule(newTabNameRule, loc.bucket, loc.prefix);
+        setTabs((prev) => [...prev, { id, title, isCustomTitle: false, bucket: loc.bucket, prefix: loc.prefix }]);
+        setActiveTabId(id);
+        setGlobalView('session');
+      }
+
+      const files = request.files || [];
+      if (files.length === 0 || !sessionConfig) return;
+      const bucket = normalizeBucketName(activeTab?.bucket || '');
+      if (!bucket) {
+        showToast('error', 'Open a bucket before uploading files');
+        return;
+      }
+      const prefix = normalizePrefix(activeTab?.prefix || '');
+      void enqueueUploadWithRenamePrompt(sessionConfig, bucket, prefix, files)
+        .then((ids) => {
+          if (!Array.isArray(ids) || ids.length === 0) return;
+          setTransferView('upload');
+          setShowTransfers(true);
+        })
+        .catch((err: any) => {
+          showToast('error', err?.message || 'Upload failed');
+        });
+    },
+    [activeTab?.bucket, activeTab?.prefix, newTabNameRule, sessionConfig, showToast],
+  );
+
+  useEffect(() => {
+    TakeLaunchRequest()
+      .then((request) => {
+        if ((request.ossPaths || []).length > 0 || (request.files || []).length > 0) {
+          setPendingLaunch(request);
+        }
+      })
+      .catch(() => {});
+
+    const off = EventsOn('app:open', (payload: main.LaunchRequest) => {
+      if (payload) setPendingLaunch(payload);
+    });
+    return () => off();
+  }, []);
+
+  useEffect(() => {
+    if (!sessionConfig || !pendingLaunch) return;
+    setPendingLaunch(null);
+    openLaunchRequest(pendingLaunch);
+  }, [openLaunchRequest, pendingLaunch, sessionConfig]);
+
+  // Navigation is only saved once the profile's previous location has been restored, so the
+  // default location shown right after login does not overwrite it.
+  const sessionRestoredRef = useRef(false);
+
+  useEffect(() => {
+    if (!sessionConfig || !sessionProfileName || !sessionRestoredRef.current) return;
+    void SaveSessionState(sessionProfileName, activeTab?.bucket || '', activeTab?.prefix || '').catch(() => {});
+  }, [activeTab?.bucket, activeTab?.prefix, sessionConfig, sessionProfileName]);
+
+  const restoreSessionState = (profileName: string) => {
+    sessionRestoredRef.current = false;
+    GetSessionState(profileName)
+      .then((state) => {
+        if (!state?.valid || !state.bucket) return;
+        const bucket = state.bucket;
+        const prefix = normalizePrefix(state.prefix || '');
+        setTabs((prev) =>
+          prev.map((t, i) =>
+            i !== 0 || t.isCustomTitle
+              ? t
+              : { ...t, bucket, prefix, title: defaultTabTitleForRule(newTabNameRule, bucket, prefix) },
+          ),
+        );
+      })
+      .catch(() => {})
+      .finally(() => {
+        sessionRestoredRef.current = true;
+      });
+  };
+
   const handleLoginSuccess = (config: main.OSSConfig, profileName?: string | null) => {
     const initialLoc = parseOssPathLocation(config?.defaultPath);
     setSessionConfig(config);
@@ -583,6 +830,9 @@ function App() {
             },
       ),
     );
+    if (profileName) {
+      restoreSessionState(profileName);
+    }
   };
 
   const handleLogout = () => {
@@ -745,6 +995,7 @@ function App() {
     }
 
     try {
+      let backgroundMoves = 0;
       for (const item of payload.items) {
         const parsed = parseOssObjectPath(item.path);
         if (!parsed?.bucket) continue;
@@ -763,7 +1014,8 @@ function App() {
           }
         }
 
-        await MoveObject(sessionConfig, srcBucket, srcKey, destBucket, destKey);
+        const jobId = await MoveObject(sessionConfig, srcBucket, srcKey, destBucket, destKey);
+        if (jobId) backgroundMoves++;
       }
 
       const sourceBucket = normalizeBucketName(payload.source?.bucket || '');
@@ -774,7 +1026,9 @@ function App() {
         EventsEmit('objects:changed', { bucket: destBucket, prefix: destPrefix });
       }
 
-      showToast('success', payload.items.length > 1 ? `Moved ${payload.items.length} items` : 'Moved 1 item');
+      if (!backgroundMoves) {
+        showToast('success', payload.items.length > 1 ? `Moved ${payload.items.length} items` : 'Moved 1 item', 5000, undoAction);
+      }
       openTab(tab.id);
     } catch (err: any) {
       showToast('error', err?.message || 'Move failed');
@@ -879,13 +1133,21 @@ function App() {
           closeTab(activeTabId);
         }
       }
+
+      // Cmd/Ctrl + Z: undo the last move, unless a text field has focus
+      if (key === 'z' && !e.shiftKey) {
+        const target = e.target as HTMLElement | null;
+        if (target && (target.tagName === 'INPUT' || target.tagName === 'TEXTAREA' || target.isContentEditable)) return;
+        e.preventDefault();
+        void undoLastMove();
+      }
     };
 
     window.addEventListener('keydown', handleKeyDown);
     return () => {
       window.removeEventListener('keydown', handleKeyDown);
     };
-  }, [activeTabId, tabs]);
+  }, [activeTabId, tabs, undoLastMove]);
 
   const inProgressCount = transferSummary.upload.taskCount + transferSummary.download.taskCount;
   const transferSummaryIdle = transferSummary.upload.taskCount <= 0 && transferSummary.download.taskCount <= 0;
@@ -1079,10 +1341,25 @@ function App() {
           onClose={() => setShowTransfers(false)}
           onReveal={(p) => OpenInFinder(p)}
           onOpen={(p) => OpenFile(p)}
+          onCancelQueued={(id) => {
+            CancelQueuedTransfer(id).catch((err: any) => showToast('error', err?.message || String(err)));
+          }}
         />
 	        {toast && !showTransfers && (
 	          <div className={`toast toast-${toast.type}`} role="status">
 	            <div className="toast-message">{toast.message}</div>
+	            {toast.action && (
+	              <button
+	                className="toast-action"
+	                type="button"
+	                onClick={() => {
+	                  toast.action?.onClick();
+	                  setToast(null);
+	                }}
+	              >
+	                {toast.action.label}
+	              </button>
+	            )}
 	            <button
 	              className="toast-close"
               type="button"
@@ -1,12 +1,13 @@
 import { useCallback, useEffect, useLayoutEffect, useMemo, useRef, useState } from 'react';
 import { main } from '../../wailsjs/go/models';
-import { CreateFile, CreateFolder, DeleteObject, EnqueueDownload, EnqueueDownloadFolder, ListBuckets, ListObjectsPage, MoveObject, PresignObject } from '../../wailsjs/go/main/OSSService';
-import { SelectDirectory, SelectFile, SelectSaveFile } from '../../wailsjs/go/main/App';
+import { CreateFile, CreateFolder, DeleteObject, EnqueueDownload, EnqueueDownloadFolder, EnqueueDownloadsFromPaths, GenerateIndexPage, GenerateShareQRCode, GetCachedBucketStats, ListBuckets, ListObjectsPage, MoveObject, PresignObject, RefreshBucketStats, RestorePrefix, RunIntegrityScan, RequestConfirmationToken, SaveShareQRCode, StopWatch, ValidateObjectKey, WatchPrefix } from '../../wailsjs/go/main/OSSService';
+import { SelectDirectory, SelectFile, SelectSaveFile, SelectSourceDirectory } from '../../wailsjs/go/main/App';
 import ConfirmationModal from './ConfirmationModal';
 import FilePreviewModal from './FilePreviewModal';
 import { EventsEmit, EventsOn } from '../../wailsjs/runtime/runtime';
 import { canReadOssDragPayload, OssDragPayload, readOssDragPayload, writeOssDragPayload } from '../ossDrag';
 import { enqueueUploadWithRenamePrompt } from '../upload';
+import { lastSegment, parentPrefix, prefixFromSegments, prefixSegments, segmentLabel } from '../ossPath';
 import './FileBrowser.css';
 import './Modal.css';
 
@@ -182,6 +183,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
   });
   
   const [buckets, setBuckets] = useState<main.BucketInfo[]>([]);
+  const [bucketStats, setBucketStats] = useState<Record<string, main.BucketStat>>({});
   const [objects, setObjects] = useState<main.ObjectInfo[]>([]);
   const [bookmarks, setBookmarks] = useState<Bookmark[]>([]);
   const [selectedPaths, setSelectedPaths] = useState<Set<string>>(() => new Set());
@@ -277,11 +279,19 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
   const [createFolderModalOpen, setCreateFolderModalOpen] = useState(false);
   const [newFolderName, setNewFolderName] = useState('');
   const [createFileModalOpen, setCreateFileModalOpen] = useState(false);
+  const [createNameError, setCreateNameError] = useState('');
   const [newFileName, setNewFileName] = useState('');
   const [moveModalOpen, setMoveModalOpen] = useState(false);
   const [moveDestValue, setMoveDestValue] = useState('');
   const [moveTargets, setMoveTargets] = useState<main.ObjectInfo[]>([]);
   const [propertiesModalOpen, setPropertiesModalOpen] = useState(false);
+  const [shareQR, setShareQR] = useState<{ name: string; url: string; image: string } | null>(null);
+  const [pasteListOpen, setPasteListOpen] = useState(false);
+  const [pasteListText, setPasteListText] = useState('');
+  const [pasteListResult, setPasteListResult] = useState<main.BatchEnqueueResult | null>(null);
+  const [indexPageOpen, setIndexPageOpen] = useState(false);
+  const [indexPageOptions, setIndexPageOptions] = useState({ title: '', depth: 0, signedLinks: false, overwrite: false });
+  const [indexPagePreview, setIndexPagePreview] = useState('');
   const [operationLoading, setOperationLoading] = useState(false);
   const [previewModalOpen, setPreviewModalOpen] = useState(false);
   const [previewObject, setPreviewObject] = useState<main.ObjectInfo | null>(null);
@@ -384,8 +394,10 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 
   const normalizeBucketName = (bucket: string) => bucket.trim().replace(/^\/+/, '').replace(/\/+$/, '');
 
+  // Keys are opaque: only leading slashes are dropped, spaces belong to the name.
+  // Prefixes are kept byte for byte (see ossPath.ts); only a trailing slash is added.
   const normalizePrefix = (prefix: string) => {
-    let p = prefix.trim().replace(/^\/+/, '');
+    let p = prefix;
     if (!p) return '';
     if (!p.endsWith('/')) p += '/';
     return p;
@@ -422,6 +434,28 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     loadBookmarks();
   }, [storageKey]);
 
+  // Check the key a new folder or file would get while its name is typed.
+  useEffect(() => {
+    const rawName = createFolderModalOpen ? newFolderName : createFileModalOpen ? newFileName : '';
+    const name = rawName.trim().replace(/^\/+/, '').replace(/\/+$/, '').replace(/\/{2,}/g, '/');
+    if (!name) {
+      setCreateNameError('');
+      return;
+    }
+    const key = `${normalizePrefix(currentPrefix)}${name}${createFolderModalOpen ? '/' : ''}`;
+    let cancelled = false;
+    ValidateObjectKey(key)
+      .then(() => {
+        if (!cancelled) setCreateNameError('');
+      })
+      .catch((err: any) => {
+        if (!cancelled) setCreateNameError(err?.message || String(err));
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, [createFolderModalOpen, createFileModalOpen, newFolderName, newFileName, currentPrefix]);
+
   useEffect(() => {
     setBookmarkMenuOpen(false);
   }, [storageKey]);
@@ -659,6 +693,42 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     return () => off();
   }, [config, currentBucket, currentPrefix, pageIndex, pageMarkers, pageSize]);
 
+  // Poll the open folder so uploads by others show up without a manual refresh. The backend allows
+  // only a few watches; folders beyond that simply are not watched.
+  useEffect(() => {
+    if (!currentBucket) return;
+    let cancelled = false;
+    let watchId = '';
+    const off = EventsOn('prefix:changed', (change: any) => {
+      if (!watchId || change?.watchId !== watchId) return;
+      EventsEmit('objects:changed', { bucket: change.bucket, prefix: change.prefix });
+    });
+    WatchPrefix(config, currentBucket, normalizePrefix(currentPrefix), 0)
+      .then((id) => {
+        if (cancelled) {
+          void StopWatch(id).catch(() => {});
+          return;
+        }
+        watchId = id;
+      })
+      .catch(() => {
+        // Too many folders watched already; this one is refreshed by hand.
+      });
+    return () => {
+      cancelled = true;
+      off();
+      if (watchId) void StopWatch(watchId).catch(() => {});
+    };
+  }, [config, currentBucket, currentPrefix]);
+
+  useEffect(() => {
+    const off = EventsOn('bucket:stats', (stat: main.BucketStat) => {
+      if (!stat?.bucket) return;
+      setBucketStats((prev) => ({ ...prev, [stat.bucket]: stat }));
+    });
+    return () => off();
+  }, []);
+
   // Close menus on click elsewhere
   useEffect(() => {
     const handleClick = () => {
@@ -677,6 +747,12 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     try {
       const result = await ListBuckets(config);
       setBuckets(result || []);
+      // Show the last known numbers at once; fresh ones arrive as bucket:stats events.
+      const names = (result || []).map((b) => b.name);
+      GetCachedBucketStats(config, names)
+        .then((cached) => setBucketStats(cached || {}))
+        .catch(() => setBucketStats({}))
+        .finally(() => RefreshBucketStats(config, names));
     } catch (err: any) {
       setError(err.message || "Failed to list buckets");
     } finally {
@@ -867,10 +943,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
       return;
     }
 
-    const parts = currentPrefix.split('/').filter((p) => p);
-    parts.pop();
-    const newPrefix = parts.length > 0 ? parts.join('/') + '/' : '';
-    navigateTo(currentBucket, newPrefix);
+    navigateTo(currentBucket, parentPrefix(currentPrefix));
   };
 
   const handleRefresh = () => {
@@ -958,10 +1031,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
           return;
       }
       
-      const parts = currentPrefix.split('/').filter(p => p);
-      const newParts = parts.slice(0, index);
-      const newPrefix = newParts.join('/') + '/';
-      navigateTo(currentBucket, newPrefix);
+      navigateTo(currentBucket, prefixFromSegments(prefixSegments(currentPrefix).slice(0, index)));
   };
 
   // Generate current OSS path
@@ -993,7 +1063,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     // Split into bucket and prefix
     const parts = pathToParse.split('/');
     const bucket = normalizeBucketName(parts[0] || '');
-    const prefix = parts.slice(1).filter(p => p).join('/');
+    const prefix = parts.slice(1).join('/');
     const normalizedPrefix = normalizePrefix(prefix);
 
     if (!bucket) {
@@ -1031,8 +1101,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     if (!profileName || !currentBucket) return;
 
     const normalizedPrefix = currentPrefix.endsWith('/') ? currentPrefix : currentPrefix + (currentPrefix ? '/' : '');
-    const labelSource = normalizedPrefix.replace(/\/$/, '');
-    const fallbackLabel = normalizedPrefix ? labelSource.split('/').filter(Boolean).pop() : currentBucket;
+    const fallbackLabel = normalizedPrefix ? segmentLabel(lastSegment(normalizedPrefix)) : currentBucket;
     const label = fallbackLabel || currentBucket;
 
     const newBookmark: Bookmark = {
@@ -1181,7 +1250,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
   };
 
   const parseObjectPath = (path: string) => {
-    let p = (path || '').trim();
+    let p = (path || '').trimStart();
     if (!p.startsWith('oss://')) return null;
     p = p.substring(6);
     p = p.replace(/^\/+/, '');
@@ -1371,7 +1440,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 
   const handleUploadFolder = async () => {
     try {
-      const dirPath = await SelectDirectory('Select Folder to Upload');
+      const dirPath = await SelectSourceDirectory('Select Folder to Upload', '');
       setUploadMenuOpen(false);
       if (!dirPath) return;
       await enqueueLocalUploadPaths([dirPath]);
@@ -1414,7 +1483,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
   const confirmCreateFile = async () => {
     if (!currentBucket) return;
     const rawName = newFileName.trim();
-    const cleanName = rawName.replace(/^\/+/, '').replace(/\/+$/, '');
+    const cleanName = rawName.replace(/^\/+/, '').replace(/\/+$/, '').replace(/\/{2,}/g, '/');
     if (!cleanName) return;
 
     setOperationLoading(true);
@@ -1454,7 +1523,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
       const parts = pathToParse.split('/');
       const bucket = normalizeBucketName(parts[0] || '');
       if (!bucket) return null;
-      const prefix = normalizePrefix(parts.slice(1).filter(Boolean).join('/'));
+      const prefix = normalizePrefix(parts.slice(1).join('/'));
       return { bucket, prefix };
     }
 
@@ -1517,7 +1586,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 
     try {
       if (isFolder(obj)) {
-        const dirPath = await SelectDirectory(`Download "${obj.name}" To`);
+        const dirPath = await SelectDirectory(`Download "${obj.name}" To`, '');
         if (!dirPath) return;
         await EnqueueDownloadFolder(config, currentBucket, parsed.key, dirPath);
       } else {
@@ -1530,6 +1599,51 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     }
   };
 
+  const openPasteList = () => {
+    setPasteListText('');
+    setPasteListResult(null);
+    setPasteListOpen(true);
+  };
+
+  const confirmPasteList = async () => {
+    if (!pasteListText.trim()) return;
+    try {
+      const dirPath = await SelectDirectory('Download Listed Files To', '');
+      if (!dirPath) return;
+      const result = await EnqueueDownloadsFromPaths(config, [pasteListText], dirPath);
+      if (result.failed === 0) {
+        setPasteListOpen(false);
+        onNotify?.({ type: 'success', message: `Queued ${result.queued} downloads` });
+        return;
+      }
+      setPasteListResult(result);
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to queue downloads' });
+    }
+  };
+
+  const openIndexPage = () => {
+    setIndexPageOptions({ title: '', depth: 0, signedLinks: false, overwrite: false });
+    setIndexPagePreview('');
+    setIndexPageOpen(true);
+  };
+
+  const runIndexPage = async (dryRun: boolean) => {
+    if (!currentBucket) return;
+    try {
+      const html = await GenerateIndexPage(config, currentBucket, currentPrefix, { ...indexPageOptions, dryRun });
+      if (dryRun) {
+        setIndexPagePreview(html);
+        return;
+      }
+      setIndexPageOpen(false);
+      EventsEmit('objects:changed', { bucket: currentBucket, prefix: currentPrefix });
+      onNotify?.({ type: 'success', message: `Uploaded ${normalizePrefix(currentPrefix)}index.html` });
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to generate index page' });
+    }
+  };
+
   const requestDelete = (targets: main.ObjectInfo[]) => {
     if (!targets.length) return;
     setDeleteTargets(targets);
@@ -1551,7 +1665,9 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
       for (const obj of deleteTargets) {
         const parsed = parseObjectPath(obj.path);
         if (!parsed?.bucket) continue;
-        await DeleteObject(config, parsed.bucket, parsed.key);
+        // The user confirmed in the modal; the backend still wants a token for every delete.
+        const token = await RequestConfirmationToken(config, 'object:delete', `oss://${parsed.bucket}/${parsed.key}`);
+        await DeleteObject(config, parsed.bucket, parsed.key, token.token);
       }
       setDeleteModalOpen(false);
       setDeleteTargets([]);
@@ -1591,6 +1707,57 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
     setContextMenu({ ...contextMenu, visible: false });
   };
 
+  const handleRestoreFolder = async () => {
+    const obj = contextMenu.object;
+    setContextMenu({ ...contextMenu, visible: false });
+    const parsed = obj ? parseObjectPath(obj.path) : null;
+    if (!obj || !parsed?.bucket || !parsed.key) return;
+    const answer = window.prompt(`Restore the archived objects in "${obj.name}" for how many days?`, '1');
+    if (answer === null) return;
+    const days = Number(answer);
+    try {
+      await RestorePrefix(config, parsed.bucket, parsed.key, days, '');
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to start restore' });
+    }
+  };
+
+  const handleIntegrityScan = async () => {
+    const obj = contextMenu.object;
+    setContextMenu({ ...contextMenu, visible: false });
+    const parsed = obj ? parseObjectPath(obj.path) : null;
+    if (!obj || !parsed?.bucket || !parsed.key) return;
+    try {
+      await RunIntegrityScan(config, parsed.bucket, parsed.key, { checkContentType: true, checkCRC: true, maxHeadRequests: 0 });
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to start scan' });
+    }
+  };
+
+  const handleShareQRCode = async () => {
+    const obj = contextMenu.object;
+    setContextMenu({ ...contextMenu, visible: false });
+    const parsed = obj ? parseObjectPath(obj.path) : null;
+    if (!obj || !parsed?.bucket || !parsed.key) return;
+    try {
+      const url = await PresignObject(config, parsed.bucket, parsed.key, '1h');
+      const image = await GenerateShareQRCode(url, 256);
+      setShareQR({ name: objectNameForKey(obj.name), url, image });
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to create QR code' });
+    }
+  };
+
+  const handleSaveShareQRCode = async () => {
+    if (!shareQR) return;
+    try {
+      const path = await SaveShareQRCode(shareQR.url, 512);
+      onNotify?.({ type: 'success', message: `QR code saved to ${path}` });
+    } catch (err: any) {
+      onNotify?.({ type: 'error', message: err?.message || 'Failed to save QR code' });
+    }
+  };
+
   const handleShowProperties = () => {
     setPropertiesModalOpen(true);
     setContextMenu({ ...contextMenu, visible: false });
@@ -1970,11 +2137,11 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
       );
 
       if (currentPrefix) {
-        const parts = currentPrefix.split('/').filter(p => p);
+        const parts = prefixSegments(currentPrefix);
         parts.forEach((part, index) => {
           crumbs.push(<span key={`sep-${index}`} className="separator">/</span>);
           const isLast = index === parts.length - 1;
-          const partPrefix = parts.slice(0, index + 1).join('/') + '/';
+          const partPrefix = prefixFromSegments(parts.slice(0, index + 1));
           crumbs.push(
             <span 
                 key={`part-${index}`} 
@@ -1988,7 +2155,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
                 onMouseEnter={(e) => openCrumbPopover(bucketDisplay, partPrefix, e.currentTarget)}
                 onMouseLeave={() => scheduleCloseCrumbPopover()}
             >
-              {part}
+              {segmentLabel(part)}
             </span>
           );
         });
@@ -2094,7 +2261,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 
               <div className="details-title">
                 <div className="details-name" title={focusedObject.name}>
-                  {focusedObject.name}
+                  {segmentLabel(focusedObject.name)}
                 </div>
                 <div className="details-subtitle">
                   {displayType(focusedObject)}
@@ -2338,7 +2505,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	                      <path d="M10 4H4c-1.1 0-1.99.9-1.99 2L2 18c0 1.1.9 2 2 2h16c1.1 0 2-.9 2-2V8c0-1.1-.9-2-2-2h-8l-2-2z"/>
 	                    </svg>
 	                  </span>
-	                  <span className="crumb-popover-item-name">{folder.name}</span>
+	                  <span className="crumb-popover-item-name">{segmentLabel(folder.name)}</span>
 	                </button>
 	              ))}
 
@@ -2397,6 +2564,12 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
                   </div>
                 )}
               </div>
+	            <button className="action-btn" type="button" onClick={openPasteList} title="Download a pasted list of oss:// paths">
+	              Download List
+	            </button>
+	            <button className="action-btn" type="button" onClick={openIndexPage} title="Upload an index.html listing this folder">
+	              Index Page
+	            </button>
 	            <button className="action-btn" type="button" onClick={requestCreateFolder} title="New Folder">
 	              New Folder
 	            </button>
@@ -2460,6 +2633,13 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
                     <div className="bucket-info">
                         <span>{bucket.region}</span>
                         <span>{bucket.creationDate}</span>
+                        {bucketStats[bucket.name] && (
+                          <span className="bucket-stats">
+                            {bucketStats[bucket.name].unavailable
+                              ? 'Stats unavailable'
+                              : `${bucketStats[bucket.name].objectCount.toLocaleString()} objects · ${formatSize(bucketStats[bucket.name].storage)}`}
+                          </span>
+                        )}
                     </div>
                     </div>
                 ))
@@ -2613,7 +2793,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 		                                 </svg>
 		                               )}
 			                            </div>
-	                            <span className="file-name-text">{obj.name}</span>
+	                            <span className="file-name-text">{segmentLabel(obj.name)}</span>
 	                          </div>
 	                        </td>
 	                        <td>{!isFolder(obj) ? formatSize(obj.size) : '-'}</td>
@@ -2801,6 +2981,36 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
             </span>
             Copy Path
           </div>
+          {contextMenu.object && !isFolder(contextMenu.object) && (
+            <div className="context-menu-item" onClick={() => void handleShareQRCode()}>
+              <span className="context-menu-icon">
+                <svg width="16" height="16" viewBox="0 0 24 24" fill="currentColor">
+                  <path d="M3 11h8V3H3v8zm2-6h4v4H5V5zm-2 16h8v-8H3v8zm2-6h4v4H5v-4zm8-12v8h8V3h-8zm6 6h-4V5h4v4zm0 10h2v2h-2zm-6-6h2v2h-2zm2 2h2v2h-2zm-2 2h2v2h-2zm2 2h2v2h-2zm2-2h2v2h-2zm0-4h2v2h-2zm2 2h2v2h-2z"/>
+                </svg>
+              </span>
+              Share QR Code
+            </div>
+          )}
+          {contextMenu.object && isFolder(contextMenu.object) && (
+            <div className="context-menu-item" onClick={() => void handleRestoreFolder()}>
+              <span className="context-menu-icon">
+                <svg width="16" height="16" viewBox="0 0 24 24" fill="currentColor">
+                  <path d="M13 3a9 9 0 00-9 9H1l3.89 3.89.07.14L9 12H6c0-3.87 3.13-7 7-7s7 3.13 7 7-3.13 7-7 7c-1.93 0-3.68-.79-4.94-2.06l-1.42 1.42A8.954 8.954 0 0013 21a9 9 0 000-18zm-1 5v5l4.28 2.54.72-1.21-3.5-2.08V8H12z"/>
+                </svg>
+              </span>
+              Restore Archived
+            </div>
+          )}
+          {contextMenu.object && isFolder(contextMenu.object) && (
+            <div className="context-menu-item" onClick={() => void handleIntegrityScan()}>
+              <span className="context-menu-icon">
+                <svg width="16" height="16" viewBox="0 0 24 24" fill="currentColor">
+                  <path d="M15.5 14h-.79l-.28-.27A6.471 6.471 0 0016 9.5 6.5 6.5 0 109.5 16c1.61 0 3.09-.59 4.23-1.57l.27.28v.79l5 4.99L20.49 19l-4.99-5zm-6 0C7.01 14 5 11.99 5 9.5S7.01 5 9.5 5 14 7.01 14 9.5 11.99 14 9.5 14z"/>
+                </svg>
+              </span>
+              Integrity Scan
+            </div>
+          )}
           <div className="context-menu-item" onClick={handleShowProperties}>
             <span className="context-menu-icon">
               <svg width="16" height="16" viewBox="0 0 24 24" fill="currentColor">
@@ -2821,6 +3031,135 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	        </div>
 	      )}
 
+	      {shareQR && (
+	        <div className="modal-overlay" onClick={() => setShareQR(null)}>
+	          <div className="modal-content" onClick={(e) => e.stopPropagation()}>
+	            <div className="modal-header">
+	              <h3 className="modal-title">Share {shareQR.name}</h3>
+	            </div>
+	            <p className="modal-description">Scan to download. The link expires in 1 hour.</p>
+	            <div style={{ display: 'flex', justifyContent: 'center', margin: '12px 0' }}>
+	              <img src={shareQR.image} alt="QR code of the share link" width={256} height={256} />
+	            </div>
+	            <div className="modal-actions">
+	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => void handleSaveShareQRCode()}>
+	                Save as PNG
+	              </button>
+	              <button className="modal-btn modal-btn-primary" type="button" onClick={() => setShareQR(null)}>
+	                Close
+	              </button>
+	            </div>
+	          </div>
+	        </div>
+	      )}
+
+	      {pasteListOpen && (
+	        <div className="modal-overlay" onClick={() => setPasteListOpen(false)}>
+	          <div className="modal-content" onClick={(e) => e.stopPropagation()}>
+	            <div className="modal-header">
+	              <h3 className="modal-title">Download List</h3>
+	            </div>
+	            <p className="modal-description">Paste one oss://bucket/key path per line. Lines starting with # are skipped.</p>
+	            <textarea
+	              className="modal-input"
+	              rows={8}
+	              value={pasteListText}
+	              placeholder="oss://bucket/path/to/file"
+	              onChange={(e) => {
+	                setPasteListText(e.target.value);
+	                setPasteListResult(null);
+	              }}
+	              autoFocus
+	            />
+	            {pasteListResult && (
+	              <div className="modal-description">
+	                <div>
+	                  Queued {pasteListResult.queued}, failed {pasteListResult.failed}:
+	                </div>
+	                {pasteListResult.lines
+	                  .filter((line) => line.outcome !== 'queued')
+	                  .map((line) => (
+	                    <div key={line.line}>
+	                      Line {line.line}: {line.input} — {line.message || line.outcome}
+	                    </div>
+	                  ))}
+	              </div>
+	            )}
+	            <div className="modal-actions">
+	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => setPasteListOpen(false)}>
+	                {pasteListResult ? 'Close' : 'Cancel'}
+	              </button>
+	              <button
+	                className="modal-btn modal-btn-primary"
+	                type="button"
+	                onClick={() => void confirmPasteList()}
+	                disabled={!pasteListText.trim() || !!pasteListResult}
+	              >
+	                Queue Downloads
+	              </button>
+	            </div>
+	          </div>
+	        </div>
+	      )}
+
+	      {indexPageOpen && (
+	        <div className="modal-overlay" onClick={() => setIndexPageOpen(false)}>
+	          <div className="modal-content" onClick={(e) => e.stopPropagation()}>
+	            <div className="modal-header">
+	              <h3 className="modal-title">Index Page</h3>
+	            </div>
+	            <p className="modal-description">Upload an index.html that lists this folder, for browsing it without a web server.</p>
+	            <input
+	              className="modal-input"
+	              type="text"
+	              value={indexPageOptions.title}
+	              placeholder="Title (defaults to the oss:// path)"
+	              onChange={(e) => setIndexPageOptions({ ...indexPageOptions, title: e.target.value })}
+	            />
+	            <label className="modal-description">
+	              Subfolder levels to include{' '}
+	              <input
+	                type="number"
+	                min={0}
+	                max={10}
+	                value={indexPageOptions.depth}
+	                onChange={(e) => setIndexPageOptions({ ...indexPageOptions, depth: Number(e.target.value) || 0 })}
+	              />
+	            </label>
+	            <label className="modal-description">
+	              <input
+	                type="checkbox"
+	                checked={indexPageOptions.signedLinks}
+	                onChange={(e) => setIndexPageOptions({ ...indexPageOptions, signedLinks: e.target.checked })}
+	              />{' '}
+	              Link through signed URLs (expire in a day)
+	            </label>
+	            <label className="modal-description">
+	              <input
+	                type="checkbox"
+	                checked={indexPageOptions.overwrite}
+	                onChange={(e) => setIndexPageOptions({ ...indexPageOptions, overwrite: e.target.checked })}
+	              />{' '}
+	              Replace an existing index.html
+	            </label>
+	            {indexPagePreview && (
+	              <iframe title="Index page preview" sandbox="" srcDoc={indexPagePreview} style={{ width: '100%', height: 240, border: 'none', background: '#fff' }} />
+	            )}
+	            <div className="modal-actions">
+	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => setIndexPageOpen(false)}>
+	                Cancel
+	              </button>
+	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => void runIndexPage(true)}>
+	                Preview
+	              </button>
+	              <button className="modal-btn modal-btn-primary" type="button" onClick={() => void runIndexPage(false)}>
+	                Upload
+	              </button>
+	            </div>
+	          </div>
+	        </div>
+	      )}
+
 	      {createFolderModalOpen && (
 	        <div className="modal-overlay" onClick={() => setCreateFolderModalOpen(false)}>
 	          <div className="modal-content" onClick={(e) => e.stopPropagation()}>
@@ -2841,6 +3180,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	              placeholder="Folder name"
 	              disabled={operationLoading}
 	            />
+	            {createNameError && <p className="modal-input-error">{createNameError}</p>}
 	            <div className="modal-actions">
 	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => setCreateFolderModalOpen(false)} disabled={operationLoading}>
 	                Cancel
@@ -2849,7 +3189,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	                className="modal-btn modal-btn-primary"
 	                type="button"
 	                onClick={() => void confirmCreateFolder()}
-	                disabled={operationLoading || !newFolderName.trim()}
+	                disabled={operationLoading || !newFolderName.trim() || !!createNameError}
 	              >
 	                {operationLoading ? 'Creating…' : 'Create'}
 	              </button>
@@ -2878,6 +3218,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	              placeholder="File name (e.g. README.md)"
 	              disabled={operationLoading}
 	            />
+	            {createNameError && <p className="modal-input-error">{createNameError}</p>}
 	            <div className="modal-actions">
 	              <button className="modal-btn modal-btn-cancel" type="button" onClick={() => setCreateFileModalOpen(false)} disabled={operationLoading}>
 	                Cancel
@@ -2886,7 +3227,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
 	                className="modal-btn modal-btn-primary"
 	                type="button"
 	                onClick={() => void confirmCreateFile()}
-	                disabled={operationLoading || !newFileName.trim()}
+	                disabled={operationLoading || !newFileName.trim() || !!createNameError}
 	              >
 	                {operationLoading ? 'Creating…' : 'Create'}
 	              </button>
@@ -2954,7 +3295,7 @@ function FileBrowser({ config, profileName, listViewMode = 'finder', initialPath
             <div className="properties-body">
               <div className="property-row">
                 <span className="property-label">Name</span>
-                <span className="property-value">{contextMenu.object.name}</span>
+                <span className="property-value">{segmentLabel(contextMenu.object.name)}</span>
               </div>
               <div className="property-row">
                 <span className="property-label">Path</span>
@@ -206,9 +206,11 @@ interface TransferModalProps {
   onClose: () => void;
   onReveal: (path: string) => void;
   onOpen: (path: string) => void;
+  // Cancels a transfer that has not started, or the files of a group that have not started.
+  onCancelQueued: (id: string) => void;
 }
 
-export default function TransferModal({ isOpen, activeTab, onTabChange, transfers, onClose, onReveal, onOpen }: TransferModalProps) {
+export default function TransferModal({ isOpen, activeTab, onTabChange, transfers, onClose, onReveal, onOpen, onCancelQueued }: TransferModalProps) {
   const [search, setSearch] = useState('');
   const [expandedItemIds, setExpandedItemIds] = useState<Record<string, boolean>>({});
 
@@ -370,19 +372,32 @@ export default function TransferModal({ isOpen, activeTab, onTabChange, transfer
     );
   };
 
-  const renderTransferActions = (t: TransferRecord) =>
-    t.type === 'download' &&
-    t.status === 'success' &&
-    t.localPath && (
-      <div className="transfer-actions">
-        <button className="transfer-action-btn" type="button" onClick={() => onReveal(t.localPath!)}>
-          Reveal
-        </button>
-        <button className="transfer-action-btn primary" type="button" onClick={() => onOpen(t.localPath!)}>
-          Open
-        </button>
-      </div>
+  const renderTransferActions = (t: TransferRecord) => {
+    const hasQueued = t.status === 'queued' || (!!t.isGroup && t.status === 'in-progress' && (t.doneCount || 0) < (t.fileCount || 0));
+    if (hasQueued) {
+      return (
+        <div className="transfer-actions">
+          <button className="transfer-action-btn" type="button" onClick={() => onCancelQueued(t.id)}>
+            {t.isGroup && t.status === 'in-progress' ? 'Cancel remaining' : 'Cancel'}
+          </button>
+        </div>
+      );
+    }
+    return (
+      t.type === 'download' &&
+      t.status === 'success' &&
+      t.localPath && (
+        <div className="transfer-actions">
+          <button className="transfer-action-btn" type="button" onClick={() => onReveal(t.localPath!)}>
+            Reveal
+          </button>
+          <button className="transfer-action-btn primary" type="button" onClick={() => onOpen(t.localPath!)}>
+            Open
+          </button>
+        </div>
+      )
     );
+  };
 
   const renderStatusMeta = (t: TransferRecord, isCompleted: boolean, speedForMeta?: number) => (
     <div className={`transfer-status-meta ${isCompleted ? 'completed' : ''}`} aria-label="Transfer summary">
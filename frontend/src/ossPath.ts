@@ -0,0 +1,25 @@
+// Keys written by other tools can contain empty segments ("logs//2024//app.log"). Prefixes are kept
+// byte for byte; these helpers split them without dropping the empty segments, which the backend
+// lists as folders named "" that open like any other.
+
+export const EMPTY_SEGMENT_LABEL = '(empty)';
+
+// prefixSegments splits a key or folder prefix: "logs//2024/" -> ["logs", "", "2024"].
+export const prefixSegments = (prefix: string) => {
+  if (!prefix) return [];
+  const trimmed = prefix.endsWith('/') ? prefix.slice(0, -1) : prefix;
+  return trimmed.split('/');
+};
+
+export const prefixFromSegments = (segments: string[]) => (segments.length ? `${segments.join('/')}/` : '');
+
+// parentPrefix returns the folder a key or folder prefix lives in: "logs//2024/" -> "logs//".
+export const parentPrefix = (prefix: string) => prefixFromSegments(prefixSegments(prefix).slice(0, -1));
+
+export const lastSegment = (prefix: string) => {
+  const segments = prefixSegments(prefix);
+  return segments.length ? segments[segments.length - 1] : '';
+};
+
+// segmentLabel is how a folder or file name is shown; an empty segment needs something to click.
+export const segmentLabel = (name: string) => (name === '' ? EMPTY_SEGMENT_LABEL : name);
@@ -2,16 +2,20 @@ import { useEffect, useState } from 'react';
 import './Login.css';
 import {
   CheckOssutilInstalled,
+  CheckRegion,
   GetDefaultProfile,
+  GetKnownRegions,
   GetOssutilPath,
+  GetProfile,
   GetSettings,
-  LoadProfiles,
+  ListProfileSummaries,
   SaveProfile,
   SaveSettings,
   SetOssutilPath,
   TestConnection,
 } from '../../wailsjs/go/main/OSSService';
 import { main } from '../../wailsjs/go/models';
+import { EventsOn } from '../../wailsjs/runtime/runtime';
 import ProfilePicker from '../components/ProfilePicker';
 
 interface LoginProps {
@@ -24,7 +28,12 @@ function Login({ onLoginSuccess }: LoginProps) {
   const [accessKeyId, setAccessKeyId] = useState('');
   const [accessKeySecret, setAccessKeySecret] = useState('');
   const [region, setRegion] = useState('cn-hangzhou');
+  const [knownRegions, setKnownRegions] = useState<main.RegionInfo[]>([]);
+  const [regionCheck, setRegionCheck] = useState<main.RegionCheck | null>(null);
   const [endpoint, setEndpoint] = useState('');
+  const [allowInsecureHttp, setAllowInsecureHttp] = useState(false);
+  const [cnameDomain, setCnameDomain] = useState('');
+  const [useDualStackEndpoint, setUseDualStackEndpoint] = useState(false);
   const [defaultPath, setDefaultPath] = useState('');
 
   const [profiles, setProfiles] = useState<main.OSSProfile[]>([]);
@@ -46,6 +55,35 @@ function Login({ onLoginSuccess }: LoginProps) {
 
   useEffect(() => {
     void initializeApp();
+    GetKnownRegions()
+      .then((regions) => setKnownRegions(regions || []))
+      .catch(() => setKnownRegions([]));
+  }, []);
+
+  // Unknown regions are allowed; the hint only catches typos such as "cn-hangzou".
+  useEffect(() => {
+    const typed = region.trim();
+    if (!typed) {
+      setRegionCheck(null);
+      return;
+    }
+    const timer = window.setTimeout(() => {
+      CheckRegion(typed)
+        .then((check) => setRegionCheck(check))
+        .catch(() => setRegionCheck(null));
+    }, 300);
+    return () => window.clearTimeout(timer);
+  }, [region]);
+
+  useEffect(() => {
+    // Profiles edited outside the app; refresh the picker but leave the form alone.
+    const off = EventsOn('config:changed', (change: any) => {
+      if (!change?.profiles) return;
+      ListProfileSummaries()
+        .then((savedProfiles) => setProfiles(savedProfiles || []))
+        .catch(() => {});
+    });
+    return () => off();
   }, []);
 
   const renderDriverStatus = (result: main.ConnectionResult): InlineMessage => {
@@ -67,7 +105,7 @@ function Login({ onLoginSuccess }: LoginProps) {
   };
 
   const loadProfilesAndDefault = async () => {
-    const savedProfiles = await LoadProfiles();
+    const savedProfiles = await ListProfileSummaries();
     setProfiles(savedProfiles || []);
 
     const defaultProfile = await GetDefaultProfile();
@@ -113,6 +151,9 @@ function Login({ onLoginSuccess }: LoginProps) {
     setAccessKeySecret(profile.config.accessKeySecret);
     setRegion(profile.config.region);
     setEndpoint(profile.config.endpoint || '');
+    setAllowInsecureHttp(!!profile.config.allowInsecureHttp);
+    setCnameDomain(profile.config.cnameDomain || '');
+    setUseDualStackEndpoint(!!profile.config.useDualStackEndpoint);
     setDefaultPath(profile.config.defaultPath || '');
   };
 
@@ -128,6 +169,9 @@ function Login({ onLoginSuccess }: LoginProps) {
     setAccessKeySecret('');
     setRegion('cn-hangzhou');
     setEndpoint('');
+    setAllowInsecureHttp(false);
+    setCnameDomain('');
+    setUseDualStackEndpoint(false);
     setDefaultPath('');
   };
 
@@ -179,6 +223,26 @@ function Login({ onLoginSuccess }: LoginProps) {
     }
   };
 
+  // Profile listings carry masked secrets; the real ones are fetched only to connect.
+  const buildConfig = async (): Promise<main.OSSConfig> => {
+    const config: main.OSSConfig = {
+      accessKeyId,
+      accessKeySecret,
+      region,
+      endpoint,
+      defaultPath,
+      allowInsecureHttp,
+      cnameDomain,
+      useDualStackEndpoint,
+    };
+    const listed = profiles.find((p) => p.name === selectedProfile);
+    if (listed?.hasSecret && accessKeySecret === listed.config.accessKeySecret) {
+      const full = await GetProfile(selectedProfile);
+      config.accessKeySecret = full.config.accessKeySecret;
+    }
+    return config;
+  };
+
   const handleTestConnection = async () => {
     if (!accessKeyId || !accessKeySecret || !region) {
       setMessage({ type: 'error', text: 'Please fill in required fields' });
@@ -189,19 +253,14 @@ function Login({ onLoginSuccess }: LoginProps) {
     setMessage(null);
 
     try {
-      const config: main.OSSConfig = {
-        accessKeyId,
-        accessKeySecret,
-        region,
-        endpoint,
-        defaultPath,
-      };
+      const config = await buildConfig();
 
       const result = await TestConnection(config);
       if (result.success) {
-        setMessage({ type: 'success', text: 'Connection successful!' });
+        const family = result.addressFamily === 'ipv6' ? ' (IPv6)' : result.addressFamily === 'ipv4' ? ' (IPv4)' : '';
+        setMessage({ type: 'success', text: `Connection successful!${family}` });
       } else {
-        setMessage({ type: 'error', text: result.message });
+        setMessage({ type: 'error', text: result.hint ? `${result.message}\n${result.hint}` : result.message });
       }
     } catch (error: any) {
       setMessage({ type: 'error', text: error.message || 'Connection test failed' });
@@ -220,17 +279,11 @@ function Login({ onLoginSuccess }: LoginProps) {
     setMessage(null);
 
     try {
-      const config: main.OSSConfig = {
-        accessKeyId,
-        accessKeySecret,
-        region,
-        endpoint,
-        defaultPath,
-      };
+      const config = await buildConfig();
 
       const result = await TestConnection(config);
       if (!result.success) {
-        setMessage({ type: 'error', text: result.message });
+        setMessage({ type: 'error', text: result.hint ? `${result.message}\n${result.hint}` : result.message });
         setLoading(false);
         return;
       }
@@ -241,8 +294,8 @@ function Login({ onLoginSuccess }: LoginProps) {
           config,
           isDefault: setAsDefault,
         });
-        await SaveProfile(profile);
-        const savedProfiles = await LoadProfiles();
+        await SaveProfile(profile, false);
+        const savedProfiles = await ListProfileSummaries();
         setProfiles(savedProfiles || []);
       }
 
@@ -374,7 +427,39 @@ function Login({ onLoginSuccess }: LoginProps) {
             <div className="form-row">
               <div className="form-group">
                 <label className="form-label">Region *</label>
-                <input type="text" className="form-input" placeholder="e.g. cn-hangzhou" value={region} onChange={(e) => setRegion(e.target.value)} />
+                <input
+                  type="text"
+                  className="form-input"
+                  placeholder="e.g. cn-hangzhou"
+                  list="known-regions"
+                  value={region}
+                  onChange={(e) => setRegion(e.target.value)}
+                />
+                <datalist id="known-regions">
+                  {knownRegions.map((known) => (
+                    <option key={known.id} value={known.id}>
+                      {known.name}
+                    </option>
+                  ))}
+                </datalist>
+                {regionCheck && !regionCheck.known && regionCheck.message && (
+                  <div className="form-hint" style={{ marginTop: '6px', opacity: 0.85, fontSize: '12px' }}>
+                    {regionCheck.message}
+                    {regionCheck.suggestion && (
+                      <>
+                        {' '}
+                        <button
+                          type="button"
+                          className="btn btn-secondary"
+                          style={{ padding: '2px 8px', fontSize: '12px' }}
+                          onClick={() => setRegion(regionCheck.suggestion!)}
+                        >
+                          Use {regionCheck.suggestion}
+                        </button>
+                      </>
+                    )}
+                  </div>
+                )}
               </div>
               <div className="form-group">
                 <label className="form-label">Endpoint</label>
@@ -391,9 +476,36 @@ function Login({ onLoginSuccess }: LoginProps) {
                     service endpoint like <code>oss-cn-hangzhou.aliyuncs.com</code>.
                   </div>
                 )}
+                {(endpoint.trim().toLowerCase().startsWith('http://') || cnameDomain.trim().toLowerCase().startsWith('http://')) && (
+                  <label className="form-hint" style={{ marginTop: '6px', fontSize: '12px', display: 'flex', gap: '6px', alignItems: 'center' }}>
+                    <input type="checkbox" checked={allowInsecureHttp} onChange={(e) => setAllowInsecureHttp(e.target.checked)} />
+                    Allow insecure HTTP (credentials and data are sent unencrypted)
+                  </label>
+                )}
+                <label className="form-hint" style={{ marginTop: '6px', fontSize: '12px', display: 'flex', gap: '6px', alignItems: 'center' }}>
+                  <input type="checkbox" checked={useDualStackEndpoint} onChange={(e) => setUseDualStackEndpoint(e.target.checked)} />
+                  Dual-stack endpoint (IPv6 and IPv4)
+                </label>
               </div>
             </div>
 
+            <div className="form-group">
+              <label className="form-label">Custom Domain</label>
+              <input
+                type="text"
+                className="form-input"
+                placeholder="e.g. cdn.example.com (optional)"
+                value={cnameDomain}
+                onChange={(e) => setCnameDomain(e.target.value)}
+              />
+              {cnameDomain.trim() && (
+                <div className="form-hint" style={{ marginTop: '6px', opacity: 0.7, fontSize: '12px' }}>
+                  Objects are read and written through this domain. Buckets cannot be listed through it: pin the bucket bound to it or set a
+                  default path.
+                </div>
+              )}
+            </div>
+
             <div className="form-group">
               <label className="form-label">Default Path</label>
               <input
@@ -90,7 +90,7 @@ function Settings({ isOpen, onBack, onThemeChange, onNotify, onSettingsSaved }:
     }
   };
 
-  const handleThemeSelect = (theme: 'dark' | 'light') => {
+  const handleThemeSelect = (theme: 'dark' | 'light' | 'system') => {
     setSettings((prev) => ({ ...prev, theme }));
     if (onThemeChange) {
       onThemeChange(theme);
@@ -263,6 +263,9 @@ function Settings({ isOpen, onBack, onThemeChange, onNotify, onSettingsSaved }:
                     <div className={`theme-option ${settings.theme === 'light' ? 'active' : ''}`} onClick={() => handleThemeSelect('light')}>
                       Light
                     </div>
+                    <div className={`theme-option ${settings.theme === 'system' ? 'active' : ''}`} onClick={() => handleThemeSelect('system')}>
+                      System
+                    </div>
                   </div>
                 </div>
                 <div className="form-group">
@@ -2,16 +2,34 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function CopyObjectKey(arg1:string):Promise<void>;
+
+export function CopyObjectPath(arg1:string,arg2:string):Promise<void>;
+
+export function CopyTextToClipboard(arg1:string):Promise<void>;
+
 export function GetAppInfo():Promise<main.AppInfo>;
 
+export function GetRecentLogEntries(arg1:number):Promise<Array<main.OperationLogEntry>>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function OpenFile(arg1:string):Promise<void>;
 
 export function OpenInFinder(arg1:string):Promise<void>;
 
-export function SelectDirectory(arg1:string):Promise<string>;
+export function OpenLogFolder():Promise<void>;
+
+export function PickDefaultDownloadDir():Promise<string>;
+
+export function RunStartupHealthCheck():Promise<main.StartupHealthReport>;
+
+export function SelectDirectory(arg1:string,arg2:string):Promise<string>;
 
 export function SelectFile():Promise<string>;
 
 export function SelectSaveFile(arg1:string):Promise<string>;
+
+export function SelectSourceDirectory(arg1:string,arg2:string):Promise<string>;
+
+export function TakeLaunchRequest():Promise<main.LaunchRequest>;
@@ -2,10 +2,26 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CopyObjectKey(arg1) {
+  return window['go']['main']['App']['CopyObjectKey'](arg1);
+}
+
+export function CopyObjectPath(arg1, arg2) {
+  return window['go']['main']['App']['CopyObjectPath'](arg1, arg2);
+}
+
+export function CopyTextToClipboard(arg1) {
+  return window['go']['main']['App']['CopyTextToClipboard'](arg1);
+}
+
 export function GetAppInfo() {
   return window['go']['main']['App']['GetAppInfo']();
 }
 
+export function GetRecentLogEntries(arg1) {
+  return window['go']['main']['App']['GetRecentLogEntries'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -18,8 +34,20 @@ export function OpenInFinder(arg1) {
   return window['go']['main']['App']['OpenInFinder'](arg1);
 }
 
-export function SelectDirectory(arg1) {
-  return window['go']['main']['App']['SelectDirectory'](arg1);
+export function OpenLogFolder() {
+  return window['go']['main']['App']['OpenLogFolder']();
+}
+
+export function PickDefaultDownloadDir() {
+  return window['go']['main']['App']['PickDefaultDownloadDir']();
+}
+
+export function RunStartupHealthCheck() {
+  return window['go']['main']['App']['RunStartupHealthCheck']();
+}
+
+export function SelectDirectory(arg1, arg2) {
+  return window['go']['main']['App']['SelectDirectory'](arg1, arg2);
 }
 
 export function SelectFile() {
@@ -29,3 +57,11 @@ export function SelectFile() {
 export function SelectSaveFile(arg1) {
   return window['go']['main']['App']['SelectSaveFile'](arg1);
 }
+
+export function SelectSourceDirectory(arg1, arg2) {
+  return window['go']['main']['App']['SelectSourceDirectory'](arg1, arg2);
+}
+
+export function TakeLaunchRequest() {
+  return window['go']['main']['App']['TakeLaunchRequest']();
+}
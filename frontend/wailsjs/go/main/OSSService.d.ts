@@ -1,66 +1,253 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
+import {time} from '../models';
 import {context} from '../models';
 
+export function AddPinnedBucket(arg1:string,arg2:string):Promise<void>;
+
+export function CanUndo():Promise<string>;
+
+export function CancelMoveJob(arg1:string):Promise<void>;
+
+export function CancelOperation(arg1:string):Promise<void>;
+
+export function CancelQueuedTransfer(arg1:string):Promise<void>;
+
+export function ChangeMasterPassphrase(arg1:string,arg2:string):Promise<void>;
+
 export function CheckOssutilInstalled():Promise<main.ConnectionResult>;
 
+export function CheckRegion(arg1:string):Promise<main.RegionCheck>;
+
 export function CheckUploadNameCollisions(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:Array<string>):Promise<Array<main.UploadNameCollision>>;
 
+export function CleanupTempFiles(arg1:time.Duration):Promise<main.TempCleanupResult>;
+
 export function CreateFile(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
 
 export function CreateFolder(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
 
-export function DeleteObject(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
+export function DeleteBucketCORS(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
+
+export function DeleteBucketEncryption(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
+
+export function DeleteBucketInventory(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function DeleteBucketPolicy(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
+
+export function DeleteBucketTagging(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
 
-export function DeleteProfile(arg1:string):Promise<void>;
+export function DeleteObject(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function DeleteProfile(arg1:string):Promise<main.DeleteProfileResult>;
+
+export function DisableEncryption(arg1:string):Promise<void>;
 
 export function DownloadFile(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
 
+export function DuplicateProfile(arg1:string,arg2:string):Promise<main.OSSProfile>;
+
 export function EnqueueDownload(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string,arg5:number):Promise<string>;
 
 export function EnqueueDownloadFolder(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<string>;
 
+export function EnqueueDownloadsFromPaths(arg1:main.OSSConfig,arg2:Array<string>,arg3:string):Promise<main.BatchEnqueueResult>;
+
 export function EnqueueUpload(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<string>;
 
 export function EnqueueUploadPaths(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:Array<string>):Promise<Array<string>>;
 
 export function EnqueueUploadRoots(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:Array<main.UploadRootSpec>):Promise<Array<string>>;
 
+export function EstimateStorageCost(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<main.CostEstimate>;
+
+export function ExportIntegrityReportCSV(arg1:string,arg2:string):Promise<void>;
+
+export function ExportProfiles(arg1:string,arg2:boolean):Promise<void>;
+
+export function ExportUsageCSV(arg1:string):Promise<void>;
+
+export function GenerateIndexPage(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:main.IndexOptions):Promise<string>;
+
+export function GenerateShareQRCode(arg1:string,arg2:number):Promise<string>;
+
+export function GetAvailableLanguages():Promise<Array<main.LanguageInfo>>;
+
+export function GetBucketCORS(arg1:main.OSSConfig,arg2:string):Promise<Array<main.CORSRuleView>>;
+
+export function GetBucketEncryption(arg1:main.OSSConfig,arg2:string):Promise<main.EncryptionConfigView>;
+
+export function GetBucketInfo(arg1:main.OSSConfig,arg2:string,arg3:main.BucketInfoOptions):Promise<main.BucketDetail>;
+
+export function GetBucketLifecycle(arg1:main.OSSConfig,arg2:string):Promise<Array<main.LifecycleRuleView>>;
+
+export function GetBucketPolicy(arg1:main.OSSConfig,arg2:string):Promise<string>;
+
+export function GetBucketReferer(arg1:main.OSSConfig,arg2:string):Promise<main.RefererConfigView>;
+
+export function GetBucketReplication(arg1:main.OSSConfig,arg2:string):Promise<Array<main.ReplicationRuleView>>;
+
+export function GetBucketReplicationProgress(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<main.ReplicationProgressView>;
+
+export function GetBucketStat(arg1:main.OSSConfig,arg2:string,arg3:boolean):Promise<main.BucketStat>;
+
+export function GetBucketTagging(arg1:main.OSSConfig,arg2:string):Promise<Record<string, string>>;
+
+export function GetBucketTransferAcceleration(arg1:main.OSSConfig,arg2:string):Promise<boolean>;
+
+export function GetBucketVersioning(arg1:main.OSSConfig,arg2:string):Promise<string>;
+
+export function GetBucketWorm(arg1:main.OSSConfig,arg2:string):Promise<main.WormInfo>;
+
+export function GetCachedBucketStats(arg1:main.OSSConfig,arg2:Array<string>):Promise<Record<string, main.BucketStat>>;
+
+export function GetConfigRecoveries():Promise<Array<main.ConfigRecovery>>;
+
 export function GetDefaultProfile():Promise<main.OSSProfile>;
 
+export function GetEffectiveTheme():Promise<string>;
+
+export function GetIntegrityReport(arg1:string):Promise<main.IntegrityReport>;
+
+export function GetKnownRegions():Promise<Array<main.RegionInfo>>;
+
 export function GetObjectText(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:number):Promise<string>;
 
+export function GetObjectURL(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:main.ObjectURLOptions):Promise<main.ObjectURLResult>;
+
 export function GetOssutilPath():Promise<string>;
 
+export function GetPendingOperations():Promise<Array<main.PendingOperation>>;
+
+export function GetPrefixRestoreStatus(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<main.PrefixRestoreStatus>;
+
+export function GetPrefixStats(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<main.PrefixStats>;
+
 export function GetProfile(arg1:string):Promise<main.OSSProfile>;
 
+export function GetProfileUsage(arg1:string,arg2:string,arg3:string):Promise<main.UsageReport>;
+
+export function GetProfilesLockStatus():Promise<main.ProfilesLockStatus>;
+
+export function GetProfilesSorted(arg1:string):Promise<Array<main.OSSProfile>>;
+
+export function GetSessionState(arg1:string):Promise<main.SessionState>;
+
 export function GetSettings():Promise<main.AppSettings>;
 
+export function GetStartLocation(arg1:string):Promise<main.StartLocation>;
+
+export function GetStoragePrices():Promise<Array<main.StoragePrice>>;
+
 export function GetTransferHistory():Promise<Array<main.TransferUpdate>>;
 
+export function GetUsageStats(arg1:number):Promise<Array<main.DailyUsage>>;
+
+export function ImportFromAliyunCLI(arg1:string):Promise<main.AliyunCLIImport>;
+
+export function ImportProfiles(arg1:string,arg2:boolean):Promise<main.ImportReport>;
+
+export function InstallOssutil(arg1:string):Promise<string>;
+
+export function ListBucketCname(arg1:main.OSSConfig,arg2:string):Promise<Array<main.CnameInfo>>;
+
+export function ListBucketInventory(arg1:main.OSSConfig,arg2:string):Promise<Array<main.InventoryConfigView>>;
+
 export function ListBuckets(arg1:main.OSSConfig):Promise<Array<main.BucketInfo>>;
 
+export function ListBucketsAllProfiles():Promise<Array<main.ProfileBuckets>>;
+
 export function ListObjects(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<Array<main.ObjectInfo>>;
 
 export function ListObjectsPage(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string,arg5:number):Promise<main.ObjectListPageResult>;
 
-export function LoadProfiles():Promise<Array<main.OSSProfile>>;
+export function ListProfileSummaries():Promise<Array<main.OSSProfile>>;
+
+export function LockProfiles():Promise<void>;
 
-export function MoveObject(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string,arg5:string):Promise<void>;
+export function MoveObject(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string,arg5:string):Promise<string>;
 
 export function PresignObject(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<string>;
 
 export function PutObjectText(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
 
-export function SaveProfile(arg1:main.OSSProfile):Promise<void>;
+export function QueryAuditLog(arg1:main.AuditFilter):Promise<Array<main.AuditEntry>>;
+
+export function QuickDownload(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<string>;
+
+export function RefreshBucketStats(arg1:main.OSSConfig,arg2:Array<string>):Promise<void>;
+
+export function RemovePinnedBucket(arg1:string,arg2:string):Promise<void>;
+
+export function RenameProfile(arg1:string,arg2:string):Promise<void>;
+
+export function RequestConfirmationToken(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<main.ConfirmationToken>;
+
+export function RequestDestructiveToken(arg1:string,arg2:string):Promise<string>;
+
+export function ResetUsageStats():Promise<void>;
+
+export function RestorePrefix(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:number,arg5:string):Promise<string>;
+
+export function RunIntegrityScan(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:main.ScanOptions):Promise<string>;
+
+export function SaveProfile(arg1:main.OSSProfile,arg2:boolean):Promise<void>;
+
+export function SaveSessionState(arg1:string,arg2:string,arg3:string):Promise<void>;
 
 export function SaveSettings(arg1:main.AppSettings):Promise<void>;
 
+export function SaveShareQRCode(arg1:string,arg2:number):Promise<string>;
+
+export function SaveStoragePrices(arg1:Array<main.StoragePrice>):Promise<void>;
+
+export function SearchObjects(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string,arg5:number):Promise<main.SearchObjectsResult>;
+
+export function SendTestNotification():Promise<void>;
+
+export function SetBucketCORS(arg1:main.OSSConfig,arg2:string,arg3:Array<main.CORSRuleView>,arg4:string):Promise<void>;
+
+export function SetBucketEncryption(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function SetBucketLifecycle(arg1:main.OSSConfig,arg2:string,arg3:Array<main.LifecycleRuleView>,arg4:string):Promise<void>;
+
+export function SetBucketPolicy(arg1:main.OSSConfig,arg2:string,arg3:string):Promise<void>;
+
+export function SetBucketReferer(arg1:main.OSSConfig,arg2:string,arg3:boolean,arg4:Array<string>,arg5:string):Promise<void>;
+
+export function SetBucketTagging(arg1:main.OSSConfig,arg2:string,arg3:Record<string, string>,arg4:string):Promise<void>;
+
+export function SetBucketTransferAcceleration(arg1:main.OSSConfig,arg2:string,arg3:boolean):Promise<void>;
+
+export function SetBucketVersioning(arg1:main.OSSConfig,arg2:string,arg3:boolean):Promise<main.BucketVersioningResult>;
+
 export function SetContext(arg1:context.Context):Promise<void>;
 
+export function SetMasterPassphrase(arg1:string):Promise<void>;
+
 export function SetOssutilPath(arg1:string):Promise<void>;
 
+export function SetProfileStartLocation(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function StopWatch(arg1:string):Promise<void>;
+
 export function TestConnection(arg1:main.OSSConfig):Promise<main.ConnectionResult>;
 
+export function TestConnectionForBucket(arg1:main.OSSConfig,arg2:string):Promise<main.ConnectionResult>;
+
+export function UndoLastOperation():Promise<main.UndoResult>;
+
+export function UnlockProfiles(arg1:string):Promise<void>;
+
 export function UploadFile(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function ValidateBucketName(arg1:string):Promise<void>;
+
+export function ValidateObjectKey(arg1:string):Promise<void>;
+
+export function ValidateProfile(arg1:main.OSSProfile):Promise<main.ProfileValidation>;
+
+export function VerifyAuditLog():Promise<main.AuditVerification>;
+
+export function WatchPrefix(arg1:main.OSSConfig,arg2:string,arg3:string,arg4:number):Promise<string>;
@@ -2,14 +2,46 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddPinnedBucket(arg1, arg2) {
+  return window['go']['main']['OSSService']['AddPinnedBucket'](arg1, arg2);
+}
+
+export function CanUndo() {
+  return window['go']['main']['OSSService']['CanUndo']();
+}
+
+export function CancelMoveJob(arg1) {
+  return window['go']['main']['OSSService']['CancelMoveJob'](arg1);
+}
+
+export function CancelOperation(arg1) {
+  return window['go']['main']['OSSService']['CancelOperation'](arg1);
+}
+
+export function CancelQueuedTransfer(arg1) {
+  return window['go']['main']['OSSService']['CancelQueuedTransfer'](arg1);
+}
+
+export function ChangeMasterPassphrase(arg1, arg2) {
+  return window['go']['main']['OSSService']['ChangeMasterPassphrase'](arg1, arg2);
+}
+
 export function CheckOssutilInstalled() {
   return window['go']['main']['OSSService']['CheckOssutilInstalled']();
 }
 
+export function CheckRegion(arg1) {
+  return window['go']['main']['OSSService']['CheckRegion'](arg1);
+}
+
 export function CheckUploadNameCollisions(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['CheckUploadNameCollisions'](arg1, arg2, arg3, arg4);
 }
 
+export function CleanupTempFiles(arg1) {
+  return window['go']['main']['OSSService']['CleanupTempFiles'](arg1);
+}
+
 export function CreateFile(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['CreateFile'](arg1, arg2, arg3, arg4);
 }
@@ -18,18 +50,46 @@ export function CreateFolder(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['CreateFolder'](arg1, arg2, arg3, arg4);
 }
 
-export function DeleteObject(arg1, arg2, arg3) {
-  return window['go']['main']['OSSService']['DeleteObject'](arg1, arg2, arg3);
+export function DeleteBucketCORS(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['DeleteBucketCORS'](arg1, arg2, arg3);
+}
+
+export function DeleteBucketEncryption(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['DeleteBucketEncryption'](arg1, arg2, arg3);
+}
+
+export function DeleteBucketInventory(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['DeleteBucketInventory'](arg1, arg2, arg3, arg4);
+}
+
+export function DeleteBucketPolicy(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['DeleteBucketPolicy'](arg1, arg2, arg3);
+}
+
+export function DeleteBucketTagging(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['DeleteBucketTagging'](arg1, arg2, arg3);
+}
+
+export function DeleteObject(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['DeleteObject'](arg1, arg2, arg3, arg4);
 }
 
 export function DeleteProfile(arg1) {
   return window['go']['main']['OSSService']['DeleteProfile'](arg1);
 }
 
+export function DisableEncryption(arg1) {
+  return window['go']['main']['OSSService']['DisableEncryption'](arg1);
+}
+
 export function DownloadFile(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['DownloadFile'](arg1, arg2, arg3, arg4);
 }
 
+export function DuplicateProfile(arg1, arg2) {
+  return window['go']['main']['OSSService']['DuplicateProfile'](arg1, arg2);
+}
+
 export function EnqueueDownload(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['OSSService']['EnqueueDownload'](arg1, arg2, arg3, arg4, arg5);
 }
@@ -38,6 +98,10 @@ export function EnqueueDownloadFolder(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['EnqueueDownloadFolder'](arg1, arg2, arg3, arg4);
 }
 
+export function EnqueueDownloadsFromPaths(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['EnqueueDownloadsFromPaths'](arg1, arg2, arg3);
+}
+
 export function EnqueueUpload(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['EnqueueUpload'](arg1, arg2, arg3, arg4);
 }
@@ -50,34 +114,202 @@ export function EnqueueUploadRoots(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['EnqueueUploadRoots'](arg1, arg2, arg3, arg4);
 }
 
+export function EstimateStorageCost(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['EstimateStorageCost'](arg1, arg2, arg3);
+}
+
+export function ExportIntegrityReportCSV(arg1, arg2) {
+  return window['go']['main']['OSSService']['ExportIntegrityReportCSV'](arg1, arg2);
+}
+
+export function ExportProfiles(arg1, arg2) {
+  return window['go']['main']['OSSService']['ExportProfiles'](arg1, arg2);
+}
+
+export function ExportUsageCSV(arg1) {
+  return window['go']['main']['OSSService']['ExportUsageCSV'](arg1);
+}
+
+export function GenerateIndexPage(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['GenerateIndexPage'](arg1, arg2, arg3, arg4);
+}
+
+export function GenerateShareQRCode(arg1, arg2) {
+  return window['go']['main']['OSSService']['GenerateShareQRCode'](arg1, arg2);
+}
+
+export function GetAvailableLanguages() {
+  return window['go']['main']['OSSService']['GetAvailableLanguages']();
+}
+
+export function GetBucketCORS(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketCORS'](arg1, arg2);
+}
+
+export function GetBucketEncryption(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketEncryption'](arg1, arg2);
+}
+
+export function GetBucketInfo(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetBucketInfo'](arg1, arg2, arg3);
+}
+
+export function GetBucketLifecycle(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketLifecycle'](arg1, arg2);
+}
+
+export function GetBucketPolicy(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketPolicy'](arg1, arg2);
+}
+
+export function GetBucketReferer(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketReferer'](arg1, arg2);
+}
+
+export function GetBucketReplication(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketReplication'](arg1, arg2);
+}
+
+export function GetBucketReplicationProgress(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetBucketReplicationProgress'](arg1, arg2, arg3);
+}
+
+export function GetBucketStat(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetBucketStat'](arg1, arg2, arg3);
+}
+
+export function GetBucketTagging(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketTagging'](arg1, arg2);
+}
+
+export function GetBucketTransferAcceleration(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketTransferAcceleration'](arg1, arg2);
+}
+
+export function GetBucketVersioning(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketVersioning'](arg1, arg2);
+}
+
+export function GetBucketWorm(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetBucketWorm'](arg1, arg2);
+}
+
+export function GetCachedBucketStats(arg1, arg2) {
+  return window['go']['main']['OSSService']['GetCachedBucketStats'](arg1, arg2);
+}
+
+export function GetConfigRecoveries() {
+  return window['go']['main']['OSSService']['GetConfigRecoveries']();
+}
+
 export function GetDefaultProfile() {
   return window['go']['main']['OSSService']['GetDefaultProfile']();
 }
 
+export function GetEffectiveTheme() {
+  return window['go']['main']['OSSService']['GetEffectiveTheme']();
+}
+
+export function GetIntegrityReport(arg1) {
+  return window['go']['main']['OSSService']['GetIntegrityReport'](arg1);
+}
+
+export function GetKnownRegions() {
+  return window['go']['main']['OSSService']['GetKnownRegions']();
+}
+
 export function GetObjectText(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['GetObjectText'](arg1, arg2, arg3, arg4);
 }
 
+export function GetObjectURL(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['GetObjectURL'](arg1, arg2, arg3, arg4);
+}
+
 export function GetOssutilPath() {
   return window['go']['main']['OSSService']['GetOssutilPath']();
 }
 
+export function GetPendingOperations() {
+  return window['go']['main']['OSSService']['GetPendingOperations']();
+}
+
+export function GetPrefixRestoreStatus(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetPrefixRestoreStatus'](arg1, arg2, arg3);
+}
+
+export function GetPrefixStats(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetPrefixStats'](arg1, arg2, arg3);
+}
+
 export function GetProfile(arg1) {
   return window['go']['main']['OSSService']['GetProfile'](arg1);
 }
 
+export function GetProfileUsage(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['GetProfileUsage'](arg1, arg2, arg3);
+}
+
+export function GetProfilesLockStatus() {
+  return window['go']['main']['OSSService']['GetProfilesLockStatus']();
+}
+
+export function GetProfilesSorted(arg1) {
+  return window['go']['main']['OSSService']['GetProfilesSorted'](arg1);
+}
+
+export function GetSessionState(arg1) {
+  return window['go']['main']['OSSService']['GetSessionState'](arg1);
+}
+
 export function GetSettings() {
   return window['go']['main']['OSSService']['GetSettings']();
 }
 
+export function GetStartLocation(arg1) {
+  return window['go']['main']['OSSService']['GetStartLocation'](arg1);
+}
+
+export function GetStoragePrices() {
+  return window['go']['main']['OSSService']['GetStoragePrices']();
+}
+
 export function GetTransferHistory() {
   return window['go']['main']['OSSService']['GetTransferHistory']();
 }
 
+export function GetUsageStats(arg1) {
+  return window['go']['main']['OSSService']['GetUsageStats'](arg1);
+}
+
+export function ImportFromAliyunCLI(arg1) {
+  return window['go']['main']['OSSService']['ImportFromAliyunCLI'](arg1);
+}
+
+export function ImportProfiles(arg1, arg2) {
+  return window['go']['main']['OSSService']['ImportProfiles'](arg1, arg2);
+}
+
+export function InstallOssutil(arg1) {
+  return window['go']['main']['OSSService']['InstallOssutil'](arg1);
+}
+
+export function ListBucketCname(arg1, arg2) {
+  return window['go']['main']['OSSService']['ListBucketCname'](arg1, arg2);
+}
+
+export function ListBucketInventory(arg1, arg2) {
+  return window['go']['main']['OSSService']['ListBucketInventory'](arg1, arg2);
+}
+
 export function ListBuckets(arg1) {
   return window['go']['main']['OSSService']['ListBuckets'](arg1);
 }
 
+export function ListBucketsAllProfiles() {
+  return window['go']['main']['OSSService']['ListBucketsAllProfiles']();
+}
+
 export function ListObjects(arg1, arg2, arg3) {
   return window['go']['main']['OSSService']['ListObjects'](arg1, arg2, arg3);
 }
@@ -86,8 +318,12 @@ export function ListObjectsPage(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['OSSService']['ListObjectsPage'](arg1, arg2, arg3, arg4, arg5);
 }
 
-export function LoadProfiles() {
-  return window['go']['main']['OSSService']['LoadProfiles']();
+export function ListProfileSummaries() {
+  return window['go']['main']['OSSService']['ListProfileSummaries']();
+}
+
+export function LockProfiles() {
+  return window['go']['main']['OSSService']['LockProfiles']();
 }
 
 export function MoveObject(arg1, arg2, arg3, arg4, arg5) {
@@ -102,26 +338,162 @@ export function PutObjectText(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['PutObjectText'](arg1, arg2, arg3, arg4);
 }
 
-export function SaveProfile(arg1) {
-  return window['go']['main']['OSSService']['SaveProfile'](arg1);
+export function QueryAuditLog(arg1) {
+  return window['go']['main']['OSSService']['QueryAuditLog'](arg1);
+}
+
+export function QuickDownload(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['QuickDownload'](arg1, arg2, arg3);
+}
+
+export function RefreshBucketStats(arg1, arg2) {
+  return window['go']['main']['OSSService']['RefreshBucketStats'](arg1, arg2);
+}
+
+export function RemovePinnedBucket(arg1, arg2) {
+  return window['go']['main']['OSSService']['RemovePinnedBucket'](arg1, arg2);
+}
+
+export function RenameProfile(arg1, arg2) {
+  return window['go']['main']['OSSService']['RenameProfile'](arg1, arg2);
+}
+
+export function RequestConfirmationToken(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['RequestConfirmationToken'](arg1, arg2, arg3);
+}
+
+export function RequestDestructiveToken(arg1, arg2) {
+  return window['go']['main']['OSSService']['RequestDestructiveToken'](arg1, arg2);
+}
+
+export function ResetUsageStats() {
+  return window['go']['main']['OSSService']['ResetUsageStats']();
+}
+
+export function RestorePrefix(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['OSSService']['RestorePrefix'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function RunIntegrityScan(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['RunIntegrityScan'](arg1, arg2, arg3, arg4);
+}
+
+export function SaveProfile(arg1, arg2) {
+  return window['go']['main']['OSSService']['SaveProfile'](arg1, arg2);
+}
+
+export function SaveSessionState(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['SaveSessionState'](arg1, arg2, arg3);
 }
 
 export function SaveSettings(arg1) {
   return window['go']['main']['OSSService']['SaveSettings'](arg1);
 }
 
+export function SaveShareQRCode(arg1, arg2) {
+  return window['go']['main']['OSSService']['SaveShareQRCode'](arg1, arg2);
+}
+
+export function SaveStoragePrices(arg1) {
+  return window['go']['main']['OSSService']['SaveStoragePrices'](arg1);
+}
+
+export function SearchObjects(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['OSSService']['SearchObjects'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SendTestNotification() {
+  return window['go']['main']['OSSService']['SendTestNotification']();
+}
+
+export function SetBucketCORS(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['SetBucketCORS'](arg1, arg2, arg3, arg4);
+}
+
+export function SetBucketEncryption(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['SetBucketEncryption'](arg1, arg2, arg3, arg4);
+}
+
+export function SetBucketLifecycle(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['SetBucketLifecycle'](arg1, arg2, arg3, arg4);
+}
+
+export function SetBucketPolicy(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['SetBucketPolicy'](arg1, arg2, arg3);
+}
+
+export function SetBucketReferer(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['OSSService']['SetBucketReferer'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function SetBucketTagging(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['SetBucketTagging'](arg1, arg2, arg3, arg4);
+}
+
+export function SetBucketTransferAcceleration(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['SetBucketTransferAcceleration'](arg1, arg2, arg3);
+}
+
+export function SetBucketVersioning(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['SetBucketVersioning'](arg1, arg2, arg3);
+}
+
 export function SetContext(arg1) {
   return window['go']['main']['OSSService']['SetContext'](arg1);
 }
 
+export function SetMasterPassphrase(arg1) {
+  return window['go']['main']['OSSService']['SetMasterPassphrase'](arg1);
+}
+
 export function SetOssutilPath(arg1) {
   return window['go']['main']['OSSService']['SetOssutilPath'](arg1);
 }
 
+export function SetProfileStartLocation(arg1, arg2, arg3) {
+  return window['go']['main']['OSSService']['SetProfileStartLocation'](arg1, arg2, arg3);
+}
+
+export function StopWatch(arg1) {
+  return window['go']['main']['OSSService']['StopWatch'](arg1);
+}
+
 export function TestConnection(arg1) {
   return window['go']['main']['OSSService']['TestConnection'](arg1);
 }
 
+export function TestConnectionForBucket(arg1, arg2) {
+  return window['go']['main']['OSSService']['TestConnectionForBucket'](arg1, arg2);
+}
+
+export function UndoLastOperation() {
+  return window['go']['main']['OSSService']['UndoLastOperation']();
+}
+
+export function UnlockProfiles(arg1) {
+  return window['go']['main']['OSSService']['UnlockProfiles'](arg1);
+}
+
 export function UploadFile(arg1, arg2, arg3, arg4) {
   return window['go']['main']['OSSService']['UploadFile'](arg1, arg2, arg3, arg4);
 }
+
+export function ValidateBucketName(arg1) {
+  return window['go']['main']['OSSService']['ValidateBucketName'](arg1);
+}
+
+export function ValidateObjectKey(arg1) {
+  return window['go']['main']['OSSService']['ValidateObjectKey'](arg1);
+}
+
+export function ValidateProfile(arg1) {
+  return window['go']['main']['OSSService']['ValidateProfile'](arg1);
+}
+
+export function VerifyAuditLog() {
+  return window['go']['main']['OSSService']['VerifyAuditLog']();
+}
+
+export function WatchPrefix(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['OSSService']['WatchPrefix'](arg1, arg2, arg3, arg4);
+}
@@ -1,111 +1,1542 @@
 export namespace main {
 	
+	export class AliyunCLISkipped {
+	    name: string;
+	    mode: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AliyunCLISkipped(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.mode = source["mode"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class OSSConfig {
+	    provider?: string;
+	    ecsRoleName?: string;
+	    accessKeyId: string;
+	    accessKeySecret: string;
+	    region: string;
+	    endpoint: string;
+	    defaultPath: string;
+	    useAccelerateEndpoint?: boolean;
+	    useInternalEndpoint?: boolean;
+	    useDualStackEndpoint?: boolean;
+	    securityToken?: string;
+	    roleArn?: string;
+	    roleSessionName?: string;
+	    sourceProfile?: string;
+	    proxyUrl?: string;
+	    proxyUser?: string;
+	    proxyPassword?: string;
+	    allowInsecureHttp?: boolean;
+	    caCertFile?: string;
+	    insecureSkipVerify?: boolean;
+	    cnameDomain?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OSSConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.provider = source["provider"];
+	        this.ecsRoleName = source["ecsRoleName"];
+	        this.accessKeyId = source["accessKeyId"];
+	        this.accessKeySecret = source["accessKeySecret"];
+	        this.region = source["region"];
+	        this.endpoint = source["endpoint"];
+	        this.defaultPath = source["defaultPath"];
+	        this.useAccelerateEndpoint = source["useAccelerateEndpoint"];
+	        this.useInternalEndpoint = source["useInternalEndpoint"];
+	        this.useDualStackEndpoint = source["useDualStackEndpoint"];
+	        this.securityToken = source["securityToken"];
+	        this.roleArn = source["roleArn"];
+	        this.roleSessionName = source["roleSessionName"];
+	        this.sourceProfile = source["sourceProfile"];
+	        this.proxyUrl = source["proxyUrl"];
+	        this.proxyUser = source["proxyUser"];
+	        this.proxyPassword = source["proxyPassword"];
+	        this.allowInsecureHttp = source["allowInsecureHttp"];
+	        this.caCertFile = source["caCertFile"];
+	        this.insecureSkipVerify = source["insecureSkipVerify"];
+	        this.cnameDomain = source["cnameDomain"];
+	    }
+	}
+	export class OSSProfile {
+	    name: string;
+	    config: OSSConfig;
+	    isDefault: boolean;
+	    secretRef?: string;
+	    lastUsedAtMs?: number;
+	    pinnedBuckets?: string[];
+	    defaultBucket?: string;
+	    defaultPrefix?: string;
+	    color?: string;
+	    notes?: string;
+	    readOnlyHint?: boolean;
+	    readOnly?: boolean;
+	    hasSecret?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new OSSProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.config = this.convertValues(source["config"], OSSConfig);
+	        this.isDefault = source["isDefault"];
+	        this.secretRef = source["secretRef"];
+	        this.lastUsedAtMs = source["lastUsedAtMs"];
+	        this.pinnedBuckets = source["pinnedBuckets"];
+	        this.defaultBucket = source["defaultBucket"];
+	        this.defaultPrefix = source["defaultPrefix"];
+	        this.color = source["color"];
+	        this.notes = source["notes"];
+	        this.readOnlyHint = source["readOnlyHint"];
+	        this.readOnly = source["readOnly"];
+	        this.hasSecret = source["hasSecret"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AliyunCLIImport {
+	    profiles: OSSProfile[];
+	    skipped: AliyunCLISkipped[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AliyunCLIImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profiles = this.convertValues(source["profiles"], OSSProfile);
+	        this.skipped = this.convertValues(source["skipped"], AliyunCLISkipped);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class AppInfo {
 	    name: string;
 	    version: string;
 	    githubUrl: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AppInfo(source);
+	        return new AppInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.githubUrl = source["githubUrl"];
+	    }
+	}
+	export class StoragePrice {
+	    region: string;
+	    storageClass: string;
+	    pricePerGbMonth: number;
+	    currency: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new StoragePrice(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.region = source["region"];
+	        this.storageClass = source["storageClass"];
+	        this.pricePerGbMonth = source["pricePerGbMonth"];
+	        this.currency = source["currency"];
+	    }
+	}
+	export class NotificationSettings {
+	    transferComplete: boolean;
+	    transferFailed: boolean;
+	    jobComplete: boolean;
+	    doNotDisturb: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.transferComplete = source["transferComplete"];
+	        this.transferFailed = source["transferFailed"];
+	        this.jobComplete = source["jobComplete"];
+	        this.doNotDisturb = source["doNotDisturb"];
+	    }
+	}
+	export class AppSettings {
+	    ossutilPath: string;
+	    workDir: string;
+	    defaultRegion: string;
+	    defaultEndpoint: string;
+	    theme: string;
+	    maxTransferThreads: number;
+	    newTabNameRule: string;
+	    fileListViewMode: string;
+	    credentialStorage: string;
+	    profileLockMinutes: number;
+	    proxyUrl?: string;
+	    proxyUser?: string;
+	    proxyPassword?: string;
+	    defaultDownloadDir?: string;
+	    ossutilExtraArgs?: string[];
+	    sdkConnectTimeoutSec: number;
+	    sdkReadWriteTimeoutSec: number;
+	    sdkMaxRetries: number;
+	    logLevel: string;
+	    language: string;
+	    notifications: NotificationSettings;
+	    skipDestructiveConfirmation?: boolean;
+	    progressEmitIntervalMs: number;
+	    skipDiskSpaceCheck?: boolean;
+	    ossutilTimeoutSec: number;
+	    tlsCaCertFile?: string;
+	    tlsInsecureSkipVerify?: boolean;
+	    storagePrices?: StoragePrice[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AppSettings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ossutilPath = source["ossutilPath"];
+	        this.workDir = source["workDir"];
+	        this.defaultRegion = source["defaultRegion"];
+	        this.defaultEndpoint = source["defaultEndpoint"];
+	        this.theme = source["theme"];
+	        this.maxTransferThreads = source["maxTransferThreads"];
+	        this.newTabNameRule = source["newTabNameRule"];
+	        this.fileListViewMode = source["fileListViewMode"];
+	        this.credentialStorage = source["credentialStorage"];
+	        this.profileLockMinutes = source["profileLockMinutes"];
+	        this.proxyUrl = source["proxyUrl"];
+	        this.proxyUser = source["proxyUser"];
+	        this.proxyPassword = source["proxyPassword"];
+	        this.defaultDownloadDir = source["defaultDownloadDir"];
+	        this.ossutilExtraArgs = source["ossutilExtraArgs"];
+	        this.sdkConnectTimeoutSec = source["sdkConnectTimeoutSec"];
+	        this.sdkReadWriteTimeoutSec = source["sdkReadWriteTimeoutSec"];
+	        this.sdkMaxRetries = source["sdkMaxRetries"];
+	        this.logLevel = source["logLevel"];
+	        this.language = source["language"];
+	        this.notifications = this.convertValues(source["notifications"], NotificationSettings);
+	        this.skipDestructiveConfirmation = source["skipDestructiveConfirmation"];
+	        this.progressEmitIntervalMs = source["progressEmitIntervalMs"];
+	        this.skipDiskSpaceCheck = source["skipDiskSpaceCheck"];
+	        this.ossutilTimeoutSec = source["ossutilTimeoutSec"];
+	        this.tlsCaCertFile = source["tlsCaCertFile"];
+	        this.tlsInsecureSkipVerify = source["tlsInsecureSkipVerify"];
+	        this.storagePrices = this.convertValues(source["storagePrices"], StoragePrice);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AuditEntry {
+	    time: string;
+	    profile: string;
+	    operation: string;
+	    bucket: string;
+	    target: string;
+	    objectCount: number;
+	    outcome: string;
+	    error?: string;
+	    prevHash: string;
+	    hash: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.profile = source["profile"];
+	        this.operation = source["operation"];
+	        this.bucket = source["bucket"];
+	        this.target = source["target"];
+	        this.objectCount = source["objectCount"];
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.prevHash = source["prevHash"];
+	        this.hash = source["hash"];
+	    }
+	}
+	export class AuditFilter {
+	    fromMs?: number;
+	    toMs?: number;
+	    bucket?: string;
+	    operations?: string[];
+	    limit?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditFilter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fromMs = source["fromMs"];
+	        this.toMs = source["toMs"];
+	        this.bucket = source["bucket"];
+	        this.operations = source["operations"];
+	        this.limit = source["limit"];
+	    }
+	}
+	export class AuditVerification {
+	    intact: boolean;
+	    entries: number;
+	    brokenAt?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AuditVerification(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.intact = source["intact"];
+	        this.entries = source["entries"];
+	        this.brokenAt = source["brokenAt"];
+	        this.message = source["message"];
+	    }
+	}
+	export class BatchLineResult {
+	    line: number;
+	    input: string;
+	    outcome: string;
+	    transferId?: string;
+	    localPath?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchLineResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.input = source["input"];
+	        this.outcome = source["outcome"];
+	        this.transferId = source["transferId"];
+	        this.localPath = source["localPath"];
+	        this.message = source["message"];
+	    }
+	}
+	export class BatchEnqueueResult {
+	    lines: BatchLineResult[];
+	    queued: number;
+	    failed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchEnqueueResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lines = this.convertValues(source["lines"], BatchLineResult);
+	        this.queued = source["queued"];
+	        this.failed = source["failed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class EncryptionConfigView {
+	    configured: boolean;
+	    algorithm?: string;
+	    kmsMasterKeyId?: string;
+	    kmsDataEncryption?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new EncryptionConfigView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.configured = source["configured"];
+	        this.algorithm = source["algorithm"];
+	        this.kmsMasterKeyId = source["kmsMasterKeyId"];
+	        this.kmsDataEncryption = source["kmsDataEncryption"];
+	    }
+	}
+	export class BucketDetail {
+	    name: string;
+	    region: string;
+	    creationDate: string;
+	    extranetEndpoint: string;
+	    intranetEndpoint: string;
+	    acl: string;
+	    storageClass: string;
+	    redundancyType: string;
+	    versioning: string;
+	    transferAcceleration: string;
+	    crossRegionReplication: string;
+	    owner: string;
+	    encryption: EncryptionConfigView;
+	    tags?: Record<string, string>;
+	    tagsError?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketDetail(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.region = source["region"];
+	        this.creationDate = source["creationDate"];
+	        this.extranetEndpoint = source["extranetEndpoint"];
+	        this.intranetEndpoint = source["intranetEndpoint"];
+	        this.acl = source["acl"];
+	        this.storageClass = source["storageClass"];
+	        this.redundancyType = source["redundancyType"];
+	        this.versioning = source["versioning"];
+	        this.transferAcceleration = source["transferAcceleration"];
+	        this.crossRegionReplication = source["crossRegionReplication"];
+	        this.owner = source["owner"];
+	        this.encryption = this.convertValues(source["encryption"], EncryptionConfigView);
+	        this.tags = source["tags"];
+	        this.tagsError = source["tagsError"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BucketInfo {
+	    name: string;
+	    region: string;
+	    creationDate: string;
+	    storageClass?: string;
+	    profile?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.region = source["region"];
+	        this.creationDate = source["creationDate"];
+	        this.storageClass = source["storageClass"];
+	        this.profile = source["profile"];
+	    }
+	}
+	export class BucketInfoOptions {
+	    includeTags: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketInfoOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.includeTags = source["includeTags"];
+	    }
+	}
+	export class BucketStorageClassStat {
+	    storageClass: string;
+	    storage: number;
+	    realStorage?: number;
+	    objectCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketStorageClassStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.storageClass = source["storageClass"];
+	        this.storage = source["storage"];
+	        this.realStorage = source["realStorage"];
+	        this.objectCount = source["objectCount"];
+	    }
+	}
+	export class BucketStat {
+	    bucket: string;
+	    storage: number;
+	    objectCount: number;
+	    multipartUploadCount: number;
+	    liveChannelCount: number;
+	    storageClasses: BucketStorageClassStat[];
+	    lastModifiedTime: string;
+	    lastModifiedAtMs?: number;
+	    fetchedAtMs: number;
+	    cached: boolean;
+	    unavailable?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.storage = source["storage"];
+	        this.objectCount = source["objectCount"];
+	        this.multipartUploadCount = source["multipartUploadCount"];
+	        this.liveChannelCount = source["liveChannelCount"];
+	        this.storageClasses = this.convertValues(source["storageClasses"], BucketStorageClassStat);
+	        this.lastModifiedTime = source["lastModifiedTime"];
+	        this.lastModifiedAtMs = source["lastModifiedAtMs"];
+	        this.fetchedAtMs = source["fetchedAtMs"];
+	        this.cached = source["cached"];
+	        this.unavailable = source["unavailable"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BucketVersioningResult {
+	    status: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BucketVersioningResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.status = source["status"];
+	        this.message = source["message"];
+	    }
+	}
+	export class CORSRuleView {
+	    allowedOrigins: string[];
+	    allowedMethods: string[];
+	    allowedHeaders?: string[];
+	    exposeHeaders?: string[];
+	    maxAgeSeconds?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CORSRuleView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.allowedOrigins = source["allowedOrigins"];
+	        this.allowedMethods = source["allowedMethods"];
+	        this.allowedHeaders = source["allowedHeaders"];
+	        this.exposeHeaders = source["exposeHeaders"];
+	        this.maxAgeSeconds = source["maxAgeSeconds"];
+	    }
+	}
+	export class CnameInfo {
+	    domain: string;
+	    status: string;
+	    lastModified: string;
+	    certificateType?: string;
+	    certificateId?: string;
+	    certificateStatus?: string;
+	    certificateExpiry?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CnameInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.domain = source["domain"];
+	        this.status = source["status"];
+	        this.lastModified = source["lastModified"];
+	        this.certificateType = source["certificateType"];
+	        this.certificateId = source["certificateId"];
+	        this.certificateStatus = source["certificateStatus"];
+	        this.certificateExpiry = source["certificateExpiry"];
+	    }
+	}
+	export class ConfigRecovery {
+	    file: string;
+	    movedTo: string;
+	    restoredFrom?: string;
+	    error: string;
+	    message: string;
+	    atMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigRecovery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.movedTo = source["movedTo"];
+	        this.restoredFrom = source["restoredFrom"];
+	        this.error = source["error"];
+	        this.message = source["message"];
+	        this.atMs = source["atMs"];
+	    }
+	}
+	export class ConfirmationToken {
+	    token: string;
+	    operation: string;
+	    target: string;
+	    description: string;
+	    estimatedObjects: number;
+	    estimateComplete: boolean;
+	    expiresAtMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfirmationToken(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token = source["token"];
+	        this.operation = source["operation"];
+	        this.target = source["target"];
+	        this.description = source["description"];
+	        this.estimatedObjects = source["estimatedObjects"];
+	        this.estimateComplete = source["estimateComplete"];
+	        this.expiresAtMs = source["expiresAtMs"];
+	    }
+	}
+	export class OssutilVersion {
+	    major: number;
+	    minor: number;
+	    patch: number;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OssutilVersion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.major = source["major"];
+	        this.minor = source["minor"];
+	        this.patch = source["patch"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class ConnectionResult {
+	    success: boolean;
+	    message: string;
+	    credentialSource?: string;
+	    tlsVerificationDisabled?: boolean;
+	    errorCode?: string;
+	    hint?: string;
+	    clockSkewSec?: number;
+	    endpoint?: string;
+	    addressFamily?: string;
+	    installPath?: string;
+	    ossutilVersion?: OssutilVersion;
+	    unsupported?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConnectionResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.success = source["success"];
+	        this.message = source["message"];
+	        this.credentialSource = source["credentialSource"];
+	        this.tlsVerificationDisabled = source["tlsVerificationDisabled"];
+	        this.errorCode = source["errorCode"];
+	        this.hint = source["hint"];
+	        this.clockSkewSec = source["clockSkewSec"];
+	        this.endpoint = source["endpoint"];
+	        this.addressFamily = source["addressFamily"];
+	        this.installPath = source["installPath"];
+	        this.ossutilVersion = this.convertValues(source["ossutilVersion"], OssutilVersion);
+	        this.unsupported = source["unsupported"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StorageClassCost {
+	    storageClass: string;
+	    bytes: number;
+	    objectCount: number;
+	    priced: boolean;
+	    pricePerGbMonth: number;
+	    monthlyCost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StorageClassCost(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.storageClass = source["storageClass"];
+	        this.bytes = source["bytes"];
+	        this.objectCount = source["objectCount"];
+	        this.priced = source["priced"];
+	        this.pricePerGbMonth = source["pricePerGbMonth"];
+	        this.monthlyCost = source["monthlyCost"];
+	    }
+	}
+	export class CostEstimate {
+	    bucket: string;
+	    prefix: string;
+	    region: string;
+	    currency: string;
+	    classes: StorageClassCost[];
+	    monthlyTotal: number;
+	    disclaimer: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CostEstimate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.region = source["region"];
+	        this.currency = source["currency"];
+	        this.classes = this.convertValues(source["classes"], StorageClassCost);
+	        this.monthlyTotal = source["monthlyTotal"];
+	        this.disclaimer = source["disclaimer"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DailyUsage {
+	    date: string;
+	    uploadedBytes: number;
+	    downloadedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DailyUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.uploadedBytes = source["uploadedBytes"];
+	        this.downloadedBytes = source["downloadedBytes"];
+	    }
+	}
+	export class DeleteProfileResult {
+	    newDefault?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeleteProfileResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.newDefault = source["newDefault"];
+	    }
+	}
+	
+	export class HealthCheckResult {
+	    name: string;
+	    status: string;
+	    durationMs: number;
+	    message?: string;
+	    hint?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new HealthCheckResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.status = source["status"];
+	        this.durationMs = source["durationMs"];
+	        this.message = source["message"];
+	        this.hint = source["hint"];
+	    }
+	}
+	export class ImportReport {
+	    added: string[];
+	    updated: string[];
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	export class IndexOptions {
+	    title?: string;
+	    depth?: number;
+	    signedLinks?: boolean;
+	    linkExpirySec?: number;
+	    dryRun?: boolean;
+	    overwrite?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new IndexOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.depth = source["depth"];
+	        this.signedLinks = source["signedLinks"];
+	        this.linkExpirySec = source["linkExpirySec"];
+	        this.dryRun = source["dryRun"];
+	        this.overwrite = source["overwrite"];
+	    }
+	}
+	export class IntegrityFinding {
+	    key: string;
+	    issue: string;
+	    detail: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntegrityFinding(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.issue = source["issue"];
+	        this.detail = source["detail"];
+	        this.size = source["size"];
+	    }
+	}
+	export class IntegrityReport {
+	    id: string;
+	    bucket: string;
+	    prefix: string;
+	    status: string;
+	    scanned: number;
+	    counts: Record<string, number>;
+	    findings: IntegrityFinding[];
+	    truncated: boolean;
+	    headRequests: number;
+	    headLimitReached: boolean;
+	    message?: string;
+	    startedAtMs: number;
+	    finishedAtMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IntegrityReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.status = source["status"];
+	        this.scanned = source["scanned"];
+	        this.counts = source["counts"];
+	        this.findings = this.convertValues(source["findings"], IntegrityFinding);
+	        this.truncated = source["truncated"];
+	        this.headRequests = source["headRequests"];
+	        this.headLimitReached = source["headLimitReached"];
+	        this.message = source["message"];
+	        this.startedAtMs = source["startedAtMs"];
+	        this.finishedAtMs = source["finishedAtMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InventoryConfigView {
+	    id: string;
+	    enabled: boolean;
+	    prefix?: string;
+	    frequency: string;
+	    includedObjectVersions: string;
+	    destinationBucket: string;
+	    destinationPrefix?: string;
+	    destinationFormat: string;
+	    destinationAccountId?: string;
+	    destinationEncryption?: string;
+	    optionalFields: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InventoryConfigView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.enabled = source["enabled"];
+	        this.prefix = source["prefix"];
+	        this.frequency = source["frequency"];
+	        this.includedObjectVersions = source["includedObjectVersions"];
+	        this.destinationBucket = source["destinationBucket"];
+	        this.destinationPrefix = source["destinationPrefix"];
+	        this.destinationFormat = source["destinationFormat"];
+	        this.destinationAccountId = source["destinationAccountId"];
+	        this.destinationEncryption = source["destinationEncryption"];
+	        this.optionalFields = source["optionalFields"];
+	    }
+	}
+	export class LanguageInfo {
+	    code: string;
+	    name: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LanguageInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.name = source["name"];
+	    }
+	}
+	export class LaunchRequest {
+	    ossPaths: string[];
+	    files: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new LaunchRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ossPaths = source["ossPaths"];
+	        this.files = source["files"];
+	    }
+	}
+	export class LifecycleTransitionView {
+	    days?: number;
+	    createdBeforeDate?: string;
+	    storageClass: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LifecycleTransitionView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.days = source["days"];
+	        this.createdBeforeDate = source["createdBeforeDate"];
+	        this.storageClass = source["storageClass"];
+	    }
+	}
+	export class LifecycleRuleView {
+	    id: string;
+	    prefix: string;
+	    tags?: Record<string, string>;
+	    status: string;
+	    expirationDays?: number;
+	    expirationDate?: string;
+	    transitions?: LifecycleTransitionView[];
+	    abortMultipartDays?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LifecycleRuleView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.prefix = source["prefix"];
+	        this.tags = source["tags"];
+	        this.status = source["status"];
+	        this.expirationDays = source["expirationDays"];
+	        this.expirationDate = source["expirationDate"];
+	        this.transitions = this.convertValues(source["transitions"], LifecycleTransitionView);
+	        this.abortMultipartDays = source["abortMultipartDays"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	export class ObjectInfo {
+	    name: string;
+	    path: string;
+	    size: number;
+	    type: string;
+	    lastModified: string;
+	    storageClass: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ObjectInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.type = source["type"];
+	        this.lastModified = source["lastModified"];
+	        this.storageClass = source["storageClass"];
+	    }
+	}
+	export class ObjectListPageResult {
+	    items: ObjectInfo[];
+	    nextMarker: string;
+	    isTruncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ObjectListPageResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], ObjectInfo);
+	        this.nextMarker = source["nextMarker"];
+	        this.isTruncated = source["isTruncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ObjectURLOptions {
+	    useCustomDomain: boolean;
+	    domain?: string;
+	    signed: boolean;
+	    expires?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ObjectURLOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.useCustomDomain = source["useCustomDomain"];
+	        this.domain = source["domain"];
+	        this.signed = source["signed"];
+	        this.expires = source["expires"];
+	    }
+	}
+	export class ObjectURLResult {
+	    url: string;
+	    domain: string;
+	    domains: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ObjectURLResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.domain = source["domain"];
+	        this.domains = source["domains"];
+	    }
+	}
+	export class OperationLogEntry {
+	    time: string;
+	    level: string;
+	    method: string;
+	    bucket?: string;
+	    key?: string;
+	    durationMs: number;
+	    outcome: string;
+	    error?: string;
+	    errorCode?: string;
+	    requestId?: string;
+	    detail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new OperationLogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = source["time"];
+	        this.level = source["level"];
+	        this.method = source["method"];
+	        this.bucket = source["bucket"];
+	        this.key = source["key"];
+	        this.durationMs = source["durationMs"];
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.errorCode = source["errorCode"];
+	        this.requestId = source["requestId"];
+	        this.detail = source["detail"];
+	    }
+	}
+	
+	export class PendingOperation {
+	    id: string;
+	    command: string;
+	    startedAtMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingOperation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.command = source["command"];
+	        this.startedAtMs = source["startedAtMs"];
+	    }
+	}
+	export class PrefixRestoreStatus {
+	    bucket: string;
+	    prefix: string;
+	    archived: number;
+	    ready: number;
+	    inProgress: number;
+	    notRestored: number;
+	    sampled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PrefixRestoreStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.archived = source["archived"];
+	        this.ready = source["ready"];
+	        this.inProgress = source["inProgress"];
+	        this.notRestored = source["notRestored"];
+	        this.sampled = source["sampled"];
+	    }
+	}
+	export class PrefixStats {
+	    bucket: string;
+	    prefix: string;
+	    storage: number;
+	    objectCount: number;
+	    storageClasses: BucketStorageClassStat[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PrefixStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.storage = source["storage"];
+	        this.objectCount = source["objectCount"];
+	        this.storageClasses = this.convertValues(source["storageClasses"], BucketStorageClassStat);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfileBuckets {
+	    profile: string;
+	    buckets: BucketInfo[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileBuckets(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.version = source["version"];
-	        this.githubUrl = source["githubUrl"];
+	        this.profile = source["profile"];
+	        this.buckets = this.convertValues(source["buckets"], BucketInfo);
+	        this.error = source["error"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class AppSettings {
-	    ossutilPath: string;
-	    workDir: string;
-	    defaultRegion: string;
-	    defaultEndpoint: string;
-	    theme: string;
-	    maxTransferThreads: number;
-	    newTabNameRule: string;
-	    fileListViewMode: string;
+	export class ProfileDailyUsage {
+	    date: string;
+	    profile?: string;
+	    uploadedBytes: number;
+	    downloadedBytes: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new AppSettings(source);
+	        return new ProfileDailyUsage(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.ossutilPath = source["ossutilPath"];
-	        this.workDir = source["workDir"];
-	        this.defaultRegion = source["defaultRegion"];
-	        this.defaultEndpoint = source["defaultEndpoint"];
-	        this.theme = source["theme"];
-	        this.maxTransferThreads = source["maxTransferThreads"];
-	        this.newTabNameRule = source["newTabNameRule"];
-	        this.fileListViewMode = source["fileListViewMode"];
+	        this.date = source["date"];
+	        this.profile = source["profile"];
+	        this.uploadedBytes = source["uploadedBytes"];
+	        this.downloadedBytes = source["downloadedBytes"];
 	    }
 	}
-	export class BucketInfo {
-	    name: string;
-	    region: string;
-	    creationDate: string;
+	export class ProfileValidation {
+	    access: string;
+	    message: string;
+	    error?: string;
+	    bucket?: string;
+	    endpoint: string;
+	    latencyMs: number;
+	    latencyError?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new BucketInfo(source);
+	        return new ProfileValidation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.region = source["region"];
-	        this.creationDate = source["creationDate"];
+	        this.access = source["access"];
+	        this.message = source["message"];
+	        this.error = source["error"];
+	        this.bucket = source["bucket"];
+	        this.endpoint = source["endpoint"];
+	        this.latencyMs = source["latencyMs"];
+	        this.latencyError = source["latencyError"];
 	    }
 	}
-	export class ConnectionResult {
-	    success: boolean;
-	    message: string;
+	export class ProfilesLockStatus {
+	    encrypted: boolean;
+	    locked: boolean;
+	    expiresAtMs?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ConnectionResult(source);
+	        return new ProfilesLockStatus(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.success = source["success"];
-	        this.message = source["message"];
+	        this.encrypted = source["encrypted"];
+	        this.locked = source["locked"];
+	        this.expiresAtMs = source["expiresAtMs"];
 	    }
 	}
-	export class OSSConfig {
-	    accessKeyId: string;
-	    accessKeySecret: string;
-	    region: string;
-	    endpoint: string;
-	    defaultPath: string;
+	export class RefererConfigView {
+	    allowEmptyReferer: boolean;
+	    referers: string[];
 	
 	    static createFrom(source: any = {}) {
-	        return new OSSConfig(source);
+	        return new RefererConfigView(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.accessKeyId = source["accessKeyId"];
-	        this.accessKeySecret = source["accessKeySecret"];
-	        this.region = source["region"];
-	        this.endpoint = source["endpoint"];
-	        this.defaultPath = source["defaultPath"];
+	        this.allowEmptyReferer = source["allowEmptyReferer"];
+	        this.referers = source["referers"];
 	    }
 	}
-	export class OSSProfile {
+	export class RegionCheck {
+	    known: boolean;
+	    suggestion?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RegionCheck(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.known = source["known"];
+	        this.suggestion = source["suggestion"];
+	        this.message = source["message"];
+	    }
+	}
+	export class RegionInfo {
+	    id: string;
 	    name: string;
-	    config: OSSConfig;
-	    isDefault: boolean;
+	    cloud: string;
+	    publicEndpoint: string;
+	    internalEndpoint: string;
+	    dualStackEndpoint?: string;
+	    accelerateEndpoint?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new OSSProfile(source);
+	        return new RegionInfo(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.config = this.convertValues(source["config"], OSSConfig);
-	        this.isDefault = source["isDefault"];
+	        this.cloud = source["cloud"];
+	        this.publicEndpoint = source["publicEndpoint"];
+	        this.internalEndpoint = source["internalEndpoint"];
+	        this.dualStackEndpoint = source["dualStackEndpoint"];
+	        this.accelerateEndpoint = source["accelerateEndpoint"];
+	    }
+	}
+	export class ReplicationProgressView {
+	    id: string;
+	    historicalObjectProgress?: string;
+	    newObjectSyncedUntil?: string;
+	    status: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplicationProgressView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.historicalObjectProgress = source["historicalObjectProgress"];
+	        this.newObjectSyncedUntil = source["newObjectSyncedUntil"];
+	        this.status = source["status"];
+	    }
+	}
+	export class ReplicationRuleView {
+	    id: string;
+	    status: string;
+	    action?: string;
+	    destinationBucket: string;
+	    destinationRegion: string;
+	    transferType?: string;
+	    prefixes: string[];
+	    replicatesHistorical: boolean;
+	    rtcStatus?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReplicationRuleView(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.status = source["status"];
+	        this.action = source["action"];
+	        this.destinationBucket = source["destinationBucket"];
+	        this.destinationRegion = source["destinationRegion"];
+	        this.transferType = source["transferType"];
+	        this.prefixes = source["prefixes"];
+	        this.replicatesHistorical = source["replicatesHistorical"];
+	        this.rtcStatus = source["rtcStatus"];
+	    }
+	}
+	export class ScanOptions {
+	    checkContentType?: boolean;
+	    checkCRC?: boolean;
+	    maxHeadRequests?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checkContentType = source["checkContentType"];
+	        this.checkCRC = source["checkCRC"];
+	        this.maxHeadRequests = source["maxHeadRequests"];
+	    }
+	}
+	export class SearchObjectsResult {
+	    items: ObjectInfo[];
+	    scanned: number;
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchObjectsResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], ObjectInfo);
+	        this.scanned = source["scanned"];
+	        this.truncated = source["truncated"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -126,42 +1557,62 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class ObjectInfo {
-	    name: string;
-	    path: string;
-	    size: number;
-	    type: string;
-	    lastModified: string;
-	    storageClass: string;
+	export class SessionState {
+	    profileName: string;
+	    bucket?: string;
+	    prefix?: string;
+	    updatedAtMs?: number;
+	    valid: boolean;
+	    reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ObjectInfo(source);
+	        return new SessionState(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.path = source["path"];
-	        this.size = source["size"];
-	        this.type = source["type"];
-	        this.lastModified = source["lastModified"];
-	        this.storageClass = source["storageClass"];
+	        this.profileName = source["profileName"];
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.updatedAtMs = source["updatedAtMs"];
+	        this.valid = source["valid"];
+	        this.reason = source["reason"];
 	    }
 	}
-	export class ObjectListPageResult {
-	    items: ObjectInfo[];
-	    nextMarker: string;
-	    isTruncated: boolean;
+	export class StartLocation {
+	    bucket?: string;
+	    prefix?: string;
+	    fallbackToBucketList: boolean;
+	    reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ObjectListPageResult(source);
+	        return new StartLocation(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.items = this.convertValues(source["items"], ObjectInfo);
-	        this.nextMarker = source["nextMarker"];
-	        this.isTruncated = source["isTruncated"];
+	        this.bucket = source["bucket"];
+	        this.prefix = source["prefix"];
+	        this.fallbackToBucketList = source["fallbackToBucketList"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class StartupHealthReport {
+	    checks: HealthCheckResult[];
+	    durationMs: number;
+	    defaultProfile?: string;
+	    ossutilVersion?: OssutilVersion;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupHealthReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checks = this.convertValues(source["checks"], HealthCheckResult);
+	        this.durationMs = source["durationMs"];
+	        this.defaultProfile = source["defaultProfile"];
+	        this.ossutilVersion = this.convertValues(source["ossutilVersion"], OssutilVersion);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -182,6 +1633,22 @@ export namespace main {
 		    return a;
 		}
 	}
+	
+	
+	export class TempCleanupResult {
+	    removedFiles: number;
+	    freedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TempCleanupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.removedFiles = source["removedFiles"];
+	        this.freedBytes = source["freedBytes"];
+	    }
+	}
 	export class TransferUpdate {
 	    id: string;
 	    profileName?: string;
@@ -202,6 +1669,8 @@ export namespace main {
 	    speedBytesPerSec?: number;
 	    etaSeconds?: number;
 	    message?: string;
+	    errorCode?: string;
+	    command?: string;
 	    startedAtMs?: number;
 	    updatedAtMs?: number;
 	    finishedAtMs?: number;
@@ -231,11 +1700,77 @@ export namespace main {
 	        this.speedBytesPerSec = source["speedBytesPerSec"];
 	        this.etaSeconds = source["etaSeconds"];
 	        this.message = source["message"];
+	        this.errorCode = source["errorCode"];
+	        this.command = source["command"];
 	        this.startedAtMs = source["startedAtMs"];
 	        this.updatedAtMs = source["updatedAtMs"];
 	        this.finishedAtMs = source["finishedAtMs"];
 	    }
 	}
+	export class UndoConflict {
+	    key: string;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class UndoResult {
+	    operation: string;
+	    srcBucket: string;
+	    srcPrefix: string;
+	    destBucket: string;
+	    destPrefix: string;
+	    undone: boolean;
+	    restored: number;
+	    removed: number;
+	    conflicts?: UndoConflict[];
+	    remaining: number;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new UndoResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation = source["operation"];
+	        this.srcBucket = source["srcBucket"];
+	        this.srcPrefix = source["srcPrefix"];
+	        this.destBucket = source["destBucket"];
+	        this.destPrefix = source["destPrefix"];
+	        this.undone = source["undone"];
+	        this.restored = source["restored"];
+	        this.removed = source["removed"];
+	        this.conflicts = this.convertValues(source["conflicts"], UndoConflict);
+	        this.remaining = source["remaining"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class UploadNameCollision {
 	    name: string;
 	    fileExists: boolean;
@@ -266,6 +1801,64 @@ export namespace main {
 	        this.remoteName = source["remoteName"];
 	    }
 	}
+	export class UsageReport {
+	    profile: string;
+	    fromDate: string;
+	    toDate: string;
+	    uploadedBytes: number;
+	    downloadedBytes: number;
+	    days: ProfileDailyUsage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.profile = source["profile"];
+	        this.fromDate = source["fromDate"];
+	        this.toDate = source["toDate"];
+	        this.uploadedBytes = source["uploadedBytes"];
+	        this.downloadedBytes = source["downloadedBytes"];
+	        this.days = this.convertValues(source["days"], ProfileDailyUsage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WormInfo {
+	    wormId?: string;
+	    state: string;
+	    retentionDays: number;
+	    creationDate?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WormInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.wormId = source["wormId"];
+	        this.state = source["state"];
+	        this.retentionDays = source["retentionDays"];
+	        this.creationDate = source["creationDate"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	languageEnglish           = "en"
+	languageChineseSimplified = "zh-CN"
+)
+
+// LanguageInfo describes a language the backend has messages for.
+type LanguageInfo struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+var availableLanguages = []LanguageInfo{
+	{Code: languageEnglish, Name: "English"},
+	{Code: languageChineseSimplified, Name: "简体中文"},
+}
+
+// messageID is the stable key of a user-facing message. The catalog entries of one ID must use
+// the same verbs in the same order (or explicit argument indexes) in every language.
+type messageID string
+
+const (
+	msgConnectionAccessPoint   messageID = "connection.accessPointEndpoint"
+	msgConnectionFailed        messageID = "connection.failed"
+	msgConnectionProxyFailed   messageID = "connection.proxyFailed"
+	msgConnectionSourceNote    messageID = "connection.credentialSource"
+	msgConnectionSuccess       messageID = "connection.success"
+	msgConnectionSuccessPinned messageID = "connection.successPinnedBucket"
+	msgConnectionTLSUnverified messageID = "connection.tlsVerificationDisabled"
+	msgConnectionAccessPointOK messageID = "connection.successAccessPoint"
+
+	msgHintInvalidAccessKeyID  messageID = "connection.hint.invalidAccessKeyId"
+	msgHintSignatureMismatch   messageID = "connection.hint.signatureDoesNotMatch"
+	msgHintRequestTimeSkewed   messageID = "connection.hint.requestTimeTooSkewed"
+	msgHintRequestTimeSkewedBy messageID = "connection.hint.requestTimeTooSkewedBy"
+	msgHintAccessDenied        messageID = "connection.hint.accessDenied"
+	msgHintNoSuchBucket        messageID = "connection.hint.noSuchBucket"
+	msgHintDNSFailure          messageID = "connection.hint.dnsFailure"
+	msgHintConnectionRefused   messageID = "connection.hint.connectionRefused"
+	msgHintTimeout             messageID = "connection.hint.timeout"
+	msgHintTLSFailure          messageID = "connection.hint.tlsFailure"
+	msgHintProxyFailure        messageID = "connection.hint.proxyFailure"
+
+	msgBucketsAccessPoint messageID = "buckets.accessPointEndpoint"
+	msgBucketsListFailed  messageID = "buckets.listFailed"
+	msgBucketsCname       messageID = "buckets.cnameProfile"
+
+	msgConfigRestoredBackup messageID = "config.restoredBackup"
+	msgConfigReset          messageID = "config.reset"
+	msgConfigReloadInvalid  messageID = "config.reloadInvalid"
+
+	msgRegionUnknown    messageID = "region.unknown"
+	msgRegionDidYouMean messageID = "region.didYouMean"
+
+	msgStorageCostDisclaimer messageID = "cost.disclaimer"
+
+	msgAuditLineUnreadable messageID = "audit.lineUnreadable"
+	msgAuditEntryModified  messageID = "audit.entryModified"
+	msgAuditChainBroken    messageID = "audit.chainBroken"
+
+	msgUndoNothing      messageID = "undo.nothing"
+	msgUndoConflicts    messageID = "undo.conflicts"
+	msgUndoCopyMissing  messageID = "undo.copyMissing"
+	msgUndoCopyModified messageID = "undo.copyModified"
+	msgUndoSourceTaken  messageID = "undo.sourceTaken"
+	msgUndoCanceled     messageID = "undo.canceled"
+	msgUndoDone         messageID = "undo.done"
+
+	msgShareQRInvalidURL  messageID = "shareQR.invalidURL"
+	msgShareQRInvalidSize messageID = "shareQR.invalidSize"
+	msgShareQRTooLong     messageID = "shareQR.tooLong"
+
+	msgWatchLimit    messageID = "watch.limit"
+	msgWatchInterval messageID = "watch.interval"
+
+	msgAllProfilesTimeout messageID = "allProfiles.timeout"
+
+	msgBatchTooManyLines messageID = "batch.tooManyLines"
+	msgBatchMalformed    messageID = "batch.malformed"
+	msgBatchNotFound     messageID = "batch.notFound"
+	msgBatchAccessDenied messageID = "batch.accessDenied"
+
+	msgIndexDepth      messageID = "index.depth"
+	msgIndexLinkExpiry messageID = "index.linkExpiry"
+	msgIndexSignFailed messageID = "index.signFailed"
+	msgIndexExists     messageID = "index.exists"
+
+	msgRestoreDays     messageID = "restore.days"
+	msgRestoreDone     messageID = "restore.done"
+	msgRestoreCanceled messageID = "restore.canceled"
+
+	msgIntegrityHeadLimit     messageID = "integrity.headLimit"
+	msgIntegrityZeroByte      messageID = "integrity.zeroByte"
+	msgIntegrityControlChar   messageID = "integrity.controlChar"
+	msgIntegrityTrailingSpace messageID = "integrity.trailingSpace"
+	msgIntegrityNoContentType messageID = "integrity.noContentType"
+	msgIntegrityContentType   messageID = "integrity.contentType"
+	msgIntegrityMissingCRC    messageID = "integrity.missingCRC"
+	msgIntegrityDone          messageID = "integrity.done"
+	msgIntegrityCanceled      messageID = "integrity.canceled"
+	msgIntegrityHeadFailed    messageID = "integrity.headFailed"
+
+	msgBucketNameRequired         messageID = "mutation.bucketNameRequired"
+	msgFolderNameRequired         messageID = "mutation.folderNameRequired"
+	msgFileNameRequired           messageID = "mutation.fileNameRequired"
+	msgFileKeyEmpty               messageID = "mutation.fileKeyEmpty"
+	msgFileNameTrailingSlash      messageID = "mutation.fileNameTrailingSlash"
+	msgFileExists                 messageID = "mutation.fileExists"
+	msgOpenBucketFailed           messageID = "mutation.openBucketFailed"
+	msgOpenSourceBucketFailed     messageID = "mutation.openSourceBucketFailed"
+	msgOpenDestBucketFailed       messageID = "mutation.openDestinationBucketFailed"
+	msgCreateFolderFailed         messageID = "mutation.createFolderFailed"
+	msgCheckFileExistsFailed      messageID = "mutation.checkFileExistsFailed"
+	msgCreateFileFailed           messageID = "mutation.createFileFailed"
+	msgMoveBucketsRequired        messageID = "mutation.moveBucketsRequired"
+	msgMoveKeysRequired           messageID = "mutation.moveKeysRequired"
+	msgMoveIntoSource             messageID = "mutation.moveIntoSource"
+	msgCopyFailed                 messageID = "mutation.copyFailed"
+	msgListFolderFailed           messageID = "mutation.listFolderFailed"
+	msgDeleteSourceFailed         messageID = "mutation.deleteSourceFailed"
+	msgDeleteSourceFailedSummary  messageID = "mutation.deleteSourceFailedSummary"
+	msgMoveJobDone                messageID = "mutation.moveJobDone"
+	msgMoveJobCanceled            messageID = "mutation.moveJobCanceled"
+	msgMoveJobDeleteFailed        messageID = "mutation.moveJobDeleteFailed"
+	msgDeleteFailed               messageID = "mutation.deleteFailed"
+	msgDeleteFailedSummary        messageID = "mutation.deleteFailedSummary"
+	msgDeletePrefixPartial        messageID = "mutation.deletePrefixPartial"
+	msgDeletePrefixCanceled       messageID = "mutation.deletePrefixCanceled"
+	msgCreateTempFileFailed       messageID = "mutation.createTempFileFailed"
+	msgWriteTempFileFailed        messageID = "mutation.writeTempFileFailed"
+	msgCloseTempFileFailed        messageID = "mutation.closeTempFileFailed"
+	msgSaveFailed                 messageID = "mutation.saveFailed"
+	msgUploadFailed               messageID = "mutation.uploadFailed"
+	msgDownloadFailed             messageID = "mutation.downloadFailed"
+	msgTransferInterrupted        messageID = "transfer.interrupted"
+	msgTransferCanceledQueued     messageID = "transfer.canceledQueued"
+	msgTransferGroupSummary       messageID = "transfer.groupSummary"
+	msgTransferGroupFailed        messageID = "transfer.groupFailed"
+	msgTransferUnknownType        messageID = "transfer.unknownType"
+	msgTransferCreateLocalDir     messageID = "transfer.createLocalDirFailed"
+	msgTransferPrepareLocalDir    messageID = "transfer.prepareLocalFolderFailed"
+	msgTransferStartFailed        messageID = "transfer.startFailed"
+	msgTransferBucketEmpty        messageID = "transfer.bucketEmpty"
+	msgTransferLocalPathEmpty     messageID = "transfer.localPathEmpty"
+	msgTransferLocalDirEmpty      messageID = "transfer.localDirEmpty"
+	msgTransferObjectKeyEmpty     messageID = "transfer.objectKeyEmpty"
+	msgTransferObjectKeyIsFolder  messageID = "transfer.objectKeyIsFolder"
+	msgTransferFolderKeyEmpty     messageID = "transfer.folderKeyEmpty"
+	msgTransferInvalidFolderKey   messageID = "transfer.invalidFolderKey"
+	msgTransferNoLocalPaths       messageID = "transfer.noLocalPaths"
+	msgTransferNoFilesToUpload    messageID = "transfer.noFilesToUpload"
+	msgTransferNoFilesToDownload  messageID = "transfer.noFilesToDownload"
+	msgTransferStatLocalPathFail  messageID = "transfer.statLocalPathFailed"
+	msgTransferWalkFolderFailed   messageID = "transfer.walkFolderFailed"
+	msgTransferNothingEnqueued    messageID = "transfer.nothingEnqueued"
+	msgTransferRemoteNameEmpty    messageID = "transfer.remoteNameEmpty"
+	msgTransferInvalidRemoteName  messageID = "transfer.invalidRemoteName"
+	msgTransferUnsafeRelativePath messageID = "transfer.unsafeRelativePath"
+	msgTransferEmptyRelativePath  messageID = "transfer.emptyRelativePath"
+)
+
+var messageCatalog = map[string]map[messageID]string{
+	languageEnglish: {
+		msgConnectionAccessPoint:   "Connection test failed: endpoint looks like an OSS Access Point (bucket-scoped), but listing buckets requires a service endpoint.\nPlease leave Endpoint empty or use something like: %s",
+		msgConnectionFailed:        "Connection failed: %s",
+		msgConnectionProxyFailed:   "Proxy connection failed: %s",
+		msgConnectionSourceNote:    " (credentials from %s)",
+		msgConnectionSuccess:       "Connection successful",
+		msgConnectionSuccessPinned: "Connection successful via pinned bucket %s; these credentials cannot list buckets",
+		msgConnectionTLSUnverified: " — TLS verification disabled",
+		msgConnectionAccessPointOK: "Connection successful: bucket %s is reachable through the access point; bucket listing is not available with this endpoint",
+
+		msgHintInvalidAccessKeyID:  "The AccessKey ID does not exist. Check for typos, or whether the key was deleted or disabled in the RAM console.",
+		msgHintSignatureMismatch:   "The AccessKey secret does not match the AccessKey ID. Re-enter the secret without surrounding spaces.",
+		msgHintRequestTimeSkewed:   "The local clock is too far off the server's. Enable automatic time synchronization and try again.",
+		msgHintRequestTimeSkewedBy: "The local clock is %d seconds off the server's; OSS allows 15 minutes. Enable automatic time synchronization and try again.",
+		msgHintAccessDenied:        "The credentials are valid but not allowed to do this. Grant the RAM user oss:ListBuckets, or set a default path to a bucket it can read.",
+		msgHintNoSuchBucket:        "The bucket in the default path does not exist in this region. Check the bucket name and the region.",
+		msgHintDNSFailure:          "The endpoint host name could not be resolved. Check the endpoint and region, and your DNS or VPN settings.",
+		msgHintConnectionRefused:   "Nothing accepted the connection. Check the endpoint's host and port, or whether a firewall blocks it.",
+		msgHintTimeout:             "The endpoint did not answer in time. Check your network or proxy, or whether the internal endpoint is used outside ECS.",
+		msgHintTLSFailure:          "The server certificate could not be verified. For a private deployment set a CA certificate file.",
+		msgHintProxyFailure:        "The proxy could not be reached or refused the connection. Check the proxy URL and credentials.",
+
+		msgBucketsAccessPoint: "failed to list buckets: Endpoint appears to be an OSS Access Point (bucket-scoped). Listing buckets must use a service endpoint. Leave Endpoint empty or set it to something like %s",
+		msgBucketsListFailed:  "failed to list buckets: %s",
+		msgBucketsCname:       "buckets cannot be listed through the custom domain %s. Pin the bucket bound to it in the profile so it shows up in the bucket list",
+
+		msgConfigRestoredBackup: "%s was damaged and has been restored from its last good copy. The damaged file was kept as %s.",
+		msgConfigReset:          "%s was damaged and has been reset. The damaged file was kept as %s.",
+		msgConfigReloadInvalid:  "%s changed but is not valid JSON; the settings in use are kept until it is fixed.",
+
+		msgRegionUnknown:    "%s is not a known region; it is used as entered.",
+		msgRegionDidYouMean: "%s is not a known region. Did you mean %s?",
+
+		msgStorageCostDisclaimer: "Estimate only: storage at the prices of your price table. Requests, traffic, retrieval and minimum storage duration charges are not included.",
+
+		msgAuditLineUnreadable: "an audit log line cannot be read: %s",
+		msgAuditEntryModified:  "an audit log entry was modified after it was written",
+		msgAuditChainBroken:    "an audit log entry is missing or out of order",
+
+		msgUndoNothing:      "there is nothing to undo",
+		msgUndoConflicts:    "not undone: %d objects changed since the move",
+		msgUndoCopyMissing:  "no longer exists",
+		msgUndoCopyModified: "was modified after the move",
+		msgUndoSourceTaken:  "another object now exists at the original key",
+		msgUndoCanceled:     "undo canceled after %d objects",
+		msgUndoDone:         "undid the move of %d objects",
+
+		msgShareQRInvalidURL:  "a QR code needs an http or https link",
+		msgShareQRInvalidSize: "QR code size must be between %d and %d pixels",
+		msgShareQRTooLong:     "the link is %d characters long; a QR code holds at most %d at medium error correction",
+
+		msgWatchLimit:    "at most %d folders can be watched at once; stop a watch first",
+		msgWatchInterval: "watch interval must be between %d and %d seconds",
+
+		msgAllProfilesTimeout: "listing buckets did not finish within %d seconds",
+
+		msgBatchTooManyLines: "the list has %d lines; at most %d can be queued at once",
+		msgBatchMalformed:    "not an oss://bucket/key path to a file",
+		msgBatchNotFound:     "the object or its bucket does not exist",
+		msgBatchAccessDenied: "this profile cannot read bucket %s",
+
+		msgIndexDepth:      "index depth must be between 0 and %d",
+		msgIndexLinkExpiry: "signed links must expire within %d days",
+		msgIndexSignFailed: "failed to sign a link for the index page: %w",
+		msgIndexExists:     "%s already exists; choose overwrite to replace it",
+
+		msgRestoreDays:     "restore days must be between %d and %d",
+		msgRestoreDone:     "requested %d restores, skipped %d already restored or restoring, %d failed",
+		msgRestoreCanceled: "restore canceled after requesting %d restores",
+
+		msgIntegrityHeadLimit:     "the HeadObject limit must be between 0 and %d",
+		msgIntegrityZeroByte:      "file is empty",
+		msgIntegrityControlChar:   "key contains the control character %s",
+		msgIntegrityTrailingSpace: "path segment %s ends with whitespace",
+		msgIntegrityNoContentType: "no Content-Type",
+		msgIntegrityContentType:   "stored as %s; the extension implies %s",
+		msgIntegrityMissingCRC:    "no x-oss-hash-crc64ecma header",
+		msgIntegrityDone:          "scanned %d objects, %d findings",
+		msgIntegrityCanceled:      "scan canceled after %d objects",
+		msgIntegrityHeadFailed:    "%d objects could not be checked (%s)",
+
+		msgBucketNameRequired:        "bucket name is required",
+		msgFolderNameRequired:        "folder name is required",
+		msgFileNameRequired:          "file name is required",
+		msgFileKeyEmpty:              "file key is empty",
+		msgFileNameTrailingSlash:     "file name cannot end with '/'",
+		msgFileExists:                "file already exists",
+		msgOpenBucketFailed:          "failed to open bucket: %w",
+		msgOpenSourceBucketFailed:    "failed to open source bucket: %w",
+		msgOpenDestBucketFailed:      "failed to open destination bucket: %w",
+		msgCreateFolderFailed:        "failed to create folder: %w",
+		msgCheckFileExistsFailed:     "failed to check file exists: %w",
+		msgCreateFileFailed:          "failed to create file: %w",
+		msgMoveBucketsRequired:       "source and destination bucket are required",
+		msgMoveKeysRequired:          "source and destination key are required",
+		msgMoveIntoSource:            "destination is inside the source folder",
+		msgCopyFailed:                "copy failed: %w",
+		msgListFolderFailed:          "failed to list folder objects: %w",
+		msgDeleteSourceFailed:        "delete source failed: %w",
+		msgDeleteSourceFailedSummary: "delete source failed: %s: %w",
+		msgMoveJobDone:               "moved %d objects",
+		msgMoveJobCanceled:           "move canceled after %d objects",
+		msgMoveJobDeleteFailed:       "%d objects were copied but their source could not be deleted",
+		msgDeleteFailed:              "delete failed: %s",
+		msgDeleteFailedSummary:       "delete failed: %s: %s",
+		msgDeletePrefixPartial:       "deleted %d objects, %d could not be deleted: %s",
+		msgDeletePrefixCanceled:      "delete stopped after %d objects",
+		msgCreateTempFileFailed:      "create temp file failed: %w",
+		msgWriteTempFileFailed:       "write temp file failed: %w",
+		msgCloseTempFileFailed:       "close temp file failed: %w",
+		msgSaveFailed:                "save failed: %s",
+		msgUploadFailed:              "upload failed: %s",
+		msgDownloadFailed:            "download failed: %s",
+
+		msgTransferInterrupted:        "Interrupted when application exited",
+		msgTransferCanceledQueued:     "Canceled before it started",
+		msgTransferGroupSummary:       "%d succeeded, %d failed",
+		msgTransferGroupFailed:        "%d failed",
+		msgTransferUnknownType:        "unknown transfer type",
+		msgTransferCreateLocalDir:     "create local directory failed: %w",
+		msgTransferPrepareLocalDir:    "prepare local folder failed: %w",
+		msgTransferStartFailed:        "failed to start ossutil: %w",
+		msgTransferBucketEmpty:        "bucket is empty",
+		msgTransferLocalPathEmpty:     "local path is empty",
+		msgTransferLocalDirEmpty:      "local directory is empty",
+		msgTransferObjectKeyEmpty:     "object key is empty",
+		msgTransferObjectKeyIsFolder:  "object key points to a folder, use EnqueueDownloadFolder",
+		msgTransferFolderKeyEmpty:     "folder key is empty",
+		msgTransferInvalidFolderKey:   "invalid folder key",
+		msgTransferNoLocalPaths:       "no local paths to upload",
+		msgTransferNoFilesToUpload:    "folder has no files to upload",
+		msgTransferNoFilesToDownload:  "folder has no files to download",
+		msgTransferStatLocalPathFail:  "stat local path failed: %w",
+		msgTransferWalkFolderFailed:   "walk folder failed: %w",
+		msgTransferNothingEnqueued:    "no transfer enqueued",
+		msgTransferRemoteNameEmpty:    "remote name is empty",
+		msgTransferInvalidRemoteName:  "invalid remote name: %s",
+		msgTransferUnsafeRelativePath: "unsafe relative path: %s",
+		msgTransferEmptyRelativePath:  "empty relative path",
+	},
+	languageChineseSimplified: {
+		msgConnectionAccessPoint:   "连接测试失败：Endpoint 看起来是 OSS 接入点（仅限单个 Bucket），而列举 Bucket 需要使用服务 Endpoint。\n请将 Endpoint 留空，或使用类似以下的地址：%s",
+		msgConnectionFailed:        "连接失败：%s",
+		msgConnectionProxyFailed:   "代理连接失败：%s",
+		msgConnectionSourceNote:    "（凭证来源：%s）",
+		msgConnectionSuccess:       "连接成功",
+		msgConnectionSuccessPinned: "已通过固定的 Bucket %s 连接成功；该凭证无权列举 Bucket",
+		msgConnectionTLSUnverified: " — 已禁用 TLS 证书校验",
+		msgConnectionAccessPointOK: "连接成功：可通过接入点访问 Bucket %s；该 Endpoint 不支持列举 Bucket",
+
+		msgHintInvalidAccessKeyID:  "AccessKey ID 不存在。请检查是否输入有误，或该密钥已在 RAM 控制台中被删除或禁用。",
+		msgHintSignatureMismatch:   "AccessKey Secret 与 AccessKey ID 不匹配。请重新输入 Secret，注意不要带有首尾空格。",
+		msgHintRequestTimeSkewed:   "本机时间与服务器相差过大。请开启自动时间同步后重试。",
+		msgHintRequestTimeSkewedBy: "本机时间与服务器相差 %d 秒，OSS 最多允许 15 分钟的偏差。请开启自动时间同步后重试。",
+		msgHintAccessDenied:        "凭证有效，但无权执行此操作。请为 RAM 用户授予 oss:ListBuckets 权限，或将默认路径设为其可读取的 Bucket。",
+		msgHintNoSuchBucket:        "默认路径中的 Bucket 在该地域不存在。请检查 Bucket 名称和地域。",
+		msgHintDNSFailure:          "无法解析 Endpoint 主机名。请检查 Endpoint 和地域，以及 DNS 或 VPN 设置。",
+		msgHintConnectionRefused:   "连接被拒绝。请检查 Endpoint 的主机和端口，或是否被防火墙拦截。",
+		msgHintTimeout:             "Endpoint 未能及时响应。请检查网络或代理，或是否在 ECS 之外使用了内网 Endpoint。",
+		msgHintTLSFailure:          "无法校验服务器证书。私有化部署请设置 CA 证书文件。",
+		msgHintProxyFailure:        "无法连接代理或代理拒绝了连接。请检查代理地址和凭证。",
+
+		msgBucketsAccessPoint: "列举 Bucket 失败：Endpoint 看起来是 OSS 接入点（仅限单个 Bucket）。列举 Bucket 必须使用服务 Endpoint。请将 Endpoint 留空，或设置为类似 %s 的地址",
+		msgBucketsListFailed:  "列举 Bucket 失败：%s",
+		msgBucketsCname:       "无法通过自定义域名 %s 列举 Bucket。请在配置中固定绑定该域名的 Bucket，使其显示在 Bucket 列表中",
+
+		msgConfigRestoredBackup: "%s 已损坏，已从最近一次的正常备份恢复。损坏的文件已保存为 %s。",
+		msgConfigReset:          "%s 已损坏，已重置。损坏的文件已保存为 %s。",
+		msgConfigReloadInvalid:  "%s 已被修改，但不是有效的 JSON；在修正之前继续使用当前设置。",
+
+		msgRegionUnknown:    "%s 不是已知地域，将按输入使用。",
+		msgRegionDidYouMean: "%s 不是已知地域。您是否要输入 %s？",
+
+		msgStorageCostDisclaimer: "仅为估算：按价格表计算的存储费用，不包括请求、流量、数据取回及最短存储时长费用。",
+
+		msgAuditLineUnreadable: "审计日志中有一行无法读取：%s",
+		msgAuditEntryModified:  "审计日志中有条目在写入后被修改",
+		msgAuditChainBroken:    "审计日志中有条目缺失或顺序错乱",
+
+		msgUndoNothing:      "没有可撤销的操作",
+		msgUndoConflicts:    "未撤销：移动后有 %d 个对象发生了变化",
+		msgUndoCopyMissing:  "已不存在",
+		msgUndoCopyModified: "在移动后被修改",
+		msgUndoSourceTaken:  "原位置已有其他对象",
+		msgUndoCanceled:     "撤销已取消，已处理 %d 个对象",
+		msgUndoDone:         "已撤销 %d 个对象的移动",
+
+		msgShareQRInvalidURL:  "二维码只能由 http 或 https 链接生成",
+		msgShareQRInvalidSize: "二维码尺寸必须在 %d 到 %d 像素之间",
+		msgShareQRTooLong:     "链接长度为 %d 个字符，中等纠错级别的二维码最多容纳 %d 个字符",
+
+		msgWatchLimit:    "最多同时监视 %d 个文件夹，请先停止一个监视",
+		msgWatchInterval: "监视间隔必须在 %d 到 %d 秒之间",
+
+		msgAllProfilesTimeout: "列举 Bucket 未在 %d 秒内完成",
+
+		msgBatchTooManyLines: "列表共 %d 行，一次最多加入 %d 行",
+		msgBatchMalformed:    "不是指向文件的 oss://bucket/key 路径",
+		msgBatchNotFound:     "对象或其所在 Bucket 不存在",
+		msgBatchAccessDenied: "当前配置无权读取 Bucket %s",
+
+		msgIndexDepth:      "索引深度必须在 0 到 %d 之间",
+		msgIndexLinkExpiry: "签名链接的有效期不能超过 %d 天",
+		msgIndexSignFailed: "为索引页签名链接失败：%w",
+		msgIndexExists:     "%s 已存在，选择覆盖才能替换",
+
+		msgRestoreDays:     "解冻天数必须在 %d 到 %d 之间",
+		msgRestoreDone:     "已提交 %d 个解冻请求，跳过 %d 个已解冻或解冻中的对象，%d 个失败",
+		msgRestoreCanceled: "解冻已取消，已提交 %d 个解冻请求",
+
+		msgIntegrityHeadLimit:     "HeadObject 请求上限必须在 0 到 %d 之间",
+		msgIntegrityZeroByte:      "文件为空",
+		msgIntegrityControlChar:   "对象名包含控制字符 %s",
+		msgIntegrityTrailingSpace: "路径片段 %s 以空白字符结尾",
+		msgIntegrityNoContentType: "没有 Content-Type",
+		msgIntegrityContentType:   "存储为 %s，但扩展名对应 %s",
+		msgIntegrityMissingCRC:    "缺少 x-oss-hash-crc64ecma 头",
+		msgIntegrityDone:          "已扫描 %d 个对象，发现 %d 个问题",
+		msgIntegrityCanceled:      "扫描已取消，已扫描 %d 个对象",
+		msgIntegrityHeadFailed:    "%d 个对象无法检查（%s）",
+
+		msgBucketNameRequired:        "Bucket 名称不能为空",
+		msgFolderNameRequired:        "文件夹名称不能为空",
+		msgFileNameRequired:          "文件名不能为空",
+		msgFileKeyEmpty:              "文件路径为空",
+		msgFileNameTrailingSlash:     "文件名不能以 '/' 结尾",
+		msgFileExists:                "文件已存在",
+		msgOpenBucketFailed:          "打开 Bucket 失败：%w",
+		msgOpenSourceBucketFailed:    "打开源 Bucket 失败：%w",
+		msgOpenDestBucketFailed:      "打开目标 Bucket 失败：%w",
+		msgCreateFolderFailed:        "创建文件夹失败：%w",
+		msgCheckFileExistsFailed:     "检查文件是否存在失败：%w",
+		msgCreateFileFailed:          "创建文件失败：%w",
+		msgMoveBucketsRequired:       "源 Bucket 和目标 Bucket 不能为空",
+		msgMoveKeysRequired:          "源路径和目标路径不能为空",
+		msgMoveIntoSource:            "目标位置位于源文件夹内",
+		msgCopyFailed:                "复制失败：%w",
+		msgListFolderFailed:          "列举文件夹内容失败：%w",
+		msgDeleteSourceFailed:        "删除源对象失败：%w",
+		msgDeleteSourceFailedSummary: "删除源对象失败：%s：%w",
+		msgMoveJobDone:               "已移动 %d 个对象",
+		msgMoveJobCanceled:           "移动已取消，已移动 %d 个对象",
+		msgMoveJobDeleteFailed:       "%d 个对象已复制，但源对象删除失败",
+		msgDeleteFailed:              "删除失败：%s",
+		msgDeleteFailedSummary:       "删除失败：%s：%s",
+		msgDeletePrefixPartial:       "已删除 %d 个对象，%d 个对象删除失败：%s",
+		msgDeletePrefixCanceled:      "删除已停止，已删除 %d 个对象",
+		msgCreateTempFileFailed:      "创建临时文件失败：%w",
+		msgWriteTempFileFailed:       "写入临时文件失败：%w",
+		msgCloseTempFileFailed:       "关闭临时文件失败：%w",
+		msgSaveFailed:                "保存失败：%s",
+		msgUploadFailed:              "上传失败：%s",
+		msgDownloadFailed:            "下载失败：%s",
+
+		msgTransferInterrupted:        "应用退出时传输被中断",
+		msgTransferCanceledQueued:     "传输在开始前已取消",
+		msgTransferGroupSummary:       "%d 个成功，%d 个失败",
+		msgTransferGroupFailed:        "%d 个失败",
+		msgTransferUnknownType:        "未知的传输类型",
+		msgTransferCreateLocalDir:     "创建本地目录失败：%w",
+		msgTransferPrepareLocalDir:    "准备本地文件夹失败：%w",
+		msgTransferStartFailed:        "启动 ossutil 失败：%w",
+		msgTransferBucketEmpty:        "Bucket 为空",
+		msgTransferLocalPathEmpty:     "本地路径为空",
+		msgTransferLocalDirEmpty:      "本地目录为空",
+		msgTransferObjectKeyEmpty:     "对象路径为空",
+		msgTransferObjectKeyIsFolder:  "对象路径指向文件夹，请使用 EnqueueDownloadFolder",
+		msgTransferFolderKeyEmpty:     "文件夹路径为空",
+		msgTransferInvalidFolderKey:   "文件夹路径无效",
+		msgTransferNoLocalPaths:       "没有要上传的本地路径",
+		msgTransferNoFilesToUpload:    "文件夹中没有可上传的文件",
+		msgTransferNoFilesToDownload:  "文件夹中没有可下载的文件",
+		msgTransferStatLocalPathFail:  "读取本地路径信息失败：%w",
+		msgTransferWalkFolderFailed:   "遍历文件夹失败：%w",
+		msgTransferNothingEnqueued:    "没有加入任何传输任务",
+		msgTransferRemoteNameEmpty:    "远程名称为空",
+		msgTransferInvalidRemoteName:  "远程名称无效：%s",
+		msgTransferUnsafeRelativePath: "不安全的相对路径：%s",
+		msgTransferEmptyRelativePath:  "相对路径为空",
+	},
+}
+
+// resolveLanguage maps a language setting to a catalog; unknown languages fall back to English.
+func resolveLanguage(language string) string {
+	language = strings.ReplaceAll(strings.TrimSpace(language), "_", "-")
+	for _, available := range availableLanguages {
+		if strings.EqualFold(language, available.Code) {
+			return available.Code
+		}
+	}
+	switch strings.ToLower(language) {
+	case "zh", "zh-hans", "zh-sg":
+		return languageChineseSimplified
+	}
+	return languageEnglish
+}
+
+// isAvailableLanguage reports whether language names a catalog, directly or through an alias.
+func isAvailableLanguage(language string) bool {
+	if resolveLanguage(language) != languageEnglish {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(language), languageEnglish)
+}
+
+func (s *OSSService) setLanguage(language string) {
+	s.languageMu.Lock()
+	s.language = resolveLanguage(language)
+	s.languageMu.Unlock()
+}
+
+func (s *OSSService) messageFormat(id messageID) string {
+	s.languageMu.RLock()
+	language := s.language
+	s.languageMu.RUnlock()
+
+	if format, ok := messageCatalog[language][id]; ok {
+		return format
+	}
+	if format, ok := messageCatalog[languageEnglish][id]; ok {
+		return format
+	}
+	return string(id)
+}
+
+// msg renders a catalog message in the current language.
+func (s *OSSService) msg(id messageID, args ...any) string {
+	format := s.messageFormat(id)
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// errorf is fmt.Errorf over a catalog message, so %w in the catalog keeps the error chain intact.
+// SDK errors among args are shown with their OSS code and request ID (see describeSDKError).
+func (s *OSSService) errorf(id messageID, args ...any) error {
+	format := s.messageFormat(id)
+	if len(args) == 0 {
+		return errors.New(format)
+	}
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			args[i] = describeSDKError(err)
+		}
+	}
+	return fmt.Errorf(format, args...)
+}
+
+// GetAvailableLanguages lists the languages backend messages can be shown in.
+func (s *OSSService) GetAvailableLanguages() []LanguageInfo {
+	return append([]LanguageInfo(nil), availableLanguages...)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	indexPageName = "index.html"
+	// maxIndexDepth bounds how many folder levels below the prefix one page lists.
+	maxIndexDepth = 10
+	// maxIndexEntries keeps the page loadable; the rest is left out with a note.
+	maxIndexEntries = 5000
+
+	defaultIndexLinkExpiry = 24 * time.Hour
+	maxIndexLinkExpiry     = 7 * 24 * time.Hour
+)
+
+// IndexOptions controls GenerateIndexPage.
+type IndexOptions struct {
+	// Title heads the page; empty means the oss:// path of the prefix.
+	Title string `json:"title,omitempty"`
+	// Depth is how many folder levels below the prefix are listed too; 0 lists the prefix only.
+	Depth int `json:"depth,omitempty"`
+	// SignedLinks links files through presigned URLs instead of relative keys, for private buckets.
+	// The page stops working when they expire.
+	SignedLinks bool `json:"signedLinks,omitempty"`
+	// LinkExpirySec is how long signed links stay valid; 0 means a day, at most 7 days.
+	LinkExpirySec int `json:"linkExpirySec,omitempty"`
+	// DryRun renders the page without uploading it.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Overwrite replaces an existing index.html.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+type indexPageEntry struct {
+	Name     string
+	Href     string
+	Size     string
+	Modified string
+}
+
+type indexPageData struct {
+	Title     string
+	Generated string
+	Entries   []indexPageEntry
+	Truncated bool
+	Limit     int
+}
+
+var indexPageTemplate = template.Must(template.New(indexPageName).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 12px; border-bottom: 1px solid #eee; }
+td.size { text-align: right; white-space: nowrap; }
+footer { margin-top: 1.5em; color: #888; font-size: 0.85em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+<thead><tr><th>Name</th><th>Size</th><th>Last modified</th></tr></thead>
+<tbody>
+{{- range .Entries}}
+<tr><td>{{if .Href}}<a href="{{.Href}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td class="size">{{.Size}}</td><td>{{.Modified}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+<footer>{{if .Truncated}}Only the first {{.Limit}} entries are listed. {{end}}Generated {{.Generated}}.</footer>
+</body>
+</html>
+`))
+
+// GenerateIndexPage renders an HTML listing of prefix and uploads it as <prefix>index.html with
+// Content-Type text/html, for browsing a public bucket without a web server. Files link by
+// relative key, or by presigned URL with opts.SignedLinks; folders link to "<folder>/", which
+// static website hosting serves as that folder's index. It returns the page, so opts.DryRun
+// previews it. An existing index.html is only replaced with opts.Overwrite.
+func (s *OSSService) GenerateIndexPage(config OSSConfig, bucketName string, prefix string, opts IndexOptions) (_ string, err error) {
+	defer s.logOperation("GenerateIndexPage", bucketName, prefix, time.Now(), &err)
+
+	if !opts.DryRun {
+		if err := s.requireWritable(config); err != nil {
+			return "", err
+		}
+	}
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return "", err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+	indexKey := prefix + indexPageName
+	if opts.Depth < 0 || opts.Depth > maxIndexDepth {
+		return "", s.errorf(msgIndexDepth, maxIndexDepth)
+	}
+	expiry := defaultIndexLinkExpiry
+	if opts.LinkExpirySec != 0 {
+		expiry = time.Duration(opts.LinkExpirySec) * time.Second
+	}
+	if opts.SignedLinks && (expiry <= 0 || expiry > maxIndexLinkExpiry) {
+		return "", s.errorf(msgIndexLinkExpiry, int(maxIndexLinkExpiry.Hours()/24))
+	}
+
+	var objects []oss.ObjectProperties
+	var folders []string
+	var truncated bool
+	err = s.withBucketRegion(config, bucketName, "ListObjects", func(bucket *oss.Bucket) error {
+		var listErr error
+		objects, folders, truncated, listErr = s.listIndexEntries(bucket, prefix, opts.Depth)
+		return listErr
+	})
+	if err != nil {
+		return "", s.errorf(msgListFolderFailed, err)
+	}
+
+	bucket, err := s.sdkBucket(config, bucketName)
+	if err != nil {
+		return "", err
+	}
+	entries := make([]indexPageEntry, 0, len(objects)+len(folders))
+	for _, folder := range folders {
+		rel := strings.TrimPrefix(folder, prefix)
+		entries = append(entries, indexPageEntry{Name: rel, Href: relativeIndexHref(rel), Size: "-"})
+	}
+	for _, object := range objects {
+		rel := strings.TrimPrefix(object.Key, prefix)
+		entry := indexPageEntry{
+			Name:     rel,
+			Href:     relativeIndexHref(rel),
+			Size:     formatByteSize(object.Size),
+			Modified: object.LastModified.UTC().Format("2006-01-02 15:04 MST"),
+		}
+		if opts.SignedLinks {
+			signed, err := bucket.SignURL(object.Key, oss.HTTPGet, int64(expiry.Seconds()))
+			if err != nil {
+				return "", s.errorf(msgIndexSignFailed, err)
+			}
+			entry.Href = signed
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	if len(entries) > maxIndexEntries {
+		entries = entries[:maxIndexEntries]
+	}
+
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		title = buildOssPath(bucketName, prefix)
+	}
+	var page bytes.Buffer
+	err = indexPageTemplate.Execute(&page, indexPageData{
+		Title:     title,
+		Generated: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		Entries:   entries,
+		Truncated: truncated,
+		Limit:     maxIndexEntries,
+	})
+	if err != nil {
+		return "", err
+	}
+	html := page.String()
+	if opts.DryRun {
+		return html, nil
+	}
+
+	target := buildOssPath(bucketName, indexKey)
+	exists, err := bucket.IsObjectExist(indexKey)
+	if err != nil {
+		return "", s.errorf(msgCheckFileExistsFailed, err)
+	}
+	if exists && !opts.Overwrite {
+		return "", s.errorf(msgIndexExists, target)
+	}
+	options := []oss.Option{oss.ContentType("text/html; charset=utf-8")}
+	if !opts.Overwrite {
+		// Another writer may create index.html between the check and the upload.
+		options = append(options, oss.ForbidOverWrite(true))
+	}
+	err = s.withSDKRetry("PutObject", func() error {
+		return bucket.PutObject(indexKey, strings.NewReader(html), options...)
+	})
+	if err != nil {
+		if isOSSErrorCode(err, "FileAlreadyExists") {
+			return "", s.errorf(msgIndexExists, target)
+		}
+		err = s.errorf(msgSaveFailed, err)
+	}
+	if exists {
+		s.recordAudit(config, "GenerateIndexPage", bucketName, target, auditCount(err), err)
+	}
+	if err != nil {
+		return "", err
+	}
+	return html, nil
+}
+
+// listIndexEntries lists the files and folders under prefix, descending depth folder levels. Folders
+// at the last level are returned without their contents. The page itself and folder placeholders
+// are left out. truncated is set when maxIndexEntries was reached.
+func (s *OSSService) listIndexEntries(bucket *oss.Bucket, prefix string, depth int) (objects []oss.ObjectProperties, folders []string, truncated bool, err error) {
+	level := []string{prefix}
+	for d := 0; d <= depth && len(level) > 0; d++ {
+		var next []string
+		for _, dir := range level {
+			marker := ""
+			for {
+				var lor oss.ListObjectsResult
+				err := s.withSDKRetry("ListObjects", func() error {
+					var listErr error
+					lor, listErr = bucket.ListObjects(oss.Prefix(dir), oss.Delimiter("/"), oss.Marker(marker), oss.MaxKeys(1000))
+					return listErr
+				})
+				if err != nil {
+					return nil, nil, false, err
+				}
+				for _, object := range lor.Objects {
+					if object.Key == dir || object.Key == prefix+indexPageName {
+						continue
+					}
+					objects = append(objects, object)
+				}
+				for _, commonPrefix := range lor.CommonPrefixes {
+					if d < depth {
+						next = append(next, commonPrefix)
+					} else {
+						folders = append(folders, commonPrefix)
+					}
+				}
+				if len(objects)+len(folders)+len(next) >= maxIndexEntries {
+					return objects, folders, true, nil
+				}
+				if !lor.IsTruncated {
+					break
+				}
+				marker = lor.NextMarker
+			}
+		}
+		level = next
+	}
+	return objects, folders, false, nil
+}
+
+// relativeIndexHref links a key relative to the page. The "./" keeps a first segment containing
+// ":" from reading as a URL scheme.
+func relativeIndexHref(rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "./" + strings.Join(segments, "/")
+}
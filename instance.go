@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// singleInstanceID names the lock Wails holds for the running instance: a flock'ed file on macOS,
+// a D-Bus name on Linux and a named mutex on Windows. The OS releases all three when the process
+// dies, so a crash never leaves a stale lock behind that blocks the next launch.
+const singleInstanceID = "com.fanbb2333.walioss"
+
+// LaunchRequest is what a launch asked walioss to open: oss:// locations to browse and local files
+// to upload.
+type LaunchRequest struct {
+	OssPaths []string `json:"ossPaths"`
+	Files    []string `json:"files"`
+}
+
+func (r LaunchRequest) empty() bool {
+	return len(r.OssPaths) == 0 && len(r.Files) == 0
+}
+
+// parseLaunchArgs picks the oss:// paths and existing local files out of a command line. Relative
+// paths are resolved against workingDirectory, the directory the launching process ran in.
+func parseLaunchArgs(args []string, workingDirectory string) LaunchRequest {
+	var request LaunchRequest
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if arg == "" || strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if strings.HasPrefix(arg, "oss://") {
+			bucket, key, ok := parseOssPath(arg)
+			if !ok {
+				continue
+			}
+			request.OssPaths = append(request.OssPaths, buildOssPath(bucket, key))
+			continue
+		}
+
+		path := expandLeadingTilde(arg)
+		if !filepath.IsAbs(path) && workingDirectory != "" {
+			path = filepath.Join(workingDirectory, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		request.Files = append(request.Files, filepath.Clean(path))
+	}
+	return request
+}
+
+func (a *App) singleInstanceLock() *options.SingleInstanceLock {
+	return &options.SingleInstanceLock{
+		UniqueId:               singleInstanceID,
+		OnSecondInstanceLaunch: a.onSecondInstanceLaunch,
+	}
+}
+
+// onSecondInstanceLaunch runs in the first instance after another launch handed over its command
+// line and exited. It brings the window to the front and passes the arguments to the frontend as
+// an app:open event.
+func (a *App) onSecondInstanceLaunch(data options.SecondInstanceData) {
+	request := parseLaunchArgs(data.Args, data.WorkingDirectory)
+	a.OSSService.logOperationEvent(logLevelInfo, "SecondInstanceLaunch", opOutcomeOK, strings.Join(data.Args, " "), nil)
+	// The read lock is held while queueing so startup cannot set ctx in between: the request is
+	// then either queued before the frontend loads or emitted to it.
+	a.ctxMu.RLock()
+	ctx := a.ctx
+	if ctx == nil {
+		a.queueLaunchRequest(request)
+		a.ctxMu.RUnlock()
+		return
+	}
+	a.ctxMu.RUnlock()
+	runtime.WindowUnminimise(ctx)
+	runtime.WindowShow(ctx)
+	if !request.empty() {
+		runtime.EventsEmit(ctx, "app:open", request)
+	}
+}
+
+func (a *App) queueLaunchRequest(request LaunchRequest) {
+	if request.empty() {
+		return
+	}
+	a.pendingLaunchMu.Lock()
+	a.pendingLaunch.OssPaths = append(a.pendingLaunch.OssPaths, request.OssPaths...)
+	a.pendingLaunch.Files = append(a.pendingLaunch.Files, request.Files...)
+	a.pendingLaunchMu.Unlock()
+}
+
+// TakeLaunchRequest returns the paths this instance was started with (and any forwarded before the
+// window was ready) and clears them. The frontend calls it once on load; later launches arrive as
+// app:open events.
+func (a *App) TakeLaunchRequest() LaunchRequest {
+	a.pendingLaunchMu.Lock()
+	defer a.pendingLaunchMu.Unlock()
+	request := a.pendingLaunch
+	a.pendingLaunch = LaunchRequest{}
+	if request.OssPaths == nil {
+		request.OssPaths = []string{}
+	}
+	if request.Files == nil {
+		request.Files = []string{}
+	}
+	return request
+}
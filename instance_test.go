@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+)
+
+func TestSecondInstanceLaunchBeforeStartupIsQueued(t *testing.T) {
+	app := &App{OSSService: newTestService(t)}
+
+	// Launches forwarded while the window is still starting run on their own goroutines.
+	const launches = 20
+	var wg sync.WaitGroup
+	for i := range launches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.onSecondInstanceLaunch(options.SecondInstanceData{Args: []string{fmt.Sprintf("oss://bucket/key-%02d", i)}})
+		}()
+	}
+	wg.Wait()
+
+	if ctx := app.runtimeContext(); ctx != nil {
+		t.Fatalf("runtimeContext() before startup = %v, want nil", ctx)
+	}
+	request := app.TakeLaunchRequest()
+	sort.Strings(request.OssPaths)
+	if len(request.OssPaths) != launches {
+		t.Fatalf("queued %d paths, want %d: %v", len(request.OssPaths), launches, request.OssPaths)
+	}
+	for i, path := range request.OssPaths {
+		if want := fmt.Sprintf("oss://bucket/key-%02d", i); path != want {
+			t.Fatalf("OssPaths[%d] = %q, want %q", i, path, want)
+		}
+	}
+	if again := app.TakeLaunchRequest(); len(again.OssPaths) != 0 || len(again.Files) != 0 {
+		t.Fatalf("second TakeLaunchRequest() = %+v, want empty", again)
+	}
+}
+
+func TestSecondInstanceLaunchWithoutPathsQueuesNothing(t *testing.T) {
+	app := &App{OSSService: newTestService(t)}
+	app.onSecondInstanceLaunch(options.SecondInstanceData{Args: []string{"--flag", "  "}})
+	if request := app.TakeLaunchRequest(); len(request.OssPaths) != 0 || len(request.Files) != 0 {
+		t.Fatalf("TakeLaunchRequest() = %+v, want empty", request)
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Issues reported in IntegrityFinding.Issue.
+const (
+	integrityZeroByte      = "zeroByte"
+	integrityContentType   = "contentType"
+	integritySuspiciousKey = "suspiciousKey"
+	integrityMissingCRC    = "missingCRC"
+)
+
+const (
+	defaultIntegrityHeadRequests = 1000
+	maxIntegrityHeadRequests     = 10000
+	// maxIntegrityFindings bounds what a report keeps; further findings are only counted.
+	maxIntegrityFindings = 10000
+	// integrityReportsKept is how many finished reports stay retrievable.
+	integrityReportsKept = 10
+
+	integrityBatchSize = 100
+)
+
+// ScanOptions selects the checks of RunIntegrityScan that need a HeadObject request per object.
+// Zero-byte files and suspicious keys are found from the listing alone.
+type ScanOptions struct {
+	// CheckContentType flags objects stored as application/octet-stream, or without a Content-Type,
+	// whose extension implies a type.
+	CheckContentType bool `json:"checkContentType,omitempty"`
+	// CheckCRC flags objects without an x-oss-hash-crc64ecma header.
+	CheckCRC bool `json:"checkCRC,omitempty"`
+	// MaxHeadRequests caps the HeadObject requests of the scan; 0 means 1000, at most 10000.
+	// Objects past the cap are not checked for content type or CRC.
+	MaxHeadRequests int `json:"maxHeadRequests,omitempty"`
+}
+
+// IntegrityFinding is one problem RunIntegrityScan found.
+type IntegrityFinding struct {
+	Key    string `json:"key"`
+	Issue  string `json:"issue"`
+	Detail string `json:"detail"`
+	Size   int64  `json:"size"`
+}
+
+// IntegrityReport is the outcome of RunIntegrityScan, retrievable through GetIntegrityReport.
+type IntegrityReport struct {
+	ID      string `json:"id"`
+	Bucket  string `json:"bucket"`
+	Prefix  string `json:"prefix"`
+	Status  string `json:"status"`
+	Scanned int64  `json:"scanned"`
+	// Counts totals findings per issue, including those beyond Findings.
+	Counts           map[string]int64   `json:"counts"`
+	Findings         []IntegrityFinding `json:"findings"`
+	Truncated        bool               `json:"truncated"`
+	HeadRequests     int64              `json:"headRequests"`
+	HeadLimitReached bool               `json:"headLimitReached"`
+	Message          string             `json:"message,omitempty"`
+	StartedAtMs      int64              `json:"startedAtMs"`
+	FinishedAtMs     int64              `json:"finishedAtMs,omitempty"`
+}
+
+// IntegrityScanUpdate is emitted as "scan:update" while a scan runs and once more, with
+// FinishedAtMs set, when it ends. Findings holds only what was found since the previous update.
+type IntegrityScanUpdate struct {
+	ID           string             `json:"id"`
+	Bucket       string             `json:"bucket"`
+	Prefix       string             `json:"prefix"`
+	Status       string             `json:"status"`
+	Scanned      int64              `json:"scanned"`
+	Counts       map[string]int64   `json:"counts"`
+	Findings     []IntegrityFinding `json:"findings,omitempty"`
+	Message      string             `json:"message,omitempty"`
+	FinishedAtMs int64              `json:"finishedAtMs,omitempty"`
+}
+
+// integrityScanStore keeps running scans and the last finished reports.
+type integrityScanStore struct {
+	mu       sync.Mutex
+	reports  map[string]*IntegrityReport
+	finished []string
+}
+
+func (st *integrityScanStore) add(report *IntegrityReport) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.reports == nil {
+		st.reports = make(map[string]*IntegrityReport)
+	}
+	st.reports[report.ID] = report
+}
+
+// finish marks id finished, dropping the oldest finished reports beyond integrityReportsKept.
+func (st *integrityScanStore) finish(id string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.finished = append(st.finished, id)
+	for len(st.finished) > integrityReportsKept {
+		delete(st.reports, st.finished[0])
+		st.finished = st.finished[1:]
+	}
+}
+
+func (st *integrityScanStore) get(id string) (IntegrityReport, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	report, ok := st.reports[id]
+	if !ok {
+		return IntegrityReport{}, false
+	}
+	out := *report
+	out.Counts = make(map[string]int64, len(report.Counts))
+	for issue, count := range report.Counts {
+		out.Counts[issue] = count
+	}
+	out.Findings = append([]IntegrityFinding{}, report.Findings...)
+	return out, true
+}
+
+// suspiciousKeyReason tells why key looks broken: a path segment ending in whitespace or a control
+// character anywhere. It returns "" for ordinary keys.
+func (s *OSSService) suspiciousKeyReason(key string) string {
+	for _, r := range key {
+		if unicode.IsControl(r) {
+			return s.msg(msgIntegrityControlChar, strconv.QuoteRune(r))
+		}
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+		if segment != strings.TrimRightFunc(segment, unicode.IsSpace) {
+			return s.msg(msgIntegrityTrailingSpace, strconv.Quote(segment))
+		}
+	}
+	return ""
+}
+
+// expectedContentType is the type the extension of key implies, or "" when it implies none.
+func expectedContentType(key string) string {
+	expected := mime.TypeByExtension(strings.ToLower(path.Ext(key)))
+	if base, _, _ := strings.Cut(expected, ";"); base == "application/octet-stream" {
+		return ""
+	}
+	return expected
+}
+
+func isGenericContentType(contentType string) bool {
+	base, _, _ := strings.Cut(contentType, ";")
+	base = strings.TrimSpace(base)
+	return base == "" || strings.EqualFold(base, "application/octet-stream")
+}
+
+// RunIntegrityScan walks prefix in the background and reports zero-byte files that are not folder
+// placeholders, keys with trailing spaces or control characters and, per opts, objects whose
+// Content-Type is missing or generic although the extension implies one, and objects without a
+// CRC64 header. It returns the scan ID, which CancelOperation accepts. Findings are emitted as
+// scan:update as they come; the report stays available through GetIntegrityReport.
+func (s *OSSService) RunIntegrityScan(config OSSConfig, bucketName string, prefix string, opts ScanOptions) (_ string, err error) {
+	defer s.logOperation("RunIntegrityScan", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return "", err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+	if opts.MaxHeadRequests == 0 {
+		opts.MaxHeadRequests = defaultIntegrityHeadRequests
+	}
+	if opts.MaxHeadRequests < 0 || opts.MaxHeadRequests > maxIntegrityHeadRequests {
+		return "", s.errorf(msgIntegrityHeadLimit, maxIntegrityHeadRequests)
+	}
+
+	id, ctx, done := s.startBackgroundOperation("scan " + buildOssPath(bucketName, prefix))
+	report := &IntegrityReport{
+		ID:          id,
+		Bucket:      bucketName,
+		Prefix:      prefix,
+		Status:      prefixJobRunning,
+		Counts:      map[string]int64{},
+		Findings:    []IntegrityFinding{},
+		StartedAtMs: time.Now().UnixMilli(),
+	}
+	s.integrityScans.add(report)
+	go func() {
+		defer done()
+		s.runIntegrityScan(ctx, config, report, opts)
+	}()
+	return id, nil
+}
+
+func (s *OSSService) runIntegrityScan(ctx context.Context, config OSSConfig, report *IntegrityReport, opts ScanOptions) {
+	store := &s.integrityScans
+	emitInterval := s.transferEmitInterval()
+	var (
+		lastEmit time.Time
+		pending  []IntegrityFinding
+		heads    atomic.Int64
+	)
+	// emit is called with store.mu held.
+	emit := func(force bool) {
+		if !force && time.Since(lastEmit) < emitInterval {
+			return
+		}
+		lastEmit = time.Now()
+		update := IntegrityScanUpdate{
+			ID:           report.ID,
+			Bucket:       report.Bucket,
+			Prefix:       report.Prefix,
+			Status:       report.Status,
+			Scanned:      report.Scanned,
+			Counts:       make(map[string]int64, len(report.Counts)),
+			Findings:     pending,
+			Message:      report.Message,
+			FinishedAtMs: report.FinishedAtMs,
+		}
+		for issue, count := range report.Counts {
+			update.Counts[issue] = count
+		}
+		pending = nil
+		s.emitIntegrityScanUpdate(update)
+	}
+	store.mu.Lock()
+	emit(true)
+	store.mu.Unlock()
+
+	result, err := runPrefixWalk(ctx, s.sdkPrefixLister(config, report.Bucket, report.Prefix), prefixWalkWorkers, integrityBatchSize,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			bucket, bucketErr := s.sdkBucket(config, report.Bucket)
+			var findings []IntegrityFinding
+			var failures []prefixObjectError
+			for _, object := range batch {
+				if ctx.Err() != nil {
+					break
+				}
+				key := object.Key
+				isPlaceholder := strings.HasSuffix(key, "/")
+				if object.Size == 0 && !isPlaceholder {
+					findings = append(findings, IntegrityFinding{Key: key, Issue: integrityZeroByte, Detail: s.msg(msgIntegrityZeroByte)})
+				}
+				if reason := s.suspiciousKeyReason(key); reason != "" {
+					findings = append(findings, IntegrityFinding{Key: key, Issue: integritySuspiciousKey, Detail: reason, Size: object.Size})
+				}
+
+				expected := ""
+				if opts.CheckContentType {
+					expected = expectedContentType(key)
+				}
+				if isPlaceholder || (expected == "" && !opts.CheckCRC) {
+					continue
+				}
+				if heads.Add(1) > int64(opts.MaxHeadRequests) {
+					continue
+				}
+				var header http.Header
+				err := bucketErr
+				if err == nil {
+					err = s.withSDKRetryContext(ctx, "GetObjectMeta", func() error {
+						props, err := bucket.GetObjectDetailedMeta(key)
+						header = props
+						return err
+					})
+				}
+				if err != nil {
+					failures = append(failures, prefixObjectError{key: key, err: describeSDKError(err)})
+					continue
+				}
+				if contentType := header.Get("Content-Type"); expected != "" && isGenericContentType(contentType) {
+					if contentType == "" {
+						contentType = s.msg(msgIntegrityNoContentType)
+					}
+					findings = append(findings, IntegrityFinding{Key: key, Issue: integrityContentType, Detail: s.msg(msgIntegrityContentType, contentType, expected), Size: object.Size})
+				}
+				if opts.CheckCRC && header.Get(oss.HTTPHeaderOssCRC64) == "" {
+					findings = append(findings, IntegrityFinding{Key: key, Issue: integrityMissingCRC, Detail: s.msg(msgIntegrityMissingCRC), Size: object.Size})
+				}
+			}
+
+			store.mu.Lock()
+			report.Scanned += int64(len(batch))
+			for _, finding := range findings {
+				report.Counts[finding.Issue]++
+				if len(report.Findings) < maxIntegrityFindings {
+					report.Findings = append(report.Findings, finding)
+					pending = append(pending, finding)
+				} else {
+					report.Truncated = true
+				}
+			}
+			emit(false)
+			store.mu.Unlock()
+			return failures
+		})
+
+	store.mu.Lock()
+	report.Scanned = result.Objects
+	report.HeadRequests = min(heads.Load(), int64(opts.MaxHeadRequests))
+	report.HeadLimitReached = heads.Load() > int64(opts.MaxHeadRequests)
+	report.FinishedAtMs = time.Now().UnixMilli()
+	found := int64(0)
+	for _, count := range report.Counts {
+		found += count
+	}
+	level, outcome := logLevelInfo, opOutcomeOK
+	switch {
+	case ctx.Err() != nil:
+		report.Status = prefixJobCanceled
+		report.Message = s.msg(msgIntegrityCanceled, report.Scanned)
+		err = ctx.Err()
+		level, outcome = logLevelWarn, opOutcomeError
+	case err != nil:
+		report.Status = prefixJobError
+		report.Message = s.errorf(msgListFolderFailed, err).Error()
+		level, outcome = logLevelError, opOutcomeError
+	default:
+		report.Status = prefixJobSuccess
+		report.Message = s.msg(msgIntegrityDone, report.Scanned, found)
+	}
+	if result.Failed > 0 {
+		report.Message += "; " + s.msg(msgIntegrityHeadFailed, result.Failed, prefixFailureText(result.Failures))
+	}
+	s.logOperationEvent(level, "RunIntegrityScan", outcome, buildOssPath(report.Bucket, report.Prefix)+": "+report.Message, err)
+	emit(true)
+	store.mu.Unlock()
+	store.finish(report.ID)
+}
+
+func (s *OSSService) emitIntegrityScanUpdate(update IntegrityScanUpdate) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, "scan:update", update)
+}
+
+// GetIntegrityReport returns the report of a running or recently finished scan.
+func (s *OSSService) GetIntegrityReport(id string) (IntegrityReport, error) {
+	report, ok := s.integrityScans.get(id)
+	if !ok {
+		return IntegrityReport{}, fmt.Errorf("scan %s is not known", id)
+	}
+	return report, nil
+}
+
+// ExportIntegrityReportCSV writes the findings of a scan to path as CSV.
+func (s *OSSService) ExportIntegrityReportCSV(id string, path string) error {
+	path = strings.TrimSpace(expandLeadingTilde(path))
+	if path == "" {
+		return fmt.Errorf("export path is required")
+	}
+	report, err := s.GetIntegrityReport(id)
+	if err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	w := csv.NewWriter(&out)
+	_ = w.Write([]string{"key", "issue", "detail", "size_bytes"})
+	for _, finding := range report.Findings {
+		_ = w.Write([]string{buildOssPath(report.Bucket, finding.Key), finding.Issue, finding.Detail, strconv.FormatInt(finding.Size, 10)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(out.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
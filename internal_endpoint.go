@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	internalEndpointProbeTimeout = 3 * time.Second
+	internalEndpointOKTTL        = 5 * time.Minute
+	internalEndpointFailTTL      = 30 * time.Second
+)
+
+// ErrInternalEndpointUnreachable is returned when a profile asks for the internal endpoint but the
+// machine cannot reach it, which almost always means it is not an ECS instance in that region.
+var ErrInternalEndpointUnreachable = errors.New("internal endpoint unreachable — are you on ECS in this region?")
+
+type internalEndpointProbe struct {
+	err       error
+	checkedAt time.Time
+}
+
+// dialEndpoint connects to host, on port 443 unless host names a port. An IPv6 literal may come
+// with or without brackets.
+func dialEndpoint(host string) (net.Conn, error) {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), "443")
+	}
+	return net.DialTimeout("tcp", host, internalEndpointProbeTimeout)
+}
+
+func probeEndpointReachable(host string) error {
+	conn, err := dialEndpoint(host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// endpointAddressFamily reports whether a connection to host goes over "ipv4" or "ipv6". The dial
+// prefers addresses the way the SDK's transport does, so it gets the same family.
+func endpointAddressFamily(host string) (string, error) {
+	conn, err := dialEndpoint(host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return "", nil
+	}
+	if addr.IP.To4() != nil {
+		return "ipv4", nil
+	}
+	return "ipv6", nil
+}
+
+// checkInternalEndpoint fails fast with ErrInternalEndpointUnreachable instead of letting every
+// request run into a connect timeout. Results are cached per host.
+func (s *OSSService) checkInternalEndpoint(config OSSConfig, endpoint string) error {
+	if !config.UseInternalEndpoint {
+		return nil
+	}
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	if !strings.Contains(strings.ToLower(host), "-internal.") {
+		return nil
+	}
+
+	s.internalProbeMu.Lock()
+	probe, ok := s.internalProbes[host]
+	s.internalProbeMu.Unlock()
+	if ok {
+		ttl := internalEndpointOKTTL
+		if probe.err != nil {
+			ttl = internalEndpointFailTTL
+		}
+		if time.Since(probe.checkedAt) < ttl {
+			return probe.err
+		}
+	}
+
+	var result error
+	if err := probeEndpointReachable(host); err != nil {
+		result = fmt.Errorf("%w (%s: %v)", ErrInternalEndpointUnreachable, host, err)
+	}
+
+	s.internalProbeMu.Lock()
+	s.internalProbes[host] = internalEndpointProbe{err: result, checkedAt: time.Now()}
+	s.internalProbeMu.Unlock()
+	return result
+}
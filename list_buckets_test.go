@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func writeBucketList(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><ListAllMyBucketsResult><Owner><ID>1</ID><DisplayName>1</DisplayName></Owner>` + body + `</ListAllMyBucketsResult>`))
+}
+
+func writeOSSError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>` + code + `</Code><Message>` + message + `</Message><RequestId>1</RequestId><HostId>host</HostId></Error>`))
+}
+
+// ossutilFallbackMarker installs a fake ossutil listing one bucket and returns a file that exists
+// once it ran.
+func ossutilFallbackMarker(t *testing.T, s *OSSService) string {
+	t.Helper()
+	marker := filepath.Join(t.TempDir(), "ran")
+	fakeOssutil(t, s, `if [ "$1" = "ls" ]; then touch '`+marker+`'; echo 'oss://from-ossutil'; echo 'Bucket Number is: 1'; fi`)
+	return marker
+}
+
+func TestListBucketsThroughSDK(t *testing.T) {
+	s := newTestService(t)
+	marker := ossutilFallbackMarker(t, s)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("marker") == "" {
+			writeBucketList(w, `<Prefix></Prefix><Marker></Marker><MaxKeys>1000</MaxKeys><IsTruncated>true</IsTruncated><NextMarker>logs</NextMarker><Buckets>`+
+				`<Bucket><Name>logs</Name><Location>oss-cn-beijing</Location><CreationDate>2024-01-02T03:04:05.000Z</CreationDate><StorageClass>IA</StorageClass></Bucket></Buckets>`)
+			return
+		}
+		writeBucketList(w, `<IsTruncated>false</IsTruncated><Buckets>`+
+			`<Bucket><Name>photos</Name><Location>oss-cn-hangzhou</Location><Region>cn-hangzhou</Region><CreationDate>2024-02-03T04:05:06.000Z</CreationDate><StorageClass>Standard</StorageClass></Bucket></Buckets>`)
+	})
+
+	buckets, err := s.ListBuckets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("buckets = %+v, want both pages", buckets)
+	}
+	if got := buckets[0]; got.Name != "logs" || got.Region != "cn-beijing" || got.StorageClass != "IA" || got.CreationDate == "" {
+		t.Errorf("first bucket = %+v", got)
+	}
+	if got := buckets[1]; got.Name != "photos" || got.Region != "cn-hangzhou" {
+		t.Errorf("second bucket = %+v", got)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("ossutil ran although the SDK listing succeeded")
+	}
+}
+
+func TestListBucketsEmptyAccount(t *testing.T) {
+	s := newTestService(t)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		writeBucketList(w, `<IsTruncated>false</IsTruncated><Buckets></Buckets>`)
+	})
+
+	buckets, err := s.ListBuckets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buckets == nil || len(buckets) != 0 {
+		t.Errorf("buckets of an empty account = %#v, want an empty list", buckets)
+	}
+}
+
+func TestListBucketsServiceErrorsDoNotFallBack(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		code, message string
+	}{
+		{"auth failure", http.StatusForbidden, "InvalidAccessKeyId", "The OSS Access Key Id you provided does not exist in our records."},
+		{"wrong region", http.StatusForbidden, "SignatureDoesNotMatch", "Invalid signing region in Authorization header."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			marker := ossutilFallbackMarker(t, s)
+			config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+				writeOSSError(w, tt.status, tt.code, tt.message)
+			})
+
+			buckets, err := s.ListBuckets(config)
+			if err == nil {
+				t.Fatalf("ListBuckets = %+v, want an error", buckets)
+			}
+			if !strings.Contains(err.Error(), tt.code) {
+				t.Errorf("error %q does not name %s", err, tt.code)
+			}
+			if _, statErr := os.Stat(marker); statErr == nil {
+				t.Error("ossutil ran although OSS answered the request")
+			}
+		})
+	}
+}
+
+func TestListBucketsPinnedWhenDenied(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		writeOSSError(w, http.StatusForbidden, "AccessDenied", "You have no right to access this object.")
+	})
+	profile := OSSProfile{Name: "team", Config: config, PinnedBuckets: []string{"team-bucket"}}
+	if err := s.saveAppStateToDir(s.configDir, appState{Profiles: []OSSProfile{profile}}); err != nil {
+		t.Fatal(err)
+	}
+
+	buckets, err := s.ListBuckets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "team-bucket" {
+		t.Errorf("buckets = %+v, want the pinned bucket", buckets)
+	}
+}
+
+func TestListBucketsFallsBackToOssutilOnTransportError(t *testing.T) {
+	s := newTestService(t)
+	marker := ossutilFallbackMarker(t, s)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		// Something that is no HTTP at all, like a proxy the SDK transport cannot talk to.
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("garbage\r\n\r\n"))
+		conn.Close()
+	})
+
+	buckets, err := s.ListBuckets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatal("ossutil did not run after the transport error")
+	}
+	if len(buckets) != 1 || buckets[0].Name != "from-ossutil" {
+		t.Errorf("buckets = %+v, want the ossutil listing", buckets)
+	}
+}
+
+func TestListBucketsAccessPointEndpoint(t *testing.T) {
+	s := newTestService(t)
+	config := OSSConfig{AccessKeyID: "id", AccessKeySecret: "secret", Region: "cn-hangzhou", Endpoint: "ap-01.cn-hangzhou.oss-accesspoint.aliyuncs.com"}
+
+	_, err := s.ListBuckets(config)
+	if err == nil || !strings.Contains(err.Error(), "oss-cn-hangzhou.aliyuncs.com") {
+		t.Errorf("ListBuckets through an access point = %v, want the service endpoint suggested", err)
+	}
+	if errors.Is(err, ErrOffline) {
+		t.Error("the access point check went to the network")
+	}
+}
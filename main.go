@@ -28,17 +28,19 @@ func main() {
 	appMenu := menu.NewMenu()
 	appSubmenu := appMenu.AddSubmenu(appName)
 	appSubmenu.AddText("About "+appName, nil, func(_ *menu.CallbackData) {
-		if app.ctx == nil {
+		ctx := app.runtimeContext()
+		if ctx == nil {
 			return
 		}
-		runtime.EventsEmit(app.ctx, "app:about")
+		runtime.EventsEmit(ctx, "app:about")
 	})
 	appSubmenu.AddSeparator()
 	appSubmenu.AddText("Quit", keys.CmdOrCtrl("q"), func(_ *menu.CallbackData) {
-		if app.ctx == nil {
+		ctx := app.runtimeContext()
+		if ctx == nil {
 			return
 		}
-		runtime.Quit(app.ctx)
+		runtime.Quit(ctx)
 	})
 	appMenu.Append(menu.EditMenu())
 	appMenu.Append(menu.WindowMenu())
@@ -56,6 +58,10 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 26, G: 26, B: 46, A: 255},
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
+		// A second launch hands its arguments to the running instance and exits, so two
+		// instances never write ~/.walioss or run transfers at the same time.
+		SingleInstanceLock: app.singleInstanceLock(),
 		DragAndDrop: &options.DragAndDrop{
 			EnableFileDrop: true,
 		},
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Move job statuses reported in MoveJobUpdate.Status.
+const (
+	moveJobRunning  = "running"
+	moveJobSuccess  = "success"
+	moveJobError    = "error"
+	moveJobCanceled = "canceled"
+)
+
+// moveJobShutdownWait bounds how long quitting waits for running moves to stop and log a summary.
+const moveJobShutdownWait = 5 * time.Second
+
+// MoveJobUpdate reports a folder move. It is emitted as "move:update" while the job runs and once
+// more, with FinishedAtMs set, when it ends.
+type MoveJobUpdate struct {
+	ID         string `json:"id"`
+	SrcBucket  string `json:"srcBucket"`
+	SrcPrefix  string `json:"srcPrefix"`
+	DestBucket string `json:"destBucket"`
+	DestPrefix string `json:"destPrefix"`
+	Status     string `json:"status"`
+	Copied     int    `json:"copied"`
+	Deleted    int    `json:"deleted"`
+	Bytes      int64  `json:"bytes"`
+	CurrentKey string `json:"currentKey,omitempty"`
+	Message    string `json:"message,omitempty"`
+	// DeleteFailed lists keys that were copied but whose source could not be deleted; they now
+	// exist in both places.
+	DeleteFailed []string `json:"deleteFailed,omitempty"`
+	StartedAtMs  int64    `json:"startedAtMs"`
+	FinishedAtMs int64    `json:"finishedAtMs,omitempty"`
+}
+
+// startMoveJob moves everything under srcPrefix to destPrefix in the background and returns the
+// job ID. The job stops between objects when canceled through CancelMoveJob or when the app quits.
+func (s *OSSService) startMoveJob(config OSSConfig, srcBucket *oss.Bucket, destBucket *oss.Bucket, srcPrefix string, destPrefix string) string {
+	ctx, cancel := context.WithCancel(s.baseContext())
+	id := fmt.Sprintf("move-%d", atomic.AddUint64(&s.moveJobSeq, 1))
+
+	s.moveJobsMu.Lock()
+	s.moveJobs[id] = cancel
+	s.moveJobsMu.Unlock()
+
+	update := MoveJobUpdate{
+		ID:          id,
+		SrcBucket:   srcBucket.BucketName,
+		SrcPrefix:   srcPrefix,
+		DestBucket:  destBucket.BucketName,
+		DestPrefix:  destPrefix,
+		Status:      moveJobRunning,
+		StartedAtMs: time.Now().UnixMilli(),
+	}
+
+	s.moveJobsWG.Add(1)
+	go func() {
+		defer s.moveJobsWG.Done()
+		defer func() {
+			s.moveJobsMu.Lock()
+			delete(s.moveJobs, id)
+			s.moveJobsMu.Unlock()
+			cancel()
+		}()
+		s.runMoveJob(ctx, config, srcBucket, destBucket, update)
+	}()
+	return id
+}
+
+func (s *OSSService) runMoveJob(ctx context.Context, config OSSConfig, srcBucket *oss.Bucket, destBucket *oss.Bucket, update MoveJobUpdate) {
+	emitInterval := s.transferEmitInterval()
+	var lastEmit time.Time
+	emit := func(force bool) {
+		if !force && time.Since(lastEmit) < emitInterval {
+			return
+		}
+		lastEmit = time.Now()
+		s.emitMoveJobUpdate(update)
+	}
+	emit(true)
+
+	var undone []undoObject
+	err := s.moveFolderObjects(ctx, config, srcBucket, destBucket, &update, &undone, func() { emit(false) })
+
+	update.CurrentKey = ""
+	update.FinishedAtMs = time.Now().UnixMilli()
+	level, outcome := logLevelInfo, opOutcomeOK
+	switch {
+	case ctx.Err() != nil:
+		update.Status = moveJobCanceled
+		update.Message = s.msg(msgMoveJobCanceled, update.Copied)
+		err = ctx.Err()
+		level, outcome = logLevelWarn, opOutcomeError
+	case err != nil:
+		update.Status = moveJobError
+		update.Message = s.redact(err.Error())
+		level, outcome = logLevelError, opOutcomeError
+	default:
+		update.Status = moveJobSuccess
+		update.Message = s.msg(msgMoveJobDone, update.Copied)
+	}
+	if failed := len(update.DeleteFailed); failed > 0 {
+		update.Message += "; " + s.msg(msgMoveJobDeleteFailed, failed)
+		if level == logLevelInfo {
+			level = logLevelWarn
+		}
+	}
+
+	// The summary also goes to the operation log: when the app is quitting nobody sees the event.
+	detail := fmt.Sprintf("%s -> %s: %s", buildOssPath(update.SrcBucket, update.SrcPrefix), buildOssPath(update.DestBucket, update.DestPrefix), update.Message)
+	if len(update.DeleteFailed) > 0 {
+		detail += "; source not deleted: " + strings.Join(update.DeleteFailed, ", ")
+	}
+	s.logOperationEvent(level, "MoveObject", outcome, detail, err)
+	auditErr := err
+	if update.Status == moveJobCanceled {
+		auditErr = fmt.Errorf("%w: %s", ErrOperationCanceled, update.Message)
+	} else if auditErr == nil && len(update.DeleteFailed) > 0 {
+		auditErr = errors.New(update.Message)
+	}
+	target := buildOssPath(update.SrcBucket, update.SrcPrefix) + " -> " + buildOssPath(update.DestBucket, update.DestPrefix)
+	s.recordAudit(config, "MoveFolder", update.SrcBucket, target, int64(update.Copied), auditErr)
+	// A move that stopped halfway can be undone as far as it got.
+	s.rememberMove(config, update.SrcBucket, update.SrcPrefix, update.DestBucket, update.DestPrefix, undone)
+	emit(true)
+}
+
+// moveFolderObjects copies each object under update.SrcPrefix and deletes its source. A failed copy
+// ends the move; a failed delete is recorded in update.DeleteFailed and the move goes on. What was
+// moved is appended to undone.
+func (s *OSSService) moveFolderObjects(ctx context.Context, config OSSConfig, srcBucket *oss.Bucket, destBucket *oss.Bucket, update *MoveJobUpdate, undone *[]undoObject, progress func()) error {
+	sameBucket := update.SrcBucket == update.DestBucket
+	marker := ""
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var lor oss.ListObjectsResult
+		err := s.withSDKRetryContext(ctx, "ListObjects", func() error {
+			var listErr error
+			lor, listErr = srcBucket.ListObjects(
+				oss.Prefix(update.SrcPrefix),
+				oss.Marker(marker),
+				oss.MaxKeys(1000),
+			)
+			return listErr
+		})
+		if err != nil {
+			return s.errorf(msgListFolderFailed, err)
+		}
+
+		for _, object := range lor.Objects {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			key := object.Key
+			targetKey, ok := movedKey(update.SrcPrefix, update.DestPrefix, key)
+			if !ok || sameBucket && key == targetKey {
+				continue
+			}
+			update.CurrentKey = key
+			progress()
+
+			var etag string
+			err = s.withSDKRetryContext(ctx, "CopyObject", func() error {
+				var copyErr error
+				etag, copyErr = s.copyObjectForMove(update.SrcBucket, destBucket, key, targetKey)
+				return copyErr
+			})
+			if err != nil {
+				return s.errorf(msgCopyFailed, err)
+			}
+			update.Copied++
+			update.Bytes += object.Size
+
+			moved := undoObject{srcKey: key, destKey: targetKey, etag: etag}
+			if err := s.withSDKRetryContext(ctx, "DeleteObject", func() error { return srcBucket.DeleteObject(key) }); err != nil {
+				s.logOperationEvent(logLevelWarn, "MoveObject", opOutcomeError, key, s.deleteSourceError(config, update.SrcBucket, err))
+				update.DeleteFailed = append(update.DeleteFailed, key)
+				moved.sourceKept = true
+			} else {
+				update.Deleted++
+			}
+			*undone = append(*undone, moved)
+			progress()
+		}
+
+		if !lor.IsTruncated {
+			return nil
+		}
+		marker = lor.NextMarker
+	}
+}
+
+// movedKey is where key goes when the folder srcPrefix moves to destPrefix: the part below
+// srcPrefix is kept byte for byte, empty segments included, so "logs//2024//app.log" moved from
+// "logs//" to "archive/" becomes "archive/2024//app.log".
+func movedKey(srcPrefix string, destPrefix string, key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, srcPrefix)
+	if !ok {
+		return "", false
+	}
+	return destPrefix + rest, true
+}
+
+// copyObjectForMove copies srcKey in srcBucketName to destKey in destBucket and returns the ETag
+// of the copy. Repeating it gives the same result, so it is safe to retry.
+func (s *OSSService) copyObjectForMove(srcBucketName string, destBucket *oss.Bucket, srcKey string, destKey string) (string, error) {
+	var result oss.CopyObjectResult
+	var err error
+	if srcBucketName == destBucket.BucketName {
+		result, err = destBucket.CopyObject(srcKey, destKey)
+	} else {
+		result, err = destBucket.CopyObjectFrom(srcBucketName, srcKey, destKey)
+	}
+	return result.ETag, err
+}
+
+func (s *OSSService) emitMoveJobUpdate(update MoveJobUpdate) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, "move:update", update)
+}
+
+// CancelMoveJob stops a folder move started by MoveObject after the object being moved. The final
+// move:update event has the status "canceled" and lists what was moved.
+func (s *OSSService) CancelMoveJob(id string) error {
+	s.moveJobsMu.Lock()
+	cancel, ok := s.moveJobs[id]
+	s.moveJobsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("move %s is not running", id)
+	}
+	cancel()
+	return nil
+}
+
+// stopMoveJobs cancels all running moves when the app quits and gives them a moment to log their
+// summaries.
+func (s *OSSService) stopMoveJobs() {
+	s.moveJobsMu.Lock()
+	for _, cancel := range s.moveJobs {
+		cancel()
+	}
+	s.moveJobsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.moveJobsWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(moveJobShutdownWait):
+		s.logOperationEvent(logLevelWarn, "MoveObject", opOutcomeError, "moves still running at exit", errors.New("timed out waiting for moves to stop"))
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Transfers that run at least this long (and every folder transfer) count as long-running jobs.
+const longRunningJobThreshold = time.Minute
+
+// NotificationSettings chooses which events raise a desktop notification.
+type NotificationSettings struct {
+	TransferComplete bool `json:"transferComplete"`
+	TransferFailed   bool `json:"transferFailed"`
+	JobComplete      bool `json:"jobComplete"`
+	// DoNotDisturb silences every notification regardless of the switches above.
+	DoNotDisturb bool `json:"doNotDisturb"`
+}
+
+func defaultNotificationSettings() NotificationSettings {
+	return NotificationSettings{TransferComplete: true, TransferFailed: true, JobComplete: true}
+}
+
+type notifier struct {
+	mu       sync.Mutex
+	settings NotificationSettings
+	// notified remembers finished transfers already announced; a group is re-emitted on
+	// every child update.
+	notified map[string]struct{}
+}
+
+func (s *OSSService) setNotificationSettings(settings NotificationSettings) {
+	s.notifier.mu.Lock()
+	s.notifier.settings = settings
+	s.notifier.mu.Unlock()
+}
+
+func (s *OSSService) notificationSettings() NotificationSettings {
+	s.notifier.mu.Lock()
+	defer s.notifier.mu.Unlock()
+	return s.notifier.settings
+}
+
+// notify shows a notification in the background; failures only reach the operation log.
+func (s *OSSService) notify(title string, body string) {
+	go func() {
+		if err := sendDesktopNotification(title, body); err != nil {
+			s.logOperationEvent(logLevelWarn, "notify", opOutcomeError, title, err)
+		}
+	}()
+}
+
+// notifyTransferFinished announces a finished top-level transfer when the settings allow it.
+func (s *OSSService) notifyTransferFinished(update TransferUpdate) {
+	if update.ParentID != "" || (update.Status != TransferStatusSuccess && update.Status != TransferStatusError) {
+		return
+	}
+
+	s.notifier.mu.Lock()
+	settings := s.notifier.settings
+	if _, done := s.notifier.notified[update.ID]; done {
+		s.notifier.mu.Unlock()
+		return
+	}
+	if len(s.notifier.notified) >= maxTransferHistoryRecords {
+		s.notifier.notified = make(map[string]struct{})
+	}
+	s.notifier.notified[update.ID] = struct{}{}
+	s.notifier.mu.Unlock()
+
+	if settings.DoNotDisturb {
+		return
+	}
+
+	verb := "Upload"
+	if update.Type == TransferTypeDownload {
+		verb = "Download"
+	}
+	if update.Status == TransferStatusError {
+		if settings.TransferFailed {
+			s.notify(fmt.Sprintf("%s failed", verb), fmt.Sprintf("%s: %s", update.Name, update.Message))
+		}
+		return
+	}
+
+	longRunning := update.IsGroup || (update.StartedAtMs > 0 &&
+		time.Duration(update.FinishedAtMs-update.StartedAtMs)*time.Millisecond >= longRunningJobThreshold)
+	if (longRunning && settings.JobComplete) || (!longRunning && settings.TransferComplete) {
+		s.notify(fmt.Sprintf("%s complete", verb), update.Name)
+	}
+}
+
+// SendTestNotification shows a notification right away so the settings page can check that the
+// platform integration works. It ignores do-not-disturb and reports why nothing could be shown.
+func (s *OSSService) SendTestNotification() error {
+	return sendDesktopNotification("walioss", "Notifications are working.")
+}
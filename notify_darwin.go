@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// sendDesktopNotification shows a Notification Center banner through osascript. The text is
+// handed over in the environment so it never has to be quoted into AppleScript.
+func sendDesktopNotification(title string, body string) error {
+	cmd := exec.Command("osascript", "-e",
+		`display notification (system attribute "WALIOSS_NOTIFY_BODY") with title (system attribute "WALIOSS_NOTIFY_TITLE")`)
+	cmd.Env = append(os.Environ(), "WALIOSS_NOTIFY_TITLE="+title, "WALIOSS_NOTIFY_BODY="+body)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %s", ossutilOutputOrError(err, output))
+	}
+	return nil
+}
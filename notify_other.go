@@ -0,0 +1,22 @@
+//go:build !darwin && !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// sendDesktopNotification uses notify-send, which ships with libnotify but is not installed
+// everywhere.
+func sendDesktopNotification(title string, body string) error {
+	binary, err := exec.LookPath("notify-send")
+	if err != nil {
+		return errors.New("notify-send was not found: install libnotify (libnotify-bin on Debian/Ubuntu, libnotify on Fedora and Arch) to get desktop notifications")
+	}
+	if output, err := exec.Command(binary, "--app-name=walioss", title, body).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %s", ossutilOutputOrError(err, output))
+	}
+	return nil
+}
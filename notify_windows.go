@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Shows a toast through the WinRT notification API; title and body come from the environment so
+// they never have to be quoted into the script.
+const windowsToastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$texts = $template.GetElementsByTagName('text')
+$texts.Item(0).AppendChild($template.CreateTextNode($env:WALIOSS_NOTIFY_TITLE)) | Out-Null
+$texts.Item(1).AppendChild($template.CreateTextNode($env:WALIOSS_NOTIFY_BODY)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('walioss').Show($toast)
+`
+
+func sendDesktopNotification(title string, body string) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+	cmd.Env = append(os.Environ(), "WALIOSS_NOTIFY_TITLE="+title, "WALIOSS_NOTIFY_BODY="+body)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %s", ossutilOutputOrError(err, output))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// awkwardKeys are object names that must reach ossutil byte for byte.
+var awkwardKeys = []string{
+	"two  spaces.txt",
+	"ends with space ",
+	"hash#1.txt",
+	"what?.txt",
+	"100%.csv",
+	"%E4%B8%AD.txt",
+	"a+b=c.txt",
+	"emoji 😀.png",
+	"中文 文件.txt",
+}
+
+func TestOssPathRoundTripsAwkwardKeys(t *testing.T) {
+	for _, key := range append(awkwardKeys, "日本/報告 2024.pdf", "folder with space/") {
+		bucket, got, ok := parseOssPath(buildOssPath("bucket", key))
+		if !ok || bucket != "bucket" || got != key {
+			t.Errorf("parseOssPath(buildOssPath(%q)) = %q, %q, %v", key, bucket, got, ok)
+		}
+	}
+}
+
+// fakeOssutilBucket installs an ossutil that lists keys the way ossutil ls prints them, "downloads"
+// by writing a file and logs the oss:// argument of every cp and rm.
+func fakeOssutilBucket(t *testing.T, s *OSSService, keys []string) (logPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	keysPath := filepath.Join(dir, "keys")
+	if err := os.WriteFile(keysPath, []byte(strings.Join(keys, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logPath = filepath.Join(dir, "log")
+	fakeOssutil(t, s, `case "$1" in
+ls)
+	echo 'LastModifiedTime                   Size(B)  StorageClass   ETAG                                  ObjectName'
+	while IFS= read -r key; do
+		printf '2024-01-02 03:04:05 +0800 CST %12d      Standard   D41D8CD98F00B204E9800998ECF8427E      oss://bucket/%s\n' 5 "$key"
+	done < '`+keysPath+`'
+	echo 'Object Number is: `+strconv.Itoa(len(keys))+`'
+	;;
+cp) printf 'cp %s\n' "$2" >> '`+logPath+`'; printf 'data' > "$3" ;;
+rm) printf 'rm %s\n' "$2" >> '`+logPath+`' ;;
+esac`)
+	return logPath
+}
+
+func TestAwkwardKeysListDownloadDelete(t *testing.T) {
+	s := newTestService(t)
+	logPath := fakeOssutilBucket(t, s, append(awkwardKeys, "日本/報告 2024.pdf"))
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+
+	objects, err := s.ListObjects(config, "bucket", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := make(map[string]ObjectInfo)
+	for _, object := range objects {
+		listed[object.Name] = object
+	}
+	for _, key := range awkwardKeys {
+		object, ok := listed[key]
+		if !ok {
+			t.Errorf("listing lacks %q; got %v", key, objects)
+			continue
+		}
+		if object.Path != buildOssPath("bucket", key) || object.Size != 5 {
+			t.Errorf("listed %q as %+v", key, object)
+		}
+	}
+	if folder, ok := listed["日本"]; !ok || folder.Type != "Folder" || folder.Path != "oss://bucket/日本/" {
+		t.Errorf("folder 日本 listed as %+v, %v", folder, ok)
+	}
+
+	var want []string
+	localDir := t.TempDir()
+	for i, key := range awkwardKeys {
+		_, object, ok := parseOssPath(listed[key].Path)
+		if !ok {
+			t.Fatalf("listed path %q does not parse", listed[key].Path)
+		}
+		local := filepath.Join(localDir, "file-"+strconv.Itoa(i))
+		if err := s.DownloadFile(config, "bucket", object, local); err != nil {
+			t.Fatalf("DownloadFile(%q): %v", object, err)
+		}
+		if _, err := os.Stat(local); err != nil {
+			t.Errorf("download of %q wrote nothing: %v", object, err)
+		}
+		token, err := s.RequestConfirmationToken(config, confirmOpDeleteObject, listed[key].Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.DeleteObject(config, "bucket", object, token.Token); err != nil {
+			t.Fatalf("DeleteObject(%q): %v", object, err)
+		}
+		want = append(want, "cp "+buildOssPath("bucket", key), "rm "+buildOssPath("bucket", key))
+	}
+
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(log), "\n"), "\n")
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ossutil was called with\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPercentInKeyIsNoProgress(t *testing.T) {
+	s := newTestService(t)
+	key := "reports/50%.csv"
+	// ossutil echoes the path it copies; the "50%" in it is no progress.
+	fakeOssutil(t, s, `echo "copying oss://bucket/`+key+`"`)
+
+	var done []int64
+	update := TransferUpdate{ProfileName: "prod", Type: TransferTypeDownload, Bucket: "bucket", Key: key, TotalBytes: 1000}
+	err := s.runOssutilWithProgress(context.Background(), []string{"cp"}, ossutilConnection{}, &update, func(u TransferUpdate) { done = append(done, u.DoneBytes) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bytes := range done {
+		if bytes == 500 {
+			t.Errorf("progress updates %v read the key's 50%% as progress", done)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// OSS naming limits, see https://help.aliyun.com/document_detail/31827.html.
+const (
+	minBucketNameLen  = 3
+	maxBucketNameLen  = 63
+	maxObjectKeyBytes = 1023
+)
+
+// NameError reports a bucket name or object key that breaks an OSS naming rule. Position is the
+// 1-based character the rule fails at, or 0 when the rule concerns the whole value.
+type NameError struct {
+	Field    string
+	Value    string
+	Position int
+	Rule     string
+}
+
+func (e *NameError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s is required", e.Field)
+	}
+	if e.Position > 0 {
+		return fmt.Sprintf("invalid %s %q: character %d %s", e.Field, e.Value, e.Position, e.Rule)
+	}
+	return fmt.Sprintf("invalid %s %q: %s", e.Field, e.Value, e.Rule)
+}
+
+// validateBucketName checks name against the OSS bucket naming rules: 3 to 63 lowercase letters,
+// digits and hyphens, neither starting nor ending with a hyphen.
+func validateBucketName(name string) error {
+	const field = "bucket name"
+	if name == "" {
+		return &NameError{Field: field}
+	}
+	position := 0
+	for _, r := range name {
+		position++
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			continue
+		}
+		rule := fmt.Sprintf("%q is not allowed; use lowercase letters, digits and hyphens", r)
+		if r >= 'A' && r <= 'Z' {
+			rule = fmt.Sprintf("%q is uppercase; bucket names are lowercase", r)
+		}
+		return &NameError{Field: field, Value: name, Position: position, Rule: rule}
+	}
+	if position < minBucketNameLen || position > maxBucketNameLen {
+		return &NameError{Field: field, Value: name, Rule: fmt.Sprintf("is %d characters long; it must be %d to %d", position, minBucketNameLen, maxBucketNameLen)}
+	}
+	if name[0] == '-' {
+		return &NameError{Field: field, Value: name, Position: 1, Rule: "is a hyphen; names must start with a letter or digit"}
+	}
+	if name[len(name)-1] == '-' {
+		return &NameError{Field: field, Value: name, Position: position, Rule: "is a hyphen; names must end with a letter or digit"}
+	}
+	return nil
+}
+
+// validateObjectKey checks a key in the normalized form OSSService uses (see normalizeObjectKey):
+// 1 to 1023 bytes of UTF-8 without a leading slash or line breaks. Keys are otherwise opaque; a
+// trailing slash marks a folder.
+func validateObjectKey(key string) error {
+	const field = "object key"
+	if key == "" {
+		return &NameError{Field: field}
+	}
+	position := 0
+	for i, r := range key {
+		position++
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(key[i:]); size == 1 {
+				return &NameError{Field: field, Value: key, Position: position, Rule: "is not valid UTF-8"}
+			}
+		}
+		if r == '\r' || r == '\n' {
+			return &NameError{Field: field, Value: key, Position: position, Rule: "is a line break, which OSS does not allow in keys"}
+		}
+	}
+	if len(key) > maxObjectKeyBytes {
+		return &NameError{Field: field, Value: key, Rule: fmt.Sprintf("is %d bytes long; OSS allows at most %d", len(key), maxObjectKeyBytes)}
+	}
+	if strings.HasPrefix(key, "/") {
+		return &NameError{Field: field, Value: key, Position: 1, Rule: "is a slash; keys must not start with one"}
+	}
+	return nil
+}
+
+// validateObjectRef checks the bucket and key of a single object or folder.
+func validateObjectRef(bucket string, key string) error {
+	if err := validateBucketName(bucket); err != nil {
+		return err
+	}
+	return validateObjectKey(key)
+}
+
+// ValidateBucketName checks name as typed into a form; every method taking a bucket name runs the
+// same check.
+func (s *OSSService) ValidateBucketName(name string) error {
+	return validateBucketName(strings.TrimSpace(name))
+}
+
+// ValidateObjectKey checks key as typed into a form, after the same normalization the object
+// methods apply.
+func (s *OSSService) ValidateObjectKey(key string) error {
+	return validateObjectKey(normalizeObjectKey(key))
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	opLogDirName   = "logs"
+	opLogFileName  = "app.log"
+	opLogMaxBytes  = 5 << 20
+	opLogKeepFiles = 5
+	// opLogRecentMax bounds the entries kept in memory for GetRecentLogEntries.
+	opLogRecentMax = 1000
+
+	logLevelDebug = "debug"
+	logLevelInfo  = "info"
+	logLevelWarn  = "warn"
+	logLevelError = "error"
+
+	opOutcomeOK    = "ok"
+	opOutcomeError = "error"
+	opOutcomeRetry = "retry"
+	opOutcomeStart = "started"
+)
+
+var logLevelRank = map[string]int{
+	logLevelDebug: 0,
+	logLevelInfo:  1,
+	logLevelWarn:  2,
+	logLevelError: 3,
+}
+
+// OperationLogEntry is one line of the operation log.
+type OperationLogEntry struct {
+	Time       string `json:"time"`
+	Level      string `json:"level"`
+	Method     string `json:"method"`
+	Bucket     string `json:"bucket,omitempty"`
+	Key        string `json:"key,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Outcome    string `json:"outcome"`
+	Error      string `json:"error,omitempty"`
+	ErrorCode  string `json:"errorCode,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// operationLog appends entries as JSON lines to <dir>/app.log, rotating to app.log.1 … app.log.N
+// once the file grows past opLogMaxBytes.
+type operationLog struct {
+	mu     sync.Mutex
+	dir    string
+	file   *os.File
+	size   int64
+	level  string
+	opened bool
+	recent []OperationLogEntry
+}
+
+func newOperationLog(dir string) *operationLog {
+	return &operationLog{dir: dir, level: logLevelInfo}
+}
+
+func normalizeLogLevel(level string) string {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if _, ok := logLevelRank[level]; ok {
+		return level
+	}
+	return logLevelInfo
+}
+
+func validateLogLevel(level string) error {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if level == "" {
+		return nil
+	}
+	if _, ok := logLevelRank[level]; !ok {
+		return fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	return nil
+}
+
+func (l *operationLog) path() string {
+	return filepath.Join(l.dir, opLogFileName)
+}
+
+func (l *operationLog) setLevel(level string) {
+	l.mu.Lock()
+	l.level = normalizeLogLevel(level)
+	l.mu.Unlock()
+}
+
+// ensureOpenLocked opens the current log file and seeds the in-memory tail from it, so the log
+// pane shows the previous session right after startup.
+func (l *operationLog) ensureOpenLocked() error {
+	if l.opened {
+		return nil
+	}
+	if err := os.MkdirAll(l.dir, 0700); err != nil {
+		return err
+	}
+	l.recent = readOperationLogTail(l.path(), opLogRecentMax)
+
+	file, err := os.OpenFile(l.path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.size = info.Size()
+	l.opened = true
+	return nil
+}
+
+func readOperationLogTail(path string, limit int) []OperationLogEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var out []OperationLogEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry OperationLogEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil {
+			continue
+		}
+		out = append(out, entry)
+		if len(out) > limit {
+			out = out[len(out)-limit:]
+		}
+	}
+	return out
+}
+
+func (l *operationLog) rotateLocked() error {
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+	base := l.path()
+	os.Remove(fmt.Sprintf("%s.%d", base, opLogKeepFiles))
+	for i := opLogKeepFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", base, i), fmt.Sprintf("%s.%d", base, i+1))
+	}
+	if err := os.Rename(base, base+".1"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.OpenFile(base, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	l.file = file
+	l.size = 0
+	return nil
+}
+
+func (l *operationLog) write(entry OperationLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if logLevelRank[entry.Level] < logLevelRank[l.level] {
+		return
+	}
+	if err := l.ensureOpenLocked(); err != nil {
+		return
+	}
+
+	l.recent = append(l.recent, entry)
+	if len(l.recent) > opLogRecentMax {
+		l.recent = append([]OperationLogEntry(nil), l.recent[len(l.recent)-opLogRecentMax:]...)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+	if l.size+int64(len(line)) > opLogMaxBytes && l.size > 0 {
+		if err := l.rotateLocked(); err != nil {
+			return
+		}
+	}
+	if l.file == nil {
+		return
+	}
+	n, _ := l.file.Write(line)
+	l.size += int64(n)
+}
+
+// recentEntries returns up to n of the newest entries, oldest first.
+func (l *operationLog) recentEntries(n int) []OperationLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ensureOpenLocked()
+	if n <= 0 || n > len(l.recent) {
+		n = len(l.recent)
+	}
+	out := make([]OperationLogEntry, n)
+	copy(out, l.recent[len(l.recent)-n:])
+	return out
+}
+
+// logOperation records a finished operation. It is meant to be deferred with a pointer to the
+// method's named error result:
+//
+//	defer s.logOperation("GetBucketCORS", bucketName, "", time.Now(), &err)
+//
+// Every exported method returns through it, so this is also where an SDK error's field dump in the
+// result is replaced by its OSS code and request ID (see describeSDKErrors).
+func (s *OSSService) logOperation(method string, bucket string, key string, start time.Time, errp *error) {
+	entry := OperationLogEntry{
+		Time:       start.UTC().Format(time.RFC3339Nano),
+		Level:      logLevelInfo,
+		Method:     method,
+		Bucket:     strings.TrimSpace(bucket),
+		Key:        s.redact(key),
+		DurationMs: time.Since(start).Milliseconds(),
+		Outcome:    opOutcomeOK,
+	}
+	if errp != nil && *errp != nil {
+		*errp = describeSDKErrors(*errp)
+		entry.Level = logLevelError
+		entry.Outcome = opOutcomeError
+		entry.Error = s.redact((*errp).Error())
+		entry.ErrorCode = ossServiceErrorCode(*errp)
+		entry.RequestID = ossRequestID(*errp)
+	}
+	s.opLog.write(entry)
+}
+
+// logOperationEvent records something that is not a finished operation, such as a retry or an
+// ossutil invocation.
+func (s *OSSService) logOperationEvent(level string, method string, outcome string, detail string, err error) {
+	entry := OperationLogEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   level,
+		Method:  method,
+		Outcome: outcome,
+		Detail:  s.redact(detail),
+	}
+	if err != nil {
+		entry.Error = s.redact(err.Error())
+		entry.ErrorCode = ossServiceErrorCode(err)
+		entry.RequestID = ossRequestID(err)
+	}
+	s.opLog.write(entry)
+}
+
+// logTransferFinished records a transfer once it reaches a final status.
+func (s *OSSService) logTransferFinished(update TransferUpdate) {
+	if update.Status != TransferStatusSuccess && update.Status != TransferStatusError {
+		return
+	}
+	entry := OperationLogEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   logLevelInfo,
+		Method:  string(update.Type),
+		Bucket:  update.Bucket,
+		Key:     s.redact(update.Key),
+		Outcome: opOutcomeOK,
+		Detail:  update.Command,
+	}
+	if update.StartedAtMs > 0 && update.FinishedAtMs >= update.StartedAtMs {
+		entry.Time = time.UnixMilli(update.StartedAtMs).UTC().Format(time.RFC3339Nano)
+		entry.DurationMs = update.FinishedAtMs - update.StartedAtMs
+	}
+	if update.Status == TransferStatusError {
+		entry.Level = logLevelError
+		entry.Outcome = opOutcomeError
+		entry.Error = update.Message
+		entry.RequestID = ossRequestID(errors.New(update.Message))
+	}
+	s.opLog.write(entry)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const bucketCnameCacheTTL = 5 * time.Minute
+
+// CnameInfo is a custom domain bound to a bucket.
+type CnameInfo struct {
+	Domain            string `json:"domain"`
+	Status            string `json:"status"`
+	LastModified      string `json:"lastModified"`
+	CertificateType   string `json:"certificateType,omitempty"`
+	CertificateID     string `json:"certificateId,omitempty"`
+	CertificateStatus string `json:"certificateStatus,omitempty"`
+	CertificateExpiry string `json:"certificateExpiry,omitempty"`
+}
+
+// ObjectURLOptions controls how GetObjectURL builds a link.
+type ObjectURLOptions struct {
+	// UseCustomDomain builds the URL on a bound CNAME instead of the OSS endpoint.
+	UseCustomDomain bool `json:"useCustomDomain"`
+	// Domain picks a specific CNAME; empty means the first enabled one.
+	Domain string `json:"domain,omitempty"`
+	// Signed adds a signature valid for Expires (Go duration, default 15m).
+	Signed  bool   `json:"signed"`
+	Expires string `json:"expires,omitempty"`
+}
+
+// ObjectURLResult is the generated link plus the custom domains the caller could choose from.
+type ObjectURLResult struct {
+	URL     string   `json:"url"`
+	Domain  string   `json:"domain"`
+	Domains []string `json:"domains"`
+}
+
+type bucketCnameCacheEntry struct {
+	cnames    []CnameInfo
+	fetchedAt time.Time
+}
+
+func isCnameEnabled(info CnameInfo) bool {
+	return strings.EqualFold(info.Status, "Enabled")
+}
+
+// ListBucketCname returns the custom domains bound to a bucket and refreshes the per-bucket cache.
+func (s *OSSService) ListBucketCname(config OSSConfig, bucketName string) (_ []CnameInfo, err error) {
+	defer s.logOperation("ListBucketCname", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.ListBucketCname(bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom domains: %w", err)
+	}
+
+	out := make([]CnameInfo, 0, len(res.Cname))
+	for _, cname := range res.Cname {
+		out = append(out, CnameInfo{
+			Domain:            cname.Domain,
+			Status:            cname.Status,
+			LastModified:      cname.LastModified,
+			CertificateType:   cname.Certificate.Type,
+			CertificateID:     cname.Certificate.CertId,
+			CertificateStatus: cname.Certificate.Status,
+			CertificateExpiry: cname.Certificate.ValidEndDate,
+		})
+	}
+
+	s.bucketCnameCacheMu.Lock()
+	s.bucketCnameCache[bucketCacheKey(config, bucketName)] = bucketCnameCacheEntry{cnames: out, fetchedAt: time.Now()}
+	s.bucketCnameCacheMu.Unlock()
+
+	return out, nil
+}
+
+func (s *OSSService) cachedBucketCnames(config OSSConfig, bucketName string) ([]CnameInfo, error) {
+	s.bucketCnameCacheMu.Lock()
+	entry, ok := s.bucketCnameCache[bucketCacheKey(config, bucketName)]
+	s.bucketCnameCacheMu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < bucketCnameCacheTTL {
+		return entry.cnames, nil
+	}
+	return s.ListBucketCname(config, bucketName)
+}
+
+func escapeObjectKeyPath(object string) string {
+	segments := strings.Split(object, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// GetObjectURL builds a link to an object, either on the bucket's OSS endpoint or on one of its
+// enabled custom domains, optionally signed.
+func (s *OSSService) GetObjectURL(config OSSConfig, bucket string, object string, opts ObjectURLOptions) (_ ObjectURLResult, err error) {
+	defer s.logOperation("GetObjectURL", bucket, object, time.Now(), &err)
+
+	bucket = strings.TrimSpace(bucket)
+	object = strings.TrimLeft(strings.TrimSpace(object), "/")
+	if err := validateBucketName(bucket); err != nil {
+		return ObjectURLResult{}, err
+	}
+	if err := validateObjectKey(object); err != nil {
+		return ObjectURLResult{}, err
+	}
+
+	if !opts.UseCustomDomain {
+		if opts.Signed {
+			signed, err := s.PresignObject(config, bucket, object, opts.Expires)
+			if err != nil {
+				return ObjectURLResult{}, err
+			}
+			return ObjectURLResult{URL: signed, Domains: []string{}}, nil
+		}
+
+		endpoint, err := sdkEndpointForConfig(config, endpointForData)
+		if err != nil {
+			return ObjectURLResult{}, err
+		}
+		if usesCname(config, endpointForData) {
+			host := normalizeEndpoint(endpoint)
+			return ObjectURLResult{
+				URL:     fmt.Sprintf("%s/%s", endpoint, escapeObjectKeyPath(object)),
+				Domain:  host,
+				Domains: []string{},
+			}, nil
+		}
+		host := strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+		return ObjectURLResult{
+			URL:     fmt.Sprintf("https://%s.%s/%s", bucket, host, escapeObjectKeyPath(object)),
+			Domain:  bucket + "." + host,
+			Domains: []string{},
+		}, nil
+	}
+
+	cnames, err := s.cachedBucketCnames(config, bucket)
+	if err != nil {
+		return ObjectURLResult{}, err
+	}
+
+	domains := make([]string, 0, len(cnames))
+	for _, cname := range cnames {
+		if isCnameEnabled(cname) {
+			domains = append(domains, cname.Domain)
+		}
+	}
+	if len(domains) == 0 {
+		return ObjectURLResult{}, fmt.Errorf("bucket %s has no enabled custom domain", bucket)
+	}
+
+	domain := domains[0]
+	if requested := strings.TrimSpace(opts.Domain); requested != "" {
+		domain = ""
+		for _, candidate := range domains {
+			if strings.EqualFold(candidate, requested) {
+				domain = candidate
+				break
+			}
+		}
+		if domain == "" {
+			return ObjectURLResult{}, fmt.Errorf("custom domain %s is not bound to bucket %s or not enabled", requested, bucket)
+		}
+	}
+
+	result := ObjectURLResult{Domain: domain, Domains: domains}
+	if !opts.Signed {
+		result.URL = fmt.Sprintf("https://%s/%s", domain, escapeObjectKeyPath(object))
+		return result, nil
+	}
+
+	expiresDuration := strings.TrimSpace(opts.Expires)
+	if expiresDuration == "" {
+		expiresDuration = "15m"
+	}
+	expires, err := time.ParseDuration(expiresDuration)
+	if err != nil {
+		return ObjectURLResult{}, fmt.Errorf("invalid expires duration: %w", err)
+	}
+	if expires < 0 {
+		return ObjectURLResult{}, fmt.Errorf("invalid expires duration: must be non-negative")
+	}
+
+	config, err = s.resolveCredentials(config)
+	if err != nil {
+		return ObjectURLResult{}, err
+	}
+	clientOptions := []oss.ClientOption{oss.UseCname(true)}
+	if region := normalizeRegion(config.Region); region != "" {
+		clientOptions = append(clientOptions, oss.Region(region))
+	}
+	if config.SecurityToken != "" {
+		clientOptions = append(clientOptions, oss.SecurityToken(config.SecurityToken))
+	}
+	client, err := oss.New("https://"+domain, config.AccessKeyID, config.AccessKeySecret, clientOptions...)
+	if err != nil {
+		return ObjectURLResult{}, fmt.Errorf("failed to create client: %w", err)
+	}
+	bkt, err := client.Bucket(bucket)
+	if err != nil {
+		return ObjectURLResult{}, fmt.Errorf("failed to open bucket: %w", err)
+	}
+	signedURL, err := bkt.SignURL(object, oss.HTTPGet, int64(expires.Seconds()))
+	if err != nil {
+		return ObjectURLResult{}, fmt.Errorf("presign failed: %w", err)
+	}
+	result.URL = signedURL
+	return result, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func cnameConfig(domain string) OSSConfig {
+	return OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou", CnameDomain: domain}
+}
+
+func TestGetObjectURLOnProfileDomain(t *testing.T) {
+	s := newTestService(t)
+	tests := []struct {
+		name   string
+		config OSSConfig
+		url    string
+		domain string
+	}{
+		{"regional endpoint", cnameConfig(""), "https://bucket.oss-cn-hangzhou.aliyuncs.com/dir/a%20b%231.txt", "bucket.oss-cn-hangzhou.aliyuncs.com"},
+		{"bare domain", cnameConfig("cdn.example.com"), "https://cdn.example.com/dir/a%20b%231.txt", "cdn.example.com"},
+		{"domain as URL", cnameConfig("https://cdn.example.com/"), "https://cdn.example.com/dir/a%20b%231.txt", "cdn.example.com"},
+		{"domain with port", cnameConfig("cdn.example.com:8443"), "https://cdn.example.com:8443/dir/a%20b%231.txt", "cdn.example.com:8443"},
+		{"http domain allowed", func() OSSConfig {
+			config := cnameConfig("http://cdn.example.com")
+			config.AllowInsecureHTTP = true
+			return config
+		}(), "http://cdn.example.com/dir/a%20b%231.txt", "cdn.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.GetObjectURL(tt.config, "bucket", "dir/a b#1.txt", ObjectURLOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.URL != tt.url || got.Domain != tt.domain {
+				t.Fatalf("GetObjectURL = %q on %q, want %q on %q", got.URL, got.Domain, tt.url, tt.domain)
+			}
+		})
+	}
+
+	if _, err := s.GetObjectURL(cnameConfig("http://cdn.example.com"), "bucket", "a.txt", ObjectURLOptions{}); err == nil || !strings.Contains(err.Error(), "Allow insecure HTTP") {
+		t.Fatalf("an http domain without AllowInsecureHTTP = %v, want a refusal", err)
+	}
+}
+
+func TestPresignedURLOnProfileDomain(t *testing.T) {
+	s := newTestService(t)
+	tests := []struct {
+		name   string
+		config OSSConfig
+		host   string
+		path   string
+	}{
+		{"regional endpoint", cnameConfig(""), "bucket.oss-cn-hangzhou.aliyuncs.com", "/dir/a b.txt"},
+		{"custom domain", cnameConfig("cdn.example.com"), "cdn.example.com", "/dir/a b.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := s.PresignObject(tt.config, "bucket", "dir/a b.txt", "10m")
+			if err != nil {
+				t.Fatal(err)
+			}
+			viaURL, err := s.GetObjectURL(tt.config, "bucket", "dir/a b.txt", ObjectURLOptions{Signed: true, Expires: "10m"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, raw := range []string{signed, viaURL.URL} {
+				u, err := url.Parse(raw)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if u.Scheme != "https" || u.Host != tt.host || u.Path != tt.path {
+					t.Errorf("signed URL %q points at %s://%s%s, want https://%s%s", raw, u.Scheme, u.Host, u.Path, tt.host, tt.path)
+				}
+				query := u.Query()
+				if query.Get("OSSAccessKeyId") == "" && query.Get("x-oss-credential") == "" {
+					t.Errorf("signed URL %q carries no credential", raw)
+				}
+				if query.Get("Signature") == "" && query.Get("x-oss-signature") == "" {
+					t.Errorf("signed URL %q carries no signature", raw)
+				}
+			}
+		})
+	}
+}
+
+// TestDataRequestsGoToProfileDomain checks that object requests of a custom-domain profile go to
+// the domain without the bucket in the host or path, and bucket calls stay on the endpoint.
+func TestDataRequestsGoToProfileDomain(t *testing.T) {
+	s := newTestService(t)
+	var mu sync.Mutex
+	var paths []string
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+strings.Split(r.Host, ":")[0]+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("ETag", `"etag"`)
+	})
+
+	bucket, err := s.sdkBucket(config, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bucket.GetObjectDetailedMeta("dir/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The SDK always addresses IP literals path-style, so the domain is the server by name.
+	config.CnameDomain = strings.Replace(config.Endpoint, "127.0.0.1", "localhost", 1)
+	config.Endpoint = "oss-cn-hangzhou.aliyuncs.com"
+	bucket, err = s.sdkBucket(config, "bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bucket.GetObjectDetailedMeta("dir/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"HEAD 127.0.0.1/bucket/dir/a.txt", "HEAD localhost/dir/a.txt"}; strings.Join(paths, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("requests = %v, want %v", paths, want)
+	}
+
+	management, err := sdkEndpointForConfig(config, endpointForManagement)
+	if err != nil || management != "https://oss-cn-hangzhou.aliyuncs.com" {
+		t.Fatalf("management endpoint = %q, %v; want the regional endpoint", management, err)
+	}
+	if addressesByRegion(config) {
+		t.Fatal("a custom-domain profile would be redirected to another region")
+	}
+}
+
+func TestSDKClientCacheKeySeparatesCname(t *testing.T) {
+	config := cnameConfig("cdn.example.com")
+	tuning := sdkTuning{connectTimeoutSec: 10, readWriteTimeoutSec: 20}
+	if sdkClientCacheKey("https://cdn.example.com", true, config, "cn-hangzhou", tuning, proxySettings{}, tlsSettings{}) ==
+		sdkClientCacheKey("https://cdn.example.com", false, config, "cn-hangzhou", tuning, proxySettings{}, tlsSettings{}) {
+		t.Fatal("a cname client and a plain client to the same URL share a cache entry")
+	}
+}
+
+func TestListBucketsOnProfileDomain(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	var requests atomic.Int32
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeBucketList(w, `<Bucket><Name>other</Name><Location>oss-cn-hangzhou</Location></Bucket>`)
+	})
+	config.CnameDomain = "cdn.example.com"
+
+	_, err := s.ListBuckets(config)
+	if err == nil || !strings.Contains(err.Error(), "cdn.example.com") || !strings.Contains(err.Error(), "Pin") {
+		t.Fatalf("ListBuckets without pinned buckets = %v, want the pin suggestion", err)
+	}
+
+	profile := OSSProfile{Name: "cdn", Config: config, PinnedBuckets: []string{"site"}}
+	if err := s.saveAppStateToDir(s.configDir, appState{Profiles: []OSSProfile{profile}}); err != nil {
+		t.Fatal(err)
+	}
+	buckets, err := s.ListBuckets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "site" {
+		t.Fatalf("buckets = %+v, want the pinned bucket", buckets)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("ListBuckets sent %d requests through a custom-domain profile", got)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const maxCORSRules = 10
+
+var allowedCORSMethods = map[string]struct{}{
+	"GET":    {},
+	"PUT":    {},
+	"POST":   {},
+	"DELETE": {},
+	"HEAD":   {},
+}
+
+// CORSRuleView is a single bucket CORS rule.
+type CORSRuleView struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+	AllowedMethods []string `json:"allowedMethods"`
+	AllowedHeaders []string `json:"allowedHeaders,omitempty"`
+	ExposeHeaders  []string `json:"exposeHeaders,omitempty"`
+	MaxAgeSeconds  int      `json:"maxAgeSeconds,omitempty"`
+}
+
+func trimNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func normalizeCORSRules(rules []CORSRuleView) ([]oss.CORSRule, error) {
+	if len(rules) > maxCORSRules {
+		return nil, fmt.Errorf("too many CORS rules: %d (max %d)", len(rules), maxCORSRules)
+	}
+
+	out := make([]oss.CORSRule, 0, len(rules))
+	for i, rule := range rules {
+		n := i + 1
+		origins := trimNonEmpty(rule.AllowedOrigins)
+		if len(origins) == 0 {
+			return nil, fmt.Errorf("CORS rule %d: at least one allowed origin is required", n)
+		}
+		for _, origin := range origins {
+			if strings.Count(origin, "*") > 1 {
+				return nil, fmt.Errorf("CORS rule %d: origin %q may contain at most one '*'", n, origin)
+			}
+		}
+
+		methods := trimNonEmpty(rule.AllowedMethods)
+		if len(methods) == 0 {
+			return nil, fmt.Errorf("CORS rule %d: at least one allowed method is required", n)
+		}
+		for j, method := range methods {
+			method = strings.ToUpper(method)
+			if _, ok := allowedCORSMethods[method]; !ok {
+				return nil, fmt.Errorf("CORS rule %d: unsupported method %q (allowed: GET, PUT, POST, DELETE, HEAD)", n, methods[j])
+			}
+			methods[j] = method
+		}
+
+		headers := trimNonEmpty(rule.AllowedHeaders)
+		for _, header := range headers {
+			if strings.Count(header, "*") > 1 {
+				return nil, fmt.Errorf("CORS rule %d: allowed header %q may contain at most one '*'", n, header)
+			}
+		}
+
+		exposeHeaders := trimNonEmpty(rule.ExposeHeaders)
+		for _, header := range exposeHeaders {
+			if strings.Contains(header, "*") {
+				return nil, fmt.Errorf("CORS rule %d: expose header %q must not contain '*'", n, header)
+			}
+		}
+
+		if rule.MaxAgeSeconds < 0 {
+			return nil, fmt.Errorf("CORS rule %d: max age must not be negative", n)
+		}
+
+		out = append(out, oss.CORSRule{
+			AllowedOrigin: origins,
+			AllowedMethod: methods,
+			AllowedHeader: headers,
+			ExposeHeader:  exposeHeaders,
+			MaxAgeSeconds: rule.MaxAgeSeconds,
+		})
+	}
+	return out, nil
+}
+
+// GetBucketCORS returns the CORS rules of a bucket; a bucket without CORS yields an empty slice.
+func (s *OSSService) GetBucketCORS(config OSSConfig, bucketName string) (_ []CORSRuleView, err error) {
+	defer s.logOperation("GetBucketCORS", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetBucketCORS(bucketName)
+	if err != nil {
+		if isOSSErrorCode(err, "NoSuchCORSConfiguration") {
+			return []CORSRuleView{}, nil
+		}
+		return nil, fmt.Errorf("failed to get CORS rules: %w", err)
+	}
+
+	out := make([]CORSRuleView, 0, len(res.CORSRules))
+	for _, rule := range res.CORSRules {
+		out = append(out, CORSRuleView{
+			AllowedOrigins: rule.AllowedOrigin,
+			AllowedMethods: rule.AllowedMethod,
+			AllowedHeaders: rule.AllowedHeader,
+			ExposeHeaders:  rule.ExposeHeader,
+			MaxAgeSeconds:  rule.MaxAgeSeconds,
+		})
+	}
+	return out, nil
+}
+
+// SetBucketCORS replaces all CORS rules of a bucket. An empty list clears the configuration, which
+// needs a token for "bucket:cors:delete" on oss://bucket/.
+func (s *OSSService) SetBucketCORS(config OSSConfig, bucketName string, rules []CORSRuleView, confirmToken string) (err error) {
+	defer s.logOperation("SetBucketCORS", bucketName, "", time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+
+	sdkRules, err := normalizeCORSRules(rules)
+	if err != nil {
+		return err
+	}
+	if len(sdkRules) == 0 {
+		return s.DeleteBucketCORS(config, bucketName, confirmToken)
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := client.SetBucketCORS(bucketName, sdkRules); err != nil {
+		return fmt.Errorf("failed to set CORS rules: %w", err)
+	}
+	return nil
+}
+
+// DeleteBucketCORS removes every CORS rule from a bucket. It needs a token for "bucket:cors:delete"
+// on oss://bucket/.
+func (s *OSSService) DeleteBucketCORS(config OSSConfig, bucketName string, confirmToken string) (err error) {
+	defer s.logOperation("DeleteBucketCORS", bucketName, "", time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+	if err := s.requireConfirmationToken(confirmToken, confirmOpDeleteBucketCORS, buildOssPath(bucketName, "")); err != nil {
+		return err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteBucketCORS(bucketName); err != nil && !isOSSErrorCode(err, "NoSuchCORSConfiguration") {
+		return fmt.Errorf("failed to delete CORS rules: %w", err)
+	}
+	return nil
+}
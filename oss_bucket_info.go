@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// BucketInfoOptions selects optional sections to embed in GetBucketInfo.
+type BucketInfoOptions struct {
+	IncludeTags bool `json:"includeTags"`
+}
+
+// BucketDetail is the full description of a bucket shown in the detail panel.
+type BucketDetail struct {
+	Name                   string               `json:"name"`
+	Region                 string               `json:"region"`
+	CreationDate           string               `json:"creationDate"`
+	ExtranetEndpoint       string               `json:"extranetEndpoint"`
+	IntranetEndpoint       string               `json:"intranetEndpoint"`
+	ACL                    string               `json:"acl"`
+	StorageClass           string               `json:"storageClass"`
+	RedundancyType         string               `json:"redundancyType"`
+	Versioning             string               `json:"versioning"`
+	TransferAcceleration   string               `json:"transferAcceleration"`
+	CrossRegionReplication string               `json:"crossRegionReplication"`
+	Owner                  string               `json:"owner"`
+	Encryption             EncryptionConfigView `json:"encryption"`
+	Tags                   map[string]string    `json:"tags,omitempty"`
+	TagsError              string               `json:"tagsError,omitempty"`
+}
+
+// GetBucketInfo returns bucket metadata. Optional sections that fail to load (for example tags
+// without tagging permission) are reported in their *Error field instead of failing the call.
+func (s *OSSService) GetBucketInfo(config OSSConfig, bucketName string, opts BucketInfoOptions) (_ BucketDetail, err error) {
+	defer s.logOperation("GetBucketInfo", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return BucketDetail{}, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return BucketDetail{}, err
+	}
+
+	// Only the bucket info request is shared; the optional sections depend on opts and are
+	// loaded per call.
+	shared, err := s.sdkFlights.do(sdkFlightKey("GetBucketInfo", config, bucketName), func() (any, error) {
+		var res oss.GetBucketInfoResult
+		err := s.withSDKRetry("GetBucketInfo", func() error {
+			var err error
+			res, err = client.GetBucketInfo(bucketName)
+			return err
+		})
+		return res, err
+	})
+	res, _ := shared.(oss.GetBucketInfoResult)
+	if err != nil {
+		return BucketDetail{}, fmt.Errorf("failed to get bucket info: %w", err)
+	}
+
+	info := res.BucketInfo
+	detail := BucketDetail{
+		Name:                   info.Name,
+		Region:                 normalizeRegion(info.Location),
+		CreationDate:           formatObjectLastModified(info.CreationDate),
+		ExtranetEndpoint:       info.ExtranetEndpoint,
+		IntranetEndpoint:       info.IntranetEndpoint,
+		ACL:                    info.ACL,
+		StorageClass:           info.StorageClass,
+		RedundancyType:         info.RedundancyType,
+		Versioning:             normalizeBucketVersioningStatus(info.Versioning),
+		TransferAcceleration:   info.TransferAcceleration,
+		CrossRegionReplication: info.CrossRegionReplication,
+		Owner:                  info.Owner.DisplayName,
+		Encryption:             encryptionViewFromRule(info.SseRule.SSEAlgorithm, info.SseRule.KMSMasterKeyID, info.SseRule.KMSDataEncryption),
+	}
+	if detail.Owner == "" {
+		detail.Owner = info.Owner.ID
+	}
+
+	if opts.IncludeTags {
+		tags, tagErr := s.GetBucketTagging(config, bucketName)
+		if tagErr != nil {
+			detail.TagsError = tagErr.Error()
+		} else {
+			detail.Tags = tags
+		}
+	}
+
+	return detail, nil
+}
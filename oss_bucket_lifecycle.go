@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	maxLifecycleRules      = 1000
+	maxLifecycleRuleIDLen  = 255
+	lifecycleDateLayout    = "2006-01-02"
+	lifecycleISODateLayout = "2006-01-02T15:04:05.000Z"
+)
+
+// LifecycleTransitionView is a flattened lifecycle transition action.
+type LifecycleTransitionView struct {
+	Days              int    `json:"days,omitempty"`
+	CreatedBeforeDate string `json:"createdBeforeDate,omitempty"` // YYYY-MM-DD
+	StorageClass      string `json:"storageClass"`
+}
+
+// LifecycleRuleView is a flattened lifecycle rule used by the rule editor.
+type LifecycleRuleView struct {
+	ID                 string                    `json:"id"`
+	Prefix             string                    `json:"prefix"`
+	Tags               map[string]string         `json:"tags,omitempty"`
+	Status             string                    `json:"status"` // "Enabled" | "Disabled"
+	ExpirationDays     int                       `json:"expirationDays,omitempty"`
+	ExpirationDate     string                    `json:"expirationDate,omitempty"` // YYYY-MM-DD
+	Transitions        []LifecycleTransitionView `json:"transitions,omitempty"`
+	AbortMultipartDays int                       `json:"abortMultipartDays,omitempty"`
+}
+
+func lifecycleDateFromISO(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	for _, layout := range []string{lifecycleISODateLayout, time.RFC3339, lifecycleDateLayout} {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts.UTC().Format(lifecycleDateLayout)
+		}
+	}
+	return value
+}
+
+func lifecycleDateToISO(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	ts, err := time.Parse(lifecycleDateLayout, lifecycleDateFromISO(value))
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD", value)
+	}
+	return ts.UTC().Format(lifecycleISODateLayout), nil
+}
+
+func normalizeLifecycleStatus(status string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "", "enabled":
+		return "Enabled", nil
+	case "disabled":
+		return "Disabled", nil
+	default:
+		return "", fmt.Errorf("invalid status %q: must be Enabled or Disabled", status)
+	}
+}
+
+func normalizeLifecycleStorageClass(storageClass string) (oss.StorageClassType, error) {
+	switch strings.ToLower(strings.TrimSpace(storageClass)) {
+	case "ia":
+		return oss.StorageIA, nil
+	case "archive":
+		return oss.StorageArchive, nil
+	case "coldarchive":
+		return oss.StorageColdArchive, nil
+	case "deepcoldarchive":
+		return oss.StorageDeepColdArchive, nil
+	default:
+		return "", fmt.Errorf("invalid transition storage class %q: must be IA, Archive, ColdArchive or DeepColdArchive", storageClass)
+	}
+}
+
+func lifecycleRuleToView(rule oss.LifecycleRule) LifecycleRuleView {
+	view := LifecycleRuleView{
+		ID:     rule.ID,
+		Prefix: rule.Prefix,
+		Status: rule.Status,
+	}
+	if len(rule.Tags) > 0 {
+		view.Tags = make(map[string]string, len(rule.Tags))
+		for _, tag := range rule.Tags {
+			view.Tags[tag.Key] = tag.Value
+		}
+	}
+	if rule.Expiration != nil {
+		view.ExpirationDays = rule.Expiration.Days
+		view.ExpirationDate = lifecycleDateFromISO(rule.Expiration.Date)
+		if view.ExpirationDate == "" {
+			view.ExpirationDate = lifecycleDateFromISO(rule.Expiration.CreatedBeforeDate)
+		}
+	}
+	for _, transition := range rule.Transitions {
+		view.Transitions = append(view.Transitions, LifecycleTransitionView{
+			Days:              transition.Days,
+			CreatedBeforeDate: lifecycleDateFromISO(transition.CreatedBeforeDate),
+			StorageClass:      string(transition.StorageClass),
+		})
+	}
+	if rule.AbortMultipartUpload != nil {
+		view.AbortMultipartDays = rule.AbortMultipartUpload.Days
+	}
+	return view
+}
+
+// lifecycleRuleFromView builds an SDK rule from a view. Fields the view does not model
+// (noncurrent-version actions, filters) are carried over from base when it is non-nil.
+func lifecycleRuleFromView(view LifecycleRuleView, base *oss.LifecycleRule) (oss.LifecycleRule, error) {
+	rule := oss.LifecycleRule{}
+	if base != nil {
+		rule.NonVersionExpiration = base.NonVersionExpiration
+		rule.NonVersionTransitions = base.NonVersionTransitions
+		rule.Filter = base.Filter
+	}
+
+	rule.ID = view.ID
+	rule.Prefix = view.Prefix
+	rule.Status = view.Status
+
+	tagKeys := make([]string, 0, len(view.Tags))
+	for key := range view.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		rule.Tags = append(rule.Tags, oss.Tag{Key: key, Value: view.Tags[key]})
+	}
+
+	if view.ExpirationDays > 0 || view.ExpirationDate != "" {
+		expiration := &oss.LifecycleExpiration{Days: view.ExpirationDays}
+		if view.ExpirationDate != "" {
+			date, err := lifecycleDateToISO(view.ExpirationDate)
+			if err != nil {
+				return oss.LifecycleRule{}, err
+			}
+			expiration.CreatedBeforeDate = date
+		}
+		if base != nil && base.Expiration != nil {
+			expiration.ExpiredObjectDeleteMarker = base.Expiration.ExpiredObjectDeleteMarker
+		}
+		rule.Expiration = expiration
+	}
+
+	for _, transition := range view.Transitions {
+		storageClass, err := normalizeLifecycleStorageClass(transition.StorageClass)
+		if err != nil {
+			return oss.LifecycleRule{}, err
+		}
+		next := oss.LifecycleTransition{Days: transition.Days, StorageClass: storageClass}
+		if transition.CreatedBeforeDate != "" {
+			date, err := lifecycleDateToISO(transition.CreatedBeforeDate)
+			if err != nil {
+				return oss.LifecycleRule{}, err
+			}
+			next.CreatedBeforeDate = date
+		}
+		rule.Transitions = append(rule.Transitions, next)
+	}
+
+	if view.AbortMultipartDays > 0 {
+		rule.AbortMultipartUpload = &oss.LifecycleAbortMultipartUpload{Days: view.AbortMultipartDays}
+	}
+
+	return rule, nil
+}
+
+func lifecycleTagsOverlap(a map[string]string, b map[string]string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for key, value := range a {
+		if other, ok := b[key]; ok && other != value {
+			return false
+		}
+	}
+	return true
+}
+
+func lifecycleExpirationsConflict(a LifecycleRuleView, b LifecycleRuleView) bool {
+	aHas := a.ExpirationDays > 0 || a.ExpirationDate != ""
+	bHas := b.ExpirationDays > 0 || b.ExpirationDate != ""
+	if !aHas || !bHas {
+		return false
+	}
+	return a.ExpirationDays != b.ExpirationDays || a.ExpirationDate != b.ExpirationDate
+}
+
+// normalizeLifecycleRules validates the rule set as a whole and returns normalized copies.
+func normalizeLifecycleRules(rules []LifecycleRuleView) ([]LifecycleRuleView, error) {
+	if len(rules) > maxLifecycleRules {
+		return nil, fmt.Errorf("too many lifecycle rules: %d (max %d)", len(rules), maxLifecycleRules)
+	}
+
+	out := make([]LifecycleRuleView, 0, len(rules))
+	seenIDs := make(map[string]struct{}, len(rules))
+	for i, rule := range rules {
+		rule.ID = strings.TrimSpace(rule.ID)
+		if rule.ID == "" {
+			rule.ID = fmt.Sprintf("rule-%d", i+1)
+		}
+		if len(rule.ID) > maxLifecycleRuleIDLen {
+			return nil, fmt.Errorf("rule %s: id is longer than %d characters", rule.ID, maxLifecycleRuleIDLen)
+		}
+		if _, ok := seenIDs[rule.ID]; ok {
+			return nil, fmt.Errorf("duplicate lifecycle rule id: %s", rule.ID)
+		}
+		seenIDs[rule.ID] = struct{}{}
+
+		rule.Prefix = strings.TrimLeft(strings.TrimSpace(rule.Prefix), "/")
+		status, err := normalizeLifecycleStatus(rule.Status)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		rule.Status = status
+
+		if rule.ExpirationDays < 0 || rule.AbortMultipartDays < 0 {
+			return nil, fmt.Errorf("rule %s: days must not be negative", rule.ID)
+		}
+		rule.ExpirationDate = strings.TrimSpace(rule.ExpirationDate)
+		if rule.ExpirationDays > 0 && rule.ExpirationDate != "" {
+			return nil, fmt.Errorf("rule %s: expiration days and expiration date are mutually exclusive", rule.ID)
+		}
+		if rule.ExpirationDate != "" {
+			if _, err := lifecycleDateToISO(rule.ExpirationDate); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+			rule.ExpirationDate = lifecycleDateFromISO(rule.ExpirationDate)
+		}
+
+		for j, transition := range rule.Transitions {
+			if _, err := normalizeLifecycleStorageClass(transition.StorageClass); err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+			transition.CreatedBeforeDate = strings.TrimSpace(transition.CreatedBeforeDate)
+			if transition.Days < 0 {
+				return nil, fmt.Errorf("rule %s: transition days must not be negative", rule.ID)
+			}
+			if (transition.Days > 0) == (transition.CreatedBeforeDate != "") {
+				return nil, fmt.Errorf("rule %s: each transition needs either days or a date", rule.ID)
+			}
+			if rule.ExpirationDays > 0 && transition.Days >= rule.ExpirationDays {
+				return nil, fmt.Errorf("rule %s: transition to %s must happen before expiration", rule.ID, transition.StorageClass)
+			}
+			rule.Transitions[j] = transition
+		}
+
+		hasAction := rule.ExpirationDays > 0 || rule.ExpirationDate != "" || len(rule.Transitions) > 0 || rule.AbortMultipartDays > 0
+		if !hasAction {
+			return nil, fmt.Errorf("rule %s: at least one action (expiration, transition or abort multipart) is required", rule.ID)
+		}
+
+		out = append(out, rule)
+	}
+
+	for i := range out {
+		for j := i + 1; j < len(out); j++ {
+			a, b := out[i], out[j]
+			if a.Status != "Enabled" || b.Status != "Enabled" {
+				continue
+			}
+			if !strings.HasPrefix(a.Prefix, b.Prefix) && !strings.HasPrefix(b.Prefix, a.Prefix) {
+				continue
+			}
+			if !lifecycleTagsOverlap(a.Tags, b.Tags) {
+				continue
+			}
+			if lifecycleExpirationsConflict(a, b) {
+				return nil, fmt.Errorf("lifecycle rules %s and %s overlap (prefixes %q and %q) with different expirations", a.ID, b.ID, a.Prefix, b.Prefix)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func getBucketLifecycleRules(client *oss.Client, bucketName string) ([]oss.LifecycleRule, error) {
+	res, err := client.GetBucketLifecycle(bucketName)
+	if err != nil {
+		if isOSSErrorCode(err, "NoSuchLifecycle") {
+			return []oss.LifecycleRule{}, nil
+		}
+		return nil, fmt.Errorf("failed to get lifecycle rules: %w", err)
+	}
+	return res.Rules, nil
+}
+
+// GetBucketLifecycle returns the bucket lifecycle rules; a bucket without rules yields an empty slice.
+func (s *OSSService) GetBucketLifecycle(config OSSConfig, bucketName string) (_ []LifecycleRuleView, err error) {
+	defer s.logOperation("GetBucketLifecycle", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := getBucketLifecycleRules(client, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]LifecycleRuleView, 0, len(rules))
+	for _, rule := range rules {
+		out = append(out, lifecycleRuleToView(rule))
+	}
+	return out, nil
+}
+
+// SetBucketLifecycle replaces the whole rule set of a bucket. Callers are expected to
+// read the current rules with GetBucketLifecycle, edit them, and send back the full list. An empty
+// list deletes every rule, which needs a token for "bucket:lifecycle:clear" on oss://bucket/.
+func (s *OSSService) SetBucketLifecycle(config OSSConfig, bucketName string, rules []LifecycleRuleView, confirmToken string) (err error) {
+	defer s.logOperation("SetBucketLifecycle", bucketName, "", time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+
+	normalized, err := normalizeLifecycleRules(rules)
+	if err != nil {
+		return err
+	}
+	target := buildOssPath(bucketName, "")
+	if len(normalized) == 0 {
+		if err := s.requireConfirmationToken(confirmToken, confirmOpClearBucketLifecycle, target); err != nil {
+			return err
+		}
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if len(normalized) == 0 {
+		if err := client.DeleteBucketLifecycle(bucketName); err != nil && !isOSSErrorCode(err, "NoSuchLifecycle") {
+			s.recordAudit(config, "DeleteBucketLifecycle", bucketName, target, 0, err)
+			return fmt.Errorf("failed to delete lifecycle rules: %w", err)
+		}
+		s.recordAudit(config, "DeleteBucketLifecycle", bucketName, target, 0, nil)
+		return nil
+	}
+
+	existing, err := getBucketLifecycleRules(client, bucketName)
+	if err != nil {
+		return err
+	}
+	existingByID := make(map[string]*oss.LifecycleRule, len(existing))
+	for i := range existing {
+		existingByID[existing[i].ID] = &existing[i]
+	}
+
+	sdkRules := make([]oss.LifecycleRule, 0, len(normalized))
+	for _, view := range normalized {
+		rule, err := lifecycleRuleFromView(view, existingByID[view.ID])
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", view.ID, err)
+		}
+		sdkRules = append(sdkRules, rule)
+	}
+
+	if err := client.SetBucketLifecycle(bucketName, sdkRules); err != nil {
+		s.recordAudit(config, "SetBucketLifecycle", bucketName, target, 0, err)
+		return fmt.Errorf("failed to set lifecycle rules: %w", err)
+	}
+	s.recordAudit(config, "SetBucketLifecycle", bucketName, target, 0, nil)
+	return nil
+}
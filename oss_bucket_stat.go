@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const bucketStatCacheTTL = 5 * time.Minute
+
+// BucketStorageClassStat is the usage of a single storage class inside a bucket.
+type BucketStorageClassStat struct {
+	StorageClass string `json:"storageClass"`
+	Storage      int64  `json:"storage"`
+	RealStorage  int64  `json:"realStorage,omitempty"`
+	ObjectCount  int64  `json:"objectCount"`
+}
+
+// BucketStat is the storage usage overview of a bucket as reported by OSS.
+type BucketStat struct {
+	Bucket               string                   `json:"bucket"`
+	Storage              int64                    `json:"storage"`
+	ObjectCount          int64                    `json:"objectCount"`
+	MultipartUploadCount int64                    `json:"multipartUploadCount"`
+	LiveChannelCount     int64                    `json:"liveChannelCount"`
+	StorageClasses       []BucketStorageClassStat `json:"storageClasses"`
+	LastModifiedTime     string                   `json:"lastModifiedTime"`
+	LastModifiedAtMs     int64                    `json:"lastModifiedAtMs,omitempty"`
+	FetchedAtMs          int64                    `json:"fetchedAtMs"`
+	Cached               bool                     `json:"cached"`
+	// Unavailable is set when the credentials may not read the bucket's statistics.
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+type bucketStatCacheEntry struct {
+	Stat      BucketStat `json:"stat"`
+	FetchedAt time.Time  `json:"fetchedAt"`
+}
+
+// sdkConfigCacheKey identifies the account and endpoint a config talks to, without the secret.
+func sdkConfigCacheKey(config OSSConfig) string {
+	return strings.Join([]string{
+		strings.TrimSpace(config.AccessKeyID),
+		normalizeRegion(config.Region),
+		normalizeEndpoint(config.Endpoint),
+	}, "\x1f")
+}
+
+func bucketCacheKey(config OSSConfig, bucketName string) string {
+	return sdkConfigCacheKey(config) + "\x1f" + bucketName
+}
+
+// GetBucketStat returns storage usage of a bucket. Results are cached for a few minutes
+// because OSS only refreshes the statistics periodically; pass forceRefresh to bypass the cache.
+func (s *OSSService) GetBucketStat(config OSSConfig, bucketName string, forceRefresh bool) (_ BucketStat, err error) {
+	defer s.logOperation("GetBucketStat", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return BucketStat{}, err
+	}
+
+	cacheKey := bucketCacheKey(config, bucketName)
+	if !forceRefresh {
+		s.bucketStatCacheMu.Lock()
+		entry, ok := s.bucketStatsLocked()[cacheKey]
+		s.bucketStatCacheMu.Unlock()
+		if ok && !entry.Stat.Unavailable && time.Since(entry.FetchedAt) < bucketStatCacheTTL {
+			stat := entry.Stat
+			stat.Cached = true
+			return stat, nil
+		}
+	}
+
+	stat, err := s.fetchBucketStat(config, bucketName)
+	s.storeBucketStat(cacheKey, stat, err)
+	if err != nil {
+		return BucketStat{}, err
+	}
+	return stat, nil
+}
+
+// fetchBucketStat asks OSS for the statistics of bucketName.
+func (s *OSSService) fetchBucketStat(config OSSConfig, bucketName string) (BucketStat, error) {
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return BucketStat{}, err
+	}
+
+	// The background refresh and an explicit refresh of the same bucket share one request.
+	shared, err := s.sdkFlights.do(sdkFlightKey("GetBucketStat", config, bucketName), func() (any, error) {
+		var res oss.GetBucketStatResult
+		err := s.withSDKRetry("GetBucketStat", func() error {
+			var err error
+			res, err = client.GetBucketStat(bucketName)
+			return err
+		})
+		return res, err
+	})
+	res, _ := shared.(oss.GetBucketStatResult)
+	if err != nil {
+		return BucketStat{}, fmt.Errorf("failed to get bucket stat: %w", err)
+	}
+
+	now := time.Now()
+	stat := BucketStat{
+		Bucket:               bucketName,
+		Storage:              res.Storage,
+		ObjectCount:          res.ObjectCount,
+		MultipartUploadCount: res.MultipartUploadCount,
+		LiveChannelCount:     res.LiveChannelCount,
+		StorageClasses: []BucketStorageClassStat{
+			{
+				StorageClass: "Standard",
+				Storage:      res.StandardStorage,
+				ObjectCount:  res.StandardObjectCount,
+			},
+			{
+				StorageClass: "IA",
+				Storage:      res.InfrequentAccessStorage,
+				RealStorage:  res.InfrequentAccessRealStorage,
+				ObjectCount:  res.InfrequentAccessObjectCount,
+			},
+			{
+				StorageClass: "Archive",
+				Storage:      res.ArchiveStorage,
+				RealStorage:  res.ArchiveRealStorage,
+				ObjectCount:  res.ArchiveObjectCount,
+			},
+			{
+				StorageClass: "ColdArchive",
+				Storage:      res.ColdArchiveStorage,
+				RealStorage:  res.ColdArchiveRealStorage,
+				ObjectCount:  res.ColdArchiveObjectCount,
+			},
+		},
+		FetchedAtMs: now.UnixMilli(),
+	}
+	if res.LastModifiedTime > 0 {
+		modified := time.Unix(res.LastModifiedTime, 0)
+		stat.LastModifiedTime = formatObjectLastModified(modified)
+		stat.LastModifiedAtMs = modified.UnixMilli()
+	}
+
+	return stat, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	bucketStatsFileName = "stats.json"
+
+	// GetBucketStat is slow on large buckets; a few at a time fill the grid without hammering OSS.
+	bucketStatRefreshConcurrency = 3
+)
+
+func (s *OSSService) bucketStatsPath() string {
+	return filepath.Join(s.configDir, bucketStatsFileName)
+}
+
+// bucketStatsLocked returns the cached statistics, reading stats.json on first use so the bucket
+// grid has numbers before anything is fetched.
+func (s *OSSService) bucketStatsLocked() map[string]bucketStatCacheEntry {
+	if s.bucketStatCacheLoaded {
+		return s.bucketStatCache
+	}
+	s.bucketStatCacheLoaded = true
+	var saved map[string]bucketStatCacheEntry
+	if _, err := s.readConfigFile(s.bucketStatsPath(), &saved); err != nil {
+		s.logOperationEvent(logLevelWarn, "GetCachedBucketStats", opOutcomeError, "", err)
+	}
+	for key, entry := range saved {
+		if _, ok := s.bucketStatCache[key]; !ok {
+			s.bucketStatCache[key] = entry
+		}
+	}
+	return s.bucketStatCache
+}
+
+func (s *OSSService) writeBucketStats() error {
+	s.bucketStatCacheMu.Lock()
+	data, err := json.MarshalIndent(s.bucketStatsLocked(), "", "  ")
+	s.bucketStatCacheMu.Unlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.bucketStatsPath(), data)
+}
+
+// storeBucketStat caches the outcome of a fetch. A denied fetch marks the bucket unavailable so the
+// refresher stops asking; other failures keep the last known numbers.
+func (s *OSSService) storeBucketStat(cacheKey string, stat BucketStat, err error) {
+	if err != nil && !isAccessDenied(err) {
+		return
+	}
+	if err != nil {
+		stat = BucketStat{Bucket: stat.Bucket, Unavailable: true, FetchedAtMs: time.Now().UnixMilli()}
+	}
+	s.bucketStatCacheMu.Lock()
+	s.bucketStatsLocked()[cacheKey] = bucketStatCacheEntry{Stat: stat, FetchedAt: time.UnixMilli(stat.FetchedAtMs)}
+	s.bucketStatCacheMu.Unlock()
+}
+
+// GetCachedBucketStats returns the statistics last seen for each bucket, however old, without
+// contacting OSS. Buckets never fetched are left out.
+func (s *OSSService) GetCachedBucketStats(config OSSConfig, bucketNames []string) map[string]BucketStat {
+	out := make(map[string]BucketStat, len(bucketNames))
+	s.bucketStatCacheMu.Lock()
+	defer s.bucketStatCacheMu.Unlock()
+	cache := s.bucketStatsLocked()
+	for _, name := range bucketNames {
+		name = strings.TrimSpace(name)
+		if entry, ok := cache[bucketCacheKey(config, name)]; ok {
+			stat := entry.Stat
+			stat.Bucket = name
+			stat.Cached = true
+			out[name] = stat
+		}
+	}
+	return out
+}
+
+// RefreshBucketStats fetches statistics for the listed buckets in the background and emits each
+// result as a "bucket:stats" event. Buckets fetched within bucketStatCacheTTL and buckets whose
+// statistics were denied are skipped; GetBucketStat with forceRefresh retries those. A new call
+// replaces a refresh still running, e.g. after switching profiles.
+func (s *OSSService) RefreshBucketStats(config OSSConfig, bucketNames []string) {
+	ctx, cancel := context.WithCancel(s.baseContext())
+	s.bucketStatRefreshMu.Lock()
+	if s.bucketStatRefreshCancel != nil {
+		s.bucketStatRefreshCancel()
+	}
+	s.bucketStatRefreshCancel = cancel
+	s.bucketStatRefreshMu.Unlock()
+
+	pending := make([]string, 0, len(bucketNames))
+	s.bucketStatCacheMu.Lock()
+	cache := s.bucketStatsLocked()
+	for _, name := range bucketNames {
+		name = strings.TrimSpace(name)
+		if validateBucketName(name) != nil {
+			continue
+		}
+		entry, ok := cache[bucketCacheKey(config, name)]
+		if ok && (entry.Stat.Unavailable || time.Since(entry.FetchedAt) < bucketStatCacheTTL) {
+			continue
+		}
+		pending = append(pending, name)
+	}
+	s.bucketStatCacheMu.Unlock()
+	if len(pending) == 0 {
+		cancel()
+		return
+	}
+
+	go func() {
+		defer cancel()
+		slots := make(chan struct{}, bucketStatRefreshConcurrency)
+		var wg sync.WaitGroup
+		for _, name := range pending {
+			select {
+			case <-ctx.Done():
+			case slots <- struct{}{}:
+			}
+			if ctx.Err() != nil {
+				break
+			}
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				s.refreshBucketStat(ctx, config, name)
+			}(name)
+		}
+		wg.Wait()
+		if err := s.writeBucketStats(); err != nil {
+			s.logOperationEvent(logLevelWarn, "RefreshBucketStats", opOutcomeError, "", err)
+		}
+	}()
+}
+
+func (s *OSSService) refreshBucketStat(ctx context.Context, config OSSConfig, bucketName string) {
+	stat, err := s.fetchBucketStat(config, bucketName)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil && !isAccessDenied(err) {
+		s.logOperationEvent(logLevelWarn, "RefreshBucketStats", opOutcomeError, bucketName, err)
+		return
+	}
+	stat.Bucket = bucketName
+	s.storeBucketStat(bucketCacheKey(config, bucketName), stat, err)
+	if err != nil {
+		stat = BucketStat{Bucket: bucketName, Unavailable: true}
+	}
+
+	s.transferCtxMu.RLock()
+	emitCtx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if emitCtx != nil {
+		runtime.EventsEmit(emitCtx, "bucket:stats", stat)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	maxBucketTags        = 20
+	maxBucketTagKeyLen   = 64
+	maxBucketTagValueLen = 128
+)
+
+func validateBucketTag(key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("tag key is required")
+	}
+	if utf8.RuneCountInString(key) > maxBucketTagKeyLen {
+		return fmt.Errorf("tag key %q is longer than %d characters", key, maxBucketTagKeyLen)
+	}
+	if utf8.RuneCountInString(value) > maxBucketTagValueLen {
+		return fmt.Errorf("value of tag %q is longer than %d characters", key, maxBucketTagValueLen)
+	}
+	lowerKey := strings.ToLower(key)
+	if strings.HasPrefix(lowerKey, "http://") || strings.HasPrefix(lowerKey, "https://") || strings.HasPrefix(lowerKey, "aliyun") {
+		return fmt.Errorf("tag key %q must not start with http://, https:// or Aliyun", key)
+	}
+	for _, text := range []string{key, value} {
+		if !utf8.ValidString(text) {
+			return fmt.Errorf("tag %q contains invalid UTF-8", key)
+		}
+		for _, r := range text {
+			if unicode.IsControl(r) {
+				return fmt.Errorf("tag %q contains a control character", key)
+			}
+		}
+	}
+	return nil
+}
+
+// GetBucketTagging returns the bucket tags; a bucket without tags yields an empty map.
+func (s *OSSService) GetBucketTagging(config OSSConfig, bucketName string) (_ map[string]string, err error) {
+	defer s.logOperation("GetBucketTagging", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetBucketTagging(bucketName)
+	if err != nil {
+		if isOSSErrorCode(err, "NoSuchTagSet") {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to get bucket tags: %w", err)
+	}
+
+	tags := make(map[string]string, len(res.Tags))
+	for _, tag := range res.Tags {
+		tags[tag.Key] = tag.Value
+	}
+	return tags, nil
+}
+
+// SetBucketTagging replaces all tags of a bucket. An empty map removes every tag, which needs a
+// token for "bucket:tagging:delete" on oss://bucket/.
+func (s *OSSService) SetBucketTagging(config OSSConfig, bucketName string, tags map[string]string, confirmToken string) (err error) {
+	defer s.logOperation("SetBucketTagging", bucketName, "", time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+
+	if len(tags) > maxBucketTags {
+		return fmt.Errorf("too many tags: %d (max %d)", len(tags), maxBucketTags)
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if err := validateBucketTag(key, value); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 0 {
+		return s.DeleteBucketTagging(config, bucketName, confirmToken)
+	}
+
+	tagging := oss.Tagging{Tags: make([]oss.Tag, 0, len(keys))}
+	for _, key := range keys {
+		tagging.Tags = append(tagging.Tags, oss.Tag{Key: key, Value: tags[key]})
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := client.SetBucketTagging(bucketName, tagging); err != nil {
+		return fmt.Errorf("failed to set bucket tags: %w", err)
+	}
+	return nil
+}
+
+// DeleteBucketTagging removes all tags from a bucket. It needs a token for "bucket:tagging:delete"
+// on oss://bucket/.
+func (s *OSSService) DeleteBucketTagging(config OSSConfig, bucketName string, confirmToken string) (err error) {
+	defer s.logOperation("DeleteBucketTagging", bucketName, "", time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+	if err := s.requireConfirmationToken(confirmToken, confirmOpDeleteBucketTagging, buildOssPath(bucketName, "")); err != nil {
+		return err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteBucketTagging(bucketName); err != nil {
+		return fmt.Errorf("failed to delete bucket tags: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const wormStateNone = "None"
+
+// wormRejectionCodes are the error codes OSS returns when a retention policy blocks a delete or overwrite.
+var wormRejectionCodes = []string{"FileImmutable", "ObjectImmutable"}
+
+// WormInfo is the retention (WORM) policy of a bucket.
+type WormInfo struct {
+	WormID        string `json:"wormId,omitempty"`
+	State         string `json:"state"`
+	RetentionDays int    `json:"retentionDays"`
+	CreationDate  string `json:"creationDate,omitempty"`
+}
+
+// GetBucketWorm returns the retention policy of a bucket; a bucket without one reports state "None".
+func (s *OSSService) GetBucketWorm(config OSSConfig, bucketName string) (_ WormInfo, err error) {
+	defer s.logOperation("GetBucketWorm", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return WormInfo{}, err
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return WormInfo{}, err
+	}
+
+	res, err := client.GetBucketWorm(bucketName)
+	if err != nil {
+		if isOSSErrorCode(err, "NoSuchWORMConfiguration") {
+			return WormInfo{State: wormStateNone}, nil
+		}
+		return WormInfo{}, fmt.Errorf("failed to get retention policy: %w", err)
+	}
+
+	info := WormInfo{
+		WormID:        res.WormId,
+		State:         strings.TrimSpace(res.State),
+		RetentionDays: res.RetentionPeriodInDays,
+		CreationDate:  res.CreationDate,
+	}
+	if info.State == "" {
+		info.State = wormStateNone
+	}
+	return info, nil
+}
+
+func isWormRejection(err error, output string) bool {
+	if isOSSErrorCode(err, wormRejectionCodes...) {
+		return true
+	}
+	for _, code := range wormRejectionCodes {
+		if strings.Contains(output, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// wormRejectionSummary explains a delete rejected by a retention policy. It returns "" when the
+// policy cannot be read, so callers fall back to the original error.
+func (s *OSSService) wormRejectionSummary(config OSSConfig, bucketName string) string {
+	info, err := s.GetBucketWorm(config, bucketName)
+	if err != nil || info.State == wormStateNone {
+		return ""
+	}
+	summary := fmt.Sprintf("bucket %s has a %s retention policy keeping objects for %d days", bucketName, info.State, info.RetentionDays)
+	if info.CreationDate != "" {
+		summary += fmt.Sprintf(" (created %s)", info.CreationDate)
+	}
+	return summary
+}
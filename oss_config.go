@@ -2,11 +2,46 @@ package main
 
 // OSSConfig represents the OSS connection configuration
 type OSSConfig struct {
+	// Provider selects where credentials come from: "static" (the fields below, default), "env"
+	// (OSS_ACCESS_KEY_ID / OSS_ACCESS_KEY_SECRET) or "ecs-ram-role" (instance metadata).
+	Provider string `json:"provider,omitempty"`
+	// ECSRoleName pins the instance RAM role for the ecs-ram-role provider; empty discovers it.
+	ECSRoleName     string `json:"ecsRoleName,omitempty"`
 	AccessKeyID     string `json:"accessKeyId"`
 	AccessKeySecret string `json:"accessKeySecret"`
 	Region          string `json:"region"`
 	Endpoint        string `json:"endpoint"`
 	DefaultPath     string `json:"defaultPath"`
+	// UseAccelerateEndpoint routes object data operations through the transfer acceleration endpoint.
+	UseAccelerateEndpoint bool `json:"useAccelerateEndpoint,omitempty"`
+	// UseInternalEndpoint uses oss-<region>-internal.aliyuncs.com, reachable only from ECS in the same region.
+	UseInternalEndpoint bool `json:"useInternalEndpoint,omitempty"`
+	// UseDualStackEndpoint uses <region>.oss.aliyuncs.com, which is reachable over IPv6 as well as IPv4.
+	UseDualStackEndpoint bool `json:"useDualStackEndpoint,omitempty"`
+	// SecurityToken is set for temporary (STS) credentials.
+	SecurityToken string `json:"securityToken,omitempty"`
+	// RoleArn makes every operation assume this RAM role using the source credentials: the
+	// profile named SourceProfile, or AccessKeyID/AccessKeySecret above when it is empty.
+	RoleArn         string `json:"roleArn,omitempty"`
+	RoleSessionName string `json:"roleSessionName,omitempty"`
+	SourceProfile   string `json:"sourceProfile,omitempty"`
+	// ProxyURL (http, https or socks5) routes this profile's traffic through a proxy, overriding
+	// the global proxy setting.
+	ProxyURL      string `json:"proxyUrl,omitempty"`
+	ProxyUser     string `json:"proxyUser,omitempty"`
+	ProxyPassword string `json:"proxyPassword,omitempty"`
+	// AllowInsecureHTTP permits an http:// Endpoint, e.g. a self-hosted gateway on a private
+	// network. Credentials and data then travel unencrypted.
+	AllowInsecureHTTP bool `json:"allowInsecureHttp,omitempty"`
+	// CACertFile adds a PEM CA bundle to verify a private deployment's certificate, overriding the
+	// CA file from the settings.
+	CACertFile string `json:"caCertFile,omitempty"`
+	// InsecureSkipVerify accepts any TLS certificate. It is refused for public aliyuncs.com endpoints.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// CnameDomain is a custom domain bound to the bucket, e.g. cdn.example.com. Object data
+	// operations and links then go to that domain instead of <bucket>.<endpoint>; bucket management
+	// still uses Endpoint/Region, and buckets cannot be listed through it.
+	CnameDomain string `json:"cnameDomain,omitempty"`
 }
 
 // OSSProfile represents a saved OSS profile
@@ -14,12 +49,58 @@ type OSSProfile struct {
 	Name      string    `json:"name"`
 	Config    OSSConfig `json:"config"`
 	IsDefault bool      `json:"isDefault"`
+	// SecretRef points at the keychain entry holding Config.AccessKeySecret when it is not stored inline.
+	SecretRef string `json:"secretRef,omitempty"`
+	// LastUsedAtMs is when the profile last completed an operation successfully.
+	LastUsedAtMs int64 `json:"lastUsedAtMs,omitempty"`
+	// PinnedBuckets are always shown in the bucket list, for credentials that cannot list buckets.
+	PinnedBuckets []string `json:"pinnedBuckets,omitempty"`
+	// DefaultBucket and DefaultPrefix are where the object browser opens on connect.
+	DefaultBucket string `json:"defaultBucket,omitempty"`
+	DefaultPrefix string `json:"defaultPrefix,omitempty"`
+	// Color is a named color or #rgb/#rrggbb hex value used to tell profiles apart.
+	Color string `json:"color,omitempty"`
+	Notes string `json:"notes,omitempty"`
+	// ReadOnlyHint asks the UI to show a read-only banner; it does not restrict anything.
+	ReadOnlyHint bool `json:"readOnlyHint,omitempty"`
+	// ReadOnly makes OSSService refuse every mutation made with this profile's config.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// HasSecret is set in redacted profiles when a secret is stored behind the mask; it is never saved.
+	HasSecret bool `json:"hasSecret,omitempty"`
+}
+
+// DeleteProfileResult reports side effects of deleting a profile.
+type DeleteProfileResult struct {
+	// NewDefault is the profile promoted to default because the deleted one was the default.
+	NewDefault string `json:"newDefault,omitempty"`
 }
 
 // ConnectionResult represents the result of a connection test
 type ConnectionResult struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	// CredentialSource names the provider chain that supplied the credentials, e.g. "env+assume-role".
+	CredentialSource string `json:"credentialSource,omitempty"`
+	// TLSVerificationDisabled is set when the profile or the settings skip certificate checks.
+	TLSVerificationDisabled bool `json:"tlsVerificationDisabled,omitempty"`
+	// ErrorCode classifies a failure: an OSS error code such as InvalidAccessKeyId, or one of
+	// DNSFailure, ConnectionRefused, Timeout, TLSFailure, ProxyFailure and Unknown.
+	ErrorCode string `json:"errorCode,omitempty"`
+	// Hint suggests how to fix the failure named by ErrorCode.
+	Hint string `json:"hint,omitempty"`
+	// ClockSkewSec is how many seconds the server's clock is ahead of the local one, when measured.
+	ClockSkewSec *int64 `json:"clockSkewSec,omitempty"`
+	// Endpoint is the endpoint URL the test contacted.
+	Endpoint string `json:"endpoint,omitempty"`
+	// AddressFamily is "ipv4" or "ipv6", the family a connection to the endpoint used after a
+	// successful test. It is empty through a proxy or when the endpoint could not be dialed.
+	AddressFamily string `json:"addressFamily,omitempty"`
+	// InstallPath is set when ossutil is missing and InstallOssutil can put it there.
+	InstallPath string `json:"installPath,omitempty"`
+	// OssutilVersion is the parsed version reported by CheckOssutilInstalled.
+	OssutilVersion *OssutilVersion `json:"ossutilVersion,omitempty"`
+	// Unsupported is set by CheckOssutilInstalled when ossutil is older than the minimum.
+	Unsupported bool `json:"unsupported,omitempty"`
 }
 
 // BucketInfo represents OSS bucket information
@@ -27,4 +108,7 @@ type BucketInfo struct {
 	Name         string `json:"name"`
 	Region       string `json:"region"`
 	CreationDate string `json:"creationDate"`
+	StorageClass string `json:"storageClass,omitempty"`
+	// Profile is the saved profile the bucket was listed with; only ListBucketsAllProfiles sets it.
+	Profile string `json:"profile,omitempty"`
 }
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEndpointHostForConfigAcceleration(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  OSSConfig
+		purpose endpointPurpose
+		want    string
+	}{
+		{
+			name:    "data goes to the accelerated endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-cn-hangzhou.aliyuncs.com", UseAccelerateEndpoint: true},
+			purpose: endpointForData,
+			want:    accelerateEndpoint,
+		},
+		{
+			name:    "accelerated without an endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", UseAccelerateEndpoint: true},
+			purpose: endpointForData,
+			want:    accelerateEndpoint,
+		},
+		{
+			name:    "management stays regional",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-cn-hangzhou.aliyuncs.com", UseAccelerateEndpoint: true},
+			purpose: endpointForManagement,
+			want:    "oss-cn-hangzhou.aliyuncs.com",
+		},
+		{
+			name:    "management drops a configured accelerated endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-accelerate.aliyuncs.com"},
+			purpose: endpointForManagement,
+			want:    "",
+		},
+		{
+			name:    "management derives the dual-stack regional endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-accelerate.aliyuncs.com", UseDualStackEndpoint: true},
+			purpose: endpointForManagement,
+			want:    "cn-hangzhou.oss.aliyuncs.com",
+		},
+		{
+			name:    "internal endpoint wins over acceleration",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-cn-hangzhou.aliyuncs.com", UseAccelerateEndpoint: true, UseInternalEndpoint: true},
+			purpose: endpointForData,
+			want:    "oss-cn-hangzhou-internal.aliyuncs.com",
+		},
+		{
+			name:    "internal replaces a configured accelerated endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-accelerate.aliyuncs.com", UseInternalEndpoint: true},
+			purpose: endpointForData,
+			want:    "oss-cn-hangzhou-internal.aliyuncs.com",
+		},
+		{
+			name:    "acceleration off keeps the endpoint",
+			config:  OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-cn-hangzhou.aliyuncs.com"},
+			purpose: endpointForData,
+			want:    "oss-cn-hangzhou.aliyuncs.com",
+		},
+	}
+	for _, tt := range tests {
+		if got := endpointHostForConfig(tt.config, tt.purpose); got != tt.want {
+			t.Errorf("%s: endpointHostForConfig = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSDKEndpointForConfigAcceleration(t *testing.T) {
+	config := OSSConfig{Region: "cn-hangzhou", Endpoint: "oss-accelerate.aliyuncs.com", UseAccelerateEndpoint: true}
+
+	data, err := sdkEndpointForConfig(config, endpointForData)
+	if err != nil || data != "https://"+accelerateEndpoint {
+		t.Errorf("data endpoint = %q, %v; want https://%s", data, err, accelerateEndpoint)
+	}
+	management, err := sdkEndpointForConfig(config, endpointForManagement)
+	if err != nil || management != "https://oss-cn-hangzhou.aliyuncs.com" {
+		t.Errorf("management endpoint = %q, %v; want the regional endpoint", management, err)
+	}
+
+	config.CnameDomain = "static.example.com"
+	data, err = sdkEndpointForConfig(config, endpointForData)
+	if err != nil || data != "https://static.example.com" {
+		t.Errorf("data endpoint with a custom domain = %q, %v; want the custom domain", data, err)
+	}
+}
+
+func TestIsAccelerateEndpoint(t *testing.T) {
+	tests := map[string]bool{
+		"oss-accelerate.aliyuncs.com":          true,
+		"OSS-Accelerate.aliyuncs.com":          true,
+		"oss-accelerate-overseas.aliyuncs.com": true,
+		"oss-cn-hangzhou.aliyuncs.com":         false,
+		"cn-hangzhou.oss.aliyuncs.com":         false,
+		"":                                     false,
+	}
+	for endpoint, want := range tests {
+		if got := isAccelerateEndpoint(endpoint); got != want {
+			t.Errorf("isAccelerateEndpoint(%q) = %v, want %v", endpoint, got, want)
+		}
+	}
+}
+
+func TestNormalizeEndpointKeepsPort(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"oss-cn-hangzhou.aliyuncs.com", "oss-cn-hangzhou.aliyuncs.com"},
+		{" https://oss-cn-hangzhou.aliyuncs.com/ ", "oss-cn-hangzhou.aliyuncs.com"},
+		{"minio.internal:9000", "minio.internal:9000"},
+		{"http://minio.internal:9000", "minio.internal:9000"},
+		{"http://minio.internal:9000/some/path?x=1#frag", "minio.internal:9000"},
+		{"minio.internal:9000/path", "minio.internal:9000"},
+		{"http://[fd00::1]:9000/", "[fd00::1]:9000"},
+		{"[fd00::1]:9000", "[fd00::1]:9000"},
+		{"fd00::1", "[fd00::1]"},
+		{"10.0.0.5:8080", "10.0.0.5:8080"},
+		{"oss-cn-hangzhou.aliyuncs.com.", "oss-cn-hangzhou.aliyuncs.com"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeEndpoint(tt.in); got != tt.want {
+			t.Errorf("normalizeEndpoint(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointURLForConfigKeepsScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		insecure bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "https with port", endpoint: "https://gateway.example.com:8443/x", want: "https://gateway.example.com:8443"},
+		{name: "bare host defaults to https", endpoint: "gateway.example.com:8443", want: "https://gateway.example.com:8443"},
+		{name: "plain http allowed", endpoint: "http://minio.internal:9000", insecure: true, want: "http://minio.internal:9000"},
+		{name: "plain http refused", endpoint: "http://minio.internal:9000", wantErr: true},
+		{name: "IPv6 literal", endpoint: "HTTP://[fd00::1]:9000", insecure: true, want: "http://[fd00::1]:9000"},
+		{name: "no endpoint", endpoint: "", want: ""},
+	}
+	for _, tt := range tests {
+		config := OSSConfig{Region: "cn-hangzhou", Endpoint: tt.endpoint, AllowInsecureHTTP: tt.insecure}
+		got, err := endpointURLForConfig(config, endpointForData)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: endpointURLForConfig = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDerivedEndpointsAreHTTPS(t *testing.T) {
+	// The configured http scheme applies to the configured host only.
+	config := OSSConfig{Region: "cn-hangzhou", Endpoint: "http://oss-cn-hangzhou.aliyuncs.com", UseInternalEndpoint: true}
+	got, err := sdkEndpointForConfig(config, endpointForData)
+	if err != nil || got != "https://oss-cn-hangzhou-internal.aliyuncs.com" {
+		t.Errorf("sdkEndpointForConfig = %q, %v; want the internal endpoint over https", got, err)
+	}
+
+	got, err = sdkEndpointForConfig(OSSConfig{Region: "cn-hangzhou"}, endpointForData)
+	if err != nil || got != "https://oss-cn-hangzhou.aliyuncs.com" {
+		t.Errorf("sdkEndpointForConfig without an endpoint = %q, %v", got, err)
+	}
+	if _, err := sdkEndpointForConfig(OSSConfig{}, endpointForData); err == nil {
+		t.Error("sdkEndpointForConfig without endpoint or region succeeded")
+	}
+}
+
+func TestOssutilConnPassesFullEndpoint(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, "echo 'ossutil version 2.1.2'")
+	config := OSSConfig{AccessKeyID: "id", AccessKeySecret: "secret", Region: "cn-hangzhou", Endpoint: "http://minio.internal:9000", AllowInsecureHTTP: true}
+
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.close()
+	if got := strings.Join(conn.args, " "); !strings.Contains(got, "--endpoint http://minio.internal:9000") {
+		t.Errorf("ossutil arguments = %q, want the endpoint with scheme and port", got)
+	}
+
+	config.AllowInsecureHTTP = false
+	if _, err := s.ossutilConn(config, endpointForData); err == nil {
+		t.Error("ossutilConn accepted a plain-http endpoint without AllowInsecureHTTP")
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossServiceErrorCode returns the OSS error code carried by err, or "" for non-service errors.
+func ossServiceErrorCode(err error) string {
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code
+	}
+	return ""
+}
+
+func isOSSErrorCode(err error, codes ...string) bool {
+	code := ossServiceErrorCode(err)
+	if code == "" {
+		return false
+	}
+	for _, c := range codes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+func isAccessDenied(err error) bool {
+	if isOSSErrorCode(err, "AccessDenied") {
+		return true
+	}
+	var serviceErr oss.ServiceError
+	return errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusForbidden
+}
+
+func isNotFound(err error) bool {
+	var serviceErr oss.ServiceError
+	return errors.As(err, &serviceErr) && serviceErr.StatusCode == http.StatusNotFound
+}
+
+// ossutil prints the request ID of failed calls as "RequestId=..." or "RequestId: ..."; SDK errors
+// that went through describeSDKError say "request id ...".
+var reOssutilRequestID = regexp.MustCompile(`(?:RequestId[=:]|request id)\s*"?([0-9A-Fa-f]{24})`)
+
+// ossRequestID returns the OSS request ID carried by err, from the SDK error or ossutil output.
+func ossRequestID(err error) string {
+	if err == nil {
+		return ""
+	}
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.RequestID
+	}
+	if m := reOssutilRequestID.FindStringSubmatch(err.Error()); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// sdkServiceError shows an oss.ServiceError the way Alibaba support asks for it: the OSS error code
+// and message followed by the request ID, rather than the SDK's field dump. It unwraps to the
+// original, so errors.As and the classifiers above still see the oss.ServiceError.
+type sdkServiceError struct {
+	err oss.ServiceError
+}
+
+func (e *sdkServiceError) Error() string {
+	text := e.err.Code
+	if text == "" {
+		text = fmt.Sprintf("HTTP %d", e.err.StatusCode)
+	}
+	if message := strings.TrimSpace(e.err.Message); message != "" {
+		text += ": " + message
+	}
+	if e.err.RequestID != "" {
+		text += fmt.Sprintf(" (request id %s)", e.err.RequestID)
+	}
+	return text
+}
+
+func (e *sdkServiceError) Unwrap() error { return e.err }
+
+// describedSDKError carries a message in which describeSDKErrors replaced the SDK's field dump.
+type describedSDKError struct {
+	msg string
+	err error
+}
+
+func (e *describedSDKError) Error() string { return e.msg }
+
+func (e *describedSDKError) Unwrap() error { return e.err }
+
+// describeSDKErrors does what describeSDKError does for a ServiceError anywhere in err's chain, such
+// as one wrapped by fmt.Errorf("failed to get CORS rules: %w", err). The chain itself is kept.
+func describeSDKErrors(err error) error {
+	var serviceErr oss.ServiceError
+	if err == nil || !errors.As(err, &serviceErr) {
+		return err
+	}
+	raw, msg := serviceErr.Error(), err.Error()
+	if !strings.Contains(msg, raw) {
+		return err
+	}
+	described := (&sdkServiceError{err: serviceErr}).Error()
+	return &describedSDKError{msg: strings.Replace(msg, raw, described, 1), err: err}
+}
+
+// describeSDKError rewrites an error returned straight from the SDK as a sdkServiceError; anything
+// else, including errors that already wrap a ServiceError, is returned unchanged.
+func describeSDKError(err error) error {
+	switch serviceErr := err.(type) {
+	case oss.ServiceError:
+		return &sdkServiceError{err: serviceErr}
+	case *oss.ServiceError:
+		if serviceErr != nil {
+			return &sdkServiceError{err: *serviceErr}
+		}
+	}
+	return err
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const testRequestID = "6554F0A1B2C3D4E5F6A7B8C9"
+
+var accessDeniedErr = oss.ServiceError{
+	Code:       "AccessDenied",
+	Message:    "You have no right to access this object.",
+	RequestID:  testRequestID,
+	HostID:     "bucket.oss-cn-hangzhou.aliyuncs.com",
+	StatusCode: http.StatusForbidden,
+}
+
+func TestSDKServiceErrorFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		err  oss.ServiceError
+		want string
+	}{
+		{"full", accessDeniedErr, "AccessDenied: You have no right to access this object. (request id " + testRequestID + ")"},
+		{"no message", oss.ServiceError{Code: "NoSuchKey", RequestID: testRequestID, StatusCode: 404}, "NoSuchKey (request id " + testRequestID + ")"},
+		{"no request id", oss.ServiceError{Code: "NoSuchBucket", Message: "The specified bucket does not exist.", StatusCode: 404}, "NoSuchBucket: The specified bucket does not exist."},
+		{"no code", oss.ServiceError{StatusCode: 502, RequestID: testRequestID}, "HTTP 502 (request id " + testRequestID + ")"},
+		{"message padded", oss.ServiceError{Code: "InvalidArgument", Message: "  bad max-keys\n", StatusCode: 400}, "InvalidArgument: bad max-keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, err := range []error{tt.err, &tt.err} {
+				if got := describeSDKError(err).Error(); got != tt.want {
+					t.Errorf("describeSDKError(%T) = %q, want %q", err, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// requireServiceErrorChain checks that err still classifies as the AccessDenied test error.
+func requireServiceErrorChain(t *testing.T, err error) {
+	t.Helper()
+	var serviceErr oss.ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr != accessDeniedErr {
+		t.Fatalf("errors.As(%v) did not find the ServiceError", err)
+	}
+	if !isAccessDenied(err) || !isOSSErrorCode(err, "AccessDenied") || ossServiceErrorCode(err) != "AccessDenied" {
+		t.Fatalf("%v no longer classifies as AccessDenied", err)
+	}
+	if got := ossRequestID(err); got != testRequestID {
+		t.Fatalf("ossRequestID(%v) = %q", err, got)
+	}
+}
+
+func TestDescribeSDKErrorKeepsChain(t *testing.T) {
+	requireServiceErrorChain(t, describeSDKError(accessDeniedErr))
+
+	notFound := describeSDKError(oss.ServiceError{Code: "NoSuchKey", StatusCode: http.StatusNotFound})
+	if !isNotFound(notFound) || isAccessDenied(notFound) {
+		t.Fatalf("%v misclassified", notFound)
+	}
+
+	plain := errors.New("invalid bucket name")
+	if describeSDKError(plain) != plain || describeSDKError(nil) != nil {
+		t.Fatal("describeSDKError changed a non-service error")
+	}
+	if wrapped := fmt.Errorf("x: %w", accessDeniedErr); describeSDKError(wrapped) != wrapped {
+		t.Fatal("describeSDKError changed an error wrapping a ServiceError")
+	}
+}
+
+func TestDescribeSDKErrorsRewritesWrappedDump(t *testing.T) {
+	wrapped := fmt.Errorf("failed to get CORS rules: %w", accessDeniedErr)
+	if !strings.Contains(wrapped.Error(), "StatusCode=403") {
+		t.Fatalf("unexpected SDK dump %q", wrapped)
+	}
+	described := describeSDKErrors(wrapped)
+	want := "failed to get CORS rules: AccessDenied: You have no right to access this object. (request id " + testRequestID + ")"
+	if described.Error() != want {
+		t.Fatalf("describeSDKErrors = %q, want %q", described, want)
+	}
+	requireServiceErrorChain(t, described)
+	if !errors.Is(described, wrapped) {
+		t.Fatal("the original error left the chain")
+	}
+
+	// Errors that already read well, or carry no ServiceError, are left alone.
+	for _, err := range []error{
+		errors.New("invalid bucket name"),
+		fmt.Errorf("%w: %s", ErrCredentialsNeedReauth, "expired"),
+		describeSDKError(accessDeniedErr),
+		nil,
+	} {
+		if got := describeSDKErrors(err); got != err {
+			t.Errorf("describeSDKErrors(%v) = %v, want it unchanged", err, got)
+		}
+	}
+}
+
+func TestErrorfDescribesSDKErrors(t *testing.T) {
+	s := newTestService(t)
+	err := s.errorf(msgListFolderFailed, accessDeniedErr)
+	want := "failed to list folder objects: AccessDenied: You have no right to access this object. (request id " + testRequestID + ")"
+	if err.Error() != want {
+		t.Fatalf("errorf = %q, want %q", err, want)
+	}
+	requireServiceErrorChain(t, err)
+}
+
+func TestOssRequestIDFromOssutilOutput(t *testing.T) {
+	tests := map[string]string{
+		"Error: oss: service returned error: StatusCode=403, ErrorCode=AccessDenied, RequestId=" + testRequestID + ", HostId=x": testRequestID,
+		"ErrorCode: AccessDenied\nRequestId: " + testRequestID + "\n":                                                           testRequestID,
+		"failed: AccessDenied (request id " + testRequestID + ")":                                                               testRequestID,
+		"RequestId=short":    "",
+		"connection refused": "",
+	}
+	for text, want := range tests {
+		if got := ossRequestID(errors.New(text)); got != want {
+			t.Errorf("ossRequestID(%q) = %q, want %q", text, got, want)
+		}
+	}
+	if ossRequestID(nil) != "" {
+		t.Error("nil error has a request id")
+	}
+}
+
+func TestSDKErrorsReachCallerAndLogDescribed(t *testing.T) {
+	s := newTestService(t)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("x-oss-request-id", testRequestID)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>You have no right to access this object.</Message><RequestId>` + testRequestID + `</RequestId><HostId>host</HostId></Error>`))
+	})
+
+	_, err := s.GetBucketCORS(config, "bucket")
+	want := "failed to get CORS rules: AccessDenied: You have no right to access this object. (request id " + testRequestID + ")"
+	if err == nil || err.Error() != want {
+		t.Fatalf("GetBucketCORS error = %v, want %q", err, want)
+	}
+	if !isAccessDenied(err) || ossRequestID(err) != testRequestID {
+		t.Fatalf("%v lost its ServiceError", err)
+	}
+
+	entries := s.opLog.recentEntries(1)
+	if len(entries) != 1 {
+		t.Fatalf("operation log has %d entries", len(entries))
+	}
+	entry := entries[0]
+	if entry.Method != "GetBucketCORS" || entry.Error != want || entry.ErrorCode != "AccessDenied" || entry.RequestID != testRequestID {
+		t.Fatalf("logged %+v", entry)
+	}
+}
+
+func TestLogOperationEventRecordsErrorCode(t *testing.T) {
+	s := newTestService(t)
+	s.logOperationEvent(logLevelWarn, "DeleteObject", opOutcomeError, "a.txt", describeSDKError(accessDeniedErr))
+	entries := s.opLog.recentEntries(1)
+	if len(entries) != 1 || entries[0].ErrorCode != "AccessDenied" || entries[0].RequestID != testRequestID {
+		t.Fatalf("logged %+v", entries)
+	}
+
+	var err error = fmt.Errorf("wrapped: %w", accessDeniedErr)
+	s.logOperation("GetObjectMeta", "bucket", "a.txt", time.Now(), &err)
+	if !strings.Contains(err.Error(), "(request id "+testRequestID+")") {
+		t.Fatalf("logOperation left %q", err)
+	}
+}
@@ -2,113 +2,132 @@ package main
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
+	"time"
 
 	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
 
+// normalizeObjectKey drops leading slashes. Surrounding spaces are part of a key and are kept.
 func normalizeObjectKey(key string) string {
-	key = strings.TrimSpace(key)
-	key = strings.TrimLeft(key, "/")
-	return key
+	return strings.TrimLeft(key, "/")
 }
 
 // CreateFolder creates a folder placeholder object (key ending with "/") so it appears in listings.
-func (s *OSSService) CreateFolder(config OSSConfig, bucketName string, prefix string, folderName string) error {
+func (s *OSSService) CreateFolder(config OSSConfig, bucketName string, prefix string, folderName string) (err error) {
+	defer s.logOperation("CreateFolder", bucketName, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
 	bucketName = strings.TrimSpace(bucketName)
 	if bucketName == "" {
-		return fmt.Errorf("bucket name is required")
+		return s.errorf(msgBucketNameRequired)
 	}
 
 	folderName = strings.TrimSpace(folderName)
-	folderName = strings.Trim(folderName, "/")
-	if folderName == "" {
-		return fmt.Errorf("folder name is required")
-	}
-
 	prefix = normalizeObjectPrefix(prefix)
-	key := normalizeObjectKey(prefix + folderName + "/")
-
-	client, err := sdkClientFromConfig(config)
-	if err != nil {
-		return err
+	key := joinTypedName(prefix, folderName)
+	if key == prefix {
+		return s.errorf(msgFolderNameRequired)
 	}
-
-	bucket, err := client.Bucket(bucketName)
-	if err != nil {
-		return fmt.Errorf("failed to open bucket: %w", err)
+	key += "/"
+	if err := validateObjectRef(bucketName, key); err != nil {
+		return err
 	}
 
-	if err := bucket.PutObject(key, bytes.NewReader(nil)); err != nil {
-		return fmt.Errorf("failed to create folder: %w", err)
+	if err := s.withBucketRegion(config, bucketName, "CreateFolder", func(bucket *oss.Bucket) error {
+		return bucket.PutObject(key, bytes.NewReader(nil))
+	}); err != nil {
+		return s.errorf(msgCreateFolderFailed, err)
 	}
 
 	return nil
 }
 
 // CreateFile creates an empty object (key not ending with "/") under the given prefix.
-func (s *OSSService) CreateFile(config OSSConfig, bucketName string, prefix string, fileName string) error {
+func (s *OSSService) CreateFile(config OSSConfig, bucketName string, prefix string, fileName string) (err error) {
+	defer s.logOperation("CreateFile", bucketName, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+
 	bucketName = strings.TrimSpace(bucketName)
 	if bucketName == "" {
-		return fmt.Errorf("bucket name is required")
+		return s.errorf(msgBucketNameRequired)
 	}
 
 	fileName = strings.TrimSpace(fileName)
-	fileName = strings.Trim(fileName, "/")
-	if fileName == "" {
-		return fmt.Errorf("file name is required")
-	}
-
 	prefix = normalizeObjectPrefix(prefix)
-	key := normalizeObjectKey(prefix + fileName)
+	key := joinTypedName(prefix, fileName)
+	if key == prefix {
+		return s.errorf(msgFileNameRequired)
+	}
 	if key == "" {
-		return fmt.Errorf("file key is empty")
+		return s.errorf(msgFileKeyEmpty)
 	}
 	if strings.HasSuffix(key, "/") {
-		return fmt.Errorf("file name cannot end with '/'")
+		return s.errorf(msgFileNameTrailingSlash)
 	}
-
-	client, err := sdkClientFromConfig(config)
-	if err != nil {
+	if err := validateObjectRef(bucketName, key); err != nil {
 		return err
 	}
 
-	bucket, err := client.Bucket(bucketName)
-	if err != nil {
-		return fmt.Errorf("failed to open bucket: %w", err)
-	}
-
-	exists, err := bucket.IsObjectExist(key)
+	var exists bool
+	err = s.withBucketRegion(config, bucketName, "CreateFile", func(bucket *oss.Bucket) error {
+		var err error
+		exists, err = bucket.IsObjectExist(key)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to check file exists: %w", err)
+		return s.errorf(msgCheckFileExistsFailed, err)
 	}
 	if exists {
-		return fmt.Errorf("file already exists")
+		return s.errorf(msgFileExists)
 	}
 
+	bucket, err := s.sdkBucket(config, bucketName)
+	if err != nil {
+		return err
+	}
 	if err := bucket.PutObject(key, bytes.NewReader(nil)); err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return s.errorf(msgCreateFileFailed, err)
 	}
 
 	return nil
 }
 
-func (s *OSSService) MoveObject(config OSSConfig, srcBucketName string, srcKey string, destBucketName string, destKey string) error {
+// MoveObject moves an object, or a folder when srcKey ends with "/". Objects are moved before it
+// returns; folders are moved by a background job whose ID is returned (see CancelMoveJob).
+func (s *OSSService) MoveObject(config OSSConfig, srcBucketName string, srcKey string, destBucketName string, destKey string) (_ string, err error) {
+	defer s.logOperation("MoveObject", srcBucketName, srcKey, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return "", err
+	}
+
 	srcBucketName = strings.TrimSpace(srcBucketName)
 	destBucketName = strings.TrimSpace(destBucketName)
 	if srcBucketName == "" || destBucketName == "" {
-		return fmt.Errorf("source and destination bucket are required")
+		return "", s.errorf(msgMoveBucketsRequired)
 	}
 
 	srcKey = normalizeObjectKey(srcKey)
 	destKey = normalizeObjectKey(destKey)
 	if srcKey == "" || destKey == "" {
-		return fmt.Errorf("source and destination key are required")
+		return "", s.errorf(msgMoveKeysRequired)
+	}
+	if err := validateObjectRef(srcBucketName, srcKey); err != nil {
+		return "", err
+	}
+	if err := validateObjectRef(destBucketName, destKey); err != nil {
+		return "", err
 	}
 
 	if srcBucketName == destBucketName && srcKey == destKey {
-		return nil
+		return "", nil
 	}
 
 	isFolder := strings.HasSuffix(srcKey, "/")
@@ -118,84 +137,57 @@ func (s *OSSService) MoveObject(config OSSConfig, srcBucketName string, srcKey s
 
 	// Prevent moving a folder into itself (same bucket).
 	if isFolder && srcBucketName == destBucketName && strings.HasPrefix(destKey, srcKey) {
-		return fmt.Errorf("destination is inside the source folder")
+		return "", s.errorf(msgMoveIntoSource)
 	}
 
-	client, err := sdkClientFromConfig(config)
-	if err != nil {
-		return err
+	if !isFolder {
+		target := buildOssPath(srcBucketName, srcKey) + " -> " + buildOssPath(destBucketName, destKey)
+		// The copy is sent to the destination bucket, the delete to the source.
+		var etag string
+		if err := s.withBucketRegion(config, destBucketName, "CopyObject", func(destBucket *oss.Bucket) error {
+			return s.withSDKRetry("CopyObject", func() error {
+				var copyErr error
+				etag, copyErr = s.copyObjectForMove(srcBucketName, destBucket, srcKey, destKey)
+				return copyErr
+			})
+		}); err != nil {
+			err = s.errorf(msgCopyFailed, err)
+			s.recordAudit(config, "MoveObject", srcBucketName, target, 0, err)
+			return "", err
+		}
+
+		if err := s.withBucketRegion(config, srcBucketName, "DeleteObject", func(srcBucket *oss.Bucket) error {
+			return s.withSDKRetry("DeleteObject", func() error { return srcBucket.DeleteObject(srcKey) })
+		}); err != nil {
+			err = s.deleteSourceError(config, srcBucketName, err)
+			s.recordAudit(config, "MoveObject", srcBucketName, target, 0, err)
+			// The object was copied but not moved; undoing removes the copy.
+			s.rememberMove(config, srcBucketName, srcKey, destBucketName, destKey, []undoObject{{srcKey: srcKey, destKey: destKey, etag: etag, sourceKept: true}})
+			return "", err
+		}
+		s.recordAudit(config, "MoveObject", srcBucketName, target, 1, nil)
+		s.rememberMove(config, srcBucketName, srcKey, destBucketName, destKey, []undoObject{{srcKey: srcKey, destKey: destKey, etag: etag}})
+		return "", nil
 	}
 
-	srcBucket, err := client.Bucket(srcBucketName)
+	srcBucket, err := s.sdkBucket(config, srcBucketName)
 	if err != nil {
-		return fmt.Errorf("failed to open source bucket: %w", err)
+		return "", s.errorf(msgOpenSourceBucketFailed, err)
 	}
-
-	destBucket, err := client.Bucket(destBucketName)
+	destBucket, err := s.sdkBucket(config, destBucketName)
 	if err != nil {
-		return fmt.Errorf("failed to open destination bucket: %w", err)
+		return "", s.errorf(msgOpenDestBucketFailed, err)
 	}
 
-	if !isFolder {
-		if srcBucketName == destBucketName {
-			if _, err := destBucket.CopyObject(srcKey, destKey); err != nil {
-				return fmt.Errorf("copy failed: %w", err)
-			}
-		} else {
-			if _, err := destBucket.CopyObjectFrom(srcBucketName, srcKey, destKey); err != nil {
-				return fmt.Errorf("copy failed: %w", err)
-			}
-		}
-
-		if err := srcBucket.DeleteObject(srcKey); err != nil {
-			return fmt.Errorf("delete source failed: %w", err)
-		}
-		return nil
-	}
-
-	// Folder move: list recursively and move each object.
-	marker := ""
-	for {
-		lor, err := srcBucket.ListObjects(
-			oss.Prefix(srcKey),
-			oss.Marker(marker),
-			oss.MaxKeys(1000),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to list folder objects: %w", err)
-		}
-
-		for _, object := range lor.Objects {
-			key := normalizeObjectKey(object.Key)
-			if !strings.HasPrefix(key, srcKey) {
-				continue
-			}
-			rel := strings.TrimPrefix(key, srcKey)
-			targetKey := destKey + rel
-
-			if srcBucketName == destBucketName {
-				if key == targetKey {
-					continue
-				}
-				if _, err := destBucket.CopyObject(key, targetKey); err != nil {
-					return fmt.Errorf("copy failed: %w", err)
-				}
-			} else {
-				if _, err := destBucket.CopyObjectFrom(srcBucketName, key, targetKey); err != nil {
-					return fmt.Errorf("copy failed: %w", err)
-				}
-			}
-
-			if err := srcBucket.DeleteObject(key); err != nil {
-				return fmt.Errorf("delete source failed: %w", err)
-			}
-		}
+	// Folders can hold many objects; move them in the background and report through move:update.
+	return s.startMoveJob(config, srcBucket, destBucket, srcKey, destKey), nil
+}
 
-		if !lor.IsTruncated {
-			break
+func (s *OSSService) deleteSourceError(config OSSConfig, bucketName string, err error) error {
+	if isWormRejection(err, "") {
+		if summary := s.wormRejectionSummary(config, bucketName); summary != "" {
+			return s.errorf(msgDeleteSourceFailedSummary, summary, err)
 		}
-		marker = lor.NextMarker
 	}
-
-	return nil
+	return s.errorf(msgDeleteSourceFailed, err)
 }
@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
@@ -15,44 +17,110 @@ type ObjectListPageResult struct {
 	IsTruncated bool         `json:"isTruncated"`
 }
 
-func sdkEndpointForConfig(config OSSConfig) (string, error) {
-	endpointHost := normalizeEndpoint(config.Endpoint)
-	if endpointHost == "" {
-		endpointHost = suggestServiceEndpoint(normalizeRegion(config.Region))
+func sdkEndpointForConfig(config OSSConfig, purpose endpointPurpose) (string, error) {
+	if usesCname(config, purpose) {
+		return cnameEndpointURL(config)
 	}
-	if endpointHost == "" {
-		return "", fmt.Errorf("missing endpoint: please set Endpoint or Region")
+	endpoint, err := endpointURLForConfig(config, purpose)
+	if err != nil || endpoint != "" {
+		return endpoint, err
 	}
+	if host := suggestServiceEndpoint(config.Region, config.UseInternalEndpoint, config.UseDualStackEndpoint); host != "" {
+		return "https://" + host, nil
+	}
+	return "", fmt.Errorf("missing endpoint: please set Endpoint or Region")
+}
+
+// usesCname reports whether requests for purpose go to config's custom domain. A custom domain
+// serves the objects of one bucket only, so bucket management stays on the regional endpoint.
+func usesCname(config OSSConfig, purpose endpointPurpose) bool {
+	return purpose == endpointForData && normalizeEndpoint(config.CnameDomain) != ""
+}
 
-	endpoint := endpointHost
-	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
-		endpoint = "https://" + endpoint
+// cnameEndpointURL returns the URL of config's custom domain. As for a configured endpoint, plain
+// http needs AllowInsecureHTTP.
+func cnameEndpointURL(config OSSConfig) (string, error) {
+	host := normalizeEndpoint(config.CnameDomain)
+	scheme := endpointScheme(config.CnameDomain)
+	if scheme == "http" && !config.AllowInsecureHTTP {
+		return "", fmt.Errorf("custom domain %s uses plain http: enable \"Allow insecure HTTP\" for this profile to use it", host)
 	}
-	return endpoint, nil
+	return scheme + "://" + host, nil
 }
 
-func sdkClientFromConfig(config OSSConfig) (*oss.Client, error) {
-	endpoint, err := sdkEndpointForConfig(config)
+func (s *OSSService) sdkClientForPurpose(config OSSConfig, purpose endpointPurpose) (*oss.Client, error) {
+	if err := s.checkNeedsReauth(config); err != nil {
+		return nil, err
+	}
+	config, err := s.resolveCredentials(config)
 	if err != nil {
 		return nil, err
 	}
 
+	endpoint, err := sdkEndpointForConfig(config, purpose)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInternalEndpoint(config, endpoint); err != nil {
+		return nil, err
+	}
+	if err := s.requireReachable(s.rememberProbeTarget(config, endpoint)); err != nil {
+		return nil, err
+	}
+
 	region := normalizeRegion(config.Region)
-	options := []oss.ClientOption{}
-	if region != "" {
-		options = append(options, oss.Region(region))
+	tuning := s.getSDKTuning()
+	proxy, hasProxy := s.proxyFor(config)
+	tlsConfig := s.tlsFor(config)
+	if err := checkTLSForEndpoint(tlsConfig, endpoint); err != nil {
+		return nil, err
 	}
+	cname := usesCname(config, purpose)
+	key := sdkClientCacheKey(endpoint, cname, config, region, tuning, proxy, tlsConfig)
+	return s.sdkClients.get(key, func() (*oss.Client, error) {
+		options := []oss.ClientOption{tuning.timeoutOption()}
+		if cname {
+			options = append(options, oss.UseCname(true))
+		}
+		if region != "" {
+			options = append(options, oss.Region(region))
+		}
+		if config.SecurityToken != "" {
+			options = append(options, oss.SecurityToken(config.SecurityToken))
+		}
+		if hasProxy {
+			options = append(options, proxy.sdkOption())
+		}
+		tlsOptions, err := tlsConfig.sdkOptions(tuning, proxy, hasProxy)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tlsOptions...)
+		return oss.New(endpoint, config.AccessKeyID, config.AccessKeySecret, options...)
+	})
+}
+
+// sdkClientFromConfig returns a client for object data operations (listing, reads, writes, signing).
+func (s *OSSService) sdkClientFromConfig(config OSSConfig) (*oss.Client, error) {
+	return s.sdkClientForPurpose(config, endpointForData)
+}
 
-	return oss.New(endpoint, config.AccessKeyID, config.AccessKeySecret, options...)
+// sdkManagementClientFromConfig returns a client for service and bucket configuration calls,
+// which are always sent to the regional endpoint.
+func (s *OSSService) sdkManagementClientFromConfig(config OSSConfig) (*oss.Client, error) {
+	return s.sdkClientForPurpose(config, endpointForManagement)
 }
 
-func sdkSmokeTestListBuckets(config OSSConfig) error {
-	client, err := sdkClientFromConfig(config)
+func (s *OSSService) sdkSmokeTestListBuckets(config OSSConfig) error {
+	client, err := s.sdkManagementClientFromConfig(config)
 	if err != nil {
 		return err
 	}
-	_, err = client.ListBuckets(oss.MaxKeys(1))
-	return err
+	err = s.withSDKRetry("ListBuckets", func() error {
+		_, err := client.ListBuckets(oss.MaxKeys(1))
+		return err
+	})
+	return s.classifyAuthError(config, err)
 }
 
 func formatObjectLastModified(ts time.Time) string {
@@ -62,9 +130,10 @@ func formatObjectLastModified(ts time.Time) string {
 	return ts.Local().Format("2006-01-02 15:04:05")
 }
 
+// normalizeObjectPrefix completes a folder prefix with a trailing slash. Like keys, prefixes are
+// otherwise kept byte for byte: leading slashes, empty segments and surrounding spaces belong to
+// the keys they select, so "/" lists the keys starting with "/", not the bucket root.
 func normalizeObjectPrefix(prefix string) string {
-	prefix = strings.TrimSpace(prefix)
-	prefix = strings.TrimLeft(prefix, "/")
 	if prefix == "" {
 		return ""
 	}
@@ -74,9 +143,41 @@ func normalizeObjectPrefix(prefix string) string {
 	return prefix
 }
 
+// listingChild returns the entry under prefix that key is listed as: a file for a key directly
+// under prefix, a folder for a common prefix one level down. Keys written by other tools can hold
+// empty segments ("logs//2024//app.log"); an empty segment is a folder named "" that is listed and
+// opened like any other, so "logs/" lists "" and "logs//" lists "2024".
+func listingChild(prefix string, key string) (name string, isFolder bool, ok bool) {
+	rest, found := strings.CutPrefix(key, prefix)
+	if !found || rest == "" {
+		return "", false, false
+	}
+	name, after, isFolder := strings.Cut(rest, "/")
+	if isFolder && after != "" {
+		return "", false, false
+	}
+	return name, isFolder, true
+}
+
+// joinTypedName appends a file or folder name typed by the user to prefix. Runs of slashes in the
+// name are collapsed so a typo does not create an empty segment; prefix is an existing location
+// and is kept as it is.
+func joinTypedName(prefix string, name string) string {
+	segments := strings.Split(strings.Trim(name, "/"), "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return prefix + strings.Join(kept, "/")
+}
+
+// buildOssPath and parseOssPath are the one place keys are turned into oss:// paths and back.
+// Keys are opaque: ossutil takes the path as a single argument without unescaping it, so spaces,
+// '#', '?', '%', '+' and any Unicode are written as they are and never URL-encoded or trimmed.
 func buildOssPath(bucketName string, key string) string {
 	bucketName = strings.Trim(bucketName, "/")
-	key = strings.TrimLeft(key, "/")
 	if bucketName == "" {
 		return "oss://"
 	}
@@ -86,10 +187,50 @@ func buildOssPath(bucketName string, key string) string {
 	return fmt.Sprintf("oss://%s/%s", bucketName, key)
 }
 
-func (s *OSSService) ListObjectsPage(config OSSConfig, bucketName string, prefix string, marker string, maxKeys int) (ObjectListPageResult, error) {
+// parseOssPath splits an oss://bucket/key path. Whitespace before the scheme is ignored, but
+// everything after the bucket is the key, byte for byte.
+func parseOssPath(path string) (bucket string, key string, ok bool) {
+	rest, ok := strings.CutPrefix(strings.TrimLeftFunc(path, unicode.IsSpace), "oss://")
+	if !ok {
+		return "", "", false
+	}
+	bucket, key, _ = strings.Cut(strings.TrimLeft(rest, "/"), "/")
+	if bucket == "" {
+		return "", "", false
+	}
+	return bucket, key, true
+}
+
+// listObjectsShared lists one page of prefix with "/" as the delimiter, sharing the request with
+// identical listings in flight. The result must be treated as read-only.
+func (s *OSSService) listObjectsShared(config OSSConfig, bucketName string, prefix string, marker string, maxKeys int) (oss.ListObjectsResult, error) {
+	flightKey := sdkFlightKey("ListObjects", config, bucketName, prefix, marker, strconv.Itoa(maxKeys))
+	shared, err := s.sdkFlights.do(flightKey, func() (any, error) {
+		var lor oss.ListObjectsResult
+		err := s.withBucketRegion(config, bucketName, "ListObjects", func(bucket *oss.Bucket) error {
+			return s.withSDKRetry("ListObjects", func() error {
+				var err error
+				lor, err = bucket.ListObjects(
+					oss.Prefix(prefix),
+					oss.Delimiter("/"),
+					oss.Marker(marker),
+					oss.MaxKeys(maxKeys),
+				)
+				return err
+			})
+		})
+		return lor, err
+	})
+	lor, _ := shared.(oss.ListObjectsResult)
+	return lor, err
+}
+
+func (s *OSSService) ListObjectsPage(config OSSConfig, bucketName string, prefix string, marker string, maxKeys int) (_ ObjectListPageResult, err error) {
+	defer s.logOperation("ListObjectsPage", bucketName, prefix, time.Now(), &err)
+
 	bucketName = strings.TrimSpace(bucketName)
-	if bucketName == "" {
-		return ObjectListPageResult{}, fmt.Errorf("bucket name is required")
+	if err := validateBucketName(bucketName); err != nil {
+		return ObjectListPageResult{}, err
 	}
 
 	prefix = normalizeObjectPrefix(prefix)
@@ -102,65 +243,37 @@ func (s *OSSService) ListObjectsPage(config OSSConfig, bucketName string, prefix
 		maxKeys = 1000
 	}
 
-	client, err := sdkClientFromConfig(config)
+	// lor may be shared with concurrent identical listings; it is only read below.
+	lor, err := s.listObjectsShared(config, bucketName, prefix, marker, maxKeys)
 	if err != nil {
-		return ObjectListPageResult{}, err
-	}
-
-	bucket, err := client.Bucket(bucketName)
-	if err != nil {
-		return ObjectListPageResult{}, fmt.Errorf("failed to open bucket: %w", err)
-	}
-
-	lor, err := bucket.ListObjects(
-		oss.Prefix(prefix),
-		oss.Delimiter("/"),
-		oss.Marker(marker),
-		oss.MaxKeys(maxKeys),
-	)
-	if err != nil {
-		return ObjectListPageResult{}, fmt.Errorf("failed to list objects: %w", err)
+		return ObjectListPageResult{}, fmt.Errorf("failed to list objects: %w", s.classifyAuthError(config, err))
 	}
+	s.markProfileUsed(config)
 
 	folders := make([]ObjectInfo, 0, len(lor.CommonPrefixes))
 	for _, commonPrefix := range lor.CommonPrefixes {
-		if prefix != "" && !strings.HasPrefix(commonPrefix, prefix) {
-			continue
-		}
-		relative := strings.TrimPrefix(commonPrefix, prefix)
-		relative = strings.TrimSuffix(relative, "/")
-		if relative == "" || strings.Contains(relative, "/") {
+		name, isFolder, ok := listingChild(prefix, commonPrefix)
+		if !ok || !isFolder {
 			continue
 		}
 
 		folders = append(folders, ObjectInfo{
-			Name: relative,
-			Path: buildOssPath(bucketName, prefix+relative+"/"),
+			Name: name,
+			Path: buildOssPath(bucketName, commonPrefix),
 			Type: "Folder",
 		})
 	}
 
 	files := make([]ObjectInfo, 0, len(lor.Objects))
 	for _, object := range lor.Objects {
-		key := strings.TrimLeft(object.Key, "/")
-		if key == "" {
-			continue
-		}
-		if prefix != "" && key == prefix {
-			continue
-		}
-		if prefix != "" && !strings.HasPrefix(key, prefix) {
-			continue
-		}
-
-		relative := strings.TrimPrefix(key, prefix)
-		if relative == "" || strings.Contains(relative, "/") {
+		name, isFolder, ok := listingChild(prefix, object.Key)
+		if !ok || isFolder {
 			continue
 		}
 
 		files = append(files, ObjectInfo{
-			Name:         relative,
-			Path:         buildOssPath(bucketName, key),
+			Name:         name,
+			Path:         buildOssPath(bucketName, object.Key),
 			Size:         object.Size,
 			Type:         "File",
 			LastModified: formatObjectLastModified(object.LastModified),
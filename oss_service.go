@@ -3,10 +3,12 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
@@ -15,6 +17,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
@@ -31,7 +34,7 @@ func normalizeEndpoint(endpoint string) string {
 		return ""
 	}
 
-	// If user pasted a full URL, keep only host.
+	// If user pasted a full URL, keep only host and port; the scheme is read by endpointScheme.
 	if strings.Contains(endpoint, "://") {
 		if u, err := url.Parse(endpoint); err == nil {
 			if u.Host != "" {
@@ -45,43 +48,274 @@ func normalizeEndpoint(endpoint string) string {
 	endpoint = strings.SplitN(endpoint, "#", 2)[0]
 	endpoint = strings.SplitN(endpoint, "/", 2)[0]
 	endpoint = strings.TrimSuffix(endpoint, ".")
+
+	// A bare IPv6 literal needs brackets, or its last group is read as a port.
+	if ip := net.ParseIP(endpoint); ip != nil && strings.Contains(endpoint, ":") {
+		endpoint = "[" + endpoint + "]"
+	}
 	return endpoint
 }
 
+// endpointScheme returns "http" when endpoint was entered as a plain-http URL, which
+// self-hosted gateways such as http://minio.internal:9000 use, and "https" otherwise.
+func endpointScheme(endpoint string) string {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(endpoint)), "http://") {
+		return "http"
+	}
+	return "https"
+}
+
+// endpointURLForConfig returns the endpoint URL to use for the given purpose, or "" to let the
+// caller derive it from the region. The configured scheme is kept only while the configured host is
+// used; derived Aliyun endpoints are always https. Plain http needs AllowInsecureHTTP.
+func endpointURLForConfig(config OSSConfig, purpose endpointPurpose) (string, error) {
+	host := endpointHostForConfig(config, purpose)
+	if host == "" {
+		return "", nil
+	}
+	scheme := "https"
+	if host == normalizeEndpoint(config.Endpoint) {
+		scheme = endpointScheme(config.Endpoint)
+	}
+	if scheme == "http" && !config.AllowInsecureHTTP {
+		return "", fmt.Errorf("endpoint %s uses plain http: enable \"Allow insecure HTTP\" for this profile to use it", host)
+	}
+	return scheme + "://" + host, nil
+}
+
 func isAccessPointEndpoint(endpoint string) bool {
 	endpoint = strings.ToLower(endpoint)
 	return strings.Contains(endpoint, ".oss-accesspoint.")
 }
 
-func suggestServiceEndpoint(region string) string {
+// suggestServiceEndpoint returns the regional endpoint host. The dual-stack form
+// (<region>.oss.aliyuncs.com) resolves to IPv6 as well as IPv4 addresses; the classic one is
+// IPv4 only. Finance and gov cloud regions use the endpoints of the region catalog, which have no
+// dual-stack form.
+func suggestServiceEndpoint(region string, internal bool, dualStack bool) string {
 	region = normalizeRegion(region)
 	if region == "" {
 		return ""
 	}
+	if known, ok := knownRegionsByID[strings.ToLower(region)]; ok && known.Cloud != regionCloudPublic {
+		if internal {
+			return known.InternalEndpoint
+		}
+		return known.PublicEndpoint
+	}
+	return genericServiceEndpoint(region, internal, dualStack)
+}
+
+// genericServiceEndpoint builds the endpoint host of a public cloud region from its ID.
+func genericServiceEndpoint(region string, internal bool, dualStack bool) string {
+	switch {
+	case dualStack && internal:
+		return fmt.Sprintf("%s-internal.oss.aliyuncs.com", region)
+	case dualStack:
+		return fmt.Sprintf("%s.oss.aliyuncs.com", region)
+	case internal:
+		return fmt.Sprintf("oss-%s-internal.aliyuncs.com", region)
+	}
 	return fmt.Sprintf("oss-%s.aliyuncs.com", region)
 }
 
+// parseRegionalEndpoint takes a regional endpoint apart: oss-<region>[-internal].aliyuncs.com,
+// the dual-stack <region>[-internal].oss.aliyuncs.com or a finance or gov cloud endpoint of the
+// region catalog. region is "" for any other host.
+func parseRegionalEndpoint(endpoint string) (region string, internal bool, dualStack bool) {
+	host := strings.ToLower(normalizeEndpoint(endpoint))
+	if known, ok := knownRegionEndpoints[host]; ok {
+		return known.region, known.internal, false
+	}
+	switch {
+	case strings.HasSuffix(host, ".oss.aliyuncs.com"):
+		region, dualStack = strings.TrimSuffix(host, ".oss.aliyuncs.com"), true
+	case strings.HasPrefix(host, "oss-") && strings.HasSuffix(host, ".aliyuncs.com") && !isAccelerateEndpoint(host):
+		region = strings.TrimSuffix(strings.TrimPrefix(host, "oss-"), ".aliyuncs.com")
+	default:
+		return "", false, false
+	}
+	region, internal = strings.CutSuffix(region, "-internal")
+	if region == "" || strings.Contains(region, ".") {
+		return "", false, false
+	}
+	return region, internal, dualStack
+}
+
+// internalEndpointFor rewrites a public regional endpoint (oss-<region>.aliyuncs.com) to its
+// -internal twin, keeping the dual-stack form. Anything else (custom domains, access points) is
+// returned unchanged.
+func internalEndpointFor(endpoint string) string {
+	region, internal, dualStack := parseRegionalEndpoint(endpoint)
+	if region == "" || internal {
+		return endpoint
+	}
+	return suggestServiceEndpoint(region, true, dualStack)
+}
+
+// dualStackEndpointFor rewrites a regional endpoint to its dual-stack twin. Anything else is
+// returned unchanged: a custom host or IP literal is reachable over whatever it resolves to.
+func dualStackEndpointFor(endpoint string) string {
+	region, internal, dualStack := parseRegionalEndpoint(endpoint)
+	if region == "" || dualStack {
+		return endpoint
+	}
+	return suggestServiceEndpoint(region, internal, true)
+}
+
+const accelerateEndpoint = "oss-accelerate.aliyuncs.com"
+
+func isAccelerateEndpoint(endpoint string) bool {
+	endpoint = strings.ToLower(endpoint)
+	return strings.HasPrefix(endpoint, "oss-accelerate.") || strings.HasPrefix(endpoint, "oss-accelerate-overseas.")
+}
+
+// endpointPurpose tells the endpoint selection whether a call moves object data or manages the
+// bucket itself. Transfer acceleration only serves object data, so management calls must keep
+// using the regional endpoint.
+type endpointPurpose int
+
+const (
+	endpointForData endpointPurpose = iota
+	endpointForManagement
+)
+
+// endpointHostForConfig returns the endpoint host to use for the given purpose, or "" to let the
+// caller derive it from the region. The internal endpoint wins over acceleration: it is both
+// cheaper and faster when it is reachable at all. The dual-stack endpoint is always named, since
+// ossutil and the SDK derive the IPv4-only one from the region.
+func endpointHostForConfig(config OSSConfig, purpose endpointPurpose) string {
+	endpoint := normalizeEndpoint(config.Endpoint)
+	dualStack := config.UseDualStackEndpoint
+	if config.UseInternalEndpoint {
+		if endpoint == "" || isAccelerateEndpoint(endpoint) {
+			return suggestServiceEndpoint(config.Region, true, dualStack)
+		}
+		endpoint = internalEndpointFor(endpoint)
+	} else if purpose == endpointForData && config.UseAccelerateEndpoint {
+		return accelerateEndpoint
+	} else if purpose == endpointForManagement && isAccelerateEndpoint(endpoint) {
+		endpoint = ""
+	}
+	if !dualStack {
+		return endpoint
+	}
+	if endpoint == "" {
+		return suggestServiceEndpoint(config.Region, false, true)
+	}
+	return dualStackEndpointFor(endpoint)
+}
+
 // OSSService handles OSS operations via ossutil
 type OSSService struct {
 	ossutilPath                  string
 	defaultOssutilPath           string
+	ossutilPathMu                sync.RWMutex
 	defaultConfigDir             string
 	configDir                    string
 	transferSeq                  uint64
 	transferCtxMu                sync.RWMutex
 	transferCtx                  context.Context
-	transferLimiterMu            sync.RWMutex
-	transferLimiter              *transferLimiter
+	transferQueueMu              sync.Mutex
+	transferQueue                *transferQueue
+	progressEmitMu               sync.RWMutex
+	progressEmitInterval         time.Duration
 	transferHistoryMu            sync.Mutex
 	transferHistoryByID          map[string]TransferUpdate
 	transferHistoryOrder         []string
 	transferHistoryLoaded        bool
 	transferHistoryLoadedDir     string
 	transferHistoryLastPersistAt time.Time
+	bucketStatCacheMu            sync.Mutex
+	bucketStatCache              map[string]bucketStatCacheEntry
+	bucketStatCacheLoaded        bool
+	bucketStatRefreshMu          sync.Mutex
+	bucketStatRefreshCancel      context.CancelFunc
+	bucketVersioningCacheMu      sync.Mutex
+	bucketVersioningCache        map[string]string
+	bucketCnameCacheMu           sync.Mutex
+	bucketCnameCache             map[string]bucketCnameCacheEntry
+	bucketExistsCacheMu          sync.Mutex
+	bucketExistsCache            map[string]bucketExistsCacheEntry
+	keychainMigrationMu          sync.Mutex
+	keychainMigratedDir          string
+	profilesKeyMu                sync.Mutex
+	profilesKey                  *profilesKey
+	profilesKeyUsedAt            time.Time
+	profilesLockTimeout          time.Duration
+	assumeRoleCacheMu            sync.Mutex
+	assumeRoleCache              map[string]stsCredentials
+	ecsRoleCacheMu               sync.Mutex
+	ecsRoleCache                 map[string]stsCredentials
+	internalProbeMu              sync.Mutex
+	internalProbes               map[string]internalEndpointProbe
+	profileUsageMu               sync.Mutex
+	profileUsagePending          map[string]int64
+	profileUsageTimer            *time.Timer
+	stateMu                      sync.Mutex
+	confirmTokensMu              sync.Mutex
+	confirmTokens                map[string]confirmationToken
+	destructiveConfirmMu         sync.RWMutex
+	skipDestructiveConfirm       bool
+	diskSpaceCheckMu             sync.RWMutex
+	skipDiskSpaceCheck           bool
+	diskFree                     func(path string) (uint64, error)
+	knownSecretsMu               sync.Mutex
+	knownSecrets                 map[string]struct{}
+	authExpiredMu                sync.Mutex
+	authExpired                  map[string]string
+	globalProxyMu                sync.RWMutex
+	globalProxy                  proxySettings
+	globalTLSMu                  sync.RWMutex
+	globalTLS                    tlsSettings
+	ossutilExtraArgsMu           sync.RWMutex
+	ossutilExtraArgs             []string
+	sdkTuningMu                  sync.RWMutex
+	sdkTuning                    sdkTuning
+	opLog                        *operationLog
+	audit                        *auditLog
+	undo                         *undoStack
+	undoRunMu                    sync.Mutex
+	tempFiles                    *tempFileManager
+	usage                        *usageTracker
+	network                      *networkMonitor
+	sdkClients                   *sdkClientCache
+	bucketRegions                *bucketRegionCache
+	sdkFlights                   *sdkFlightGroup
+	ossutilCallsMu               sync.Mutex
+	ossutilCalls                 map[string]ossutilCall
+	ossutilTimeout               time.Duration
+	ossutilCallSeq               uint64
+	moveJobsMu                   sync.Mutex
+	moveJobs                     map[string]context.CancelFunc
+	moveJobsWG                   sync.WaitGroup
+	moveJobSeq                   uint64
+	prefixWatchesMu              sync.Mutex
+	prefixWatches                map[string]context.CancelFunc
+	prefixWatchSeq               uint64
+	allProfilesBuckets           allProfilesBucketsCache
+	integrityScans               integrityScanStore
+	sessionStateMu               sync.Mutex
+	sessionStates                map[string]sessionStateEntry
+	sessionStateTimer            *time.Timer
+	configRecoveriesMu           sync.Mutex
+	configRecoveries             []ConfigRecovery
+	configWatchMu                sync.Mutex
+	configFileSums               map[string][sha256.Size]byte
+	configSettingsSum            [sha256.Size]byte
+	configProfilesSum            [sha256.Size]byte
+	configDirMoved               chan struct{}
+	languageMu                   sync.RWMutex
+	language                     string
+	ossutilVersionMu             sync.Mutex
+	ossutilVersions              map[string]ossutilVersionCacheEntry
+	notifier                     notifier
+	themeMu                      sync.RWMutex
+	theme                        string
 }
 
 const (
-	appStateSchemaVersion = 2
+	appStateSchemaVersion = 3
 	appStateFileName      = "config.json"
 	legacySettingsName    = "settings.json"
 	legacyProfilesName    = "profiles.json"
@@ -92,6 +326,19 @@ type appState struct {
 	SchemaVersion int          `json:"schemaVersion"`
 	Settings      AppSettings  `json:"settings"`
 	Profiles      []OSSProfile `json:"profiles"`
+	// ProfilesEncrypted means Profiles live in profiles.enc instead of this file.
+	ProfilesEncrypted bool `json:"profilesEncrypted,omitempty"`
+
+	profilesLocked    bool
+	encryptedProfiles []byte
+}
+
+// requireProfiles fails when the profiles of an encrypted state have not been unlocked.
+func (state appState) requireProfiles() error {
+	if state.profilesLocked {
+		return ErrProfilesLocked
+	}
+	return nil
 }
 
 type workDirRef struct {
@@ -187,10 +434,21 @@ func defaultAppSettings(workDir string) AppSettings {
 		WorkDir:            compactHomePath(workDir),
 		DefaultRegion:      "",
 		DefaultEndpoint:    "",
-		Theme:              "dark",
+		Theme:              themeDark,
 		MaxTransferThreads: 3,
 		NewTabNameRule:     "folder",
 		FileListViewMode:   "finder",
+		CredentialStorage:  credentialStorageKeychain,
+		ProfileLockMinutes: defaultProfileLockMinutes,
+		// Older config files lack these fields and keep the defaults.
+		SDKConnectTimeoutSec:   defaultSDKConnectTimeoutSec,
+		SDKReadWriteTimeoutSec: defaultSDKReadWriteTimeoutSec,
+		SDKMaxRetries:          defaultSDKMaxRetries,
+		LogLevel:               logLevelInfo,
+		Language:               languageEnglish,
+		Notifications:          defaultNotificationSettings(),
+		ProgressEmitIntervalMs: defaultProgressEmitIntervalMs,
+		OssutilTimeoutSec:      defaultOssutilTimeoutSec,
 	}
 }
 
@@ -203,9 +461,9 @@ func normalizeAppSettings(settings AppSettings, fallbackWorkDir string) AppSetti
 
 	out.Theme = strings.TrimSpace(out.Theme)
 	switch out.Theme {
-	case "dark", "light":
+	case themeDark, themeLight, themeSystem:
 	default:
-		out.Theme = "dark"
+		out.Theme = themeDark
 	}
 
 	if out.MaxTransferThreads <= 0 {
@@ -215,6 +473,9 @@ func normalizeAppSettings(settings AppSettings, fallbackWorkDir string) AppSetti
 		out.MaxTransferThreads = 64
 	}
 
+	out.ProgressEmitIntervalMs = normalizeProgressEmitIntervalMs(out.ProgressEmitIntervalMs)
+	out.OssutilTimeoutSec = normalizeOssutilTimeoutSec(out.OssutilTimeoutSec)
+
 	out.NewTabNameRule = strings.TrimSpace(out.NewTabNameRule)
 	switch out.NewTabNameRule {
 	case "folder", "newTab":
@@ -229,9 +490,68 @@ func normalizeAppSettings(settings AppSettings, fallbackWorkDir string) AppSetti
 		out.FileListViewMode = "finder"
 	}
 
+	out.CredentialStorage = normalizeCredentialStorage(out.CredentialStorage)
+	out.ProfileLockMinutes = normalizeProfileLockMinutes(out.ProfileLockMinutes)
+	out.ProxyURL = strings.TrimSpace(out.ProxyURL)
+	out.ProxyUser = strings.TrimSpace(out.ProxyUser)
+	out.TLSCACertFile = strings.TrimSpace(out.TLSCACertFile)
+	out.OssutilExtraArgs = normalizeOssutilExtraArgs(out.OssutilExtraArgs)
+	out.LogLevel = normalizeLogLevel(out.LogLevel)
+	if out.Language = strings.TrimSpace(out.Language); out.Language == "" {
+		out.Language = languageEnglish
+	}
+	if out.SDKConnectTimeoutSec <= 0 {
+		out.SDKConnectTimeoutSec = defaultSDKConnectTimeoutSec
+	}
+	if out.SDKReadWriteTimeoutSec <= 0 {
+		out.SDKReadWriteTimeoutSec = defaultSDKReadWriteTimeoutSec
+	}
+	if out.DefaultDownloadDir = strings.TrimSpace(out.DefaultDownloadDir); out.DefaultDownloadDir != "" {
+		out.DefaultDownloadDir = compactHomePath(filepath.Clean(expandLeadingTilde(out.DefaultDownloadDir)))
+	}
+
 	return out
 }
 
+// resetInvalidSettings replaces every field of settings read from disk that is out of its allowed
+// range with the field's default, so one bad value (a hand edit, a newer build's setting) does not
+// cost the user the rest of their choices. It returns the JSON names of the fields it reset.
+func resetInvalidSettings(settings AppSettings, defaults AppSettings) (AppSettings, []string) {
+	out := settings
+	var reset []string
+	check := func(name string, invalid bool, apply func()) {
+		if invalid {
+			apply()
+			reset = append(reset, name)
+		}
+	}
+
+	check("theme", strings.TrimSpace(out.Theme) != "" && validateTheme(out.Theme) != nil, func() { out.Theme = defaults.Theme })
+	check("maxTransferThreads", out.MaxTransferThreads < 0 || out.MaxTransferThreads > 64, func() { out.MaxTransferThreads = defaults.MaxTransferThreads })
+	check("profileLockMinutes", out.ProfileLockMinutes < 0 || out.ProfileLockMinutes > maxProfileLockMinutes, func() { out.ProfileLockMinutes = defaults.ProfileLockMinutes })
+	check("sdkConnectTimeoutSec", out.SDKConnectTimeoutSec < 0 || out.SDKConnectTimeoutSec > maxSDKConnectTimeoutSec, func() { out.SDKConnectTimeoutSec = defaults.SDKConnectTimeoutSec })
+	check("sdkReadWriteTimeoutSec", out.SDKReadWriteTimeoutSec < 0 || out.SDKReadWriteTimeoutSec > maxSDKReadWriteTimeoutSec, func() { out.SDKReadWriteTimeoutSec = defaults.SDKReadWriteTimeoutSec })
+	check("sdkMaxRetries", out.SDKMaxRetries < 0 || out.SDKMaxRetries > maxSDKMaxRetries, func() { out.SDKMaxRetries = defaults.SDKMaxRetries })
+	check("logLevel", validateLogLevel(out.LogLevel) != nil, func() { out.LogLevel = defaults.LogLevel })
+	check("language", strings.TrimSpace(out.Language) != "" && !isAvailableLanguage(out.Language), func() { out.Language = defaults.Language })
+	check("ossutilExtraArgs", validateOssutilExtraArgs(normalizeOssutilExtraArgs(out.OssutilExtraArgs)) != nil, func() { out.OssutilExtraArgs = defaults.OssutilExtraArgs })
+	if proxy := strings.TrimSpace(out.ProxyURL); proxy != "" {
+		_, err := validateProxyURL(proxy)
+		check("proxyUrl", err != nil, func() { out.ProxyURL = defaults.ProxyURL })
+	}
+	return out, reset
+}
+
+// loadedSettings validates and normalizes settings read from dir, logging the fields that had to
+// fall back to their defaults.
+func (s *OSSService) loadedSettings(settings AppSettings, dir string) AppSettings {
+	settings, reset := resetInvalidSettings(settings, defaultAppSettings(dir))
+	if len(reset) > 0 {
+		s.logOperationEvent(logLevelWarn, "LoadSettings", opOutcomeOK, "reset invalid settings to defaults: "+strings.Join(reset, ", "), nil)
+	}
+	return normalizeAppSettings(settings, dir)
+}
+
 func readWorkDirRefFromDefault(defaultDir string) string {
 	defaultDir = normalizeWorkDirPath(defaultDir, defaultDir)
 	refPath := filepath.Join(defaultDir, workDirRefFileName)
@@ -257,45 +577,63 @@ func NewOSSService() *OSSService {
 		configDir = refDir
 	}
 
-	// Try to find ossutil in bin/ directory relative to executable
-	ossutilPath := "ossutil" // Default to PATH lookup
-
-	// Get executable directory
-	exePath, err := os.Executable()
-	if err == nil {
-		exeDir := filepath.Dir(exePath)
-		// Check for ossutil in bin/ subdirectory
-		binPath := filepath.Join(exeDir, "bin", "ossutil")
-		if _, err := os.Stat(binPath); err == nil {
-			ossutilPath = binPath
-		}
-		// Also check in same directory as executable
-		sameDirPath := filepath.Join(exeDir, "ossutil")
-		if _, err := os.Stat(sameDirPath); err == nil {
-			ossutilPath = sameDirPath
-		}
-	}
-
-	// For development mode, check relative to working directory
-	if ossutilPath == "ossutil" {
-		cwd, err := os.Getwd()
-		if err == nil {
-			cwdBinPath := filepath.Join(cwd, "bin", "ossutil")
-			if _, err := os.Stat(cwdBinPath); err == nil {
-				ossutilPath = cwdBinPath
-			}
-		}
-	}
-
-	return &OSSService{
-		ossutilPath:          ossutilPath,
-		defaultOssutilPath:   ossutilPath,
-		defaultConfigDir:     defaultConfigDir,
-		configDir:            configDir,
-		transferLimiter:      newTransferLimiter(3),
-		transferHistoryByID:  make(map[string]TransferUpdate),
-		transferHistoryOrder: make([]string, 0, 64),
-	}
+	ossutilPath := discoverOssutilPath(defaultConfigDir)
+
+	s := &OSSService{
+		ossutilPath:           ossutilPath,
+		defaultOssutilPath:    ossutilPath,
+		defaultConfigDir:      defaultConfigDir,
+		configDir:             configDir,
+		transferHistoryByID:   make(map[string]TransferUpdate),
+		transferHistoryOrder:  make([]string, 0, 64),
+		bucketStatCache:       make(map[string]bucketStatCacheEntry),
+		bucketVersioningCache: make(map[string]string),
+		bucketCnameCache:      make(map[string]bucketCnameCacheEntry),
+		bucketExistsCache:     make(map[string]bucketExistsCacheEntry),
+		assumeRoleCache:       make(map[string]stsCredentials),
+		ecsRoleCache:          make(map[string]stsCredentials),
+		internalProbes:        make(map[string]internalEndpointProbe),
+		profileUsagePending:   make(map[string]int64),
+		confirmTokens:         make(map[string]confirmationToken),
+		knownSecrets:          make(map[string]struct{}),
+		diskFree:              diskFreeBytes,
+		authExpired:           make(map[string]string),
+		opLog:                 newOperationLog(filepath.Join(defaultConfigDir, opLogDirName)),
+		audit:                 newAuditLog(defaultConfigDir),
+		undo:                  newUndoStack(),
+		tempFiles:             newTempFileManager(filepath.Join(defaultConfigDir, tempDirName)),
+		usage:                 newUsageTracker(filepath.Join(defaultConfigDir, usageFileName)),
+		network:               newNetworkMonitor(),
+		sdkClients:            newSDKClientCache(),
+		bucketRegions:         newBucketRegionCache(),
+		sdkFlights:            newSDKFlightGroup(),
+		ossutilCalls:          make(map[string]ossutilCall),
+		moveJobs:              make(map[string]context.CancelFunc),
+		prefixWatches:         make(map[string]context.CancelFunc),
+		configFileSums:        make(map[string][sha256.Size]byte),
+		configDirMoved:        make(chan struct{}, 1),
+		ossutilTimeout:        defaultOssutilTimeoutSec * time.Second,
+		language:              languageEnglish,
+		theme:                 themeDark,
+		ossutilVersions:       make(map[string]ossutilVersionCacheEntry),
+		notifier: notifier{
+			settings: defaultNotificationSettings(),
+			notified: make(map[string]struct{}),
+		},
+		sdkTuning: sdkTuning{
+			connectTimeoutSec:   defaultSDKConnectTimeoutSec,
+			readWriteTimeoutSec: defaultSDKReadWriteTimeoutSec,
+			maxRetries:          defaultSDKMaxRetries,
+		},
+	}
+	s.transferQueue = newTransferQueue(3, s.runTransferJob)
+	if err := s.tempFiles.loadManifest(s.readConfigFile); err != nil {
+		s.logOperationEvent(logLevelWarn, "LoadTempFiles", opOutcomeError, "", err)
+	}
+	if err := s.usage.load(s.readConfigFile); err != nil {
+		s.logOperationEvent(logLevelWarn, "LoadUsage", opOutcomeError, "", err)
+	}
+	return s
 }
 
 func ossutilStartFailed(err error) bool {
@@ -316,10 +654,10 @@ func ossutilStartFailed(err error) bool {
 func ossutilOutputOrError(err error, output []byte) string {
 	msg := strings.TrimSpace(string(output))
 	if msg != "" {
-		return msg
+		return redactSecretFlags(msg)
 	}
 	if err != nil {
-		return err.Error()
+		return redactSecretFlags(err.Error())
 	}
 	return ""
 }
@@ -339,50 +677,224 @@ func firstHTTPURL(output string) string {
 	return ""
 }
 
-func (s *OSSService) runOssutil(args ...string) ([]byte, error) {
-	primary := strings.TrimSpace(s.ossutilPath)
-	fallback := strings.TrimSpace(s.defaultOssutilPath)
+// ossutilConnection is what every ossutil call needs to reach OSS. With ossutil 2.x credentials
+// travel in the environment (OSS_ACCESS_KEY_ID, OSS_ACCESS_KEY_SECRET and OSS_SESSION_TOKEN) so
+// they never show up in the process list; 1.x only takes them as flags.
+type ossutilConnection struct {
+	args []string
+	env  []string
+	// config is the unresolved config the connection was built for, used to classify failures.
+	config OSSConfig
+	// dialect spells the flags of the binary the connection is for.
+	dialect ossutilDialect
+	// probeAddr is what the network monitor probes when the endpoint cannot be reached.
+	probeAddr string
+	// release removes the credentials file of an ossutil 1.x connection; nil otherwise.
+	release func()
+}
 
-	if primary == "" {
-		primary = fallback
+// close removes what the connection wrote to disk. Every connection from ossutilConn must be
+// closed once its commands have run.
+func (c ossutilConnection) close() {
+	if c.release != nil {
+		c.release()
 	}
-	if primary == "" {
-		primary = "ossutil"
+}
+
+// ossutilConn returns the credentials, region and endpoint shared by every ossutil call.
+func (s *OSSService) ossutilConn(config OSSConfig, purpose endpointPurpose) (ossutilConnection, error) {
+	if err := s.checkNeedsReauth(config); err != nil {
+		return ossutilConnection{}, err
+	}
+	probeAddr := s.ossutilProbeAddr(config, purpose)
+	if err := s.requireReachable(probeAddr); err != nil {
+		return ossutilConnection{}, err
+	}
+	original := config
+	config, err := s.resolveCredentials(config)
+	if err != nil {
+		return ossutilConnection{}, err
 	}
 
-	cmd := exec.Command(primary, args...)
+	dialect := s.dialect()
+	env, credentialsFile := dialect.credentials(config)
+	conn := ossutilConnection{
+		config:    original,
+		probeAddr: probeAddr,
+		args:      dialect.region(normalizeRegion(config.Region)),
+		env:       env,
+		dialect:   dialect,
+	}
+	if proxy, ok := s.proxyFor(config); ok {
+		conn.env = append(conn.env, proxy.env()...)
+	}
+	endpoint, err := endpointURLForConfig(config, purpose)
+	if err != nil {
+		return ossutilConnection{}, err
+	}
+	if endpoint != "" {
+		if err := s.checkInternalEndpoint(config, endpoint); err != nil {
+			return ossutilConnection{}, err
+		}
+		conn.args = append(conn.args, dialect.endpoint(endpoint)...)
+	}
+	tlsConfig := s.tlsFor(config)
+	if !tlsConfig.isDefault() {
+		// Without an endpoint ossutil derives the public one from the region.
+		checked := endpoint
+		if checked == "" {
+			checked = suggestServiceEndpoint(config.Region, config.UseInternalEndpoint, config.UseDualStackEndpoint)
+		}
+		if err := checkTLSForEndpoint(tlsConfig, checked); err != nil {
+			return ossutilConnection{}, err
+		}
+		tlsArgs, tlsEnv := tlsConfig.ossutilArgs(dialect)
+		conn.args = append(conn.args, tlsArgs...)
+		conn.env = append(conn.env, tlsEnv...)
+	}
+	if credentialsFile != nil {
+		path, release, err := s.writeOssutilCredentials(credentialsFile)
+		if err != nil {
+			return ossutilConnection{}, err
+		}
+		conn.args = append(conn.args, dialect.configFile(path)...)
+		conn.release = release
+	}
+	return conn, nil
+}
+
+// writeOssutilCredentials writes an ossutil config file and returns its path and a func that
+// removes it. Like every temp file it is created with mode 0600 in a 0700 directory.
+func (s *OSSService) writeOssutilCredentials(content []byte) (string, func(), error) {
+	file, err := s.tempFiles.create(tempPurposeCredentials, "ossutil-*.conf")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write ossutil credentials: %w", err)
+	}
+	path := file.Name()
+	release := func() { s.tempFiles.release(path) }
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		release()
+		return "", nil, fmt.Errorf("failed to write ossutil credentials: %w", err)
+	}
+	return path, release, nil
+}
+
+// ossutilCommand builds an ossutil command with env added to the app's own environment.
+func ossutilCommand(ctx context.Context, binary string, env []string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, binary, args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd
+}
+
+// runOssutil runs a short ossutil command. It is killed when ctx ends, after the default timeout
+// when ctx has no deadline, or through CancelOperation.
+func (s *OSSService) runOssutil(ctx context.Context, conn ossutilConnection, args ...string) ([]byte, error) {
+	// Extra arguments only make sense for commands that talk to OSS, not for "version".
+	if conn.env != nil {
+		args = s.withOssutilExtraArgs(args)
+	}
+	primary, fallback := s.ossutilBinaries()
+
+	command := s.ossutilCommandLine(primary, args)
+	s.logOperationEvent(logLevelDebug, "ossutil", opOutcomeStart, command, nil)
+	ctx, done := s.startOssutilCall(ctx, command)
+	defer done()
+
+	cmd := ossutilCommand(ctx, primary, conn.env, args...)
 	output, err := cmd.CombinedOutput()
 	if err == nil || !ossutilStartFailed(err) || fallback == "" || fallback == primary {
-		return output, err
+		return s.finishOssutilRun(ctx, command, conn, output, err)
 	}
 
 	// Retry with the auto-discovered ossutil path.
-	fallbackCmd := exec.Command(fallback, args...)
+	fallbackCmd := ossutilCommand(ctx, fallback, conn.env, args...)
 	fallbackOutput, fallbackErr := fallbackCmd.CombinedOutput()
 	if fallbackErr == nil || !ossutilStartFailed(fallbackErr) {
-		// Stick to the working one for subsequent operations.
-		s.ossutilPath = fallback
-		return fallbackOutput, fallbackErr
+		s.useOssutilFallback(primary, fallback)
+		return s.finishOssutilRun(ctx, command, conn, fallbackOutput, fallbackErr)
 	}
 
-	return output, err
+	return s.finishOssutilRun(ctx, command, conn, output, err)
+}
+
+// finishOssutilRun scrubs secrets from the output of a failed ossutil run, which callers turn
+// into error messages, and flags expired credentials. Successful output is parsed, not shown,
+// and is returned untouched.
+func (s *OSSService) finishOssutilRun(ctx context.Context, command string, conn ossutilConnection, output []byte, err error) ([]byte, error) {
+	if err == nil {
+		return output, nil
+	}
+	if _, ok := staticTokenKey(conn.config); ok && ossutilOutputTokenExpired(output) {
+		s.markNeedsReauth(conn.config)
+	}
+	if ctx.Err() == nil {
+		s.noteEndpointFailure(conn.probeAddr, errors.New(string(output)))
+	}
+	return []byte(s.redact(string(output))), ossutilContextError(ctx, command, s.redactError(err))
 }
 
 // SetOssutilPath sets custom ossutil binary path
 func (s *OSSService) SetOssutilPath(path string) {
 	resolved := normalizeOssutilPath(path)
+	s.ossutilPathMu.Lock()
 	if resolved == "" {
 		s.ossutilPath = s.defaultOssutilPath
-		return
+	} else {
+		s.ossutilPath = resolved
 	}
-	s.ossutilPath = resolved
+	s.ossutilPathMu.Unlock()
 }
 
 // GetOssutilPath returns current ossutil path
 func (s *OSSService) GetOssutilPath() string {
+	s.ossutilPathMu.RLock()
+	defer s.ossutilPathMu.RUnlock()
 	return s.ossutilPath
 }
 
+// ossutilBinaries returns the binary to run and the auto-discovered one to retry with when it
+// cannot be started.
+func (s *OSSService) ossutilBinaries() (primary, fallback string) {
+	s.ossutilPathMu.RLock()
+	defer s.ossutilPathMu.RUnlock()
+	return s.ossutilBinariesLocked()
+}
+
+func (s *OSSService) ossutilBinariesLocked() (primary, fallback string) {
+	primary = strings.TrimSpace(s.ossutilPath)
+	fallback = strings.TrimSpace(s.defaultOssutilPath)
+	if primary == "" {
+		primary = fallback
+	}
+	if primary == "" {
+		primary = "ossutil"
+	}
+	return primary, fallback
+}
+
+// useOssutilFallback sticks to fallback for subsequent operations after primary failed to start,
+// unless the path was changed in the meantime.
+func (s *OSSService) useOssutilFallback(primary, fallback string) {
+	s.ossutilPathMu.Lock()
+	defer s.ossutilPathMu.Unlock()
+	if current, _ := s.ossutilBinariesLocked(); current == primary {
+		s.ossutilPath = fallback
+	}
+}
+
+// usingDefaultOssutil reports whether the active binary is the auto-discovered one.
+func (s *OSSService) usingDefaultOssutil() bool {
+	s.ossutilPathMu.RLock()
+	defer s.ossutilPathMu.RUnlock()
+	return s.ossutilPath == s.defaultOssutilPath
+}
+
 func (s *OSSService) stateFilePathIn(dir string) string {
 	return filepath.Join(dir, appStateFileName)
 }
@@ -400,13 +912,21 @@ func (s *OSSService) workDirRefPath() string {
 }
 
 func (s *OSSService) applySettingsRuntime(settings AppSettings) {
-	resolved := normalizeOssutilPath(settings.OssutilPath)
-	if resolved == "" {
-		s.ossutilPath = s.defaultOssutilPath
-	} else {
-		s.ossutilPath = resolved
-	}
+	s.SetOssutilPath(settings.OssutilPath)
 	s.setMaxTransferThreads(settings.MaxTransferThreads)
+	s.setProgressEmitInterval(settings.ProgressEmitIntervalMs)
+	s.setProfileLockMinutes(settings.ProfileLockMinutes)
+	s.setGlobalProxy(settings)
+	s.setGlobalTLS(settings)
+	s.setOssutilExtraArgs(settings.OssutilExtraArgs)
+	s.setSDKTuning(settings)
+	s.opLog.setLevel(settings.LogLevel)
+	s.setLanguage(settings.Language)
+	s.setTheme(settings.Theme)
+	s.setNotificationSettings(settings.Notifications)
+	s.setSkipDestructiveConfirmation(settings.SkipDestructiveConfirmation)
+	s.setSkipDiskSpaceCheck(settings.SkipDiskSpaceCheck)
+	s.setOssutilTimeout(settings.OssutilTimeoutSec)
 }
 
 func (s *OSSService) writeWorkDirRef(workDir string) error {
@@ -428,15 +948,12 @@ func (s *OSSService) writeWorkDirRef(workDir string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(refPath, data, 0600)
+	return writeFileAtomic(refPath, data)
 }
 
-func (s *OSSService) saveAppStateToDir(dir string, state appState) error {
-	dir = normalizeWorkDirPath(dir, s.defaultConfigDir)
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return err
-	}
-
+// appStateFiles renders state into the files that make up a config directory: config.json and,
+// for encrypted profiles, profiles.enc.
+func (s *OSSService) appStateFiles(dir string, state appState) ([]pendingFile, error) {
 	state.SchemaVersion = appStateSchemaVersion
 	state.Settings = normalizeAppSettings(state.Settings, dir)
 	state.Settings.WorkDir = compactHomePath(dir)
@@ -444,11 +961,65 @@ func (s *OSSService) saveAppStateToDir(dir string, state appState) error {
 		state.Profiles = []OSSProfile{}
 	}
 
+	files := make([]pendingFile, 0, 2)
+	if state.ProfilesEncrypted {
+		encrypted, err := s.encryptedProfilesData(state)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, pendingFile{path: s.encryptedProfilesPathIn(dir), data: encrypted})
+		state.Profiles = []OSSProfile{}
+	}
+
 	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, pendingFile{path: s.stateFilePathIn(dir), data: data})
+	return files, nil
+}
+
+func (s *OSSService) saveAppStateToDir(dir string, state appState) error {
+	dir = normalizeWorkDirPath(dir, s.defaultConfigDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	files, err := s.appStateSaveFiles(dir, state)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.stateFilePathIn(dir), data, 0600)
+	s.noteConfigWrite(files)
+	return writeFilesTogether(files)
+}
+
+// appStateSaveFiles is appStateFiles plus the backups a save refreshes. Callers that write more
+// files in the same step pass all of them to noteConfigWrite and writeFilesTogether.
+func (s *OSSService) appStateSaveFiles(dir string, state appState) ([]pendingFile, error) {
+	files, err := s.appStateFiles(dir, state)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the previous version as the last good backup, but never back up a damaged file, nor a
+	// secret this save takes out of config.json.
+	if previous, err := os.ReadFile(s.stateFilePathIn(dir)); err == nil && json.Valid(previous) {
+		previous, _ = scrubRemovedSecrets(previous, inlineProfiles(state))
+		files = append(files, pendingFile{path: s.stateBackupPathIn(dir), data: previous})
+	}
+	// The copies kept from before a schema migration would otherwise hold such secrets forever.
+	migrationBackups, _ := filepath.Glob(s.stateFilePathIn(dir) + ".v*.bak")
+	for _, path := range migrationBackups {
+		if data, err := os.ReadFile(path); err == nil {
+			if scrubbed, changed := scrubRemovedSecrets(data, inlineProfiles(state)); changed {
+				files = append(files, pendingFile{path: path, data: scrubbed})
+			}
+		}
+	}
+	return files, nil
+}
+
+func (s *OSSService) stateBackupPathIn(dir string) string {
+	return s.stateFilePathIn(dir) + ".bak"
 }
 
 func (s *OSSService) loadAppStateFromDir(dir string) (appState, error) {
@@ -460,38 +1031,39 @@ func (s *OSSService) loadAppStateFromDir(dir string) (appState, error) {
 	}
 
 	if data, err := os.ReadFile(s.stateFilePathIn(dir)); err == nil {
-		if err := json.Unmarshal(data, &state); err != nil {
+		data, err := s.migrateAppStateFile(dir, data)
+		if err != nil {
+			return appState{}, err
+		}
+		// A damaged file falls back to config.json.bak, the last good copy, or to the defaults.
+		if err := s.decodeConfigFile(s.stateFilePathIn(dir), data, &state); err != nil {
 			return appState{}, err
 		}
-		state.Settings = normalizeAppSettings(state.Settings, dir)
+		state.Settings = s.loadedSettings(state.Settings, dir)
 		if state.Profiles == nil {
 			state.Profiles = []OSSProfile{}
 		}
+		if state.ProfilesEncrypted {
+			if err := s.loadEncryptedProfilesInto(dir, &state); err != nil {
+				return appState{}, err
+			}
+		}
 		return state, nil
 	} else if !os.IsNotExist(err) {
 		return appState{}, err
 	}
 
-	migrated := false
-	if data, err := os.ReadFile(s.legacySettingsPathIn(dir)); err == nil {
-		if err := json.Unmarshal(data, &state.Settings); err != nil {
-			return appState{}, err
-		}
-		migrated = true
-	} else if !os.IsNotExist(err) {
+	foundSettings, err := s.readConfigFile(s.legacySettingsPathIn(dir), &state.Settings)
+	if err != nil {
 		return appState{}, err
 	}
-
-	if data, err := os.ReadFile(s.legacyProfilesPathIn(dir)); err == nil {
-		if err := json.Unmarshal(data, &state.Profiles); err != nil {
-			return appState{}, err
-		}
-		migrated = true
-	} else if !os.IsNotExist(err) {
+	foundProfiles, err := s.readConfigFile(s.legacyProfilesPathIn(dir), &state.Profiles)
+	if err != nil {
 		return appState{}, err
 	}
+	migrated := foundSettings || foundProfiles
 
-	state.Settings = normalizeAppSettings(state.Settings, dir)
+	state.Settings = s.loadedSettings(state.Settings, dir)
 	if state.Profiles == nil {
 		state.Profiles = []OSSProfile{}
 	}
@@ -521,6 +1093,11 @@ func (s *OSSService) loadAppState() (appState, error) {
 	}
 
 	s.configDir = dir
+	if !state.ProfilesEncrypted && s.migrateProfileSecretsToKeychain(dir, &state) {
+		if err := s.saveAppStateToDir(dir, state); err != nil {
+			return appState{}, err
+		}
+	}
 	return state, nil
 }
 
@@ -560,55 +1137,265 @@ func parseDefaultPathLocation(path string) (string, string, bool) {
 
 // TestConnection tests the OSS connection with given config
 func (s *OSSService) TestConnection(config OSSConfig) ConnectionResult {
+	return s.TestConnectionForBucket(config, "")
+}
+
+// TestConnectionForBucket is TestConnection for an access-point endpoint, which can only reach
+// one bucket: bucket is checked with a one-key listing through the access point. Without it the
+// bucket of the default path or the first pinned bucket is used. Other endpoints ignore bucket.
+func (s *OSSService) TestConnectionForBucket(config OSSConfig, bucket string) ConnectionResult {
+	start := time.Now()
+	bucket = strings.TrimSpace(bucket)
+	if bucket != "" {
+		if err := validateBucketName(bucket); err != nil {
+			return ConnectionResult{Success: false, Message: err.Error()}
+		}
+	}
+	result := s.testConnection(config, bucket)
+	result.Message = s.redact(result.Message)
+
+	var err error
+	if !result.Success {
+		err = errors.New(result.Message)
+	}
+	s.logOperation("TestConnection", "", "", start, &err)
+	return result
+}
+
+func (s *OSSService) testConnection(config OSSConfig, accessPointBucket string) ConnectionResult {
 	region := normalizeRegion(config.Region)
 	endpoint := normalizeEndpoint(config.Endpoint)
 
 	defaultBucket, defaultPrefix, hasDefaultLocation := parseDefaultPathLocation(config.DefaultPath)
-	if endpoint != "" && isAccessPointEndpoint(endpoint) && !hasDefaultLocation {
+	accessPoint := endpoint != "" && isAccessPointEndpoint(endpoint)
+	if accessPoint {
+		// An access point serves a single bucket and cannot list buckets; test that bucket instead.
+		if accessPointBucket == "" && hasDefaultLocation {
+			accessPointBucket = defaultBucket
+		}
+		if accessPointBucket == "" {
+			if pinned := s.pinnedBucketsFor(config); len(pinned) > 0 {
+				accessPointBucket = pinned[0]
+			}
+		}
+		if accessPointBucket == "" {
+			return ConnectionResult{
+				Success: false,
+				Message: s.msg(msgConnectionAccessPoint, suggestServiceEndpoint(region, config.UseInternalEndpoint, config.UseDualStackEndpoint)),
+			}
+		}
+		if accessPointBucket != defaultBucket {
+			defaultPrefix = ""
+		}
+		defaultBucket, hasDefaultLocation = accessPointBucket, true
+	}
+
+	resolved, source, err := s.resolveCredentialsWithSource(config)
+	if err != nil {
 		return ConnectionResult{
-			Success: false,
-			Message: fmt.Sprintf(
-				"Connection test failed: endpoint looks like an OSS Access Point (bucket-scoped), but listing buckets requires a service endpoint.\n"+
-					"Please leave Endpoint empty or use something like: %s",
-				suggestServiceEndpoint(region),
-			),
+			Success:          false,
+			Message:          s.msg(msgConnectionFailed, err.Error()),
+			CredentialSource: source,
 		}
 	}
+	sourceNote := s.msg(msgConnectionSourceNote, describeCredentialSource(source))
+	tlsDisabled := s.tlsFor(resolved).insecureSkipVerify
+	if tlsDisabled {
+		sourceNote += s.msg(msgConnectionTLSUnverified)
+	}
+
+	proxy, hasProxy := s.proxyFor(resolved)
+	if hasProxy {
+		if err := checkProxyReachable(proxy); err != nil {
+			return ConnectionResult{
+				Success:                 false,
+				Message:                 s.msg(msgConnectionProxyFailed, err.Error()),
+				CredentialSource:        source,
+				TLSVerificationDisabled: tlsDisabled,
+			}
+		}
+	}
+	purpose := endpointForManagement
+	if hasDefaultLocation {
+		purpose = endpointForData
+	}
+	contacted, _ := sdkEndpointForConfig(resolved, purpose)
+	// The SDK does not expose its connections. Dialing the same host the same way after a
+	// successful test shows which address family it got; through a proxy there is no telling.
+	addressFamily := func() string {
+		if hasProxy {
+			return ""
+		}
+		u, err := url.Parse(contacted)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		port := u.Port()
+		if port == "" {
+			port = "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+		}
+		family, _ := endpointAddressFamily(net.JoinHostPort(u.Hostname(), port))
+		return family
+	}
+	failure := func(err error) ConnectionResult {
+		id := msgConnectionFailed
+		if hasProxy && isProxyError(err) {
+			id = msgConnectionProxyFailed
+		}
+		result := ConnectionResult{
+			Success:                 false,
+			Message:                 s.msg(id, err.Error()) + sourceNote,
+			CredentialSource:        source,
+			TLSVerificationDisabled: tlsDisabled,
+		}
+		s.diagnoseConnection(&result, resolved, contacted, err)
+		return result
+	}
 
 	// Use SDK paged listing for a lightweight smoke test (avoid slow full ls on huge prefixes).
 	if hasDefaultLocation {
-		_, err := s.ListObjectsPage(config, defaultBucket, defaultPrefix, "", 1)
+		_, err := s.ListObjectsPage(resolved, defaultBucket, defaultPrefix, "", 1)
 		if err != nil {
-			return ConnectionResult{
-				Success: false,
-				Message: fmt.Sprintf("Connection failed: %s", err.Error()),
-			}
+			return failure(err)
 		}
 
+		s.markProfileUsed(config)
+		message := s.msg(msgConnectionSuccess)
+		if accessPoint {
+			message = s.msg(msgConnectionAccessPointOK, defaultBucket)
+		}
 		return ConnectionResult{
-			Success: true,
-			Message: "Connection successful",
+			Success:                 true,
+			Message:                 message + sourceNote,
+			CredentialSource:        source,
+			TLSVerificationDisabled: tlsDisabled,
+			AddressFamily:           addressFamily(),
 		}
 	}
 
-	if err := sdkSmokeTestListBuckets(config); err != nil {
-		return ConnectionResult{
-			Success: false,
-			Message: fmt.Sprintf("Connection failed: %s", err.Error()),
+	if err := s.sdkSmokeTestListBuckets(resolved); err != nil {
+		if isAccessDenied(err) {
+			if bucket, ok := s.probePinnedBuckets(config, resolved); ok {
+				s.markProfileUsed(config)
+				return ConnectionResult{
+					Success:                 true,
+					Message:                 s.msg(msgConnectionSuccessPinned, bucket) + sourceNote,
+					CredentialSource:        source,
+					TLSVerificationDisabled: tlsDisabled,
+					AddressFamily:           addressFamily(),
+				}
+			}
 		}
+		return failure(err)
 	}
 
-	return ConnectionResult{Success: true, Message: "Connection successful"}
+	s.markProfileUsed(config)
+
+	return ConnectionResult{
+		Success:                 true,
+		Message:                 s.msg(msgConnectionSuccess) + sourceNote,
+		CredentialSource:        source,
+		TLSVerificationDisabled: tlsDisabled,
+		AddressFamily:           addressFamily(),
+	}
 }
 
-// SaveProfile saves an OSS profile to config directory
-func (s *OSSService) SaveProfile(profile OSSProfile) error {
+// SaveProfile saves an OSS profile to config directory. With validateFirst the credentials are
+// checked with ValidateProfile and the profile is not saved if they turn out to be invalid.
+func (s *OSSService) SaveProfile(profile OSSProfile, validateFirst bool) error {
+	profile.HasSecret = false
+	if hasMaskedSecrets(profile) {
+		existing, err := s.GetProfile(profile.Name)
+		if err != nil {
+			return fmt.Errorf("cannot keep the saved secret of profile %s: %w", profile.Name, err)
+		}
+		keepMaskedSecrets(&profile, *existing)
+	}
+
+	if validateFirst {
+		validation, err := s.ValidateProfile(profile)
+		if err != nil {
+			return err
+		}
+		if validation.Access == profileAccessInvalid {
+			return fmt.Errorf("profile validation failed: %s %s", validation.Message, validation.Error)
+		}
+	}
+
+	if err := normalizeProfileMetadata(&profile); err != nil {
+		return err
+	}
+	if proxyURL := strings.TrimSpace(profile.Config.ProxyURL); proxyURL != "" {
+		if _, err := validateProxyURL(proxyURL); err != nil {
+			return err
+		}
+		profile.Config.ProxyURL = proxyURL
+	}
+	if caFile := strings.TrimSpace(profile.Config.CACertFile); caFile != "" {
+		if err := validateCACertFile(caFile); err != nil {
+			return err
+		}
+		profile.Config.CACertFile = caFile
+	}
+	profile.Config.CnameDomain = strings.TrimSpace(profile.Config.CnameDomain)
+	if check := s.CheckRegion(profile.Config.Region); check.Message != "" {
+		s.logOperationEvent(logLevelWarn, "SaveProfile", opOutcomeOK, profile.Name+": "+check.Message, nil)
+	}
+	s.clearNeedsReauth(profile.Config)
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	state, err := s.loadAppState()
 	if err != nil {
 		return err
 	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
 	profiles := state.Profiles
 
+	if profile.Config.AccessKeySecret == "" && profile.SecretRef == "" {
+		for _, p := range profiles {
+			if p.Name == profile.Name {
+				profile.SecretRef = p.SecretRef
+				break
+			}
+		}
+	}
+	storage := state.Settings.CredentialStorage
+	if state.ProfilesEncrypted {
+		// The encrypted file already protects the secret.
+		storage = credentialStoragePlaintext
+	}
+	if err := storeProfileSecret(&profile, storage); err != nil {
+		return err
+	}
+
+	// LastUsedAtMs, PinnedBuckets and the start location have their own setters; an edit form does
+	// not send them back.
+	for _, p := range profiles {
+		if p.Name != profile.Name {
+			continue
+		}
+		if profile.LastUsedAtMs == 0 {
+			profile.LastUsedAtMs = p.LastUsedAtMs
+		}
+		if profile.PinnedBuckets == nil {
+			profile.PinnedBuckets = p.PinnedBuckets
+		}
+		if profile.DefaultBucket == "" {
+			profile.DefaultBucket, profile.DefaultPrefix = p.DefaultBucket, p.DefaultPrefix
+		}
+		break
+	}
+
 	// Update or add profile
 	found := false
 	for i, p := range profiles {
@@ -632,66 +1419,157 @@ func (s *OSSService) SaveProfile(profile OSSProfile) error {
 	}
 
 	state.Profiles = profiles
-	return s.saveAppStateToDir(s.configDir, state)
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		return err
+	}
+	// Clients built from the old credentials or endpoint must not be reused.
+	s.sdkClients.clear()
+	return nil
 }
 
-// LoadProfiles loads all saved profiles
-func (s *OSSService) LoadProfiles() ([]OSSProfile, error) {
+// ListProfileSummaries returns every saved profile with its secrets masked, for rendering profile
+// lists. Use GetProfile for the credentials when connecting.
+func (s *OSSService) ListProfileSummaries() ([]OSSProfile, error) {
+	profiles, err := s.storedProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		profiles[i] = redactProfile(profiles[i])
+	}
+	return profiles, nil
+}
+
+// storedProfiles loads all saved profiles as stored: secrets kept in the keychain stay behind their
+// SecretRef, so rendering a list never reads the keychain, which may prompt the user.
+func (s *OSSService) storedProfiles() ([]OSSProfile, error) {
 	state, err := s.loadAppState()
 	if err != nil {
 		return nil, err
 	}
-	if state.Profiles == nil {
-		return []OSSProfile{}, nil
+	if err := state.requireProfiles(); err != nil {
+		return nil, err
 	}
 	return state.Profiles, nil
 }
 
+// loadProfiles loads all saved profiles with their secrets resolved.
+func (s *OSSService) loadProfiles() ([]OSSProfile, error) {
+	stored, err := s.storedProfiles()
+	if err != nil {
+		return nil, err
+	}
+	profiles := make([]OSSProfile, 0, len(stored))
+	for _, p := range stored {
+		profiles = append(profiles, resolveProfileSecretOrEmpty(p))
+	}
+	return profiles, nil
+}
+
 // GetProfile loads a specific profile by name
 func (s *OSSService) GetProfile(name string) (*OSSProfile, error) {
 	state, err := s.loadAppState()
 	if err != nil {
 		return nil, err
 	}
+	if err := state.requireProfiles(); err != nil {
+		return nil, err
+	}
 
 	for _, p := range state.Profiles {
 		if p.Name == name {
-			return &p, nil
+			resolved, err := resolveProfileSecret(p)
+			if err != nil {
+				return nil, err
+			}
+			return &resolved, nil
 		}
 	}
 
-	return nil, fmt.Errorf("profile not found: %s", name)
+	return nil, profileNotFound(name)
 }
 
-// DeleteProfile deletes a profile by name
-func (s *OSSService) DeleteProfile(name string) error {
+// DeleteProfile deletes a profile by name. When the default profile is removed, the first
+// remaining profile becomes the default; the result names it so the UI can tell the user.
+func (s *OSSService) DeleteProfile(name string) (DeleteProfileResult, error) {
+	unlock, err := s.lockState()
+	if err != nil {
+		return DeleteProfileResult{}, err
+	}
+	defer unlock()
+
 	state, err := s.loadAppState()
 	if err != nil {
-		return err
+		return DeleteProfileResult{}, err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return DeleteProfileResult{}, err
 	}
 
 	profiles := state.Profiles
 	newProfiles := make([]OSSProfile, 0)
+	var removed []OSSProfile
+	removedDefault := false
 	for _, p := range profiles {
 		if p.Name != name {
 			newProfiles = append(newProfiles, p)
+		} else {
+			removed = append(removed, p)
+			removedDefault = removedDefault || p.IsDefault
 		}
 	}
+	if len(removed) == 0 {
+		return DeleteProfileResult{}, profileNotFound(name)
+	}
+
+	result := DeleteProfileResult{}
+	if removedDefault && len(newProfiles) > 0 {
+		newProfiles[0].IsDefault = true
+		result.NewDefault = newProfiles[0].Name
+	}
 
 	state.Profiles = newProfiles
-	return s.saveAppStateToDir(s.configDir, state)
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		return DeleteProfileResult{}, err
+	}
+	for _, p := range removed {
+		deleteProfileSecret(p)
+	}
+	_ = s.deleteSessionState(name)
+	s.sdkClients.clear()
+	return result, nil
 }
 
-// GetDefaultProfile returns the default profile if set
+// GetDefaultProfile returns the default profile if set, with its secrets masked like
+// ListProfileSummaries.
 func (s *OSSService) GetDefaultProfile() (*OSSProfile, error) {
+	profiles, err := s.storedProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		if p.IsDefault {
+			redacted := redactProfile(p)
+			return &redacted, nil
+		}
+	}
+	return nil, nil
+}
+
+// defaultProfile returns the default profile with its secret resolved, or nil when none is set.
+func (s *OSSService) defaultProfile() (*OSSProfile, error) {
 	state, err := s.loadAppState()
 	if err != nil {
 		return nil, err
 	}
+	if err := state.requireProfiles(); err != nil {
+		return nil, err
+	}
 
 	for _, p := range state.Profiles {
 		if p.IsDefault {
-			return &p, nil
+			resolved := resolveProfileSecretOrEmpty(p)
+			return &resolved, nil
 		}
 	}
 
@@ -699,37 +1577,105 @@ func (s *OSSService) GetDefaultProfile() (*OSSProfile, error) {
 }
 
 // ListBuckets lists all buckets for the given config
-func (s *OSSService) ListBuckets(config OSSConfig) ([]BucketInfo, error) {
+func (s *OSSService) ListBuckets(config OSSConfig) (_ []BucketInfo, err error) {
+	defer s.logOperation("ListBuckets", "", "", time.Now(), &err)
+
 	region := normalizeRegion(config.Region)
 	endpoint := normalizeEndpoint(config.Endpoint)
 
 	if endpoint != "" && isAccessPointEndpoint(endpoint) {
-		return nil, fmt.Errorf(
-			"failed to list buckets: Endpoint appears to be an OSS Access Point (bucket-scoped). Listing buckets must use a service endpoint. Leave Endpoint empty or set it to something like %s",
-			suggestServiceEndpoint(region),
-		)
+		return nil, s.errorf(msgBucketsAccessPoint, suggestServiceEndpoint(region, config.UseInternalEndpoint, config.UseDualStackEndpoint))
 	}
 
-	args := []string{
-		"ls",
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
+	pinned := s.pinnedBucketsFor(config)
+	if domain := normalizeEndpoint(config.CnameDomain); domain != "" {
+		if len(pinned) == 0 {
+			return nil, s.errorf(msgBucketsCname, domain)
+		}
+		return mergePinnedBuckets(nil, pinned, region), nil
 	}
+	buckets, err := s.sdkListBuckets(config)
+	if err == nil {
+		s.markProfileUsed(config)
+		return mergePinnedBuckets(buckets, pinned, region), nil
+	}
+	// Fall back to ossutil only when the request never got an answer from OSS, e.g. a TLS or
+	// proxy setup the SDK transport cannot handle.
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) || errors.Is(err, ErrOffline) || errors.Is(err, ErrCredentialsNeedReauth) {
+		if len(pinned) > 0 && isAccessDenied(err) {
+			s.markProfileUsed(config)
+			return mergePinnedBuckets(nil, pinned, region), nil
+		}
+		return nil, s.errorf(msgBucketsListFailed, s.redact(err.Error()))
+	}
+	s.logOperationEvent(logLevelWarn, "ListBuckets", opOutcomeRetry, "SDK request failed, falling back to ossutil", err)
+	return s.ossutilListBuckets(config, pinned)
+}
 
-	// Make bucket output stable and easy to parse (one bucket per line: oss://bucket-name).
-	args = append(args, "--short-format")
+// sdkListBuckets lists every bucket of the account, following the pagination markers.
+func (s *OSSService) sdkListBuckets(config OSSConfig) ([]BucketInfo, error) {
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	buckets := []BucketInfo{}
+	marker := ""
+	for {
+		var result oss.ListBucketsResult
+		err := s.withSDKRetry("ListBuckets", func() error {
+			var err error
+			result, err = client.ListBuckets(oss.Marker(marker), oss.MaxKeys(1000))
+			return err
+		})
+		if err != nil {
+			return nil, s.classifyAuthError(config, err)
+		}
+		for _, bucket := range result.Buckets {
+			region := strings.TrimSpace(bucket.Region)
+			if region == "" {
+				region = normalizeRegion(bucket.Location)
+			}
+			buckets = append(buckets, BucketInfo{
+				Name:         bucket.Name,
+				Region:       region,
+				CreationDate: formatObjectLastModified(bucket.CreationDate),
+				StorageClass: bucket.StorageClass,
+			})
+		}
+		if !result.IsTruncated || result.NextMarker == "" {
+			return buckets, nil
+		}
+		marker = result.NextMarker
 	}
+}
 
-	output, err := s.runOssutil(args...)
+// ossutilListBuckets is the ossutil ls fallback of ListBuckets.
+func (s *OSSService) ossutilListBuckets(config OSSConfig, pinned []string) ([]BucketInfo, error) {
+	region := normalizeRegion(config.Region)
+	conn, err := s.ossutilConn(config, endpointForManagement)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list buckets: %s", ossutilOutputOrError(err, output))
+		return nil, err
 	}
+	defer conn.close()
+	args := append([]string{"ls"}, conn.args...)
 
-	return s.parseBucketList(string(output)), nil
+	// Make bucket output stable and easy to parse (one bucket per line: oss://bucket-name).
+	args = append(args, conn.dialect.shortFormat())
+
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
+	if err != nil {
+		// Bucket-scoped credentials cannot list buckets; the pinned ones keep navigation working.
+		if len(pinned) > 0 && ossutilAccessDenied(output) {
+			s.markProfileUsed(config)
+			return mergePinnedBuckets(nil, pinned, region), nil
+		}
+		return nil, s.errorf(msgBucketsListFailed, ossutilOutputOrError(err, output))
+	}
+
+	s.markProfileUsed(config)
+	return mergePinnedBuckets(s.parseBucketList(string(output)), pinned, region), nil
 }
 
 // parseBucketList parses ossutil ls output to bucket list
@@ -770,24 +1716,23 @@ func (s *OSSService) parseBucketList(output string) []BucketInfo {
 }
 
 // ListObjects lists objects in a bucket with optional prefix
-func (s *OSSService) ListObjects(config OSSConfig, bucketName string, prefix string) ([]ObjectInfo, error) {
-	bucketUrl := fmt.Sprintf("oss://%s/%s", bucketName, prefix)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+func (s *OSSService) ListObjects(config OSSConfig, bucketName string, prefix string) (_ []ObjectInfo, err error) {
+	defer s.logOperation("ListObjects", bucketName, prefix, time.Now(), &err)
 
-	args := []string{
-		"ls",
-		bucketUrl,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return nil, err
 	}
+	bucketUrl := buildOssPath(bucketName, prefix)
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		return nil, err
 	}
+	defer conn.close()
+	args := append([]string{"ls", bucketUrl}, conn.args...)
 
-	output, err := s.runOssutil(args...)
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list objects: %s", ossutilOutputOrError(err, output))
@@ -801,28 +1746,31 @@ func (s *OSSService) parseObjectList(output string, bucketName string, prefix st
 	var objects []ObjectInfo
 	lines := strings.Split(output, "\n")
 	seenFolders := make(map[string]bool)
+	bucketPath := buildOssPath(bucketName, "")
 
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "Object Number") || strings.HasPrefix(line, "Total Size") {
+		// Only the line ending is stripped: the object name is the last column and may itself
+		// contain or end with spaces.
+		line = strings.TrimLeftFunc(strings.TrimSuffix(line, "\r"), unicode.IsSpace)
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "Object Number") || strings.HasPrefix(line, "Total Size") {
 			continue
 		}
 
 		// Parse file lines with metadata
 		// Format: LastModifiedTime Size(B) StorageClass ETAG ObjectName
 		// Example: 2023-01-01 12:00:00 1234567 Standard D41D8CD98F oss://bucket/path/file.mp4
-		fields := strings.Fields(line)
-		if len(fields) < 5 {
+		pathIdx := strings.Index(line, bucketPath)
+		if pathIdx == -1 {
 			continue
 		}
-
-		objectPath := fields[len(fields)-1]
-		if !strings.HasPrefix(objectPath, "oss://") {
+		objectPath := line[pathIdx:]
+		fields := append(strings.Fields(line[:pathIdx]), objectPath)
+		if len(fields) < 5 {
 			continue
 		}
 
 		// Extract full key relative to bucket
-		fullKey := strings.TrimPrefix(objectPath, fmt.Sprintf("oss://%s/", bucketName))
+		_, fullKey, _ := parseOssPath(objectPath)
 
 		// Skip if this is before our prefix
 		if !strings.HasPrefix(fullKey, prefix) && fullKey+"/" != prefix {
@@ -850,7 +1798,7 @@ func (s *OSSService) parseObjectList(output string, bucketName string, prefix st
 				seenFolders[folderName] = true
 				objects = append(objects, ObjectInfo{
 					Name: folderName,
-					Path: fmt.Sprintf("oss://%s/%s%s/", bucketName, prefix, folderName),
+					Path: buildOssPath(bucketName, prefix+folderName+"/"),
 					Type: "Folder",
 				})
 			}
@@ -894,106 +1842,139 @@ func (s *OSSService) parseObjectList(output string, bucketName string, prefix st
 }
 
 // DownloadFile downloads a file from OSS
-func (s *OSSService) DownloadFile(config OSSConfig, bucket string, object string, localPath string) error {
-	cloudUrl := fmt.Sprintf("oss://%s/%s", bucket, object)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+func (s *OSSService) DownloadFile(config OSSConfig, bucket string, object string, localPath string) (err error) {
+	defer s.logOperation("DownloadFile", bucket, object, time.Now(), &err)
 
-	args := []string{
-		"cp",
-		cloudUrl,
-		localPath,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
-		"-f", // Force overwrite
+	if err := validateObjectRef(bucket, object); err != nil {
+		return err
+	}
+	cloudUrl := buildOssPath(bucket, object)
+	target, _, err := downloadTargetPath(localPath, object)
+	if err != nil {
+		return err
 	}
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+	args := append([]string{"cp", cloudUrl, target}, conn.args...)
+	if strings.HasSuffix(object, "/") {
+		// A folder key becomes a local directory holding everything under it.
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return err
+		}
+		args[2] = target + string(filepath.Separator)
+		args = append(args, conn.dialect.recursive())
 	}
+	args = append(args, conn.dialect.force()) // Force overwrite
 
-	output, err := s.runOssutil(args...)
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
 
 	if err != nil {
-		return fmt.Errorf("download failed: %s", ossutilOutputOrError(err, output))
+		return s.errorf(msgDownloadFailed, ossutilOutputOrError(err, output))
 	}
 
 	return nil
 }
 
 // UploadFile uploads a file to OSS
-func (s *OSSService) UploadFile(config OSSConfig, bucket string, prefix string, localPath string) error {
-	fileName := filepath.Base(localPath)
-	cloudUrl := fmt.Sprintf("oss://%s/%s%s", bucket, prefix, fileName)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+func (s *OSSService) UploadFile(config OSSConfig, bucket string, prefix string, localPath string) (err error) {
+	defer s.logOperation("UploadFile", bucket, prefix, time.Now(), &err)
 
-	args := []string{
-		"cp",
-		localPath,
-		cloudUrl,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
-		"-f", // Force overwrite
+	if err := s.requireWritable(config); err != nil {
+		return err
 	}
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	if err := validateBucketName(bucket); err != nil {
+		return err
 	}
+	prefix, err = sanitizeObjectPrefix(prefix)
+	if err != nil {
+		return err
+	}
+	fileName := filepath.Base(localPath)
+	cloudUrl := buildOssPath(bucket, prefix+fileName)
 
-	output, err := s.runOssutil(args...)
-
+	conn, err := s.ossutilConn(config, endpointForData)
 	if err != nil {
-		return fmt.Errorf("upload failed: %s", ossutilOutputOrError(err, output))
+		return err
 	}
+	defer conn.close()
+	args := append([]string{"cp", localPath, cloudUrl}, conn.args...)
+	args = append(args, conn.dialect.force()) // Force overwrite
 
-	return nil
+	overwrites := s.objectExists(config, bucket, prefix+fileName)
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
+	if err != nil {
+		err = s.errorf(msgUploadFailed, ossutilOutputOrError(err, output))
+	}
+	if overwrites {
+		s.recordAudit(config, "UploadFile", bucket, cloudUrl, auditCount(err), err)
+	}
+	return err
 }
 
-// DeleteObject deletes an object from OSS
-func (s *OSSService) DeleteObject(config OSSConfig, bucket string, object string) error {
-	cloudUrl := fmt.Sprintf("oss://%s/%s", bucket, object)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+// DeleteObject deletes an object from OSS. It needs a token from RequestDestructiveToken for
+// "object:delete" on the object's oss:// path. Deleting a folder (a key ending in "/") removes
+// everything under it in batches and can be canceled through CancelOperation.
+func (s *OSSService) DeleteObject(config OSSConfig, bucket string, object string, confirmToken string) (err error) {
+	defer s.logOperation("DeleteObject", bucket, object, time.Now(), &err)
 
-	args := []string{
-		"rm",
-		cloudUrl,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
-		"-f", // Force delete without confirmation prompt (since we handle it in UI)
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+	if err := validateObjectRef(bucket, object); err != nil {
+		return err
+	}
+	if err := s.requireConfirmationToken(confirmToken, confirmOpDeleteObject, buildOssPath(bucket, object)); err != nil {
+		return err
 	}
-
-	// recursive delete if it looks like a directory (though in OSS directories are fake, ossutil -r helps for common prefixes)
 	if strings.HasSuffix(object, "/") {
-		args = append(args, "-r")
+		deleted, err := s.deletePrefix(config, bucket, object)
+		s.recordAudit(config, "DeletePrefix", bucket, buildOssPath(bucket, object), deleted, err)
+		return err
 	}
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	cloudUrl := buildOssPath(bucket, object)
+
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		return err
 	}
+	defer conn.close()
+	args := append([]string{"rm", cloudUrl}, conn.args...)
+	args = append(args, conn.dialect.force()) // Force delete without confirmation prompt (since we handle it in UI)
 
-	output, err := s.runOssutil(args...)
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
 
 	if err != nil {
-		return fmt.Errorf("delete failed: %s", ossutilOutputOrError(err, output))
+		message := ossutilOutputOrError(err, output)
+		s.recordAudit(config, "DeleteObject", bucket, cloudUrl, 0, errors.New(message))
+		if isWormRejection(err, message) {
+			if summary := s.wormRejectionSummary(config, bucket); summary != "" {
+				return s.errorf(msgDeleteFailedSummary, summary, message)
+			}
+		}
+		return s.errorf(msgDeleteFailed, message)
 	}
 
+	s.recordAudit(config, "DeleteObject", bucket, cloudUrl, 1, nil)
 	return nil
 }
 
-func (s *OSSService) PresignObject(config OSSConfig, bucket string, object string, expiresDuration string) (string, error) {
+func (s *OSSService) PresignObject(config OSSConfig, bucket string, object string, expiresDuration string) (_ string, err error) {
+	defer s.logOperation("PresignObject", bucket, object, time.Now(), &err)
+
 	bucket = strings.TrimSpace(bucket)
-	object = strings.TrimLeft(strings.TrimSpace(object), "/")
+	object = normalizeObjectKey(object)
 
-	if bucket == "" {
-		return "", fmt.Errorf("bucket name is required")
+	if err := validateBucketName(bucket); err != nil {
+		return "", err
 	}
-	if object == "" {
-		return "", fmt.Errorf("object key is required")
+	if err := validateObjectKey(object); err != nil {
+		return "", err
 	}
 
 	expiresDuration = strings.TrimSpace(expiresDuration)
@@ -1009,14 +1990,11 @@ func (s *OSSService) PresignObject(config OSSConfig, bucket string, object strin
 		return "", fmt.Errorf("invalid expires duration: must be non-negative")
 	}
 
-	client, err := sdkClientFromConfig(config)
+	// Nothing is sent before the link is used, so only a region already learned can be applied.
+	bkt, err := s.sdkBucket(config, bucket)
 	if err != nil {
 		return "", err
 	}
-	bkt, err := client.Bucket(bucket)
-	if err != nil {
-		return "", fmt.Errorf("failed to open bucket: %w", err)
-	}
 
 	timeoutSeconds := int64(expires.Seconds())
 	signedURL, err := bkt.SignURL(object, oss.HTTPGet, timeoutSeconds)
@@ -1032,10 +2010,13 @@ func (s *OSSService) PresignObject(config OSSConfig, bucket string, object strin
 	return parts[0], nil
 }
 
-func (s *OSSService) GetObjectText(config OSSConfig, bucket string, object string, maxBytes int) (string, error) {
-	cloudUrl := fmt.Sprintf("oss://%s/%s", bucket, object)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+func (s *OSSService) GetObjectText(config OSSConfig, bucket string, object string, maxBytes int) (_ string, err error) {
+	defer s.logOperation("GetObjectText", bucket, object, time.Now(), &err)
+
+	if err := validateObjectRef(bucket, object); err != nil {
+		return "", err
+	}
+	cloudUrl := buildOssPath(bucket, object)
 
 	if maxBytes <= 0 {
 		maxBytes = 256 * 1024
@@ -1044,21 +2025,23 @@ func (s *OSSService) GetObjectText(config OSSConfig, bucket string, object strin
 		maxBytes = 5 * 1024 * 1024
 	}
 
-	args := []string{
-		"cat",
-		cloudUrl,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
-		"--count", strconv.Itoa(maxBytes),
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		return "", err
 	}
+	defer conn.close()
+	args := append([]string{"cat", cloudUrl}, conn.args...)
+	args = append(args, conn.dialect.catLimit(maxBytes)...)
+	args = s.withOssutilExtraArgs(args)
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
-	}
+	primary, fallback := s.ossutilBinaries()
+
+	command := s.ossutilCommandLine(primary, args)
+	ctx, done := s.startOssutilCall(s.baseContext(), command)
+	defer done()
 
 	runSplit := func(bin string, args ...string) ([]byte, []byte, error) {
-		cmd := exec.Command(bin, args...)
+		cmd := ossutilCommand(ctx, bin, conn.env, args...)
 		var stdout bytes.Buffer
 		var stderr bytes.Buffer
 		cmd.Stdout = &stdout
@@ -1067,25 +2050,19 @@ func (s *OSSService) GetObjectText(config OSSConfig, bucket string, object strin
 		return stdout.Bytes(), stderr.Bytes(), err
 	}
 
-	primary := strings.TrimSpace(s.ossutilPath)
-	fallback := strings.TrimSpace(s.defaultOssutilPath)
-	if primary == "" {
-		primary = fallback
-	}
-	if primary == "" {
-		primary = "ossutil"
-	}
-
 	stdout, stderr, err := runSplit(primary, args...)
 	if err != nil && ossutilStartFailed(err) && fallback != "" && fallback != primary {
 		// Retry with the auto-discovered ossutil path.
 		fallbackStdout, fallbackStderr, fallbackErr := runSplit(fallback, args...)
 		if fallbackErr == nil || !ossutilStartFailed(fallbackErr) {
-			s.ossutilPath = fallback
+			s.useOssutilFallback(primary, fallback)
 			stdout, stderr, err = fallbackStdout, fallbackStderr, fallbackErr
 		}
 	}
 
+	if err := ossutilContextError(ctx, command, err); errors.Is(err, ErrOperationTimedOut) || errors.Is(err, ErrOperationCanceled) {
+		return "", err
+	}
 	if err != nil {
 		msg := strings.TrimSpace(string(stderr))
 		if msg == "" {
@@ -1097,7 +2074,12 @@ func (s *OSSService) GetObjectText(config OSSConfig, bucket string, object strin
 		return "", fmt.Errorf("read object failed: %s", msg)
 	}
 
-	return stripOssutilElapsedFooter(string(stdout)), nil
+	text := stripOssutilElapsedFooter(string(stdout))
+	if len(text) > maxBytes {
+		// ossutil 1.x cannot limit cat output itself.
+		text = text[:maxBytes]
+	}
+	return text, nil
 }
 
 func stripOssutilElapsedFooter(output string) string {
@@ -1137,78 +2119,120 @@ func isOssutilElapsedFooterLine(line string) bool {
 	return err == nil
 }
 
-func (s *OSSService) PutObjectText(config OSSConfig, bucket string, object string, content string) error {
-	cloudUrl := fmt.Sprintf("oss://%s/%s", bucket, object)
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
+func (s *OSSService) PutObjectText(config OSSConfig, bucket string, object string, content string) (err error) {
+	defer s.logOperation("PutObjectText", bucket, object, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return err
+	}
+	if err := validateObjectRef(bucket, object); err != nil {
+		return err
+	}
+
+	cloudUrl := buildOssPath(bucket, object)
 
-	tmpFile, err := os.CreateTemp("", "walioss-edit-*")
+	tmpFile, err := s.tempFiles.create(tempPurposeEdit, "walioss-edit-*")
 	if err != nil {
-		return fmt.Errorf("create temp file failed: %w", err)
+		return s.errorf(msgCreateTempFileFailed, err)
 	}
-	defer func() {
-		_ = os.Remove(tmpFile.Name())
-	}()
+	defer s.tempFiles.release(tmpFile.Name())
 
 	if _, err := tmpFile.WriteString(content); err != nil {
 		_ = tmpFile.Close()
-		return fmt.Errorf("write temp file failed: %w", err)
+		return s.errorf(msgWriteTempFileFailed, err)
 	}
 	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("close temp file failed: %w", err)
+		return s.errorf(msgCloseTempFileFailed, err)
 	}
+	s.tempFiles.finish(tmpFile.Name())
 
-	args := []string{
-		"cp",
-		tmpFile.Name(),
-		cloudUrl,
-		"--access-key-id", config.AccessKeyID,
-		"--access-key-secret", config.AccessKeySecret,
-		"--region", region,
-		"-f",
-	}
-
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		return err
 	}
+	defer conn.close()
+	args := append([]string{"cp", tmpFile.Name(), cloudUrl}, conn.args...)
+	args = append(args, conn.dialect.force())
 
-	output, err := s.runOssutil(args...)
+	overwrites := s.objectExists(config, bucket, object)
+	output, err := s.runOssutil(s.baseContext(), conn, args...)
 	if err != nil {
-		return fmt.Errorf("save failed: %s", ossutilOutputOrError(err, output))
+		err = s.errorf(msgSaveFailed, ossutilOutputOrError(err, output))
 	}
-
-	return nil
+	if overwrites {
+		s.recordAudit(config, "PutObjectText", bucket, cloudUrl, auditCount(err), err)
+	}
+	return err
 }
 
 // CheckOssutilInstalled checks if ossutil is installed and accessible
 func (s *OSSService) CheckOssutilInstalled() ConnectionResult {
-	output, err := s.runOssutil("version")
+	output, err := s.runOssutil(s.baseContext(), ossutilConnection{}, "version")
+	if err != nil {
+		// ossutil 1.x has no version command and only answers -v.
+		if version, versionErr := s.ossutilVersion(); versionErr == nil {
+			output, err = []byte(version.Raw), nil
+		}
+	}
 
 	if err != nil {
-		return ConnectionResult{
+		result := ConnectionResult{
 			Success: false,
 			Message: fmt.Sprintf("ossutil not found or not accessible: %s", err.Error()),
 		}
+		// Only offer the installer when the user has not pointed us at a binary of their own.
+		if _, _, platformErr := ossutilReleasePlatform(); platformErr == nil && s.usingDefaultOssutil() {
+			result.InstallPath = filepath.Join(s.ossutilInstallDir(), ossutilBinaryName())
+		}
+		return result
 	}
 
-	return ConnectionResult{
+	result := ConnectionResult{
 		Success: true,
 		Message: strings.TrimSpace(string(output)),
 	}
+	version, err := parseOssutilVersion(string(output))
+	if err == nil {
+		s.rememberOssutilVersion(version)
+	} else if version, err = s.ossutilVersion(); err != nil {
+		return result
+	}
+	result.OssutilVersion = &version
+	if version.less(minSupportedOssutilVersion) {
+		result.Success = false
+		result.Unsupported = true
+		result.Message = fmt.Sprintf("unsupported ossutil version, found %s need ≥%s", version, minSupportedOssutilVersion)
+	}
+	return result
 }
 
 // saveProfiles saves profiles to config file
 func (s *OSSService) saveProfiles(profiles []OSSProfile) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	state, err := s.loadAppState()
 	if err != nil {
 		return err
 	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
 	state.Profiles = profiles
 	return s.saveAppStateToDir(s.configDir, state)
 }
 
 // GetSettings loads application settings
 func (s *OSSService) GetSettings() (AppSettings, error) {
+	unlock, err := s.lockState()
+	if err != nil {
+		return AppSettings{}, err
+	}
+	defer unlock()
+
 	state, err := s.loadAppState()
 	if err != nil {
 		return AppSettings{}, err
@@ -1226,12 +2250,45 @@ func (s *OSSService) GetSettings() (AppSettings, error) {
 
 // SaveSettings persists application settings
 func (s *OSSService) SaveSettings(settings AppSettings) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	state, err := s.loadAppState()
 	if err != nil {
 		return err
 	}
 
+	if err := validateTheme(settings.Theme); err != nil {
+		return err
+	}
 	nextSettings := normalizeAppSettings(settings, s.defaultConfigDir)
+	if nextSettings.ProxyURL != "" {
+		if _, err := validateProxyURL(nextSettings.ProxyURL); err != nil {
+			return err
+		}
+	}
+	if nextSettings.TLSCACertFile != "" {
+		if err := validateCACertFile(nextSettings.TLSCACertFile); err != nil {
+			return err
+		}
+	}
+	if err := validateOssutilExtraArgs(nextSettings.OssutilExtraArgs); err != nil {
+		return err
+	}
+	if err := validateSDKTuning(nextSettings); err != nil {
+		return err
+	}
+	if err := validateLogLevel(settings.LogLevel); err != nil {
+		return err
+	}
+	if nextSettings.DefaultDownloadDir != "" {
+		if err := ensureWritableDir(expandLeadingTilde(nextSettings.DefaultDownloadDir)); err != nil {
+			return err
+		}
+	}
 	targetDir := nextSettings.WorkDir
 	previousDir := s.configDir
 	state.Settings = nextSettings
@@ -1241,6 +2298,9 @@ func (s *OSSService) SaveSettings(settings AppSettings) error {
 	}
 	s.copyTransferHistoryIfNeeded(previousDir, targetDir)
 	s.configDir = targetDir
+	if targetDir != previousDir {
+		s.noteConfigDirMoved()
+	}
 	if err := s.writeWorkDirRef(targetDir); err != nil {
 		return err
 	}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestService returns an OSSService whose config directory lives under a temporary home, so
+// tests never read or write the user's ~/.walioss.
+func newTestService(t testing.TB) *OSSService {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return NewOSSService()
+}
+
+// fakeOSS serves handler as an OSS endpoint and returns a profile that talks to it. The SDK
+// addresses IP endpoints path-style, so requests arrive as /<bucket>/<key>.
+func fakeOSS(t testing.TB, handler http.HandlerFunc) OSSConfig {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return OSSConfig{
+		AccessKeyID:       "LTAI-test",
+		AccessKeySecret:   "secret",
+		Region:            "cn-hangzhou",
+		Endpoint:          server.URL,
+		AllowInsecureHTTP: true,
+	}
+}
+
+// writeListing answers a ListObjects request with keys and prefixes.
+func writeListing(w http.ResponseWriter, keys []string, prefixes []string) {
+	w.Header().Set("Content-Type", "application/xml")
+	body := `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`
+	for _, key := range keys {
+		body += `<Contents><Key>` + key + `</Key><Size>1</Size><LastModified>2024-01-01T00:00:00.000Z</LastModified><StorageClass>Standard</StorageClass></Contents>`
+	}
+	for _, prefix := range prefixes {
+		body += `<CommonPrefixes><Prefix>` + prefix + `</Prefix></CommonPrefixes>`
+	}
+	_, _ = w.Write([]byte(body + `</ListBucketResult>`))
+}
+
+// fakeBucket is an in-memory bucket behind fakeOSS. It answers delimiter listings with markers
+// and HEAD requests, and counts both.
+type fakeBucket struct {
+	mu    sync.Mutex
+	keys  []string
+	lists int
+	heads int
+}
+
+func newFakeBucket(keys ...string) *fakeBucket {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	return &fakeBucket{keys: sorted}
+}
+
+func (b *fakeBucket) requests() (lists int, heads int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lists, b.heads
+}
+
+func (b *fakeBucket) serve(w http.ResponseWriter, r *http.Request) {
+	_, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if r.Method == http.MethodHead {
+		b.heads++
+		i := sort.SearchStrings(b.keys, key)
+		if i == len(b.keys) || b.keys[i] != key {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "1")
+		return
+	}
+	b.lists++
+	query := r.URL.Query()
+	prefix, marker, delimiter := query.Get("prefix"), query.Get("marker"), query.Get("delimiter")
+	maxKeys, err := strconv.Atoi(query.Get("max-keys"))
+	if err != nil || maxKeys <= 0 {
+		maxKeys = 100
+	}
+
+	type contents struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+		StorageClass string `xml:"StorageClass"`
+	}
+	type commonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
+	result := struct {
+		XMLName        xml.Name       `xml:"ListBucketResult"`
+		Name           string         `xml:"Name"`
+		Prefix         string         `xml:"Prefix"`
+		IsTruncated    bool           `xml:"IsTruncated"`
+		NextMarker     string         `xml:"NextMarker,omitempty"`
+		Contents       []contents     `xml:"Contents"`
+		CommonPrefixes []commonPrefix `xml:"CommonPrefixes"`
+	}{Name: "bucket", Prefix: prefix}
+	count := 0
+	for _, k := range b.keys {
+		if !strings.HasPrefix(k, prefix) || k <= marker || (strings.HasSuffix(marker, "/") && strings.HasPrefix(k, marker)) {
+			continue
+		}
+		entry := k
+		if delimiter != "" {
+			if i := strings.Index(k[len(prefix):], delimiter); i >= 0 {
+				entry = k[:len(prefix)+i+len(delimiter)]
+			}
+		}
+		if n := len(result.CommonPrefixes); n > 0 && result.CommonPrefixes[n-1].Prefix == entry {
+			continue
+		}
+		if count == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+		count++
+		result.NextMarker = entry
+		if entry != k {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: entry})
+		} else {
+			result.Contents = append(result.Contents, contents{Key: k, Size: 1, LastModified: "2024-01-01T00:00:00.000Z", StorageClass: "Standard"})
+		}
+	}
+	if !result.IsTruncated {
+		result.NextMarker = ""
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
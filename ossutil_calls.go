@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultOssutilTimeoutSec = 60
+	minOssutilTimeoutSec     = 5
+	maxOssutilTimeoutSec     = 3600
+)
+
+var (
+	// ErrOperationTimedOut is returned when an ossutil command runs past its timeout.
+	ErrOperationTimedOut = errors.New("operation timed out")
+	// ErrOperationCanceled is returned when an ossutil command was canceled through CancelOperation.
+	ErrOperationCanceled = errors.New("operation canceled")
+)
+
+// PendingOperation is an ossutil command that is still running, listed so the UI can offer to
+// cancel a stuck one.
+type PendingOperation struct {
+	ID          string `json:"id"`
+	Command     string `json:"command"`
+	StartedAtMs int64  `json:"startedAtMs"`
+}
+
+type ossutilCall struct {
+	operation PendingOperation
+	cancel    context.CancelFunc
+}
+
+func normalizeOssutilTimeoutSec(sec int) int {
+	if sec <= 0 {
+		return defaultOssutilTimeoutSec
+	}
+	if sec < minOssutilTimeoutSec {
+		return minOssutilTimeoutSec
+	}
+	if sec > maxOssutilTimeoutSec {
+		return maxOssutilTimeoutSec
+	}
+	return sec
+}
+
+func (s *OSSService) setOssutilTimeout(sec int) {
+	s.ossutilCallsMu.Lock()
+	s.ossutilTimeout = time.Duration(normalizeOssutilTimeoutSec(sec)) * time.Second
+	s.ossutilCallsMu.Unlock()
+}
+
+// baseContext is the context ossutil calls derive from: the app's, so they end with it.
+func (s *OSSService) baseContext() context.Context {
+	s.transferCtxMu.RLock()
+	defer s.transferCtxMu.RUnlock()
+	if s.transferCtx != nil {
+		return s.transferCtx
+	}
+	return context.Background()
+}
+
+// startOssutilCall registers a non-transfer ossutil command for CancelOperation and applies the
+// default timeout unless ctx already has a deadline. The returned function must be called when
+// the command has finished.
+func (s *OSSService) startOssutilCall(ctx context.Context, command string) (context.Context, func()) {
+	s.ossutilCallsMu.Lock()
+	timeout := s.ossutilTimeout
+	s.ossutilCallsMu.Unlock()
+	if timeout <= 0 {
+		timeout = defaultOssutilTimeoutSec * time.Second
+	}
+
+	var cancel context.CancelFunc
+	if _, ok := ctx.Deadline(); ok {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	_, done := s.trackPendingOperation(command, cancel)
+	return ctx, done
+}
+
+// startLongOperation registers an SDK walk over a prefix for CancelOperation. Unlike ossutil
+// commands it has no timeout: walking millions of keys legitimately takes a while. The returned
+// function must be called when the walk has finished.
+func (s *OSSService) startLongOperation(ctx context.Context, command string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	_, done := s.trackPendingOperation(command, cancel)
+	return ctx, done
+}
+
+// startBackgroundOperation is startLongOperation for walks that outlive the call starting them. The
+// returned ID is what the caller hands back for CancelOperation.
+func (s *OSSService) startBackgroundOperation(command string) (string, context.Context, func()) {
+	ctx, cancel := context.WithCancel(s.baseContext())
+	id, done := s.trackPendingOperation(command, cancel)
+	return id, ctx, done
+}
+
+func (s *OSSService) trackPendingOperation(command string, cancel context.CancelFunc) (string, func()) {
+	id := fmt.Sprintf("op-%d", atomic.AddUint64(&s.ossutilCallSeq, 1))
+	s.ossutilCallsMu.Lock()
+	s.ossutilCalls[id] = ossutilCall{
+		operation: PendingOperation{ID: id, Command: command, StartedAtMs: time.Now().UnixMilli()},
+		cancel:    cancel,
+	}
+	s.ossutilCallsMu.Unlock()
+
+	return id, func() {
+		s.ossutilCallsMu.Lock()
+		delete(s.ossutilCalls, id)
+		s.ossutilCallsMu.Unlock()
+		cancel()
+	}
+}
+
+// ossutilContextError turns a command killed by its context into ErrOperationTimedOut or
+// ErrOperationCanceled naming the (already redacted) command; other errors pass through.
+func ossutilContextError(ctx context.Context, command string, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", ErrOperationTimedOut, command)
+	case context.Canceled:
+		return fmt.Errorf("%w: %s", ErrOperationCanceled, command)
+	}
+	return err
+}
+
+// GetPendingOperations lists the ossutil commands (other than transfers) and prefix walks that are
+// still running.
+func (s *OSSService) GetPendingOperations() []PendingOperation {
+	s.ossutilCallsMu.Lock()
+	defer s.ossutilCallsMu.Unlock()
+	out := make([]PendingOperation, 0, len(s.ossutilCalls))
+	for _, call := range s.ossutilCalls {
+		out = append(out, call.operation)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAtMs < out[j].StartedAtMs })
+	return out
+}
+
+// CancelOperation stops a running command or walk listed by GetPendingOperations. The bound call
+// waiting on it returns ErrOperationCanceled.
+func (s *OSSService) CancelOperation(id string) error {
+	s.ossutilCallsMu.Lock()
+	call, ok := s.ossutilCalls[id]
+	s.ossutilCallsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("operation %s is not running", id)
+	}
+	call.cancel()
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Oldest ossutil 1.x that understands --region together with --sign-version v4.
+var minSupportedOssutilVersion = OssutilVersion{Major: 1, Minor: 7, Patch: 0}
+
+// Matches "ossutil version: v1.7.19" (1.x) as well as "ossutil version 2.1.2 ..." (2.x).
+var reOssutilVersionOutput = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+
+// OssutilVersion is the parsed version of an ossutil binary.
+type OssutilVersion struct {
+	Major int    `json:"major"`
+	Minor int    `json:"minor"`
+	Patch int    `json:"patch"`
+	Raw   string `json:"raw"`
+}
+
+func (v OssutilVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func (v OssutilVersion) less(other OssutilVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+func parseOssutilVersion(output string) (OssutilVersion, error) {
+	raw := strings.TrimSpace(output)
+	m := reOssutilVersionOutput.FindStringSubmatch(raw)
+	if m == nil {
+		return OssutilVersion{}, fmt.Errorf("cannot parse ossutil version from %q", raw)
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+	return OssutilVersion{Major: major, Minor: minor, Patch: patch, Raw: raw}, nil
+}
+
+// ossutilVersionCacheEntry remembers the version of one binary; a changed size or modification
+// time (an upgrade in place) invalidates it.
+type ossutilVersionCacheEntry struct {
+	version OssutilVersion
+	modTime time.Time
+	size    int64
+}
+
+func ossutilBinaryStamp(binary string) (time.Time, int64) {
+	info, err := os.Stat(binary)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// ossutilVersion returns the version of the active ossutil binary, running "version" (2.x) or
+// "-v" (1.x) once per binary.
+func (s *OSSService) ossutilVersion() (OssutilVersion, error) {
+	binary := strings.TrimSpace(s.GetOssutilPath())
+	modTime, size := ossutilBinaryStamp(binary)
+
+	s.ossutilVersionMu.Lock()
+	entry, ok := s.ossutilVersions[binary]
+	s.ossutilVersionMu.Unlock()
+	if ok && entry.modTime.Equal(modTime) && entry.size == size {
+		return entry.version, nil
+	}
+
+	output, err := s.runOssutil(s.baseContext(), ossutilConnection{}, "version")
+	if err != nil {
+		var fallbackErr error
+		if output, fallbackErr = s.runOssutil(s.baseContext(), ossutilConnection{}, "-v"); fallbackErr != nil {
+			return OssutilVersion{}, err
+		}
+	}
+	version, err := parseOssutilVersion(string(output))
+	if err != nil {
+		return OssutilVersion{}, err
+	}
+	s.rememberOssutilVersion(version)
+	return version, nil
+}
+
+// rememberOssutilVersion caches version for the active binary. runOssutil may have switched to
+// the fallback binary, so the path is read again here.
+func (s *OSSService) rememberOssutilVersion(version OssutilVersion) {
+	binary := strings.TrimSpace(s.GetOssutilPath())
+	modTime, size := ossutilBinaryStamp(binary)
+	s.ossutilVersionMu.Lock()
+	s.ossutilVersions[binary] = ossutilVersionCacheEntry{version: version, modTime: modTime, size: size}
+	s.ossutilVersionMu.Unlock()
+}
+
+// ossutilDialect spells command line flags for one ossutil major version.
+type ossutilDialect struct {
+	major int
+}
+
+// dialect returns the flag dialect of the active binary; 2.x is assumed when the version cannot
+// be determined, since that is what the app bundles.
+func (s *OSSService) dialect() ossutilDialect {
+	version, err := s.ossutilVersion()
+	if err != nil || version.Major < 1 {
+		return ossutilDialect{major: 2}
+	}
+	return ossutilDialect{major: version.Major}
+}
+
+// credentials returns how config's credentials are passed: 2.x reads them from the environment.
+// 1.x only takes them as flags, which other users can read from the process list, or from a
+// config file, so for 1.x it returns the content of that file instead.
+func (d ossutilDialect) credentials(config OSSConfig) (env []string, configFile []byte) {
+	if d.major >= 2 {
+		// Always set all three so credentials inherited from the user's shell cannot mix in.
+		return []string{
+			"OSS_ACCESS_KEY_ID=" + config.AccessKeyID,
+			"OSS_ACCESS_KEY_SECRET=" + config.AccessKeySecret,
+			"OSS_SESSION_TOKEN=" + config.SecurityToken,
+		}, nil
+	}
+	var file strings.Builder
+	file.WriteString("[Credentials]\n")
+	fmt.Fprintf(&file, "accessKeyID = %s\n", config.AccessKeyID)
+	fmt.Fprintf(&file, "accessKeySecret = %s\n", config.AccessKeySecret)
+	if config.SecurityToken != "" {
+		fmt.Fprintf(&file, "stsToken = %s\n", config.SecurityToken)
+	}
+	return []string{}, []byte(file.String())
+}
+
+// configFile points 1.x at the config file written for credentials.
+func (d ossutilDialect) configFile(path string) []string {
+	return []string{"--config-file", path}
+}
+
+// region selects the region; 1.x signs with V1 unless V4 is asked for explicitly.
+func (d ossutilDialect) region(region string) []string {
+	if region == "" {
+		return nil
+	}
+	if d.major >= 2 {
+		return []string{"--region", region}
+	}
+	return []string{"--region", region, "--sign-version", "v4"}
+}
+
+func (d ossutilDialect) endpoint(endpoint string) []string {
+	return []string{"--endpoint", endpoint}
+}
+
+// force skips confirmation prompts of cp and rm.
+func (d ossutilDialect) force() string {
+	return "-f"
+}
+
+// skipVerifyCert accepts any TLS certificate; both major versions spell it the same.
+func (d ossutilDialect) skipVerifyCert() string {
+	return "--skip-verify-cert"
+}
+
+// recursive makes rm remove everything under a prefix.
+func (d ossutilDialect) recursive() string {
+	return "-r"
+}
+
+// shortFormat makes ls print one oss:// URL per line.
+func (d ossutilDialect) shortFormat() string {
+	return "--short-format"
+}
+
+// catLimit caps how much cat prints. 1.x has no such flag, so the caller has to truncate.
+func (d ossutilDialect) catLimit(maxBytes int) []string {
+	if d.major >= 2 {
+		return []string{"--count", strconv.Itoa(maxBytes)}
+	}
+	return nil
+}
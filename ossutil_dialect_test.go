@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Recorded output of "ossutil -v" (1.x) and "ossutil version" (2.x).
+const (
+	ossutil1VersionOutput = "ossutil version: v1.7.19\n"
+	ossutil2VersionOutput = "ossutil version 2.1.2 (linux/amd64)\nGo version: go1.22.5\n"
+)
+
+// ossutil1Script answers like ossutil 1.x, which has no version command.
+func ossutil1Script(version string) string {
+	return `if [ "$1" = "-v" ]; then echo 'ossutil version: ` + version + `'; exit 0; fi
+echo 'Error: invalid command: version, please try "help" for more information' >&2
+exit 1`
+}
+
+func TestParseOssutilVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   OssutilVersion
+	}{
+		{ossutil1VersionOutput, OssutilVersion{Major: 1, Minor: 7, Patch: 19, Raw: "ossutil version: v1.7.19"}},
+		{ossutil2VersionOutput, OssutilVersion{Major: 2, Minor: 1, Patch: 2, Raw: strings.TrimSpace(ossutil2VersionOutput)}},
+		{"ossutil version 2.0.6-beta.01091200", OssutilVersion{Major: 2, Minor: 0, Patch: 6, Raw: "ossutil version 2.0.6-beta.01091200"}},
+	}
+	for _, tt := range tests {
+		got, err := parseOssutilVersion(tt.output)
+		if err != nil || got != tt.want {
+			t.Errorf("parseOssutilVersion(%q) = %+v, %v; want %+v", tt.output, got, err, tt.want)
+		}
+	}
+	if _, err := parseOssutilVersion("command not found"); err == nil {
+		t.Error("parseOssutilVersion accepted output without a version")
+	}
+}
+
+func TestOssutilVersionLess(t *testing.T) {
+	tests := []struct {
+		v    OssutilVersion
+		want bool
+	}{
+		{OssutilVersion{Major: 1, Minor: 6, Patch: 99}, true},
+		{OssutilVersion{Major: 1, Minor: 7, Patch: 0}, false},
+		{OssutilVersion{Major: 1, Minor: 7, Patch: 19}, false},
+		{OssutilVersion{Major: 0, Minor: 99, Patch: 0}, true},
+		{OssutilVersion{Major: 2}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.v.less(minSupportedOssutilVersion); got != tt.want {
+			t.Errorf("%s less than %s = %v, want %v", tt.v, minSupportedOssutilVersion, got, tt.want)
+		}
+	}
+}
+
+func TestOssutilDialectFlags(t *testing.T) {
+	v1, v2 := ossutilDialect{major: 1}, ossutilDialect{major: 2}
+	if got, want := v1.region("cn-hangzhou"), []string{"--region", "cn-hangzhou", "--sign-version", "v4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("1.x region flags = %v, want %v", got, want)
+	}
+	if got, want := v2.region("cn-hangzhou"), []string{"--region", "cn-hangzhou"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("2.x region flags = %v, want %v", got, want)
+	}
+	if v1.region("") != nil || v2.region("") != nil {
+		t.Error("an empty region produced flags")
+	}
+	if got := v1.catLimit(100); got != nil {
+		t.Errorf("1.x cat limit = %v, want none", got)
+	}
+	if got, want := v2.catLimit(100), []string{"--count", "100"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("2.x cat limit = %v, want %v", got, want)
+	}
+
+	config := OSSConfig{AccessKeyID: "id", AccessKeySecret: "secret"}
+	if env, file := v2.credentials(config); len(env) != 3 || file != nil {
+		t.Errorf("2.x credentials = %v, %q; want environment variables only", env, file)
+	}
+	if env, file := v1.credentials(config); len(env) != 0 || !strings.Contains(string(file), "accessKeySecret = secret") {
+		t.Errorf("1.x credentials = %v, %q; want a config file only", env, file)
+	}
+}
+
+func TestDialectFollowsInstalledOssutil(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		script string
+		major  int
+	}{
+		{"1.x", ossutil1Script("v1.7.19"), 1},
+		{"2.x", "printf '" + strings.ReplaceAll(ossutil2VersionOutput, "\n", "\\n") + "'", 2},
+		// Without a readable version the current major line is assumed.
+		{"unknown", "echo 'something else'", 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			fakeOssutil(t, s, tt.script)
+			if got := s.dialect().major; got != tt.major {
+				t.Errorf("dialect major = %d, want %d", got, tt.major)
+			}
+		})
+	}
+}
+
+func TestCheckOssutilInstalledReportsVersion(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, ossutil1Script("v1.7.19"))
+
+	result := s.CheckOssutilInstalled()
+	if !result.Success || result.Unsupported {
+		t.Fatalf("CheckOssutilInstalled = %+v, want a supported 1.x", result)
+	}
+	if result.OssutilVersion == nil || result.OssutilVersion.Major != 1 || result.OssutilVersion.Patch != 19 {
+		t.Errorf("OssutilVersion = %+v, want 1.7.19", result.OssutilVersion)
+	}
+}
+
+func TestCheckOssutilInstalledRefusesOldVersion(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, ossutil1Script("v1.6.3"))
+
+	result := s.CheckOssutilInstalled()
+	if result.Success || !result.Unsupported {
+		t.Fatalf("CheckOssutilInstalled = %+v, want an unsupported version", result)
+	}
+	if !strings.Contains(result.Message, "found 1.6.3 need ≥1.7.0") {
+		t.Errorf("message = %q", result.Message)
+	}
+}
+
+func TestOssutilVersionIsCachedPerBinary(t *testing.T) {
+	s := newTestService(t)
+	calls := filepath.Join(t.TempDir(), "calls")
+	fakeOssutil(t, s, "echo run >> '"+calls+"'\n"+"echo 'ossutil version 2.1.2'")
+
+	for range 3 {
+		if _, err := s.ossutilVersion(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, _ := os.ReadFile(calls)
+	if n := strings.Count(string(runs), "run"); n != 1 {
+		t.Errorf("ossutil ran %d times for three version lookups, want once", n)
+	}
+
+	// Another binary is asked again.
+	fakeOssutil(t, s, ossutil1Script("v1.7.19"))
+	version, err := s.ossutilVersion()
+	if err != nil || version.Major != 1 {
+		t.Errorf("version of the new binary = %+v, %v; want 1.x", version, err)
+	}
+}
+
+func TestOssutilConnKeepsSecretsOffTheCommandLine(t *testing.T) {
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "top-secret", SecurityToken: "sts-token", Region: "cn-hangzhou"}
+	for _, tt := range []struct {
+		name    string
+		version string
+	}{
+		{"1.x", "echo 'ossutil version: v1.7.19'"},
+		{"2.x", "echo 'ossutil version 2.1.2 (linux/amd64)'"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			fakeOssutil(t, s, tt.version)
+
+			conn, err := s.ossutilConn(config, endpointForData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commandLine := strings.Join(conn.args, " ")
+			for _, secret := range []string{config.AccessKeySecret, config.SecurityToken} {
+				if strings.Contains(commandLine, secret) {
+					t.Fatalf("arguments carry a secret: %s", commandLine)
+				}
+			}
+			if conn.dialect.major >= 2 {
+				if conn.release != nil {
+					t.Error("a 2.x connection wrote a credentials file")
+				}
+				return
+			}
+
+			path := ""
+			for i, arg := range conn.args {
+				if arg == "--config-file" && i+1 < len(conn.args) {
+					path = conn.args[i+1]
+				}
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("credentials file %q: %v", path, err)
+			}
+			if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+				t.Errorf("credentials file mode = %v, want 0600", info.Mode().Perm())
+			}
+			content, _ := os.ReadFile(path)
+			for _, line := range []string{"[Credentials]", "accessKeyID = LTAI-test", "accessKeySecret = top-secret", "stsToken = sts-token"} {
+				if !strings.Contains(string(content), line) {
+					t.Errorf("credentials file lacks %q:\n%s", line, content)
+				}
+			}
+
+			conn.close()
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("credentials file still exists after close: %v", err)
+			}
+		})
+	}
+}
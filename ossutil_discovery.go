@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+)
+
+// ossutilBinaryNames lists the names ossutil is shipped under on this OS, preferred first. Windows
+// releases are ossutil.exe or, for older 64-bit builds, ossutil64.exe.
+func ossutilBinaryNames() []string {
+	if goruntime.GOOS == "windows" {
+		return []string{"ossutil.exe", "ossutil64.exe", "ossutil"}
+	}
+	return []string{"ossutil", "ossutil64"}
+}
+
+// discoverOssutilPath looks for ossutil next to the executable, in its bin/ directory, in bin/ of
+// the working directory (development mode), where InstallOssutil puts it and finally on PATH, where
+// exec.LookPath also applies PATHEXT on Windows. It returns plain "ossutil" when nothing is found,
+// so errors name the binary the user has to install.
+func discoverOssutilPath(defaultConfigDir string) string {
+	var dirs []string
+	if exePath, err := os.Executable(); err == nil {
+		exeDir := filepath.Dir(exePath)
+		dirs = append(dirs, exeDir, filepath.Join(exeDir, "bin"))
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, filepath.Join(cwd, "bin"))
+	}
+	dirs = append(dirs, filepath.Join(defaultConfigDir, "bin"))
+
+	names := ossutilBinaryNames()
+	for _, dir := range dirs {
+		for _, name := range names {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+	}
+	for _, name := range names {
+		if found, err := exec.LookPath(name); err == nil {
+			return found
+		}
+	}
+	return "ossutil"
+}
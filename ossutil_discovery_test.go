@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+)
+
+func TestOssutilBinaryNames(t *testing.T) {
+	names := ossutilBinaryNames()
+	want := "ossutil"
+	if goruntime.GOOS == "windows" {
+		want = "ossutil.exe"
+	}
+	if len(names) == 0 || names[0] != want {
+		t.Errorf("ossutilBinaryNames() = %v, want %s first", names, want)
+	}
+}
+
+func installFakeBinary(t *testing.T, dir string, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscoverOssutilInConfigDirWithSpaces(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "Program Files", "wali oss")
+	want := installFakeBinary(t, filepath.Join(configDir, "bin"), ossutilBinaryNames()[0])
+	t.Setenv("PATH", "")
+
+	if got := discoverOssutilPath(configDir); got != want {
+		t.Errorf("discoverOssutilPath = %q, want %q", got, want)
+	}
+}
+
+func TestDiscoverOssutilPrefersFirstName(t *testing.T) {
+	names := ossutilBinaryNames()
+	configDir := t.TempDir()
+	installFakeBinary(t, filepath.Join(configDir, "bin"), names[1])
+	want := installFakeBinary(t, filepath.Join(configDir, "bin"), names[0])
+	t.Setenv("PATH", "")
+
+	if got := discoverOssutilPath(configDir); got != want {
+		t.Errorf("discoverOssutilPath = %q, want %q", got, want)
+	}
+}
+
+func TestDiscoverOssutilOnPath(t *testing.T) {
+	pathDir := t.TempDir()
+	// ossutil64 is the fallback name on every OS; on Windows LookPath completes it through PATHEXT.
+	name := "ossutil64"
+	if goruntime.GOOS == "windows" {
+		name += ".exe"
+		t.Setenv("PATHEXT", ".EXE")
+	}
+	want := installFakeBinary(t, pathDir, name)
+	t.Setenv("PATH", pathDir)
+
+	if got := discoverOssutilPath(t.TempDir()); got != want {
+		t.Errorf("discoverOssutilPath = %q, want %q from PATH", got, want)
+	}
+}
+
+func TestDiscoverOssutilNotFound(t *testing.T) {
+	t.Setenv("PATH", "")
+	if got := discoverOssutilPath(t.TempDir()); got != "ossutil" {
+		t.Errorf("discoverOssutilPath = %q, want plain ossutil", got)
+	}
+}
+
+func TestUploadPlanKeysUseForwardSlashes(t *testing.T) {
+	s := newTestService(t)
+	root := filepath.Join(t.TempDir(), "photos")
+	for _, rel := range []string{"top.jpg", filepath.Join("2024", "jan", "a.jpg")} {
+		installFakeBinary(t, filepath.Dir(filepath.Join(root, rel)), filepath.Base(rel))
+	}
+
+	plan, err := s.buildUploadPlan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make(map[string]bool)
+	for _, file := range plan.Files {
+		keys[file.RelativeKey] = true
+	}
+	for _, want := range []string{"photos/top.jpg", "photos/2024/jan/a.jpg"} {
+		if !keys[want] {
+			t.Errorf("plan keys = %v, want %s", keys, want)
+		}
+	}
+}
+
+func TestSafeRelativeDownloadPathUsesLocalSeparators(t *testing.T) {
+	s := newTestService(t)
+	got, err := s.safeRelativeDownloadPath("/2024/jan/a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("2024", "jan", "a.jpg"); got != want {
+		t.Errorf("safeRelativeDownloadPath = %q, want %q", got, want)
+	}
+	for _, unsafe := range []string{"../escape", "a/../../escape", ""} {
+		if _, err := s.safeRelativeDownloadPath(unsafe); err == nil {
+			t.Errorf("safeRelativeDownloadPath(%q) succeeded", unsafe)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestLocalFileNameWindows(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"report.txt", "report.txt"},
+		{"C:report.txt", "C_report.txt"},
+		{`a\b.txt`, "a_b.txt"},
+		{`what?<>|*".txt`, "what______.txt"},
+		{"trailing dot.", "trailing dot"},
+		{"trailing space ", "trailing space"},
+		{"tab\there", "tab_here"},
+		{"..", "_"},
+	}
+	for _, tt := range tests {
+		if got := localFileName(tt.in); got != tt.want {
+			t.Errorf("localFileName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSafeRelativeDownloadPathWindows(t *testing.T) {
+	s := newTestService(t)
+	tests := []struct {
+		in, want string
+	}{
+		{"2024/jan/a.jpg", `2024\jan\a.jpg`},
+		{"logs/12:00/out.txt", `logs\12_00\out.txt`},
+	}
+	for _, tt := range tests {
+		got, err := s.safeRelativeDownloadPath(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("safeRelativeDownloadPath(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, unsafe := range []string{`C:\Windows\x`, "C:/Windows/x", "D:relative", `\\server\share\x`, `..\escape`} {
+		if _, err := s.safeRelativeDownloadPath(unsafe); err == nil {
+			t.Errorf("safeRelativeDownloadPath(%q) succeeded", unsafe)
+		}
+	}
+}
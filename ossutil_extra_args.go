@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Flags walioss sets itself; passing them again would conflict or leak credentials into argv.
+var managedOssutilFlags = map[string]struct{}{
+	"-i":                  {},
+	"--access-key-id":     {},
+	"-k":                  {},
+	"--access-key-secret": {},
+	"--sts-token":         {},
+	"-e":                  {},
+	"--endpoint":          {},
+	"--region":            {},
+	"-f":                  {},
+	"--force":             {},
+	"-c":                  {},
+	"--config-file":       {},
+	"--proxy":             {},
+}
+
+func normalizeOssutilExtraArgs(args []string) []string {
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			out = append(out, arg)
+		}
+	}
+	return out
+}
+
+func validateOssutilExtraArgs(args []string) error {
+	for _, arg := range args {
+		name, _, _ := strings.Cut(arg, "=")
+		if _, ok := managedOssutilFlags[name]; ok {
+			return fmt.Errorf("ossutil argument %s is managed by walioss and cannot be set as an extra argument", name)
+		}
+	}
+	return nil
+}
+
+func (s *OSSService) setOssutilExtraArgs(args []string) {
+	s.ossutilExtraArgsMu.Lock()
+	s.ossutilExtraArgs = append([]string(nil), args...)
+	s.ossutilExtraArgsMu.Unlock()
+}
+
+// withOssutilExtraArgs appends the user's extra arguments after the built-in ones.
+func (s *OSSService) withOssutilExtraArgs(args []string) []string {
+	s.ossutilExtraArgsMu.RLock()
+	defer s.ossutilExtraArgsMu.RUnlock()
+	if len(s.ossutilExtraArgs) == 0 {
+		return args
+	}
+	out := make([]string, 0, len(args)+len(s.ossutilExtraArgs))
+	out = append(out, args...)
+	return append(out, s.ossutilExtraArgs...)
+}
+
+// ossutilCommandLine renders a command for display, with secrets redacted.
+func (s *OSSService) ossutilCommandLine(binary string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	for _, part := range append([]string{binary}, args...) {
+		if part == "" || strings.ContainsAny(part, " \t\"'") {
+			part = fmt.Sprintf("%q", part)
+		}
+		parts = append(parts, part)
+	}
+	return s.redact(strings.Join(parts, " "))
+}
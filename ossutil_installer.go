@@ -0,0 +1,356 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultOssutilVersion = "2.1.2"
+
+	// Release archives are published as <base>/<version>/ossutil-<version>-<os>-<arch>.zip with
+	// the SHA-256 of each archive next to it as <archive>.sha256.
+	ossutilReleaseBaseURL = "https://gosspublic.alicdn.com/ossutil/v2"
+
+	ossutilDownloadAttempts = 5
+	ossutilDownloadTimeout  = 10 * time.Minute
+	ossutilChecksumMaxBytes = 4 << 10
+)
+
+var reOssutilVersion = regexp.MustCompile(`^2\.\d+\.\d+(-[0-9A-Za-z.]+)?$`)
+
+// OssutilInstallProgress is emitted as "ossutil:install" while InstallOssutil runs.
+type OssutilInstallProgress struct {
+	Version    string `json:"version"`
+	Stage      string `json:"stage"` // "download" | "verify" | "extract" | "done"
+	DoneBytes  int64  `json:"doneBytes,omitempty"`
+	TotalBytes int64  `json:"totalBytes,omitempty"`
+	Attempt    int    `json:"attempt,omitempty"`
+}
+
+func ossutilReleasePlatform() (string, string, error) {
+	var osName string
+	switch goruntime.GOOS {
+	case "darwin":
+		osName = "mac"
+	case "linux", "windows":
+		osName = goruntime.GOOS
+	default:
+		return "", "", fmt.Errorf("no ossutil release for %s", goruntime.GOOS)
+	}
+	switch goruntime.GOARCH {
+	case "amd64", "arm64", "386":
+		return osName, goruntime.GOARCH, nil
+	}
+	return "", "", fmt.Errorf("no ossutil release for %s/%s", goruntime.GOOS, goruntime.GOARCH)
+}
+
+func ossutilReleaseURL(version string) (string, error) {
+	osName, arch, err := ossutilReleasePlatform()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/ossutil-%s-%s-%s.zip", ossutilReleaseBaseURL, version, version, osName, arch), nil
+}
+
+func ossutilBinaryName() string {
+	if goruntime.GOOS == "windows" {
+		return "ossutil.exe"
+	}
+	return "ossutil"
+}
+
+// ossutilInstallDir is where InstallOssutil puts the binary: a writable bin/ next to the
+// executable for portable installs, otherwise ~/.walioss/bin.
+func (s *OSSService) ossutilInstallDir() string {
+	if exePath, err := os.Executable(); err == nil {
+		portable := filepath.Join(filepath.Dir(exePath), "bin")
+		if info, err := os.Stat(portable); err == nil && info.IsDir() && ensureWritableDir(portable) == nil {
+			return portable
+		}
+	}
+	return filepath.Join(s.defaultConfigDir, "bin")
+}
+
+func (s *OSSService) emitOssutilInstall(progress OssutilInstallProgress) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx != nil {
+		runtime.EventsEmit(ctx, "ossutil:install", progress)
+	}
+}
+
+func (s *OSSService) installerHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy, ok := s.proxyFor(OSSConfig{}); ok {
+		if u, err := validateProxyURL(proxy.url); err == nil {
+			if proxy.user != "" {
+				u.User = url.UserPassword(proxy.user, proxy.password)
+			}
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+	return &http.Client{Transport: transport, Timeout: ossutilDownloadTimeout}
+}
+
+func fetchOssutilChecksum(client *http.Client, archiveURL string) (string, error) {
+	resp, err := client.Get(archiveURL + ".sha256")
+	if err != nil {
+		return "", fmt.Errorf("failed to download checksum: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download checksum: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, ossutilChecksumMaxBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to download checksum: %w", err)
+	}
+	// Accept both a bare digest and "sha256sum" output ("<digest>  <file>").
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", errors.New("published checksum is malformed")
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return "", errors.New("published checksum is malformed")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// downloadWithResume downloads archiveURL into partPath, continuing a previous partial download
+// with a Range request and retrying transient failures.
+func (s *OSSService) downloadWithResume(client *http.Client, archiveURL string, partPath string, progress OssutilInstallProgress) error {
+	var lastErr error
+	for attempt := 1; attempt <= ossutilDownloadAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(sdkRetryDelay(attempt - 1))
+		}
+		progress.Attempt = attempt
+		done, err := s.downloadAttempt(client, archiveURL, partPath, progress)
+		if done {
+			return nil
+		}
+		lastErr = err
+		s.logOperationEvent(logLevelWarn, "InstallOssutil", opOutcomeRetry, fmt.Sprintf("download attempt %d/%d failed", attempt, ossutilDownloadAttempts), err)
+	}
+	return fmt.Errorf("failed to download ossutil after %d attempts: %w", ossutilDownloadAttempts, lastErr)
+}
+
+func (s *OSSService) downloadAttempt(client *http.Client, archiveURL string, partPath string, progress OssutilInstallProgress) (bool, error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, archiveURL, nil)
+	if err != nil {
+		return false, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// The server ignored the range; start over.
+		offset = 0
+		flags |= os.O_TRUNC
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete (or bogus); the checksum decides.
+		return true, nil
+	case http.StatusNotFound:
+		return false, fmt.Errorf("ossutil %s is not published for this platform", progress.Version)
+	default:
+		return false, fmt.Errorf("download failed: %s", resp.Status)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0600)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	progress.Stage = "download"
+	progress.DoneBytes = offset
+	if resp.ContentLength > 0 {
+		progress.TotalBytes = offset + resp.ContentLength
+	}
+	s.emitOssutilInstall(progress)
+
+	buf := make([]byte, 64*1024)
+	lastEmit := time.Now()
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := file.Write(buf[:n]); err != nil {
+				return false, err
+			}
+			progress.DoneBytes += int64(n)
+			if time.Since(lastEmit) > 200*time.Millisecond {
+				s.emitOssutilInstall(progress)
+				lastEmit = time.Now()
+			}
+		}
+		if readErr == io.EOF {
+			s.emitOssutilInstall(progress)
+			return true, nil
+		}
+		if readErr != nil {
+			return false, readErr
+		}
+	}
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// extractOssutilBinary copies the ossutil executable out of the release archive into target,
+// going through a temporary file so a half-written binary never replaces a working one.
+func extractOssutilBinary(archivePath string, target string) error {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open ossutil archive: %w", err)
+	}
+	defer reader.Close()
+
+	binaryName := ossutilBinaryName()
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() || filepath.Base(entry.Name) != binaryName {
+			continue
+		}
+		src, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read ossutil archive: %w", err)
+		}
+		defer src.Close()
+
+		tmp, err := os.CreateTemp(filepath.Dir(target), ".ossutil-*")
+		if err != nil {
+			return err
+		}
+		tmpPath := tmp.Name()
+		defer os.Remove(tmpPath)
+
+		if _, err := io.Copy(tmp, src); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to extract ossutil: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmpPath, 0755); err != nil {
+			return err
+		}
+		return os.Rename(tmpPath, target)
+	}
+	return fmt.Errorf("ossutil archive does not contain %s", binaryName)
+}
+
+// InstallOssutil downloads the official ossutil release for this platform (the default version
+// when version is empty), verifies its published checksum, installs it and points the settings at
+// it. Progress is reported through "ossutil:install" events. It refuses to run when the settings
+// point at a custom ossutil elsewhere, so a user's own binary is never replaced.
+func (s *OSSService) InstallOssutil(version string) (_ string, err error) {
+	defer s.logOperation("InstallOssutil", "", "", time.Now(), &err)
+
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if version == "" {
+		version = defaultOssutilVersion
+	}
+	if !reOssutilVersion.MatchString(version) {
+		return "", fmt.Errorf("unsupported ossutil version %q: expected a 2.x release such as %s", version, defaultOssutilVersion)
+	}
+
+	dir := s.ossutilInstallDir()
+	target := filepath.Join(dir, ossutilBinaryName())
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return "", err
+	}
+	if custom := normalizeOssutilPath(settings.OssutilPath); custom != "" && custom != target {
+		return "", fmt.Errorf("ossutil path is set to %s; clear it in settings before installing ossutil", settings.OssutilPath)
+	}
+
+	archiveURL, err := ossutilReleaseURL(version)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	client := s.installerHTTPClient()
+	checksum, err := fetchOssutilChecksum(client, archiveURL)
+	if err != nil {
+		return "", err
+	}
+
+	// The partial download carries the version so a different version never resumes from it.
+	partPath := filepath.Join(dir, fmt.Sprintf(".ossutil-%s.zip.part", version))
+	progress := OssutilInstallProgress{Version: version}
+	if err := s.downloadWithResume(client, archiveURL, partPath, progress); err != nil {
+		return "", err
+	}
+
+	progress.Stage = "verify"
+	s.emitOssutilInstall(progress)
+	actual, err := fileSHA256(partPath)
+	if err != nil {
+		return "", err
+	}
+	if actual != checksum {
+		// A corrupt partial file would fail every resume, so start from scratch next time.
+		os.Remove(partPath)
+		return "", fmt.Errorf("ossutil download is corrupt: checksum %s, expected %s", actual, checksum)
+	}
+
+	progress.Stage = "extract"
+	s.emitOssutilInstall(progress)
+	if err := extractOssutilBinary(partPath, target); err != nil {
+		return "", err
+	}
+	os.Remove(partPath)
+
+	settings.OssutilPath = compactHomePath(target)
+	if err := s.SaveSettings(settings); err != nil {
+		return "", err
+	}
+
+	progress.Stage = "done"
+	s.emitOssutilInstall(progress)
+	return target, nil
+}
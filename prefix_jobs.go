@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	// deleteObjectsBatchSize is the most keys one DeleteObjects request accepts.
+	deleteObjectsBatchSize = 1000
+
+	defaultSearchLimit = 200
+	maxSearchLimit     = 1000
+
+	// prefixFailureDetails is how many sampled failures an error message names.
+	prefixFailureDetails = 3
+)
+
+// errNotDeleted marks a key missing from a DeleteObjects answer without an error of its own.
+var errNotDeleted = errors.New("not deleted")
+
+// deletePrefix deletes every object under prefix in batches and returns how many were deleted. It
+// can be canceled through CancelOperation; objects deleted until then stay deleted.
+func (s *OSSService) deletePrefix(config OSSConfig, bucketName string, prefix string) (int64, error) {
+	target := buildOssPath(bucketName, prefix)
+	ctx, done := s.startLongOperation(s.baseContext(), "rm -r "+target)
+	defer done()
+
+	result, err := runPrefixWalk(ctx, s.sdkPrefixLister(config, bucketName, prefix), prefixWalkWorkers, deleteObjectsBatchSize,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			return s.deleteObjectBatch(ctx, config, bucketName, batch)
+		})
+	deleted := result.Objects - result.Failed
+	switch {
+	case ctx.Err() != nil:
+		return deleted, fmt.Errorf("%w: %s", ErrOperationCanceled, s.msg(msgDeletePrefixCanceled, deleted))
+	case err != nil:
+		return deleted, s.errorf(msgListFolderFailed, err)
+	case result.Failed == 0:
+		return deleted, nil
+	}
+
+	if isWormRejection(result.FirstErr, "") {
+		if summary := s.wormRejectionSummary(config, bucketName); summary != "" {
+			return deleted, s.errorf(msgDeleteFailedSummary, summary, describeSDKError(result.FirstErr).Error())
+		}
+	}
+	return deleted, s.errorf(msgDeletePrefixPartial, deleted, result.Failed, prefixFailureText(result.Failures))
+}
+
+func (s *OSSService) deleteObjectBatch(ctx context.Context, config OSSConfig, bucketName string, batch []oss.ObjectProperties) []prefixObjectError {
+	keys := make([]string, len(batch))
+	for i, object := range batch {
+		keys[i] = object.Key
+	}
+	failAll := func(err error) []prefixObjectError {
+		failures := make([]prefixObjectError, len(keys))
+		for i, key := range keys {
+			failures[i] = prefixObjectError{key: key, err: err}
+		}
+		return failures
+	}
+
+	bucket, err := s.sdkBucket(config, bucketName)
+	if err != nil {
+		return failAll(err)
+	}
+	var res oss.DeleteObjectsResult
+	err = s.withSDKRetryContext(ctx, "DeleteObjects", func() error {
+		var err error
+		res, err = bucket.DeleteObjects(keys)
+		return err
+	})
+	if err != nil {
+		return failAll(describeSDKError(err))
+	}
+
+	deleted := make(map[string]struct{}, len(res.DeletedObjects))
+	for _, key := range res.DeletedObjects {
+		deleted[key] = struct{}{}
+	}
+	var failures []prefixObjectError
+	for _, key := range keys {
+		if _, ok := deleted[key]; !ok {
+			failures = append(failures, prefixObjectError{key: key, err: errNotDeleted})
+		}
+	}
+	return failures
+}
+
+func prefixFailureText(failures []PrefixWalkFailure) string {
+	parts := make([]string, 0, prefixFailureDetails)
+	for _, failure := range failures {
+		if len(parts) == prefixFailureDetails {
+			break
+		}
+		parts = append(parts, failure.Key+": "+failure.Error)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// PrefixStats totals the objects under a prefix.
+type PrefixStats struct {
+	Bucket         string                   `json:"bucket"`
+	Prefix         string                   `json:"prefix"`
+	Storage        int64                    `json:"storage"`
+	ObjectCount    int64                    `json:"objectCount"`
+	StorageClasses []BucketStorageClassStat `json:"storageClasses"`
+}
+
+// GetPrefixStats lists everything under prefix, or the whole bucket when prefix is empty, and
+// totals size and object count per storage class. Large prefixes take a while; the walk is listed
+// by GetPendingOperations and can be canceled.
+func (s *OSSService) GetPrefixStats(config OSSConfig, bucketName string, prefix string) (_ PrefixStats, err error) {
+	defer s.logOperation("GetPrefixStats", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return PrefixStats{}, err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+
+	ctx, done := s.startLongOperation(s.baseContext(), "du "+buildOssPath(bucketName, prefix))
+	defer done()
+
+	var (
+		classesMu sync.Mutex
+		classes   = make(map[string]*BucketStorageClassStat)
+	)
+	result, err := runPrefixWalk(ctx, s.sdkPrefixLister(config, bucketName, prefix), prefixWalkWorkers, prefixWalkPageSize,
+		func(_ context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			classesMu.Lock()
+			defer classesMu.Unlock()
+			for _, object := range batch {
+				class := object.StorageClass
+				if class == "" {
+					class = "Standard"
+				}
+				stat, ok := classes[class]
+				if !ok {
+					stat = &BucketStorageClassStat{StorageClass: class}
+					classes[class] = stat
+				}
+				stat.Storage += object.Size
+				stat.ObjectCount++
+			}
+			return nil
+		})
+	if err != nil {
+		if ctx.Err() != nil {
+			return PrefixStats{}, fmt.Errorf("%w: du %s", ErrOperationCanceled, buildOssPath(bucketName, prefix))
+		}
+		return PrefixStats{}, s.errorf(msgListFolderFailed, s.classifyAuthError(config, err))
+	}
+
+	stats := PrefixStats{
+		Bucket:         bucketName,
+		Prefix:         prefix,
+		Storage:        result.Bytes,
+		ObjectCount:    result.Objects,
+		StorageClasses: make([]BucketStorageClassStat, 0, len(classes)),
+	}
+	for _, stat := range classes {
+		stats.StorageClasses = append(stats.StorageClasses, *stat)
+	}
+	sort.Slice(stats.StorageClasses, func(i, j int) bool {
+		return stats.StorageClasses[i].StorageClass < stats.StorageClasses[j].StorageClass
+	})
+	return stats, nil
+}
+
+// SearchObjectsResult holds the matches of SearchObjects.
+type SearchObjectsResult struct {
+	Items []ObjectInfo `json:"items"`
+	// Scanned is how many keys were looked at.
+	Scanned int64 `json:"scanned"`
+	// Truncated is set when the search stopped at the limit; more keys may match.
+	Truncated bool `json:"truncated"`
+}
+
+// SearchObjects finds keys under prefix whose path below the prefix contains query, ignoring case.
+// It stops after limit matches (200 when limit is 0, at most 1000). Items are named by their path
+// below the prefix and sorted by it.
+func (s *OSSService) SearchObjects(config OSSConfig, bucketName string, prefix string, query string, limit int) (_ SearchObjectsResult, err error) {
+	defer s.logOperation("SearchObjects", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return SearchObjectsResult{}, err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+	needle := strings.ToLower(strings.TrimSpace(query))
+	if needle == "" {
+		return SearchObjectsResult{}, errors.New("search text is required")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	command := fmt.Sprintf("find %s %q", buildOssPath(bucketName, prefix), query)
+	ctx, done := s.startLongOperation(s.baseContext(), command)
+	defer done()
+	searchCtx, stopSearch := context.WithCancel(ctx)
+	defer stopSearch()
+
+	var (
+		matchesMu sync.Mutex
+		matches   = make([]ObjectInfo, 0, limit)
+		truncated bool
+	)
+	result, err := runPrefixWalk(searchCtx, s.sdkPrefixLister(config, bucketName, prefix), prefixWalkWorkers, prefixWalkPageSize,
+		func(_ context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			matchesMu.Lock()
+			defer matchesMu.Unlock()
+			for _, object := range batch {
+				name := strings.TrimPrefix(object.Key, prefix)
+				if !strings.Contains(strings.ToLower(name), needle) {
+					continue
+				}
+				if len(matches) == limit {
+					truncated = true
+					stopSearch()
+					return nil
+				}
+				item := ObjectInfo{
+					Name:         name,
+					Path:         buildOssPath(bucketName, object.Key),
+					Size:         object.Size,
+					Type:         "File",
+					LastModified: formatObjectLastModified(object.LastModified),
+					StorageClass: object.StorageClass,
+				}
+				if strings.HasSuffix(object.Key, "/") {
+					item.Type = "Folder"
+				}
+				matches = append(matches, item)
+			}
+			return nil
+		})
+	if err != nil && !truncated {
+		if ctx.Err() != nil {
+			return SearchObjectsResult{}, fmt.Errorf("%w: %s", ErrOperationCanceled, command)
+		}
+		return SearchObjectsResult{}, s.errorf(msgListFolderFailed, s.classifyAuthError(config, err))
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+	return SearchObjectsResult{Items: matches, Scanned: result.Objects, Truncated: truncated}, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	prefixWalkPageSize = 1000
+	// prefixWalkQueueSize bounds how far listing runs ahead of the workers; listing waits when the
+	// queue is full, so a slow consumer never makes keys pile up in memory.
+	prefixWalkQueueSize = 2 * prefixWalkPageSize
+	prefixWalkWorkers   = 4
+	// prefixWalkFailureSamples bounds the failures kept for the report; the rest are only counted.
+	prefixWalkFailureSamples = 20
+)
+
+// PrefixWalkFailure is an object a walk could not handle.
+type PrefixWalkFailure struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// prefixWalkResult totals a walk. Walks over millions of keys keep nothing per key: only these
+// counters and the first prefixWalkFailureSamples failures.
+type prefixWalkResult struct {
+	Objects  int64
+	Bytes    int64
+	Failed   int64
+	Failures []PrefixWalkFailure
+	// FirstErr is the error of the first failure, for callers that explain particular errors.
+	FirstErr error
+}
+
+// prefixPageLister returns the page of objects after marker.
+type prefixPageLister func(ctx context.Context, marker string) (oss.ListObjectsResult, error)
+
+// prefixBatchFunc handles a batch of listed objects and returns the objects it failed on with their
+// errors. The batch slice is reused once it returns.
+type prefixBatchFunc func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError
+
+type prefixObjectError struct {
+	key string
+	err error
+}
+
+// runPrefixWalk lists every object through list and hands them to workers in batches of up to
+// batchSize. One goroutine lists into a bounded queue and the workers drain it, so memory stays
+// flat however many keys the prefix holds. When ctx ends or listing fails, listing stops, the
+// workers finish the batch in hand and drop what is queued. The result counts what was handed to
+// act; the error is the listing error or ctx's.
+func runPrefixWalk(ctx context.Context, list prefixPageLister, workers int, batchSize int, act prefixBatchFunc) (prefixWalkResult, error) {
+	walkCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	queue := make(chan oss.ObjectProperties, prefixWalkQueueSize)
+	listDone := make(chan error, 1)
+	go func() {
+		defer close(queue)
+		marker := ""
+		for {
+			page, err := list(walkCtx, marker)
+			if err != nil {
+				listDone <- err
+				stop()
+				return
+			}
+			for _, object := range page.Objects {
+				select {
+				case queue <- object:
+				case <-walkCtx.Done():
+					listDone <- nil
+					return
+				}
+			}
+			if !page.IsTruncated || page.NextMarker == "" {
+				listDone <- nil
+				return
+			}
+			marker = page.NextMarker
+		}
+	}()
+
+	var (
+		objects, bytes, failed atomic.Int64
+		failuresMu             sync.Mutex
+		failures               []PrefixWalkFailure
+		firstErr               error
+	)
+	handle := func(batch []oss.ObjectProperties) {
+		for _, object := range batch {
+			bytes.Add(object.Size)
+		}
+		objects.Add(int64(len(batch)))
+		errs := act(walkCtx, batch)
+		if len(errs) == 0 {
+			return
+		}
+		failed.Add(int64(len(errs)))
+		failuresMu.Lock()
+		if firstErr == nil {
+			firstErr = errs[0].err
+		}
+		for _, e := range errs {
+			if len(failures) >= prefixWalkFailureSamples {
+				break
+			}
+			failures = append(failures, PrefixWalkFailure{Key: e.key, Error: e.err.Error()})
+		}
+		failuresMu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			batch := make([]oss.ObjectProperties, 0, batchSize)
+			for object := range queue {
+				if walkCtx.Err() != nil {
+					continue
+				}
+				batch = append(batch, object)
+				if len(batch) == batchSize {
+					handle(batch)
+					batch = batch[:0]
+				}
+			}
+			if len(batch) > 0 && walkCtx.Err() == nil {
+				handle(batch)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := prefixWalkResult{
+		Objects:  objects.Load(),
+		Bytes:    bytes.Load(),
+		Failed:   failed.Load(),
+		Failures: failures,
+		FirstErr: firstErr,
+	}
+	if err := <-listDone; err != nil {
+		return result, err
+	}
+	return result, ctx.Err()
+}
+
+// sdkPrefixLister lists everything under prefix, following the bucket to its region.
+func (s *OSSService) sdkPrefixLister(config OSSConfig, bucketName string, prefix string) prefixPageLister {
+	return func(ctx context.Context, marker string) (oss.ListObjectsResult, error) {
+		var lor oss.ListObjectsResult
+		err := s.withBucketRegion(config, bucketName, "ListObjects", func(bucket *oss.Bucket) error {
+			return s.withSDKRetryContext(ctx, "ListObjects", func() error {
+				var err error
+				lor, err = bucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker), oss.MaxKeys(prefixWalkPageSize))
+				return err
+			})
+		})
+		return lor, err
+	}
+}
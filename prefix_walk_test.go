@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// syntheticLister lists total generated keys a page at a time, building each page only when it is
+// asked for. listed counts the objects handed out.
+func syntheticLister(total int, listed *atomic.Int64) prefixPageLister {
+	return func(ctx context.Context, marker string) (oss.ListObjectsResult, error) {
+		start := 0
+		if marker != "" {
+			start, _ = strconv.Atoi(marker)
+		}
+		end := min(start+prefixWalkPageSize, total)
+		page := oss.ListObjectsResult{Objects: make([]oss.ObjectProperties, 0, end-start)}
+		for i := start; i < end; i++ {
+			page.Objects = append(page.Objects, oss.ObjectProperties{Key: fmt.Sprintf("data/%09d.bin", i), Size: int64(i % 7)})
+		}
+		if end < total {
+			page.IsTruncated, page.NextMarker = true, strconv.Itoa(end)
+		}
+		listed.Add(int64(end - start))
+		return page, ctx.Err()
+	}
+}
+
+func syntheticBytes(total int) int64 {
+	var sum int64
+	for i := range total {
+		sum += int64(i % 7)
+	}
+	return sum
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func TestPrefixWalkMillionKeysInFlatMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("lists a million keys")
+	}
+	const total = 1_000_000
+	var listed atomic.Int64
+	baseline := heapInUse()
+
+	var batches atomic.Int64
+	var peakMu sync.Mutex
+	var peak uint64
+	result, err := runPrefixWalk(context.Background(), syntheticLister(total, &listed), prefixWalkWorkers, 100,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			if batches.Add(1)%1000 == 0 {
+				heap := heapInUse()
+				peakMu.Lock()
+				peak = max(peak, heap)
+				peakMu.Unlock()
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Objects != total || result.Bytes != syntheticBytes(total) || result.Failed != 0 {
+		t.Fatalf("result = %+v, want %d objects of %d bytes", result, total, syntheticBytes(total))
+	}
+	if peak == 0 {
+		t.Fatal("memory was never sampled")
+	}
+	// Holding every key would take well over 100 MB; the walk holds a queue and a page.
+	if growth := int64(peak) - int64(baseline); growth > 16<<20 {
+		t.Fatalf("heap grew by %d MB while walking", growth>>20)
+	}
+}
+
+func TestPrefixWalkListingWaitsForWorkers(t *testing.T) {
+	var listed atomic.Int64
+	release := make(chan struct{})
+	var handled atomic.Int64
+	const workers, batchSize = 2, 10
+	done := make(chan prefixWalkResult, 1)
+	go func() {
+		result, _ := runPrefixWalk(context.Background(), syntheticLister(100_000, &listed), workers, batchSize,
+			func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+				<-release
+				handled.Add(int64(len(batch)))
+				return nil
+			})
+		done <- result
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	// With the workers stuck, listing can be ahead by the queue, one page and a batch per worker.
+	if got, limit := listed.Load(), int64(prefixWalkQueueSize+prefixWalkPageSize+workers*batchSize); got > limit {
+		t.Fatalf("listed %d objects ahead of stuck workers, want at most %d", got, limit)
+	}
+	close(release)
+	if result := <-done; result.Objects != 100_000 || handled.Load() != 100_000 {
+		t.Fatalf("result = %+v, handled %d", result, handled.Load())
+	}
+}
+
+func TestPrefixWalkCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var listed, handled atomic.Int64
+	var afterCancel atomic.Int64
+	var canceled atomic.Bool
+	result, err := runPrefixWalk(ctx, syntheticLister(1_000_000, &listed), prefixWalkWorkers, 50,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			if canceled.Load() {
+				afterCancel.Add(1)
+			}
+			if handled.Add(int64(len(batch))) >= 5000 && !canceled.Swap(true) {
+				cancel()
+			}
+			return nil
+		})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if result.Objects != handled.Load() {
+		t.Fatalf("result counts %d objects, act saw %d", result.Objects, handled.Load())
+	}
+	// Only batches already in hand when the walk was canceled may still run.
+	if afterCancel.Load() > prefixWalkWorkers {
+		t.Fatalf("%d batches started after cancel", afterCancel.Load())
+	}
+	if got := listed.Load(); got > 5000+prefixWalkQueueSize+2*prefixWalkPageSize {
+		t.Fatalf("listing went on to %d objects after cancel", got)
+	}
+}
+
+func TestPrefixWalkCanceledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var listed atomic.Int64
+	var calls atomic.Int64
+	result, err := runPrefixWalk(ctx, syntheticLister(10_000, &listed), prefixWalkWorkers, 50,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			calls.Add(1)
+			return nil
+		})
+	if !errors.Is(err, context.Canceled) || calls.Load() != 0 || result.Objects != 0 {
+		t.Fatalf("canceled walk = %+v, %v after %d batches", result, err, calls.Load())
+	}
+}
+
+func TestPrefixWalkListingError(t *testing.T) {
+	failure := errors.New("listing failed")
+	var listed atomic.Int64
+	pages := 0
+	list := func(ctx context.Context, marker string) (oss.ListObjectsResult, error) {
+		if pages++; pages == 3 {
+			return oss.ListObjectsResult{}, failure
+		}
+		return syntheticLister(10_000, &listed)(ctx, marker)
+	}
+	result, err := runPrefixWalk(context.Background(), list, prefixWalkWorkers, 50,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError { return nil })
+	if !errors.Is(err, failure) {
+		t.Fatalf("err = %v, want the listing error", err)
+	}
+	if result.Objects > 2*prefixWalkPageSize {
+		t.Fatalf("result counts %d objects from two pages", result.Objects)
+	}
+}
+
+func TestPrefixWalkAggregatesFailures(t *testing.T) {
+	const total = 5000
+	var listed atomic.Int64
+	result, err := runPrefixWalk(context.Background(), syntheticLister(total, &listed), prefixWalkWorkers, 64,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			var failures []prefixObjectError
+			for _, object := range batch {
+				if n, _ := strconv.Atoi(object.Key[len("data/") : len("data/")+9]); n%3 == 0 {
+					failures = append(failures, prefixObjectError{key: object.Key, err: fmt.Errorf("AccessDenied on %s", object.Key)})
+				}
+			}
+			return failures
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFailed := int64((total + 2) / 3)
+	if result.Objects != total || result.Failed != wantFailed {
+		t.Fatalf("objects %d, failed %d; want %d and %d", result.Objects, result.Failed, total, wantFailed)
+	}
+	if len(result.Failures) != prefixWalkFailureSamples {
+		t.Fatalf("kept %d failures, want %d", len(result.Failures), prefixWalkFailureSamples)
+	}
+	for _, failure := range result.Failures {
+		if failure.Key == "" || failure.Error != "AccessDenied on "+failure.Key {
+			t.Errorf("failure %+v", failure)
+		}
+	}
+	if result.FirstErr == nil {
+		t.Fatal("FirstErr is nil")
+	}
+}
+
+func TestPrefixWalkEmptyPrefix(t *testing.T) {
+	var listed atomic.Int64
+	calls := 0
+	result, err := runPrefixWalk(context.Background(), syntheticLister(0, &listed), prefixWalkWorkers, 50,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			calls++
+			return nil
+		})
+	if err != nil || calls != 0 || result.Objects != 0 || result.Failures != nil {
+		t.Fatalf("empty walk = %+v, %v after %d batches", result, err, calls)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// maxPrefixWatches is how many prefixes may be polled at once; each poll is a listing request.
+	maxPrefixWatches = 4
+
+	defaultPrefixWatchInterval = 15 * time.Second
+	minPrefixWatchInterval     = 5 * time.Second
+	maxPrefixWatchInterval     = time.Hour
+
+	// prefixWatchPageSize matches the file browser's default page, so a poll and a refresh of the
+	// same folder can share one listing request.
+	prefixWatchPageSize = 100
+)
+
+// PrefixChange is emitted as "prefix:changed" when a watched prefix lists differently than on the
+// previous poll. Names are relative to the prefix; folders end with "/". Only the first page of the
+// listing is compared, so Truncated says whether there may be more changes further down.
+type PrefixChange struct {
+	WatchID   string   `json:"watchId"`
+	Bucket    string   `json:"bucket"`
+	Prefix    string   `json:"prefix"`
+	Added     []string `json:"added,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Modified  []string `json:"modified,omitempty"`
+	Truncated bool     `json:"truncated"`
+}
+
+// prefixSnapshot maps each name of a listing page to what identifies its content.
+type prefixSnapshot struct {
+	entries   map[string]string
+	truncated bool
+}
+
+// WatchPrefix polls the first page of prefix every intervalSec seconds (0 means 15) and emits
+// prefix:changed when it differs from the last poll. Polling pauses while offline. It returns the
+// watch ID for StopWatch.
+func (s *OSSService) WatchPrefix(config OSSConfig, bucketName string, prefix string, intervalSec int) (_ string, err error) {
+	defer s.logOperation("WatchPrefix", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return "", err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+
+	interval := defaultPrefixWatchInterval
+	if intervalSec != 0 {
+		interval = time.Duration(intervalSec) * time.Second
+	}
+	if interval < minPrefixWatchInterval || interval > maxPrefixWatchInterval {
+		return "", s.errorf(msgWatchInterval, int(minPrefixWatchInterval.Seconds()), int(maxPrefixWatchInterval.Seconds()))
+	}
+
+	s.prefixWatchesMu.Lock()
+	defer s.prefixWatchesMu.Unlock()
+	if len(s.prefixWatches) >= maxPrefixWatches {
+		return "", s.errorf(msgWatchLimit, maxPrefixWatches)
+	}
+	ctx, cancel := context.WithCancel(s.baseContext())
+	id := fmt.Sprintf("watch-%d", atomic.AddUint64(&s.prefixWatchSeq, 1))
+	s.prefixWatches[id] = cancel
+
+	go func() {
+		defer func() {
+			s.prefixWatchesMu.Lock()
+			delete(s.prefixWatches, id)
+			s.prefixWatchesMu.Unlock()
+			cancel()
+		}()
+		s.runPrefixWatch(ctx, id, config, bucketName, prefix, interval)
+	}()
+	return id, nil
+}
+
+// StopWatch ends a watch started by WatchPrefix.
+func (s *OSSService) StopWatch(id string) error {
+	s.prefixWatchesMu.Lock()
+	cancel, ok := s.prefixWatches[id]
+	s.prefixWatchesMu.Unlock()
+	if !ok {
+		return fmt.Errorf("watch %s is not running", id)
+	}
+	cancel()
+	return nil
+}
+
+// stopPrefixWatches ends every watch when the app quits.
+func (s *OSSService) stopPrefixWatches() {
+	s.prefixWatchesMu.Lock()
+	defer s.prefixWatchesMu.Unlock()
+	for _, cancel := range s.prefixWatches {
+		cancel()
+	}
+}
+
+func (s *OSSService) runPrefixWatch(ctx context.Context, id string, config OSSConfig, bucketName string, prefix string, interval time.Duration) {
+	target := buildOssPath(bucketName, prefix)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous *prefixSnapshot
+	failing := false
+	for {
+		// While the endpoint is offline the poll waits for it instead of failing every tick.
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.onlineSignal(s.sdkProbeAddr(s.configForBucket(config, bucketName), endpointForData)):
+		}
+
+		lor, err := s.listObjectsShared(config, bucketName, prefix, "", prefixWatchPageSize)
+		switch {
+		case err != nil:
+			// One log line per outage, not one per tick.
+			if !failing {
+				s.logOperationEvent(logLevelWarn, "WatchPrefix", opOutcomeError, target, err)
+			}
+			failing = true
+		default:
+			failing = false
+			current := newPrefixSnapshot(prefix, lor)
+			if previous != nil {
+				if change, changed := diffPrefixSnapshots(*previous, current); changed {
+					change.WatchID, change.Bucket, change.Prefix = id, bucketName, prefix
+					s.emitPrefixChange(change)
+				}
+			}
+			previous = &current
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func newPrefixSnapshot(prefix string, lor oss.ListObjectsResult) prefixSnapshot {
+	snapshot := prefixSnapshot{entries: make(map[string]string, len(lor.Objects)+len(lor.CommonPrefixes)), truncated: lor.IsTruncated}
+	for _, commonPrefix := range lor.CommonPrefixes {
+		if name, isFolder, ok := listingChild(prefix, commonPrefix); ok && isFolder {
+			snapshot.entries[name+"/"] = ""
+		}
+	}
+	for _, object := range lor.Objects {
+		key := strings.TrimLeft(object.Key, "/")
+		if name, isFolder, ok := listingChild(prefix, key); ok && !isFolder {
+			snapshot.entries[name] = object.ETag + "\x00" + strconv.FormatInt(object.Size, 10)
+		}
+	}
+	return snapshot
+}
+
+// diffPrefixSnapshots tells what changed from previous to current.
+func diffPrefixSnapshots(previous prefixSnapshot, current prefixSnapshot) (PrefixChange, bool) {
+	change := PrefixChange{Truncated: current.truncated}
+	for name, fingerprint := range current.entries {
+		before, ok := previous.entries[name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, name)
+		case before != fingerprint:
+			change.Modified = append(change.Modified, name)
+		}
+	}
+	for name := range previous.entries {
+		if _, ok := current.entries[name]; !ok {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Strings(change.Modified)
+	changed := len(change.Added)+len(change.Removed)+len(change.Modified) > 0
+	return change, changed
+}
+
+func (s *OSSService) emitPrefixChange(change PrefixChange) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, "prefix:changed", change)
+}
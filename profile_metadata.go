@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxProfileNotesLen = 2000
+
+var profileColorNames = map[string]struct{}{
+	"red":    {},
+	"orange": {},
+	"yellow": {},
+	"green":  {},
+	"cyan":   {},
+	"blue":   {},
+	"purple": {},
+	"pink":   {},
+	"gray":   {},
+}
+
+var reProfileHexColor = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+
+// normalizeProfileMetadata trims and validates the display-only fields of a profile.
+func normalizeProfileMetadata(profile *OSSProfile) error {
+	color := strings.ToLower(strings.TrimSpace(profile.Color))
+	if color != "" {
+		if _, ok := profileColorNames[color]; !ok && !reProfileHexColor.MatchString(color) {
+			return fmt.Errorf("invalid profile color %q: use a name like red or blue, or a hex value like #e5484d", profile.Color)
+		}
+	}
+	profile.Color = color
+
+	notes := strings.TrimSpace(profile.Notes)
+	if utf8.RuneCountInString(notes) > maxProfileNotesLen {
+		return fmt.Errorf("profile notes are longer than %d characters", maxProfileNotesLen)
+	}
+	profile.Notes = notes
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// pinnedBucketsFor returns the buckets pinned to every saved profile that uses the same
+// account and endpoint as config.
+func (s *OSSService) pinnedBucketsFor(config OSSConfig) []string {
+	signature := transferConfigSignature(config)
+	state, err := s.loadAppState()
+	if err != nil || state.requireProfiles() != nil {
+		return nil
+	}
+
+	var out []string
+	seen := make(map[string]struct{})
+	for _, profile := range state.Profiles {
+		if transferConfigSignature(profile.Config) != signature {
+			continue
+		}
+		for _, bucket := range profile.PinnedBuckets {
+			if _, ok := seen[bucket]; ok {
+				continue
+			}
+			seen[bucket] = struct{}{}
+			out = append(out, bucket)
+		}
+	}
+	return out
+}
+
+// mergePinnedBuckets appends pinned buckets that the listing did not already contain.
+func mergePinnedBuckets(buckets []BucketInfo, pinned []string, region string) []BucketInfo {
+	listed := make(map[string]struct{}, len(buckets))
+	for _, bucket := range buckets {
+		listed[bucket.Name] = struct{}{}
+	}
+	for _, name := range pinned {
+		if _, ok := listed[name]; ok {
+			continue
+		}
+		buckets = append(buckets, BucketInfo{Name: name, Region: region})
+	}
+	return buckets
+}
+
+func ossutilAccessDenied(output []byte) bool {
+	return bytes.Contains(output, []byte("AccessDenied")) || bytes.Contains(output, []byte("StatusCode=403"))
+}
+
+// verifyBucketAccess checks that config can reach bucketName, first through GetBucketInfo and then
+// through a one-key listing, since bucket-scoped policies often grant only one of the two.
+func (s *OSSService) verifyBucketAccess(config OSSConfig, bucketName string) error {
+	management, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+	if _, err := management.GetBucketInfo(bucketName); err == nil {
+		return nil
+	}
+
+	client, err := s.sdkClientFromConfig(config)
+	if err != nil {
+		return err
+	}
+	bucket, err := client.Bucket(bucketName)
+	if err != nil {
+		return err
+	}
+	if _, err := bucket.ListObjects(oss.MaxKeys(1)); err != nil {
+		return fmt.Errorf("cannot access bucket %s: %w", bucketName, err)
+	}
+	return nil
+}
+
+// probePinnedBuckets returns the first bucket pinned for config that the resolved credentials can access.
+func (s *OSSService) probePinnedBuckets(config OSSConfig, resolved OSSConfig) (string, bool) {
+	for _, bucket := range s.pinnedBucketsFor(config) {
+		if s.verifyBucketAccess(resolved, bucket) == nil {
+			return bucket, true
+		}
+	}
+	return "", false
+}
+
+// AddPinnedBucket pins a bucket to a profile so it shows up even when the credentials are not
+// allowed to list buckets. Access to the bucket is verified before it is saved.
+func (s *OSSService) AddPinnedBucket(profileName string, bucketName string) (err error) {
+	defer s.logOperation("AddPinnedBucket", bucketName, "", time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return err
+	}
+
+	profile, err := s.GetProfile(profileName)
+	if err != nil {
+		return err
+	}
+	for _, pinned := range profile.PinnedBuckets {
+		if pinned == bucketName {
+			return nil
+		}
+	}
+	if err := s.verifyBucketAccess(profile.Config, bucketName); err != nil {
+		return err
+	}
+
+	return s.updatePinnedBuckets(profileName, func(pinned []string) []string {
+		for _, existing := range pinned {
+			if existing == bucketName {
+				return pinned
+			}
+		}
+		return append(pinned, bucketName)
+	})
+}
+
+// RemovePinnedBucket unpins a bucket from a profile.
+func (s *OSSService) RemovePinnedBucket(profileName string, bucketName string) error {
+	bucketName = strings.TrimSpace(bucketName)
+	if bucketName == "" {
+		return fmt.Errorf("bucket name is required")
+	}
+
+	return s.updatePinnedBuckets(profileName, func(pinned []string) []string {
+		out := pinned[:0]
+		for _, existing := range pinned {
+			if existing != bucketName {
+				out = append(out, existing)
+			}
+		}
+		return out
+	})
+}
+
+func (s *OSSService) updatePinnedBuckets(profileName string, update func([]string) []string) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
+
+	for i := range state.Profiles {
+		if state.Profiles[i].Name == profileName {
+			state.Profiles[i].PinnedBuckets = update(state.Profiles[i].PinnedBuckets)
+			return s.saveAppStateToDir(s.configDir, state)
+		}
+	}
+	return profileNotFound(profileName)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	credentialStorageKeychain  = "keychain"
+	credentialStoragePlaintext = "plaintext"
+
+	keychainService   = "walioss"
+	keychainRefPrefix = "keychain:"
+)
+
+// keychainGet reads a secret from the system keychain, which may prompt the user; tests replace it
+// to see when it is called.
+var keychainGet = keyring.Get
+
+func normalizeCredentialStorage(value string) string {
+	switch strings.TrimSpace(value) {
+	case credentialStoragePlaintext:
+		return credentialStoragePlaintext
+	default:
+		return credentialStorageKeychain
+	}
+}
+
+// keychainSecretRef is the reference written to config.json in place of the secret,
+// e.g. "keychain:walioss/prod".
+func keychainSecretRef(profileName string) string {
+	return keychainRefPrefix + keychainService + "/" + profileName
+}
+
+func keychainAccountFromRef(ref string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(ref), keychainRefPrefix+keychainService+"/")
+	if !ok || rest == "" {
+		return "", false
+	}
+	return rest, true
+}
+
+// storeProfileSecret moves the AccessKeySecret of profile into the system keychain and leaves a
+// reference behind. With plaintext storage the secret is written inline again and any keychain
+// entry is dropped. A profile without a secret keeps whatever reference it already had.
+func storeProfileSecret(profile *OSSProfile, storage string) error {
+	if normalizeCredentialStorage(storage) == credentialStoragePlaintext {
+		if profile.SecretRef == "" {
+			return nil
+		}
+		resolved, err := resolveProfileSecret(*profile)
+		if err != nil {
+			return err
+		}
+		deleteProfileSecret(resolved)
+		resolved.SecretRef = ""
+		*profile = resolved
+		return nil
+	}
+
+	secret := profile.Config.AccessKeySecret
+	if secret == "" {
+		return nil
+	}
+	if err := keyring.Set(keychainService, profile.Name, secret); err != nil {
+		return fmt.Errorf("failed to store secret in the system keychain (switch credential storage to plaintext if this machine has no keychain): %w", err)
+	}
+	profile.Config.AccessKeySecret = ""
+	profile.SecretRef = keychainSecretRef(profile.Name)
+	return nil
+}
+
+// resolveProfileSecret fills in the AccessKeySecret of a profile whose secret lives in the keychain.
+func resolveProfileSecret(profile OSSProfile) (OSSProfile, error) {
+	if profile.Config.AccessKeySecret != "" || profile.SecretRef == "" {
+		return profile, nil
+	}
+	account, ok := keychainAccountFromRef(profile.SecretRef)
+	if !ok {
+		return profile, fmt.Errorf("profile %s has an unsupported secret reference: %s", profile.Name, profile.SecretRef)
+	}
+	secret, err := keychainGet(keychainService, account)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return profile, fmt.Errorf("secret for profile %s is missing from the system keychain", profile.Name)
+		}
+		return profile, fmt.Errorf("failed to read secret for profile %s from the system keychain: %w", profile.Name, err)
+	}
+	profile.Config.AccessKeySecret = secret
+	return profile, nil
+}
+
+// resolveProfileSecretOrEmpty resolves the secret but keeps the profile usable (with an empty
+// secret) when the keychain is unavailable, so listings still render and the user can re-enter it.
+func resolveProfileSecretOrEmpty(profile OSSProfile) OSSProfile {
+	resolved, err := resolveProfileSecret(profile)
+	if err != nil {
+		return profile
+	}
+	return resolved
+}
+
+// maskedProfileSecret stands in for secrets in profiles sent to the frontend for display. SaveProfile
+// reads it back as "keep the stored value".
+const maskedProfileSecret = "********"
+
+// redactProfile replaces the secrets of profile with maskedProfileSecret so a listing never carries
+// them into the webview; GetProfile returns the real values when a connection is made.
+func redactProfile(profile OSSProfile) OSSProfile {
+	profile.HasSecret = profile.Config.AccessKeySecret != "" || profile.SecretRef != ""
+	if profile.HasSecret {
+		profile.Config.AccessKeySecret = maskedProfileSecret
+	}
+	if profile.Config.SecurityToken != "" {
+		profile.Config.SecurityToken = maskedProfileSecret
+	}
+	if profile.Config.ProxyPassword != "" {
+		profile.Config.ProxyPassword = maskedProfileSecret
+	}
+	profile.SecretRef = ""
+	return profile
+}
+
+func hasMaskedSecrets(profile OSSProfile) bool {
+	return profile.Config.AccessKeySecret == maskedProfileSecret ||
+		profile.Config.SecurityToken == maskedProfileSecret ||
+		profile.Config.ProxyPassword == maskedProfileSecret
+}
+
+// keepMaskedSecrets fills the masked secrets of profile in from existing, the stored profile with
+// its secret resolved.
+func keepMaskedSecrets(profile *OSSProfile, existing OSSProfile) {
+	if profile.Config.AccessKeySecret == maskedProfileSecret {
+		profile.Config.AccessKeySecret = existing.Config.AccessKeySecret
+		profile.SecretRef = ""
+	}
+	if profile.Config.SecurityToken == maskedProfileSecret {
+		profile.Config.SecurityToken = existing.Config.SecurityToken
+	}
+	if profile.Config.ProxyPassword == maskedProfileSecret {
+		profile.Config.ProxyPassword = existing.Config.ProxyPassword
+	}
+}
+
+// deleteProfileSecret removes the keychain entry of a profile, if any. Failures are ignored: a
+// stale entry is harmless and must not block deleting the profile.
+func deleteProfileSecret(profile OSSProfile) {
+	if account, ok := keychainAccountFromRef(profile.SecretRef); ok {
+		_ = keyring.Delete(keychainService, account)
+	}
+}
+
+// migrateProfileSecretsToKeychain moves plaintext secrets left over from older versions into the
+// keychain. It runs at most once per config directory and reports whether state changed.
+func (s *OSSService) migrateProfileSecretsToKeychain(dir string, state *appState) bool {
+	if normalizeCredentialStorage(state.Settings.CredentialStorage) != credentialStorageKeychain {
+		return false
+	}
+
+	s.keychainMigrationMu.Lock()
+	defer s.keychainMigrationMu.Unlock()
+	if s.keychainMigratedDir == dir {
+		return false
+	}
+	s.keychainMigratedDir = dir
+
+	changed := false
+	for i := range state.Profiles {
+		profile := state.Profiles[i]
+		if profile.Config.AccessKeySecret == "" {
+			continue
+		}
+		if err := storeProfileSecret(&profile, credentialStorageKeychain); err != nil {
+			// No usable keychain: leave the remaining secrets where they are.
+			break
+		}
+		state.Profiles[i] = profile
+		changed = true
+	}
+	return changed
+}
+
+// inlineProfiles returns the profiles state writes into config.json itself: none when they are
+// encrypted into profiles.enc.
+func inlineProfiles(state appState) []OSSProfile {
+	if state.ProfilesEncrypted {
+		return nil
+	}
+	return state.Profiles
+}
+
+// scrubRemovedSecrets clears every secret from a config.json backup that the profiles now
+// written to config.json no longer hold inline, such as one moved into the keychain or into
+// profiles.enc, so the backup does not keep it in plaintext. A scrubbed AccessKeySecret takes the
+// profile's keychain reference, if it has one. It reports whether anything was cleared; a backup it
+// cannot parse is returned unchanged.
+func scrubRemovedSecrets(backup []byte, inline []OSSProfile) ([]byte, bool) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(backup, &doc); err != nil || doc == nil {
+		return backup, false
+	}
+	var profiles []map[string]json.RawMessage
+	if err := json.Unmarshal(doc["profiles"], &profiles); err != nil {
+		return backup, false
+	}
+	byName := make(map[string]OSSProfile, len(inline))
+	for _, profile := range inline {
+		byName[profile.Name] = profile
+	}
+
+	changed := false
+	for _, profile := range profiles {
+		var name string
+		_ = json.Unmarshal(profile["name"], &name)
+		var config map[string]json.RawMessage
+		if err := json.Unmarshal(profile["config"], &config); err != nil || config == nil {
+			continue
+		}
+		current := byName[name]
+		scrubbed := false
+		for field, kept := range map[string]string{
+			"accessKeySecret": current.Config.AccessKeySecret,
+			"securityToken":   current.Config.SecurityToken,
+			"proxyPassword":   current.Config.ProxyPassword,
+		} {
+			var stored string
+			if err := json.Unmarshal(config[field], &stored); err != nil || stored == "" || kept != "" {
+				continue
+			}
+			config[field] = json.RawMessage(`""`)
+			if field == "accessKeySecret" && current.SecretRef != "" {
+				ref, _ := json.Marshal(current.SecretRef)
+				profile["secretRef"] = ref
+			}
+			scrubbed = true
+		}
+		if scrubbed {
+			profile["config"], _ = json.Marshal(config)
+			changed = true
+		}
+	}
+	if !changed {
+		return backup, false
+	}
+	doc["profiles"], _ = json.Marshal(profiles)
+	scrubbedBackup, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return backup, false
+	}
+	return scrubbedBackup, true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestScrubRemovedSecrets(t *testing.T) {
+	backup := []byte(`{
+  "schemaVersion": 2,
+  "profiles": [
+    {"name": "moved", "config": {"accessKeyId": "id", "accessKeySecret": "s1", "securityToken": "t1"}},
+    {"name": "inline", "config": {"accessKeyId": "id", "accessKeySecret": "s2"}},
+    {"name": "deleted", "config": {"accessKeyId": "id", "proxyPassword": "p3"}}
+  ]
+}`)
+	inline := []OSSProfile{
+		{Name: "moved", SecretRef: keychainSecretRef("moved")},
+		{Name: "inline", Config: OSSConfig{AccessKeySecret: "s2-new"}},
+	}
+
+	scrubbed, changed := scrubRemovedSecrets(backup, inline)
+	if !changed {
+		t.Fatal("nothing was scrubbed")
+	}
+	for _, secret := range []string{`"s1"`, `"t1"`, `"p3"`} {
+		if strings.Contains(string(scrubbed), secret) {
+			t.Errorf("scrubbed backup still holds %s:\n%s", secret, scrubbed)
+		}
+	}
+	var state appState
+	if err := json.Unmarshal(scrubbed, &state); err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Profiles[0].SecretRef; got != keychainSecretRef("moved") {
+		t.Errorf("secretRef = %q, want the keychain reference", got)
+	}
+	// A secret still stored inline stays in the backup, even when it was changed since.
+	if got := state.Profiles[1].Config.AccessKeySecret; got != "s2" {
+		t.Errorf("inline secret = %q, want s2", got)
+	}
+	if state.SchemaVersion != 2 || state.Profiles[0].Config.AccessKeyID != "id" {
+		t.Errorf("other fields were lost: %+v", state)
+	}
+
+	if _, changed := scrubRemovedSecrets(scrubbed, inline); changed {
+		t.Error("scrubbing twice changed the backup again")
+	}
+	if got, changed := scrubRemovedSecrets([]byte("not json"), nil); changed || string(got) != "not json" {
+		t.Error("an unparsable backup was changed")
+	}
+}
+
+func TestSaveAppStateDoesNotBackUpRemovedSecrets(t *testing.T) {
+	s := newTestService(t)
+	dir := t.TempDir()
+	plain := appState{Profiles: []OSSProfile{{Name: "p", Config: OSSConfig{AccessKeyID: "id", AccessKeySecret: "plaintext-secret"}}}}
+	if err := s.saveAppStateToDir(dir, plain); err != nil {
+		t.Fatal(err)
+	}
+
+	moved := appState{Profiles: []OSSProfile{{Name: "p", Config: OSSConfig{AccessKeyID: "id"}, SecretRef: keychainSecretRef("p")}}}
+	if err := s.saveAppStateToDir(dir, moved); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(s.stateBackupPathIn(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(backup), "plaintext-secret") {
+		t.Fatalf("config.json.bak keeps the secret moved to the keychain:\n%s", backup)
+	}
+}
+
+// countKeychainReads counts keychainGet calls for the rest of the test.
+func countKeychainReads(t *testing.T) *int {
+	t.Helper()
+	reads := new(int)
+	get := keychainGet
+	keychainGet = func(service string, account string) (string, error) {
+		*reads++
+		return get(service, account)
+	}
+	t.Cleanup(func() { keychainGet = get })
+	return reads
+}
+
+func TestProfileSummariesDoNotReadKeychain(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod", "staging")
+	reads := countKeychainReads(t)
+
+	summaries, err := s.ListProfileSummaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("summaries = %+v", summaries)
+	}
+	for _, p := range summaries {
+		if !p.HasSecret || p.Config.AccessKeySecret != maskedProfileSecret || p.SecretRef != "" {
+			t.Errorf("summary %+v is not masked", p)
+		}
+	}
+	if profile, err := s.GetDefaultProfile(); err != nil || profile == nil || profile.Name != "prod" || profile.Config.AccessKeySecret != maskedProfileSecret {
+		t.Fatalf("GetDefaultProfile = %+v, %v", profile, err)
+	}
+	if s.healthCheckProfiles().Status != healthStatusOK {
+		t.Fatal("the profile health check failed")
+	}
+	if *reads != 0 {
+		t.Fatalf("listing profiles read the keychain %d times", *reads)
+	}
+
+	profile, err := s.GetProfile("staging")
+	if err != nil || profile.Config.AccessKeySecret != "secret-staging" {
+		t.Fatalf("GetProfile = %+v, %v", profile, err)
+	}
+	if *reads != 1 {
+		t.Fatalf("GetProfile read the keychain %d times, want 1", *reads)
+	}
+}
+
+func TestProfileSummariesWithKeychainUnavailable(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod")
+	keyring.MockInitWithError(errors.New("keychain locked"))
+	t.Cleanup(keyring.MockInit)
+
+	summaries, err := s.ListProfileSummaries()
+	if err != nil || len(summaries) != 1 || !summaries[0].HasSecret {
+		t.Fatalf("summaries = %+v, %v", summaries, err)
+	}
+	if _, err := s.GetProfile("prod"); err == nil {
+		t.Fatal("GetProfile succeeded without the keychain")
+	}
+}
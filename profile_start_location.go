@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const bucketExistsCacheTTL = 5 * time.Minute
+
+// StartLocation is where the object browser opens after connecting with a profile.
+type StartLocation struct {
+	Bucket string `json:"bucket,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// FallbackToBucketList is set when there is no start location or it no longer exists; the UI
+	// should open the bucket list instead.
+	FallbackToBucketList bool   `json:"fallbackToBucketList"`
+	Reason               string `json:"reason,omitempty"`
+}
+
+type bucketExistsCacheEntry struct {
+	exists    bool
+	checkedAt time.Time
+}
+
+// bucketExists reports whether a bucket is still there. A bucket the credentials may not inspect
+// counts as existing, since bucket-scoped credentials are often denied GetBucketInfo.
+func (s *OSSService) bucketExists(config OSSConfig, bucketName string) (bool, error) {
+	cacheKey := bucketCacheKey(config, bucketName)
+	s.bucketExistsCacheMu.Lock()
+	entry, ok := s.bucketExistsCache[cacheKey]
+	s.bucketExistsCacheMu.Unlock()
+	if ok && time.Since(entry.checkedAt) < bucketExistsCacheTTL {
+		return entry.exists, nil
+	}
+
+	client, err := s.sdkManagementClientFromConfig(config)
+	if err != nil {
+		return false, err
+	}
+	exists := true
+	if _, err := client.GetBucketInfo(bucketName); err != nil {
+		switch {
+		case isOSSErrorCode(err, "NoSuchBucket"):
+			exists = false
+		case isAccessDenied(err):
+		default:
+			return false, fmt.Errorf("failed to get bucket info: %w", err)
+		}
+	}
+
+	s.bucketExistsCacheMu.Lock()
+	s.bucketExistsCache[cacheKey] = bucketExistsCacheEntry{exists: exists, checkedAt: time.Now()}
+	s.bucketExistsCacheMu.Unlock()
+	return exists, nil
+}
+
+// SetProfileStartLocation sets the bucket and prefix a profile opens in. An empty bucket clears it.
+func (s *OSSService) SetProfileStartLocation(name string, bucket string, prefix string) error {
+	bucket = strings.TrimSpace(bucket)
+	if bucket == "" {
+		prefix = ""
+	} else if err := validateBucketName(bucket); err != nil {
+		return err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
+
+	for i := range state.Profiles {
+		if state.Profiles[i].Name == name {
+			state.Profiles[i].DefaultBucket = bucket
+			state.Profiles[i].DefaultPrefix = prefix
+			return s.saveAppStateToDir(s.configDir, state)
+		}
+	}
+	return profileNotFound(name)
+}
+
+// GetStartLocation returns where a profile should open. The profile's start location wins over
+// the legacy Config.DefaultPath; either is verified to still exist before it is returned.
+func (s *OSSService) GetStartLocation(profileName string) (StartLocation, error) {
+	profile, err := s.GetProfile(profileName)
+	if err != nil {
+		return StartLocation{}, err
+	}
+
+	bucket, prefix := profile.DefaultBucket, profile.DefaultPrefix
+	if bucket == "" {
+		bucket, prefix, _ = parseDefaultPathLocation(profile.Config.DefaultPath)
+	}
+	if bucket == "" {
+		return StartLocation{FallbackToBucketList: true}, nil
+	}
+
+	exists, err := s.bucketExists(profile.Config, bucket)
+	if err != nil {
+		return StartLocation{}, err
+	}
+	if !exists {
+		return StartLocation{
+			FallbackToBucketList: true,
+			Reason:               fmt.Sprintf("Bucket %s no longer exists.", bucket),
+		}, nil
+	}
+	return StartLocation{Bucket: bucket, Prefix: prefix}, nil
+}
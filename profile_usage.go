@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// profileUsageFlushDelay debounces LastUsedAtMs writes: a burst of operations costs one save.
+const profileUsageFlushDelay = 30 * time.Second
+
+// markProfileUsed records that config just completed an operation successfully. The matching
+// profile's LastUsedAtMs is persisted later by flushProfileUsage.
+func (s *OSSService) markProfileUsed(config OSSConfig) {
+	signature := transferConfigSignature(config)
+	if strings.Trim(signature, "\x1f") == "" {
+		return
+	}
+
+	s.profileUsageMu.Lock()
+	defer s.profileUsageMu.Unlock()
+	s.profileUsagePending[signature] = time.Now().UnixMilli()
+	if s.profileUsageTimer == nil {
+		s.profileUsageTimer = time.AfterFunc(profileUsageFlushDelay, func() {
+			_ = s.flushProfileUsage()
+		})
+	}
+}
+
+// flushProfileUsage writes pending usage timestamps. It re-reads the state right before writing
+// and only touches LastUsedAtMs, so profile edits made in the meantime are kept.
+func (s *OSSService) flushProfileUsage() error {
+	s.profileUsageMu.Lock()
+	pending := s.profileUsagePending
+	s.profileUsagePending = make(map[string]int64)
+	s.profileUsageTimer = nil
+	s.profileUsageMu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if state.requireProfiles() != nil {
+		return nil
+	}
+
+	changed := false
+	for i := range state.Profiles {
+		usedAt, ok := pending[transferConfigSignature(state.Profiles[i].Config)]
+		if ok && usedAt > state.Profiles[i].LastUsedAtMs {
+			state.Profiles[i].LastUsedAtMs = usedAt
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return s.saveAppStateToDir(s.configDir, state)
+}
+
+// GetProfilesSorted returns the profiles ordered by "recent" (most recently used first, never-used
+// ones after them by name) or by "name". Secrets are masked as in ListProfileSummaries.
+func (s *OSSService) GetProfilesSorted(by string) ([]OSSProfile, error) {
+	profiles, err := s.ListProfileSummaries()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := func(i, j int) bool {
+		left := strings.ToLower(profiles[i].Name)
+		right := strings.ToLower(profiles[j].Name)
+		if left == right {
+			return profiles[i].Name < profiles[j].Name
+		}
+		return left < right
+	}
+
+	switch strings.TrimSpace(by) {
+	case "", "recent":
+		sort.SliceStable(profiles, func(i, j int) bool {
+			if profiles[i].LastUsedAtMs != profiles[j].LastUsedAtMs {
+				return profiles[i].LastUsedAtMs > profiles[j].LastUsedAtMs
+			}
+			return byName(i, j)
+		})
+	default:
+		sort.SliceStable(profiles, byName)
+	}
+	return profiles, nil
+}
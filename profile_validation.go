@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	profileAccessFull         = "full access"
+	profileAccessListOnly     = "list-only"
+	profileAccessBucketScoped = "bucket-scoped"
+	profileAccessInvalid      = "invalid"
+
+	// Key used for the read probe; a 404 proves read permission just as well as a real object.
+	profileValidationProbeKey = ".walioss-permission-probe"
+)
+
+// ProfileValidation summarises what the credentials of a profile are allowed to do.
+type ProfileValidation struct {
+	Access    string `json:"access"`
+	Message   string `json:"message"`
+	Error     string `json:"error,omitempty"`
+	Bucket    string `json:"bucket,omitempty"`
+	Endpoint  string `json:"endpoint"`
+	LatencyMs int64  `json:"latencyMs"`
+	// LatencyError is set when the endpoint could not be reached for the latency measurement.
+	LatencyError string `json:"latencyError,omitempty"`
+}
+
+func isInvalidCredentials(err error) bool {
+	return isOSSErrorCode(err, "InvalidAccessKeyId", "SignatureDoesNotMatch", "InvalidSecurityToken", "SecurityTokenExpired")
+}
+
+// measureEndpointLatency times a TCP connect to the management endpoint of config.
+func measureEndpointLatency(config OSSConfig) (string, int64, error) {
+	endpoint, err := sdkEndpointForConfig(config, endpointForManagement)
+	if err != nil {
+		return "", 0, err
+	}
+	host := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	start := time.Now()
+	if err := probeEndpointReachable(host); err != nil {
+		return host, 0, err
+	}
+	return host, time.Since(start).Milliseconds(), nil
+}
+
+// ValidateProfile runs a quick permission battery against a profile: a bucket listing, bucket
+// info on the first visible bucket and an object read probe. The result classifies the
+// credentials and includes the endpoint latency so internal and public endpoints can be compared.
+func (s *OSSService) ValidateProfile(profile OSSProfile) (_ ProfileValidation, err error) {
+	defer s.logOperation("ValidateProfile", "", "", time.Now(), &err)
+
+	profile, err = resolveProfileSecret(profile)
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+	config := profile.Config
+
+	var result ProfileValidation
+	host, latency, latencyErr := measureEndpointLatency(config)
+	result.Endpoint = host
+	result.LatencyMs = latency
+	if latencyErr != nil {
+		result.LatencyError = latencyErr.Error()
+	}
+
+	resolved, err := s.resolveCredentials(config)
+	if err != nil {
+		result.Access = profileAccessInvalid
+		result.Message = "Credentials could not be resolved."
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	client, err := s.sdkManagementClientFromConfig(resolved)
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+
+	bucketName := ""
+	listed, listErr := client.ListBuckets(oss.MaxKeys(1))
+	switch {
+	case listErr == nil:
+		if len(listed.Buckets) > 0 {
+			bucketName = listed.Buckets[0].Name
+		}
+	case isAccessDenied(listErr):
+		// Signed correctly but not allowed to list: fall back to the bucket the profile opens in,
+		// or the first pinned one.
+		defaultBucket, _, ok := parseDefaultPathLocation(config.DefaultPath)
+		if !ok && len(profile.PinnedBuckets) > 0 {
+			defaultBucket, ok = profile.PinnedBuckets[0], true
+		}
+		if !ok {
+			result.Access = profileAccessBucketScoped
+			result.Message = "The credentials are valid but cannot list buckets. Pin a bucket or set a default path to a bucket they can access."
+			result.Error = listErr.Error()
+			return result, nil
+		}
+		bucketName = defaultBucket
+	default:
+		result.Access = profileAccessInvalid
+		result.Message = "Listing buckets failed."
+		if isInvalidCredentials(listErr) {
+			result.Message = "The AccessKey ID or secret is wrong."
+		}
+		result.Error = listErr.Error()
+		return result, nil
+	}
+
+	result.Bucket = bucketName
+	if bucketName == "" {
+		result.Access = profileAccessFull
+		result.Message = "Buckets can be listed; the account has no buckets to probe further."
+		return result, nil
+	}
+
+	// Bucket-scoped policies often grant object access only, so for them the read probe decides.
+	if _, err := client.GetBucketInfo(bucketName); err != nil && listErr == nil {
+		if !isAccessDenied(err) {
+			return ProfileValidation{}, fmt.Errorf("failed to get bucket info: %w", err)
+		}
+		result.Access = profileAccessListOnly
+		result.Message = fmt.Sprintf("Buckets can be listed, but bucket %s cannot be inspected.", bucketName)
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	dataClient, err := s.sdkClientFromConfig(resolved)
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+	bucket, err := dataClient.Bucket(bucketName)
+	if err != nil {
+		return ProfileValidation{}, err
+	}
+	if _, err := bucket.GetObjectDetailedMeta(profileValidationProbeKey); err != nil && !isNotFound(err) {
+		if listErr != nil {
+			result.Access = profileAccessInvalid
+			result.Message = fmt.Sprintf("The credentials can neither list buckets nor read bucket %s.", bucketName)
+			result.Error = err.Error()
+			return result, nil
+		}
+		result.Access = profileAccessListOnly
+		result.Message = fmt.Sprintf("Bucket %s is visible, but its objects cannot be read.", bucketName)
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	if listErr != nil {
+		result.Access = profileAccessBucketScoped
+		result.Message = fmt.Sprintf("The credentials can only access bucket %s.", bucketName)
+		return result, nil
+	}
+	result.Access = profileAccessFull
+	result.Message = fmt.Sprintf("Buckets can be listed and objects in %s can be read.", bucketName)
+	return result, nil
+}
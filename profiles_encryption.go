@@ -0,0 +1,478 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptedProfilesFileName = "profiles.enc"
+	encryptedProfilesVersion  = 1
+
+	profilesScryptN     = 1 << 15
+	profilesScryptR     = 8
+	profilesScryptP     = 1
+	profilesKeyLen      = 32
+	profilesSaltLen     = 16
+	minPassphraseLength = 8
+
+	defaultProfileLockMinutes = 15
+	maxProfileLockMinutes     = 24 * 60
+)
+
+var (
+	// ErrProfilesLocked is returned by every profile read or write while an encrypted profiles file
+	// has not been unlocked (or the unlock timed out).
+	ErrProfilesLocked = errors.New("profiles are locked: unlock them with the master passphrase first")
+	// ErrWrongPassphrase means the passphrase does not match the one the file was encrypted with.
+	ErrWrongPassphrase = errors.New("incorrect master passphrase")
+	// ErrProfilesCorrupted means the encrypted file could not be read even though the passphrase is right.
+	ErrProfilesCorrupted = errors.New("encrypted profiles file is corrupted")
+)
+
+// encryptedProfilesFile is the on-disk format of profiles.enc.
+type encryptedProfilesFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Check      []byte `json:"check"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// profilesKey is the unlocked key material held in memory only.
+type profilesKey struct {
+	encKey []byte
+	check  []byte
+	salt   []byte
+	n      int
+	r      int
+	p      int
+}
+
+// ProfilesLockStatus tells the frontend whether it has to ask for the master passphrase.
+type ProfilesLockStatus struct {
+	Encrypted   bool  `json:"encrypted"`
+	Locked      bool  `json:"locked"`
+	ExpiresAtMs int64 `json:"expiresAtMs,omitempty"`
+}
+
+func (s *OSSService) encryptedProfilesPathIn(dir string) string {
+	return filepath.Join(dir, encryptedProfilesFileName)
+}
+
+// deriveProfilesKey derives the encryption key and a separate check value. The check lets us tell a
+// wrong passphrase apart from a damaged ciphertext, which AES-GCM alone cannot.
+func deriveProfilesKey(passphrase string, salt []byte, n, r, p int) (*profilesKey, error) {
+	derived, err := scrypt.Key([]byte(passphrase), salt, n, r, p, profilesKeyLen*2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	check := sha256.Sum256(derived[profilesKeyLen:])
+	return &profilesKey{
+		encKey: derived[:profilesKeyLen],
+		check:  check[:],
+		salt:   salt,
+		n:      n,
+		r:      r,
+		p:      p,
+	}, nil
+}
+
+func newProfilesKey(passphrase string) (*profilesKey, error) {
+	if len([]rune(passphrase)) < minPassphraseLength {
+		return nil, fmt.Errorf("master passphrase must be at least %d characters", minPassphraseLength)
+	}
+	salt := make([]byte, profilesSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return deriveProfilesKey(passphrase, salt, profilesScryptN, profilesScryptR, profilesScryptP)
+}
+
+func encryptProfiles(key *profilesKey, profiles []OSSProfile) ([]byte, error) {
+	if profiles == nil {
+		profiles = []OSSProfile{}
+	}
+	plaintext, err := json.Marshal(profiles)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key.encKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	file := encryptedProfilesFile{
+		Version:    encryptedProfilesVersion,
+		KDF:        "scrypt",
+		N:          key.n,
+		R:          key.r,
+		P:          key.p,
+		Salt:       key.salt,
+		Check:      key.check,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+func parseEncryptedProfilesFile(data []byte) (encryptedProfilesFile, error) {
+	var file encryptedProfilesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return encryptedProfilesFile{}, fmt.Errorf("%w: %v", ErrProfilesCorrupted, err)
+	}
+	if file.Version != encryptedProfilesVersion || file.KDF != "scrypt" {
+		return encryptedProfilesFile{}, fmt.Errorf("%w: unsupported format (version %d, kdf %q)", ErrProfilesCorrupted, file.Version, file.KDF)
+	}
+	if len(file.Salt) == 0 || len(file.Check) == 0 || len(file.Nonce) == 0 || file.N <= 1 || file.R <= 0 || file.P <= 0 {
+		return encryptedProfilesFile{}, fmt.Errorf("%w: missing key parameters", ErrProfilesCorrupted)
+	}
+	return file, nil
+}
+
+func decryptProfiles(key *profilesKey, file encryptedProfilesFile) ([]OSSProfile, error) {
+	if subtle.ConstantTimeCompare(key.check, file.Check) != 1 {
+		return nil, ErrWrongPassphrase
+	}
+
+	block, err := aes.NewCipher(key.encKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: bad nonce", ErrProfilesCorrupted)
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: authentication failed", ErrProfilesCorrupted)
+	}
+
+	var profiles []OSSProfile
+	if err := json.Unmarshal(plaintext, &profiles); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProfilesCorrupted, err)
+	}
+	if profiles == nil {
+		profiles = []OSSProfile{}
+	}
+	return profiles, nil
+}
+
+// unlockWithPassphrase verifies passphrase against the file and returns the key and profiles.
+func unlockWithPassphrase(passphrase string, data []byte) (*profilesKey, []OSSProfile, error) {
+	file, err := parseEncryptedProfilesFile(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := deriveProfilesKey(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, nil, err
+	}
+	profiles, err := decryptProfiles(key, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, profiles, nil
+}
+
+func normalizeProfileLockMinutes(minutes int) int {
+	if minutes <= 0 {
+		return defaultProfileLockMinutes
+	}
+	if minutes > maxProfileLockMinutes {
+		return maxProfileLockMinutes
+	}
+	return minutes
+}
+
+func (s *OSSService) setProfileLockMinutes(minutes int) {
+	s.profilesKeyMu.Lock()
+	s.profilesLockTimeout = time.Duration(normalizeProfileLockMinutes(minutes)) * time.Minute
+	s.profilesKeyMu.Unlock()
+}
+
+// profileLockTimeout must be called with profilesKeyMu held.
+func (s *OSSService) profileLockTimeout() time.Duration {
+	if s.profilesLockTimeout <= 0 {
+		return defaultProfileLockMinutes * time.Minute
+	}
+	return s.profilesLockTimeout
+}
+
+// activeProfilesKey returns the unlocked key, dropping it once the idle timeout has passed.
+// Every successful use extends the timeout.
+func (s *OSSService) activeProfilesKey() *profilesKey {
+	s.profilesKeyMu.Lock()
+	defer s.profilesKeyMu.Unlock()
+	if s.profilesKey == nil {
+		return nil
+	}
+	if time.Since(s.profilesKeyUsedAt) > s.profileLockTimeout() {
+		s.profilesKey = nil
+		return nil
+	}
+	s.profilesKeyUsedAt = time.Now()
+	return s.profilesKey
+}
+
+func (s *OSSService) setProfilesKey(key *profilesKey) {
+	s.profilesKeyMu.Lock()
+	s.profilesKey = key
+	s.profilesKeyUsedAt = time.Now()
+	s.profilesKeyMu.Unlock()
+}
+
+// loadEncryptedProfilesInto fills state.Profiles from profiles.enc when unlocked, or marks the state
+// as locked and keeps the raw file so saving settings does not lose it.
+func (s *OSSService) loadEncryptedProfilesInto(dir string, state *appState) error {
+	data, err := os.ReadFile(s.encryptedProfilesPathIn(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s is missing", ErrProfilesCorrupted, encryptedProfilesFileName)
+		}
+		return err
+	}
+
+	state.Profiles = []OSSProfile{}
+	key := s.activeProfilesKey()
+	if key == nil {
+		state.profilesLocked = true
+		state.encryptedProfiles = data
+		return nil
+	}
+
+	file, err := parseEncryptedProfilesFile(data)
+	if err != nil {
+		return err
+	}
+	profiles, err := decryptProfiles(key, file)
+	if errors.Is(err, ErrWrongPassphrase) {
+		// The file was re-encrypted elsewhere (e.g. another work dir); ask again.
+		s.setProfilesKey(nil)
+		state.profilesLocked = true
+		state.encryptedProfiles = data
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	state.Profiles = profiles
+	return nil
+}
+
+// encryptedProfilesData renders the profiles.enc part of state. A locked state writes back the
+// file it was loaded from untouched.
+func (s *OSSService) encryptedProfilesData(state appState) ([]byte, error) {
+	if state.profilesLocked {
+		if len(state.encryptedProfiles) == 0 {
+			return nil, ErrProfilesLocked
+		}
+		return state.encryptedProfiles, nil
+	}
+	key := s.activeProfilesKey()
+	if key == nil {
+		return nil, ErrProfilesLocked
+	}
+	encrypted, err := encryptProfiles(key, state.Profiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt profiles: %w", err)
+	}
+	return encrypted, nil
+}
+
+// UnlockProfiles decrypts the profiles file for the current session. The key stays in memory until
+// LockProfiles is called or the profiles go unused for the lock timeout.
+func (s *OSSService) UnlockProfiles(passphrase string) error {
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if !state.ProfilesEncrypted {
+		return fmt.Errorf("profiles are not encrypted")
+	}
+
+	data, err := os.ReadFile(s.encryptedProfilesPathIn(s.configDir))
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted profiles: %w", err)
+	}
+	key, _, err := unlockWithPassphrase(passphrase, data)
+	if err != nil {
+		return err
+	}
+	s.setProfilesKey(key)
+	return nil
+}
+
+// LockProfiles forgets the unlocked key immediately.
+func (s *OSSService) LockProfiles() {
+	s.setProfilesKey(nil)
+}
+
+// GetProfilesLockStatus reports whether profiles are encrypted and currently locked.
+func (s *OSSService) GetProfilesLockStatus() (ProfilesLockStatus, error) {
+	state, err := s.loadAppState()
+	if err != nil {
+		return ProfilesLockStatus{}, err
+	}
+	status := ProfilesLockStatus{Encrypted: state.ProfilesEncrypted, Locked: state.profilesLocked}
+	if state.ProfilesEncrypted && !state.profilesLocked {
+		s.profilesKeyMu.Lock()
+		status.ExpiresAtMs = s.profilesKeyUsedAt.Add(s.profileLockTimeout()).UnixMilli()
+		s.profilesKeyMu.Unlock()
+	}
+	return status, nil
+}
+
+// SetMasterPassphrase turns on encryption: profiles move from config.json into profiles.enc.
+// Secrets kept in the keychain are pulled back into the encrypted file.
+func (s *OSSService) SetMasterPassphrase(passphrase string) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if state.ProfilesEncrypted {
+		return fmt.Errorf("profiles are already encrypted; use ChangeMasterPassphrase instead")
+	}
+
+	key, err := newProfilesKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	// Keychain entries are only dropped once their secrets are saved in profiles.enc; a failed save
+	// leaves the profiles as they were.
+	var fromKeychain []OSSProfile
+	for i, profile := range state.Profiles {
+		if profile.SecretRef == "" {
+			continue
+		}
+		resolved, err := resolveProfileSecret(profile)
+		if err != nil {
+			return err
+		}
+		resolved.SecretRef = ""
+		state.Profiles[i] = resolved
+		fromKeychain = append(fromKeychain, profile)
+	}
+
+	s.setProfilesKey(key)
+	state.ProfilesEncrypted = true
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		s.setProfilesKey(nil)
+		return err
+	}
+	for _, profile := range fromKeychain {
+		deleteProfileSecret(profile)
+	}
+	return nil
+}
+
+// ChangeMasterPassphrase re-encrypts the profiles under a new passphrase.
+func (s *OSSService) ChangeMasterPassphrase(oldPassphrase string, newPassphrase string) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if !state.ProfilesEncrypted {
+		return fmt.Errorf("profiles are not encrypted")
+	}
+
+	data, err := os.ReadFile(s.encryptedProfilesPathIn(s.configDir))
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted profiles: %w", err)
+	}
+	_, profiles, err := unlockWithPassphrase(oldPassphrase, data)
+	if err != nil {
+		return err
+	}
+
+	key, err := newProfilesKey(newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	s.setProfilesKey(key)
+	state.Profiles = profiles
+	state.profilesLocked = false
+	state.encryptedProfiles = nil
+	return s.saveAppStateToDir(s.configDir, state)
+}
+
+// DisableEncryption decrypts the profiles back into config.json and removes profiles.enc.
+func (s *OSSService) DisableEncryption(passphrase string) error {
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if !state.ProfilesEncrypted {
+		return nil
+	}
+
+	encPath := s.encryptedProfilesPathIn(s.configDir)
+	data, err := os.ReadFile(encPath)
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted profiles: %w", err)
+	}
+	_, profiles, err := unlockWithPassphrase(passphrase, data)
+	if err != nil {
+		return err
+	}
+
+	state.ProfilesEncrypted = false
+	state.Profiles = profiles
+	state.profilesLocked = false
+	state.encryptedProfiles = nil
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		return err
+	}
+	s.setProfilesKey(nil)
+	if err := os.Remove(encPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove encrypted profiles: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+func keychainProfileState(t *testing.T) appState {
+	t.Helper()
+	if err := keyring.Set(keychainService, "prod", "keychain-secret"); err != nil {
+		t.Fatal(err)
+	}
+	return appState{Profiles: []OSSProfile{{
+		Name:      "prod",
+		Config:    OSSConfig{AccessKeyID: "id", Region: "cn-hangzhou"},
+		SecretRef: keychainSecretRef("prod"),
+	}}}
+}
+
+func TestSetMasterPassphraseKeepsKeychainWhenSaveFails(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	if err := s.saveAppStateToDir(s.configDir, keychainProfileState(t)); err != nil {
+		t.Fatal(err)
+	}
+	// A directory in the way of profiles.enc makes the save fail.
+	if err := os.MkdirAll(s.encryptedProfilesPathIn(s.configDir)+"/blocked", 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetMasterPassphrase(testPassphrase); err == nil {
+		t.Fatal("SetMasterPassphrase succeeded although profiles.enc could not be written")
+	}
+	if secret, err := keyring.Get(keychainService, "prod"); err != nil || secret != "keychain-secret" {
+		t.Fatalf("keychain entry after a failed save = %q, %v; want it kept", secret, err)
+	}
+}
+
+func TestSetMasterPassphraseMovesKeychainSecrets(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	if err := s.saveAppStateToDir(s.configDir, keychainProfileState(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetMasterPassphrase(testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keyring.Get(keychainService, "prod"); err != keyring.ErrNotFound {
+		t.Fatalf("keychain entry after encrypting: %v, want it deleted", err)
+	}
+	profiles, err := s.loadProfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || profiles[0].Config.AccessKeySecret != "keychain-secret" {
+		t.Fatalf("encrypted profiles = %+v, want the keychain secret inline", profiles)
+	}
+}
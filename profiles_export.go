@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const profilesExportSchemaVersion = 1
+
+// profilesExport is the file written by ExportProfiles.
+type profilesExport struct {
+	SchemaVersion   int          `json:"schemaVersion"`
+	ExportedAt      string       `json:"exportedAt"`
+	IncludesSecrets bool         `json:"includesSecrets"`
+	Profiles        []OSSProfile `json:"profiles"`
+}
+
+// ImportReport lists what ImportProfiles did with each profile name.
+type ImportReport struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+// ExportProfiles writes every profile to path. Secrets are blanked unless includeSecrets is set.
+func (s *OSSService) ExportProfiles(path string, includeSecrets bool) error {
+	path = strings.TrimSpace(expandLeadingTilde(path))
+	if path == "" {
+		return fmt.Errorf("export path is required")
+	}
+
+	profiles, err := s.loadProfiles()
+	if err != nil {
+		return err
+	}
+
+	exported := make([]OSSProfile, 0, len(profiles))
+	for _, p := range profiles {
+		if includeSecrets && p.Config.AccessKeySecret == "" && p.SecretRef != "" {
+			return fmt.Errorf("secret for profile %s could not be read from the system keychain", p.Name)
+		}
+		p.SecretRef = ""
+		if !includeSecrets {
+			p.Config.AccessKeySecret = ""
+			p.Config.SecurityToken = ""
+			p.Config.ProxyPassword = ""
+		}
+		exported = append(exported, p)
+	}
+
+	data, err := json.MarshalIndent(profilesExport{
+		SchemaVersion:   profilesExportSchemaVersion,
+		ExportedAt:      time.Now().UTC().Format(time.RFC3339),
+		IncludesSecrets: includeSecrets,
+		Profiles:        exported,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// decodeProfilesExport reads an export file. A bare profile array (the legacy profiles.json
+// layout) is accepted as schema version 0.
+func decodeProfilesExport(data []byte) (profilesExport, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var profiles []OSSProfile
+		if err := json.Unmarshal([]byte(trimmed), &profiles); err != nil {
+			return profilesExport{}, fmt.Errorf("invalid profiles file: %w", err)
+		}
+		return profilesExport{Profiles: profiles}, nil
+	}
+
+	var export profilesExport
+	if err := json.Unmarshal([]byte(trimmed), &export); err != nil {
+		return profilesExport{}, fmt.Errorf("invalid profiles file: %w", err)
+	}
+	if export.SchemaVersion > profilesExportSchemaVersion {
+		return profilesExport{}, fmt.Errorf("profiles file uses schema version %d; this version of walioss supports up to %d", export.SchemaVersion, profilesExportSchemaVersion)
+	}
+	return export, nil
+}
+
+// ImportProfiles merges the profiles in path into the saved ones. Existing names are skipped unless
+// overwrite is set; an imported profile without a secret keeps the existing one.
+func (s *OSSService) ImportProfiles(path string, overwrite bool) (ImportReport, error) {
+	path = strings.TrimSpace(expandLeadingTilde(path))
+	if path == "" {
+		return ImportReport{}, fmt.Errorf("import path is required")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("failed to read import file: %w", err)
+	}
+	export, err := decodeProfilesExport(data)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return ImportReport{}, err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return ImportReport{}, err
+	}
+
+	storage := state.Settings.CredentialStorage
+	if state.ProfilesEncrypted {
+		storage = credentialStoragePlaintext
+	}
+
+	report := ImportReport{Added: []string{}, Updated: []string{}, Skipped: []string{}}
+	indexByName := make(map[string]int, len(state.Profiles))
+	hasDefault := false
+	for i, p := range state.Profiles {
+		indexByName[p.Name] = i
+		hasDefault = hasDefault || p.IsDefault
+	}
+
+	seen := make(map[string]struct{}, len(export.Profiles))
+	for _, imported := range export.Profiles {
+		imported.Name = strings.TrimSpace(imported.Name)
+		if imported.Name == "" {
+			continue
+		}
+		if _, dup := seen[imported.Name]; dup {
+			report.Skipped = append(report.Skipped, imported.Name)
+			continue
+		}
+		seen[imported.Name] = struct{}{}
+		imported.SecretRef = ""
+		if err := normalizeProfileMetadata(&imported); err != nil {
+			report.Skipped = append(report.Skipped, imported.Name)
+			continue
+		}
+
+		idx, exists := indexByName[imported.Name]
+		if exists && !overwrite {
+			report.Skipped = append(report.Skipped, imported.Name)
+			continue
+		}
+
+		if exists {
+			existing := state.Profiles[idx]
+			if imported.Config.AccessKeySecret == "" {
+				imported.SecretRef = existing.SecretRef
+				imported.Config.AccessKeySecret = existing.Config.AccessKeySecret
+			}
+			// Keep the local default choice; an import never steals it.
+			imported.IsDefault = existing.IsDefault
+		} else if imported.IsDefault && hasDefault {
+			imported.IsDefault = false
+		}
+
+		if err := storeProfileSecret(&imported, storage); err != nil {
+			return ImportReport{}, err
+		}
+
+		if exists {
+			state.Profiles[idx] = imported
+			report.Updated = append(report.Updated, imported.Name)
+		} else {
+			indexByName[imported.Name] = len(state.Profiles)
+			state.Profiles = append(state.Profiles, imported)
+			report.Added = append(report.Added, imported.Name)
+			hasDefault = hasDefault || imported.IsDefault
+		}
+	}
+
+	if len(report.Added) == 0 && len(report.Updated) == 0 {
+		return report, nil
+	}
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		return ImportReport{}, err
+	}
+	return report, nil
+}
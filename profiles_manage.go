@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// ErrProfileNotFound is returned (wrapped with the name) when a named profile does not exist.
+var ErrProfileNotFound = errors.New("profile not found")
+
+func profileNotFound(name string) error {
+	return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+}
+
+// renameTransferHistoryLocked returns the history store with every record of oldName moved to
+// newName, plus the rebuilt in-memory index. Nothing is applied until the caller commits.
+func (s *OSSService) renameTransferHistoryLocked(oldName string, newName string) ([]byte, map[string]TransferUpdate, []string, error) {
+	s.ensureTransferHistoryLoadedLocked()
+
+	byID := make(map[string]TransferUpdate, len(s.transferHistoryByID))
+	order := make([]string, 0, len(s.transferHistoryOrder))
+	for _, storageID := range s.transferHistoryOrder {
+		item, ok := s.transferHistoryByID[storageID]
+		if !ok {
+			continue
+		}
+		if item.ProfileName == oldName {
+			item.ProfileName = newName
+			storageID = transferHistoryStorageID(newName, item.ID)
+		}
+		if _, exists := byID[storageID]; !exists {
+			order = append(order, storageID)
+		}
+		byID[storageID] = item
+	}
+
+	history := make([]TransferUpdate, 0, len(order))
+	for _, storageID := range order {
+		history = append(history, byID[storageID])
+	}
+	data, err := json.MarshalIndent(buildTransferHistoryStore(history), "", "  ")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return data, byID, order, nil
+}
+
+// RenameProfile renames a profile and everything keyed by its name: its keychain entry, source
+// profile references of role profiles, the transfer history and the usage counters. The default
+// flag is kept.
+func (s *OSSService) RenameProfile(oldName string, newName string) error {
+	oldName = strings.TrimSpace(oldName)
+	newName = strings.TrimSpace(newName)
+	if oldName == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if newName == "" {
+		return fmt.Errorf("new profile name is required")
+	}
+	if oldName == newName {
+		return nil
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
+
+	index := -1
+	for i, p := range state.Profiles {
+		if p.Name == newName {
+			return fmt.Errorf("a profile named %s already exists", newName)
+		}
+		if p.Name == oldName {
+			index = i
+		}
+	}
+	if index < 0 {
+		return profileNotFound(oldName)
+	}
+
+	renamed := state.Profiles[index]
+	oldSecretProfile := renamed
+	renamed.Name = newName
+	movedSecret := false
+	if renamed.SecretRef != "" {
+		resolved, err := resolveProfileSecret(renamed)
+		if err != nil {
+			return err
+		}
+		if err := keyring.Set(keychainService, newName, resolved.Config.AccessKeySecret); err != nil {
+			return fmt.Errorf("failed to move secret in the system keychain: %w", err)
+		}
+		renamed.SecretRef = keychainSecretRef(newName)
+		movedSecret = true
+	}
+	state.Profiles[index] = renamed
+
+	for i := range state.Profiles {
+		if state.Profiles[i].Config.SourceProfile == oldName {
+			state.Profiles[i].Config.SourceProfile = newName
+		}
+	}
+
+	dir := s.configDir
+	files, err := s.appStateSaveFiles(dir, state)
+	if err != nil {
+		if movedSecret {
+			_ = keyring.Delete(keychainService, newName)
+		}
+		return err
+	}
+
+	s.transferHistoryMu.Lock()
+	historyData, byID, order, err := s.renameTransferHistoryLocked(oldName, newName)
+	if err == nil {
+		files = append(files, pendingFile{path: s.transferHistoryPathIn(s.transferHistoryLoadedDir), data: historyData})
+		s.noteConfigWrite(files)
+		err = writeFilesTogether(files)
+	}
+	if err == nil {
+		s.transferHistoryByID = byID
+		s.transferHistoryOrder = order
+	}
+	s.transferHistoryMu.Unlock()
+
+	if err != nil {
+		if movedSecret {
+			_ = keyring.Delete(keychainService, newName)
+		}
+		return fmt.Errorf("failed to rename profile: %w", err)
+	}
+
+	if movedSecret {
+		deleteProfileSecret(oldSecretProfile)
+	}
+	_ = s.renameSessionState(oldName, newName)
+	_ = s.usage.renameProfile(oldName, newName)
+	return nil
+}
+
+// DuplicateProfile copies every field of sourceName into a new, non-default profile named newName.
+// A keychain-held secret is copied into its own keychain entry.
+func (s *OSSService) DuplicateProfile(sourceName string, newName string) (*OSSProfile, error) {
+	sourceName = strings.TrimSpace(sourceName)
+	newName = strings.TrimSpace(newName)
+	if sourceName == "" {
+		return nil, fmt.Errorf("profile name is required")
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new profile name is required")
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return nil, err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return nil, err
+	}
+
+	var source *OSSProfile
+	for i := range state.Profiles {
+		if state.Profiles[i].Name == newName {
+			return nil, fmt.Errorf("a profile named %s already exists", newName)
+		}
+		if state.Profiles[i].Name == sourceName {
+			source = &state.Profiles[i]
+		}
+	}
+	if source == nil {
+		return nil, profileNotFound(sourceName)
+	}
+
+	duplicate, err := resolveProfileSecret(*source)
+	if err != nil {
+		return nil, err
+	}
+	duplicate.Name = newName
+	duplicate.IsDefault = false
+	duplicate.SecretRef = ""
+
+	stored := duplicate
+	if source.SecretRef != "" && !state.ProfilesEncrypted {
+		if err := storeProfileSecret(&stored, credentialStorageKeychain); err != nil {
+			return nil, err
+		}
+	}
+
+	state.Profiles = append(state.Profiles, stored)
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		deleteProfileSecret(stored)
+		return nil, err
+	}
+	return &duplicate, nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// seedProfiles saves one keychain profile per name with a remembered location; the first is the
+// default.
+func seedProfiles(t *testing.T, s *OSSService, names ...string) {
+	t.Helper()
+	state := appState{}
+	for i, name := range names {
+		if err := keyring.Set(keychainService, name, "secret-"+name); err != nil {
+			t.Fatal(err)
+		}
+		state.Profiles = append(state.Profiles, OSSProfile{
+			Name:      name,
+			Config:    OSSConfig{AccessKeyID: "id", Region: "cn-hangzhou"},
+			SecretRef: keychainSecretRef(name),
+			IsDefault: i == 0,
+		})
+	}
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		t.Fatal(err)
+	}
+	s.sessionStateMu.Lock()
+	defer s.sessionStateMu.Unlock()
+	for _, name := range names {
+		s.sessionStatesLocked()[name] = sessionStateEntry{Bucket: "bucket", Prefix: "photos/"}
+	}
+	if err := s.writeSessionStatesLocked(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func storedProfileNames(t *testing.T, s *OSSService) (names []string, defaultName string) {
+	t.Helper()
+	state, err := s.loadAppState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range state.Profiles {
+		names = append(names, p.Name)
+		if p.IsDefault {
+			if defaultName != "" {
+				t.Errorf("both %s and %s are default", defaultName, p.Name)
+			}
+			defaultName = p.Name
+		}
+	}
+	return names, defaultName
+}
+
+func requireProfileCleanedUp(t *testing.T, s *OSSService, name string) {
+	t.Helper()
+	if _, err := keyring.Get(keychainService, name); err != keyring.ErrNotFound {
+		t.Errorf("keychain entry of %s: %v, want it deleted", name, err)
+	}
+	s.sessionStateMu.Lock()
+	_, ok := s.sessionStatesLocked()[name]
+	s.sessionStateMu.Unlock()
+	if ok {
+		t.Errorf("the saved location of %s was kept", name)
+	}
+}
+
+func TestDeleteProfileUnknownName(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod", "staging")
+
+	_, err := s.DeleteProfile("missing")
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("DeleteProfile(missing) = %v, want ErrProfileNotFound", err)
+	}
+	names, defaultName := storedProfileNames(t, s)
+	if len(names) != 2 || defaultName != "prod" {
+		t.Errorf("profiles after a failed delete = %v, default %q", names, defaultName)
+	}
+	if secret, err := keyring.Get(keychainService, "prod"); err != nil || secret != "secret-prod" {
+		t.Errorf("keychain entry of prod = %q, %v; want it kept", secret, err)
+	}
+}
+
+func TestDeleteProfileOnlyProfile(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod")
+
+	result, err := s.DeleteProfile("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NewDefault != "" {
+		t.Errorf("NewDefault = %q with no profile left", result.NewDefault)
+	}
+	if names, _ := storedProfileNames(t, s); len(names) != 0 {
+		t.Errorf("profiles after deleting the only one = %v", names)
+	}
+	requireProfileCleanedUp(t, s, "prod")
+}
+
+func TestDeleteProfilePromotesNewDefault(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod", "staging", "dev")
+
+	result, err := s.DeleteProfile("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NewDefault != "staging" {
+		t.Errorf("NewDefault = %q, want the first remaining profile", result.NewDefault)
+	}
+	names, defaultName := storedProfileNames(t, s)
+	if len(names) != 2 || defaultName != "staging" {
+		t.Errorf("profiles = %v, default %q; want staging as default", names, defaultName)
+	}
+	requireProfileCleanedUp(t, s, "prod")
+	if secret, err := keyring.Get(keychainService, "staging"); err != nil || secret != "secret-staging" {
+		t.Errorf("keychain entry of staging = %q, %v; want it kept", secret, err)
+	}
+}
+
+func TestDeleteProfileKeepsDefault(t *testing.T) {
+	keyring.MockInit()
+	s := newTestService(t)
+	seedProfiles(t, s, "prod", "staging")
+
+	result, err := s.DeleteProfile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NewDefault != "" {
+		t.Errorf("NewDefault = %q although the default was kept", result.NewDefault)
+	}
+	if _, defaultName := storedProfileNames(t, s); defaultName != "prod" {
+		t.Errorf("default = %q, want prod", defaultName)
+	}
+	requireProfileCleanedUp(t, s, "staging")
+}
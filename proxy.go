@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// proxySettings is the proxy an operation goes through.
+type proxySettings struct {
+	url      string
+	user     string
+	password string
+}
+
+func validateProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", raw, err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme must be http, https, socks5 or socks5h", raw)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: host is required", raw)
+	}
+	return u, nil
+}
+
+// proxyFor returns the proxy a config should use: its own proxy when it has one, otherwise the
+// global proxy from the settings.
+func (s *OSSService) proxyFor(config OSSConfig) (proxySettings, bool) {
+	if proxyURL := strings.TrimSpace(config.ProxyURL); proxyURL != "" {
+		return proxySettings{url: proxyURL, user: config.ProxyUser, password: config.ProxyPassword}, true
+	}
+	s.globalProxyMu.RLock()
+	defer s.globalProxyMu.RUnlock()
+	return s.globalProxy, s.globalProxy.url != ""
+}
+
+func (s *OSSService) setGlobalProxy(settings AppSettings) {
+	s.rememberSecrets(OSSConfig{ProxyPassword: settings.ProxyPassword})
+	proxy := proxySettings{url: settings.ProxyURL, user: settings.ProxyUser, password: settings.ProxyPassword}
+	s.globalProxyMu.Lock()
+	changed := s.globalProxy != proxy
+	s.globalProxy = proxy
+	s.globalProxyMu.Unlock()
+	if changed {
+		s.sdkClients.clear()
+	}
+}
+
+func (p proxySettings) sdkOption() oss.ClientOption {
+	if p.user != "" {
+		return oss.AuthProxy(p.url, p.user, p.password)
+	}
+	return oss.Proxy(p.url)
+}
+
+// env returns proxy variables for an ossutil child process. NO_PROXY is cleared so the user's
+// shell settings cannot route around the proxy.
+func (p proxySettings) env() []string {
+	proxyURL := p.url
+	if u, err := p.authURL(); err == nil {
+		proxyURL = u.String()
+	}
+	return []string{
+		"HTTPS_PROXY=" + proxyURL,
+		"HTTP_PROXY=" + proxyURL,
+		"ALL_PROXY=" + proxyURL,
+		"NO_PROXY=",
+	}
+}
+
+// authURL is the proxy URL with the credentials, if any, embedded.
+func (p proxySettings) authURL() (*url.URL, error) {
+	u, err := url.Parse(p.url)
+	if err != nil {
+		return nil, err
+	}
+	if p.user != "" {
+		u.User = url.UserPassword(p.user, p.password)
+	}
+	return u, nil
+}
+
+// hostPort is the proxy server address, with the scheme's default port when none is given.
+func (p proxySettings) hostPort() (string, error) {
+	u, err := validateProxyURL(p.url)
+	if err != nil {
+		return "", err
+	}
+	if u.Port() != "" {
+		return u.Host, nil
+	}
+	port := "80"
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		port = "443"
+	case "socks5", "socks5h":
+		port = "1080"
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// isProxyError reports whether err came from connecting to the proxy rather than from OSS.
+func isProxyError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "proxyconnect") || strings.Contains(msg, "socks connect")
+}
+
+// checkProxyReachable connects to the proxy server itself so TestConnection can tell a dead proxy
+// apart from an OSS failure.
+func checkProxyReachable(p proxySettings) error {
+	hostPort, err := p.hostPort()
+	if err != nil {
+		return err
+	}
+	if err := probeEndpointReachable(hostPort); err != nil {
+		return fmt.Errorf("proxy %s is unreachable: %w", hostPort, err)
+	}
+	return nil
+}
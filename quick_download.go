@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const maxDownloadNameSuffix = 1000
+
+// ensureWritableDir creates dir if needed and checks that files can be created in it.
+// ErrDirectoryNotWritable is wrapped by errors about directories files cannot be written to.
+var ErrDirectoryNotWritable = errors.New("directory is not writable")
+
+// checkDirWritable proves dir accepts new files by creating and removing a probe file.
+func checkDirWritable(dir string) error {
+	probe, err := os.CreateTemp(dir, ".walioss-write-check-*")
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrDirectoryNotWritable, dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func ensureWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("download directory %s cannot be created: %w", dir, err)
+	}
+	return checkDirWritable(dir)
+}
+
+// localFileName turns the last segment of an object key into a file name the local OS accepts.
+// Windows additionally forbids <>:"|?* and control characters.
+func localFileName(name string) string {
+	illegal := "/\x00"
+	if goruntime.GOOS == "windows" {
+		illegal = "/\\<>:\"|?*\x00"
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(illegal, r) || (goruntime.GOOS == "windows" && r < 0x20) {
+			return '_'
+		}
+		return r
+	}, name)
+	if goruntime.GOOS == "windows" {
+		// Explorer cannot handle names ending in a dot or space.
+		name = strings.TrimRight(name, ". ")
+	}
+	if name == "" || name == "." || name == ".." {
+		name = "_"
+	}
+	return name
+}
+
+// downloadTargetPath returns where a download of key to localPath ends up: localPath itself, or
+// the key's base name inside it when localPath is an existing directory. isDir reports the latter.
+func downloadTargetPath(localPath string, key string) (target string, isDir bool, err error) {
+	info, err := os.Stat(localPath)
+	if err != nil || !info.IsDir() {
+		return localPath, false, nil
+	}
+	base := path.Base(strings.TrimSuffix(key, "/"))
+	if base == "." || base == "/" || base == "" {
+		return "", true, fmt.Errorf("cannot derive a file name from key %q", key)
+	}
+	target = filepath.Join(localPath, localFileName(base))
+	if !strings.HasSuffix(key, "/") {
+		// Downloads overwrite files, but never a directory of the same name.
+		if info, err := os.Stat(target); err == nil && info.IsDir() {
+			return "", true, fmt.Errorf("%s is a directory", target)
+		}
+	}
+	return target, true, nil
+}
+
+// reserveDownloadPath picks dir/name, or "name (1).ext", "name (2).ext", ... when taken, and
+// creates an empty placeholder so concurrent quick downloads never pick the same file.
+func reserveDownloadPath(dir string, name string) (string, error) {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 0; i < maxDownloadNameSuffix; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+		}
+		localPath := filepath.Join(dir, candidate)
+		f, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			f.Close()
+			return localPath, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("failed to create %s: %w", localPath, err)
+		}
+	}
+	return "", fmt.Errorf("too many files named %s in %s", name, dir)
+}
+
+func (s *OSSService) defaultDownloadDir() string {
+	state, err := s.loadAppState()
+	if err != nil {
+		return ""
+	}
+	return expandLeadingTilde(state.Settings.DefaultDownloadDir)
+}
+
+// QuickDownload downloads an object into the default download directory without asking for a
+// location. An existing file of the same name is kept and the download gets a "name (1).ext" name.
+func (s *OSSService) QuickDownload(config OSSConfig, bucket string, key string) (_ string, err error) {
+	defer s.logOperation("QuickDownload", bucket, key, time.Now(), &err)
+
+	bucket = normalizeTransferBucket(bucket)
+	key = normalizeTransferObjectKey(key)
+	if bucket == "" {
+		return "", errors.New("bucket is empty")
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", errors.New("object key must point to a file")
+	}
+	if err := validateObjectRef(bucket, key); err != nil {
+		return "", err
+	}
+
+	dir := s.defaultDownloadDir()
+	if dir == "" {
+		return "", errors.New("no default download directory is set")
+	}
+	if err := ensureWritableDir(dir); err != nil {
+		return "", err
+	}
+
+	var totalBytes int64
+	_ = s.withBucketRegion(config, bucket, "GetObjectMeta", func(bkt *oss.Bucket) error {
+		props, err := bkt.GetObjectDetailedMeta(key)
+		if err == nil {
+			totalBytes, _ = strconv.ParseInt(props.Get("Content-Length"), 10, 64)
+		}
+		return err
+	})
+
+	localPath, err := reserveDownloadPath(dir, path.Base(key))
+	if err != nil {
+		return "", err
+	}
+	id, err := s.EnqueueDownload(config, bucket, key, localPath, totalBytes)
+	if err != nil {
+		os.Remove(localPath)
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrReadOnlyProfile is returned (wrapped with the profile name) when a mutation is attempted
+// with the config of a read-only profile.
+var ErrReadOnlyProfile = errors.New("profile is read-only")
+
+// requireWritable refuses mutations for configs that belong to a read-only profile. It runs
+// before any network call; listing, downloads, previews and URL signing do not call it. It fails
+// closed: while the profiles cannot be read, e.g. because they are locked, nothing is changed.
+func (s *OSSService) requireWritable(config OSSConfig) error {
+	identity := credentialIdentity(config)
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	if err := state.requireProfiles(); err != nil {
+		return err
+	}
+	for _, profile := range state.Profiles {
+		if profile.ReadOnly && credentialIdentity(profile.Config) == identity {
+			return fmt.Errorf("%w: %s", ErrReadOnlyProfile, profile.Name)
+		}
+	}
+	return nil
+}
+
+// credentialIdentity is who a config acts as: the credential provider, the AccessKey ID and the
+// role it assumes. Region, endpoint and default path do not matter, so a read-only profile stays
+// read-only however its endpoint is spelled.
+func credentialIdentity(config OSSConfig) string {
+	provider, err := normalizeCredentialProvider(config.Provider)
+	if err != nil {
+		provider = strings.ToLower(strings.TrimSpace(config.Provider))
+	}
+	return strings.Join([]string{
+		provider,
+		strings.TrimSpace(config.AccessKeyID),
+		strings.TrimSpace(config.ECSRoleName),
+		strings.TrimSpace(config.RoleArn),
+		strings.TrimSpace(config.SourceProfile),
+	}, "\x1f")
+}
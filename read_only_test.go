@@ -0,0 +1,251 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// mutatingMethods must refuse configs of read-only profiles before any network call.
+var mutatingMethods = []string{
+	"CreateFile",
+	"CreateFolder",
+	"DeleteBucketCORS",
+	"DeleteBucketEncryption",
+	"DeleteBucketInventory",
+	"DeleteBucketPolicy",
+	"DeleteBucketTagging",
+	"DeleteObject",
+	"EnqueueUpload",
+	"EnqueueUploadPaths",
+	"EnqueueUploadRoots",
+	"GenerateIndexPage",
+	"MoveObject",
+	"PutObjectText",
+	"RestorePrefix",
+	"SetBucketCORS",
+	"SetBucketEncryption",
+	"SetBucketLifecycle",
+	"SetBucketPolicy",
+	"SetBucketReferer",
+	"SetBucketTagging",
+	"SetBucketTransferAcceleration",
+	"SetBucketVersioning",
+	"UploadFile",
+}
+
+// readMethods take a config but change nothing on OSS, so read-only profiles may call them.
+var readMethods = []string{
+	"CheckUploadNameCollisions",
+	"DownloadFile",
+	"EnqueueDownload",
+	"EnqueueDownloadFolder",
+	"EnqueueDownloadsFromPaths",
+	"EstimateStorageCost",
+	"GetBucketCORS",
+	"GetBucketEncryption",
+	"GetBucketInfo",
+	"GetBucketLifecycle",
+	"GetBucketPolicy",
+	"GetBucketReferer",
+	"GetBucketReplication",
+	"GetBucketReplicationProgress",
+	"GetBucketStat",
+	"GetBucketTagging",
+	"GetBucketTransferAcceleration",
+	"GetBucketVersioning",
+	"GetBucketWorm",
+	"GetCachedBucketStats",
+	"GetObjectText",
+	"GetObjectURL",
+	"GetPrefixRestoreStatus",
+	"GetPrefixStats",
+	"ListBucketCname",
+	"ListBucketInventory",
+	"ListBuckets",
+	"ListObjects",
+	"ListObjectsPage",
+	"PresignObject",
+	"QuickDownload",
+	"RefreshBucketStats",
+	"RequestConfirmationToken",
+	"RunIntegrityScan",
+	"SearchObjects",
+	"TestConnection",
+	"TestConnectionForBucket",
+	"WatchPrefix",
+}
+
+// readOnlyFixture saves config as the read-only profile "support" and counts the requests the fake
+// OSS server receives.
+func readOnlyFixture(t *testing.T) (*OSSService, OSSConfig, *atomic.Int64) {
+	t.Helper()
+	keyring.MockInit()
+	s := newTestService(t)
+	var requests atomic.Int64
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeListing(w, []string{"a.txt"}, nil)
+	})
+	profile := OSSProfile{Name: "support", Config: config, ReadOnly: true, IsDefault: true}
+	if err := s.saveAppStateToDir(s.configDir, appState{Profiles: []OSSProfile{profile}}); err != nil {
+		t.Fatal(err)
+	}
+	return s, config, &requests
+}
+
+// testArg returns a plausible argument of type typ: a valid bucket name and key for strings, one
+// element for string slices and the zero value otherwise.
+func testArg(typ reflect.Type) reflect.Value {
+	switch {
+	case typ.Kind() == reflect.String:
+		return reflect.ValueOf("walioss-test").Convert(typ)
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String:
+		return reflect.ValueOf([]string{"walioss-test"}).Convert(typ)
+	case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Int64:
+		return reflect.ValueOf(1).Convert(typ)
+	}
+	return reflect.Zero(typ)
+}
+
+func TestEveryConfigMethodIsClassified(t *testing.T) {
+	classified := make(map[string]bool)
+	for _, name := range append(append([]string{}, mutatingMethods...), readMethods...) {
+		if classified[name] {
+			t.Errorf("%s is listed twice", name)
+		}
+		classified[name] = true
+	}
+
+	configType := reflect.TypeOf(OSSConfig{})
+	serviceType := reflect.TypeOf(&OSSService{})
+	var missing []string
+	for i := range serviceType.NumMethod() {
+		method := serviceType.Method(i)
+		// In[0] is the receiver.
+		if method.Type.NumIn() < 2 || method.Type.In(1) != configType {
+			continue
+		}
+		if !classified[method.Name] {
+			missing = append(missing, method.Name)
+		}
+		delete(classified, method.Name)
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		t.Errorf("%s takes a config but is neither in mutatingMethods nor in readMethods; mutations must call requireWritable", name)
+	}
+	for name := range classified {
+		t.Errorf("%s is listed but is no OSSService method taking a config", name)
+	}
+}
+
+func TestMutationsRefuseReadOnlyProfile(t *testing.T) {
+	s, config, requests := readOnlyFixture(t)
+	service := reflect.ValueOf(s)
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+
+	for _, name := range mutatingMethods {
+		method := service.MethodByName(name)
+		if !method.IsValid() {
+			t.Errorf("OSSService has no method %s", name)
+			continue
+		}
+		typ := method.Type()
+		if typ.NumOut() == 0 || typ.Out(typ.NumOut()-1) != errorType {
+			t.Errorf("%s does not return an error", name)
+			continue
+		}
+		args := []reflect.Value{reflect.ValueOf(config)}
+		for i := 1; i < typ.NumIn(); i++ {
+			args = append(args, testArg(typ.In(i)))
+		}
+		out := method.Call(args)
+		err, _ := out[len(out)-1].Interface().(error)
+		if !errors.Is(err, ErrReadOnlyProfile) {
+			t.Errorf("%s with a read-only profile = %v, want ErrReadOnlyProfile", name, err)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("refused mutations sent %d requests", n)
+	}
+}
+
+func TestReadOnlyProfileCanStillList(t *testing.T) {
+	s, config, requests := readOnlyFixture(t)
+
+	page, err := s.ListObjectsPage(config, "bucket", "", "", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || requests.Load() == 0 {
+		t.Errorf("listing with a read-only profile = %+v after %d requests", page, requests.Load())
+	}
+	if err := s.requireWritable(config); !errors.Is(err, ErrReadOnlyProfile) {
+		t.Errorf("requireWritable = %v, want ErrReadOnlyProfile", err)
+	}
+}
+
+func TestRequireWritableAllowsOtherProfiles(t *testing.T) {
+	s, config, _ := readOnlyFixture(t)
+
+	other := config
+	other.AccessKeyID = "LTAI-other"
+	if err := s.requireWritable(other); err != nil {
+		t.Errorf("requireWritable for a config of no read-only profile = %v", err)
+	}
+
+	state, err := s.loadAppState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Profiles[0].ReadOnly = false
+	if err := s.saveAppStateToDir(s.configDir, state); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.requireWritable(config); err != nil {
+		t.Errorf("requireWritable after clearing ReadOnly = %v", err)
+	}
+}
+
+func TestRequireWritableMatchesCredentialIdentity(t *testing.T) {
+	s, config, _ := readOnlyFixture(t)
+
+	respelled := config
+	respelled.Endpoint = " " + strings.ToUpper(config.Endpoint) + "/"
+	respelled.Region = ""
+	respelled.DefaultPath = "oss://elsewhere/logs/"
+	if err := s.requireWritable(respelled); !errors.Is(err, ErrReadOnlyProfile) {
+		t.Errorf("requireWritable with another endpoint spelling and default path = %v, want ErrReadOnlyProfile", err)
+	}
+
+	assumed := config
+	assumed.RoleArn = "acs:ram::123456789012:role/admin"
+	if err := s.requireWritable(assumed); err != nil {
+		t.Errorf("requireWritable for the same key assuming a role = %v, want nil", err)
+	}
+}
+
+func TestRequireWritableFailsClosedWhileProfilesLocked(t *testing.T) {
+	s, config, requests := readOnlyFixture(t)
+	if err := s.SetMasterPassphrase(testPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	s.LockProfiles()
+
+	if err := s.requireWritable(config); !errors.Is(err, ErrProfilesLocked) {
+		t.Fatalf("requireWritable while locked = %v, want ErrProfilesLocked", err)
+	}
+	if err := s.DeleteObject(config, "bucket", "a.txt", ""); !errors.Is(err, ErrProfilesLocked) {
+		t.Errorf("DeleteObject while locked = %v, want ErrProfilesLocked", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("refused mutation sent %d requests", n)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+const redactedValue = "******"
+
+// Secrets shorter than this are not scrubbed by value; replacing them would mangle unrelated text.
+const minRedactableSecretLen = 6
+
+// Values following these flags are scrubbed even when the secret itself is unknown,
+// e.g. when an exec error echoes a command line.
+var reSecretFlagValue = regexp.MustCompile(`(--(?:access-key-secret|access-key-id|sts-token))(=|\s+)("[^"]*"|'[^']*'|\S+)`)
+
+func redactSecretFlags(text string) string {
+	return reSecretFlagValue.ReplaceAllString(text, "${1}${2}"+redactedValue)
+}
+
+// rememberSecrets registers credential values so redact can scrub them from any later message.
+func (s *OSSService) rememberSecrets(config OSSConfig) {
+	values := []string{config.AccessKeySecret, config.SecurityToken, config.ProxyPassword}
+
+	s.knownSecretsMu.Lock()
+	defer s.knownSecretsMu.Unlock()
+	for _, value := range values {
+		if len(value) >= minRedactableSecretLen {
+			s.knownSecrets[value] = struct{}{}
+		}
+	}
+}
+
+// redact removes every known secret and every credential flag value from text. All error and
+// message strings built from ossutil output or exec errors pass through here before they leave
+// OSSService.
+func (s *OSSService) redact(text string) string {
+	if text == "" {
+		return text
+	}
+	s.knownSecretsMu.Lock()
+	for secret := range s.knownSecrets {
+		if strings.Contains(text, secret) {
+			text = strings.ReplaceAll(text, secret, redactedValue)
+		}
+	}
+	s.knownSecretsMu.Unlock()
+	return redactSecretFlags(text)
+}
+
+// redactedError is an error whose message has been scrubbed but which still unwraps to the original.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+func (s *OSSService) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := s.redact(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
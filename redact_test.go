@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testSecret = "s3cr3t-value-that-must-not-leak"
+
+func TestRedactSecretFlags(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ossutil cp --access-key-secret abc123 x", "ossutil cp --access-key-secret ****** x"},
+		{"--access-key-id=LTAI5t --sts-token 'a b'", "--access-key-id=****** --sts-token ******"},
+		{`--sts-token "quoted token" rest`, "--sts-token ****** rest"},
+		{"no flags here", "no flags here"},
+	}
+	for _, tt := range tests {
+		if got := redactSecretFlags(tt.in); got != tt.want {
+			t.Errorf("redactSecretFlags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactKnownSecrets(t *testing.T) {
+	s := newTestService(t)
+	s.rememberSecrets(OSSConfig{AccessKeySecret: testSecret, SecurityToken: "sts-token-value", ProxyPassword: "short"})
+
+	got := s.redact("denied for " + testSecret + " with sts-token-value via short")
+	if strings.Contains(got, testSecret) || strings.Contains(got, "sts-token-value") {
+		t.Errorf("redact left a secret in %q", got)
+	}
+	// Secrets too short to scrub safely are left alone.
+	if !strings.Contains(got, "short") {
+		t.Errorf("redact mangled unrelated text: %q", got)
+	}
+
+	wrapped := errors.New("exec failed: " + testSecret)
+	err := s.redactError(wrapped)
+	if strings.Contains(err.Error(), testSecret) || !errors.Is(err, wrapped) {
+		t.Errorf("redactError = %v; want a scrubbed error wrapping the original", err)
+	}
+}
+
+func TestOssutilOutputOrErrorRedactsFlags(t *testing.T) {
+	output := []byte("Error: command ossutil ls --access-key-id LTAI5t --access-key-secret " + testSecret)
+	if got := ossutilOutputOrError(nil, output); strings.Contains(got, testSecret) || strings.Contains(got, "LTAI5t") {
+		t.Errorf("ossutilOutputOrError(output) = %q", got)
+	}
+	err := errors.New("exec: ossutil --access-key-secret=" + testSecret + ": permission denied")
+	if got := ossutilOutputOrError(err, nil); strings.Contains(got, testSecret) {
+		t.Errorf("ossutilOutputOrError(err) = %q", got)
+	}
+}
+
+func TestTransferUpdatesNeverCarrySecrets(t *testing.T) {
+	s := newTestService(t)
+	// The fake echoes the secret it is given, and a flag value, the way a failing ossutil may.
+	fakeOssutil(t, s, `echo "OK size: 10 $OSS_ACCESS_KEY_SECRET"
+echo "Error: signature mismatch for $OSS_ACCESS_KEY_SECRET (--sts-token $OSS_SESSION_TOKEN)" >&2
+exit 1`)
+
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: testSecret, SecurityToken: "sts-" + testSecret, Region: "cn-hangzhou"}
+	var updates []TransferUpdate
+	update := TransferUpdate{ID: "t1", ProfileName: "prod", Type: TransferTypeDownload, Bucket: "bucket", Key: "key", LocalPath: t.TempDir() + "/key", TotalBytes: 100}
+	s.runTransfer(config, update, func(u TransferUpdate) { updates = append(updates, u) })
+
+	if len(updates) == 0 || updates[len(updates)-1].Status != TransferStatusError {
+		t.Fatalf("updates = %+v, want the transfer to fail", updates)
+	}
+	if !strings.Contains(updates[len(updates)-1].Message, "signature mismatch") {
+		t.Errorf("final message %q lost the ossutil error", updates[len(updates)-1].Message)
+	}
+	for _, u := range updates {
+		encoded, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(encoded), testSecret) {
+			t.Errorf("emitted update carries the secret: %s", encoded)
+		}
+	}
+}
+
+func TestTestConnectionRedactsSecrets(t *testing.T) {
+	s := newTestService(t)
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>SignatureDoesNotMatch</Code>` +
+			`<Message>The request signature we calculated does not match, key ` + testSecret + `</Message>` +
+			`<RequestId>1</RequestId><HostId>host</HostId></Error>`))
+	})
+	config.AccessKeySecret = testSecret
+
+	result := s.TestConnection(config)
+	if result.Success {
+		t.Fatal("TestConnection succeeded against a server rejecting the signature")
+	}
+	if strings.Contains(result.Message, testSecret) {
+		t.Errorf("TestConnection message carries the secret: %q", result.Message)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"strings"
+)
+
+const (
+	regionCloudPublic  = "public"
+	regionCloudFinance = "finance"
+	regionCloudGov     = "gov"
+)
+
+// regionSuggestDistance is the most edits a typed region may be away from a known one to be
+// suggested in its place: "cn-hangzou" is one edit from "cn-hangzhou".
+const regionSuggestDistance = 2
+
+// RegionInfo describes a region of the built-in catalog and the endpoints it is reached through.
+type RegionInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Cloud is "public", or "finance" and "gov" for the isolated clouds, whose endpoints do not
+	// follow the oss-<region>.aliyuncs.com pattern.
+	Cloud            string `json:"cloud"`
+	PublicEndpoint   string `json:"publicEndpoint"`
+	InternalEndpoint string `json:"internalEndpoint"`
+	// DualStackEndpoint and AccelerateEndpoint are empty where the region has none.
+	DualStackEndpoint  string `json:"dualStackEndpoint,omitempty"`
+	AccelerateEndpoint string `json:"accelerateEndpoint,omitempty"`
+}
+
+// RegionCheck is the verdict on a typed region ID. Unknown regions are still accepted: the catalog
+// lags behind new regions.
+type RegionCheck struct {
+	Known bool `json:"known"`
+	// Suggestion is the closest known region ID when the typed one looks like a typo of it.
+	Suggestion string `json:"suggestion,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// publicRegion lists a region of the public cloud; its endpoints follow the generic pattern.
+func publicRegion(id string, name string, mainland bool) RegionInfo {
+	accelerate := accelerateEndpoint
+	if !mainland {
+		accelerate = "oss-accelerate-overseas.aliyuncs.com"
+	}
+	return RegionInfo{
+		ID:                 id,
+		Name:               name,
+		Cloud:              regionCloudPublic,
+		PublicEndpoint:     genericServiceEndpoint(id, false, false),
+		InternalEndpoint:   genericServiceEndpoint(id, true, false),
+		DualStackEndpoint:  genericServiceEndpoint(id, false, true),
+		AccelerateEndpoint: accelerate,
+	}
+}
+
+var knownRegions = []RegionInfo{
+	publicRegion("cn-hangzhou", "China (Hangzhou)", true),
+	publicRegion("cn-shanghai", "China (Shanghai)", true),
+	publicRegion("cn-nanjing", "China (Nanjing - Local Region)", true),
+	publicRegion("cn-fuzhou", "China (Fuzhou - Local Region)", true),
+	publicRegion("cn-wuhan-lr", "China (Wuhan - Local Region)", true),
+	publicRegion("cn-qingdao", "China (Qingdao)", true),
+	publicRegion("cn-beijing", "China (Beijing)", true),
+	publicRegion("cn-zhangjiakou", "China (Zhangjiakou)", true),
+	publicRegion("cn-huhehaote", "China (Hohhot)", true),
+	publicRegion("cn-wulanchabu", "China (Ulanqab)", true),
+	publicRegion("cn-shenzhen", "China (Shenzhen)", true),
+	publicRegion("cn-heyuan", "China (Heyuan)", true),
+	publicRegion("cn-guangzhou", "China (Guangzhou)", true),
+	publicRegion("cn-chengdu", "China (Chengdu)", true),
+	publicRegion("cn-hongkong", "China (Hong Kong)", false),
+	publicRegion("ap-northeast-1", "Japan (Tokyo)", false),
+	publicRegion("ap-northeast-2", "South Korea (Seoul)", false),
+	publicRegion("ap-southeast-1", "Singapore", false),
+	publicRegion("ap-southeast-3", "Malaysia (Kuala Lumpur)", false),
+	publicRegion("ap-southeast-5", "Indonesia (Jakarta)", false),
+	publicRegion("ap-southeast-6", "Philippines (Manila)", false),
+	publicRegion("ap-southeast-7", "Thailand (Bangkok)", false),
+	publicRegion("eu-central-1", "Germany (Frankfurt)", false),
+	publicRegion("eu-west-1", "UK (London)", false),
+	publicRegion("us-west-1", "US (Silicon Valley)", false),
+	publicRegion("us-east-1", "US (Virginia)", false),
+	publicRegion("na-south-1", "Mexico", false),
+	publicRegion("me-east-1", "UAE (Dubai)", false),
+	publicRegion("me-central-1", "Saudi Arabia (Riyadh)", false),
+	{
+		ID:               "cn-hangzhou-finance",
+		Name:             "China East 1 Finance",
+		Cloud:            regionCloudFinance,
+		PublicEndpoint:   "oss-cn-hzjbp-a.aliyuncs.com",
+		InternalEndpoint: "oss-cn-hzjbp-a-internal.aliyuncs.com",
+	},
+	{
+		ID:               "cn-shanghai-finance-1",
+		Name:             "China East 2 Finance",
+		Cloud:            regionCloudFinance,
+		PublicEndpoint:   "oss-cn-shanghai-finance-1-pub.aliyuncs.com",
+		InternalEndpoint: "oss-cn-shanghai-finance-1-internal.aliyuncs.com",
+	},
+	{
+		ID:               "cn-shenzhen-finance-1",
+		Name:             "China South 1 Finance",
+		Cloud:            regionCloudFinance,
+		PublicEndpoint:   "oss-cn-szfinance.aliyuncs.com",
+		InternalEndpoint: "oss-cn-szfinance-internal.aliyuncs.com",
+	},
+	{
+		ID:               "cn-beijing-finance-1",
+		Name:             "China North 2 Finance",
+		Cloud:            regionCloudFinance,
+		PublicEndpoint:   "oss-cn-beijing-finance-1-pub.aliyuncs.com",
+		InternalEndpoint: "oss-cn-beijing-finance-1-internal.aliyuncs.com",
+	},
+	{
+		ID:               "cn-north-2-gov-1",
+		Name:             "China North 2 Ali Gov 1",
+		Cloud:            regionCloudGov,
+		PublicEndpoint:   "oss-cn-north-2-gov-1.aliyuncs.com",
+		InternalEndpoint: "oss-cn-north-2-gov-1-internal.aliyuncs.com",
+	},
+}
+
+var (
+	knownRegionsByID = make(map[string]RegionInfo, len(knownRegions))
+	// knownRegionEndpoints maps the hosts of the isolated clouds back to their region, since they
+	// cannot be parsed by pattern.
+	knownRegionEndpoints = make(map[string]knownRegionEndpoint)
+)
+
+type knownRegionEndpoint struct {
+	region   string
+	internal bool
+}
+
+func init() {
+	for _, region := range knownRegions {
+		knownRegionsByID[region.ID] = region
+		if region.Cloud == regionCloudPublic {
+			continue
+		}
+		knownRegionEndpoints[region.PublicEndpoint] = knownRegionEndpoint{region: region.ID}
+		knownRegionEndpoints[region.InternalEndpoint] = knownRegionEndpoint{region: region.ID, internal: true}
+	}
+}
+
+// GetKnownRegions returns the built-in region catalog for the profile editor.
+func (s *OSSService) GetKnownRegions() []RegionInfo {
+	return append([]RegionInfo{}, knownRegions...)
+}
+
+// CheckRegion tells whether region is in the catalog and, when it is not, which known region it
+// was probably meant to be.
+func (s *OSSService) CheckRegion(region string) RegionCheck {
+	region = strings.ToLower(normalizeRegion(region))
+	if region == "" {
+		return RegionCheck{}
+	}
+	if _, ok := knownRegionsByID[region]; ok {
+		return RegionCheck{Known: true}
+	}
+	if suggestion := closestKnownRegion(region); suggestion != "" {
+		return RegionCheck{Suggestion: suggestion, Message: s.msg(msgRegionDidYouMean, region, suggestion)}
+	}
+	return RegionCheck{Message: s.msg(msgRegionUnknown, region)}
+}
+
+// closestKnownRegion returns the known region ID fewest edits away from region, if that is close
+// enough to be a typo.
+func closestKnownRegion(region string) string {
+	best, bestDistance := "", regionSuggestDistance+1
+	for _, known := range knownRegions {
+		if d := editDistance(region, known.ID); d < bestDistance {
+			best, bestDistance = known.ID, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
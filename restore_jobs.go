@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Prefix job statuses reported by background walks such as RestorePrefix.
+const (
+	prefixJobRunning  = "running"
+	prefixJobSuccess  = "success"
+	prefixJobError    = "error"
+	prefixJobCanceled = "canceled"
+)
+
+const (
+	minRestoreDays = 1
+	maxRestoreDays = 365
+	// maxArchiveRestoreDays is the longest an Archive object stays restored; Cold Archive allows
+	// maxRestoreDays.
+	maxArchiveRestoreDays = 7
+
+	// restoreBatchSize is how many objects a worker restores before reporting progress.
+	restoreBatchSize = 50
+	// restoreStatusSampleSize bounds how many archived objects GetPrefixRestoreStatus looks at.
+	restoreStatusSampleSize = 5000
+)
+
+// PrefixRestoreUpdate reports a RestorePrefix job. It is emitted as "restore:update" while the job
+// runs and once more, with FinishedAtMs set, when it ends.
+type PrefixRestoreUpdate struct {
+	ID     string `json:"id"`
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+	Status string `json:"status"`
+	// Scanned counts every listed object, archived or not.
+	Scanned   int64 `json:"scanned"`
+	Requested int64 `json:"requested"`
+	// Skipped counts archived objects already restored or being restored.
+	Skipped      int64               `json:"skipped"`
+	Failed       int64               `json:"failed"`
+	Failures     []PrefixWalkFailure `json:"failures,omitempty"`
+	Message      string              `json:"message,omitempty"`
+	StartedAtMs  int64               `json:"startedAtMs"`
+	FinishedAtMs int64               `json:"finishedAtMs,omitempty"`
+}
+
+// PrefixRestoreStatus is how far the archived objects under a prefix are restored.
+type PrefixRestoreStatus struct {
+	Bucket      string `json:"bucket"`
+	Prefix      string `json:"prefix"`
+	Archived    int64  `json:"archived"`
+	Ready       int64  `json:"ready"`
+	InProgress  int64  `json:"inProgress"`
+	NotRestored int64  `json:"notRestored"`
+	// Sampled is set when only the first archived objects were looked at.
+	Sampled bool `json:"sampled"`
+}
+
+func isArchivedStorageClass(class string) bool {
+	switch oss.StorageClassType(class) {
+	case oss.StorageArchive, oss.StorageColdArchive, oss.StorageDeepColdArchive:
+		return true
+	}
+	return false
+}
+
+// restoreState reads the x-oss-restore header, which listings return as RestoreInfo:
+// `ongoing-request="true"` while restoring, then `ongoing-request="false", expiry-date="..."`
+// until the restored copy expires.
+func restoreState(info string, now time.Time) (ongoing bool, ready bool) {
+	if strings.Contains(info, `ongoing-request="true"`) {
+		return true, false
+	}
+	if !strings.Contains(info, `ongoing-request="false"`) {
+		return false, false
+	}
+	_, expiry, ok := strings.Cut(info, `expiry-date="`)
+	if !ok {
+		return false, true
+	}
+	expiry, _, _ = strings.Cut(expiry, `"`)
+	expires, err := http.ParseTime(expiry)
+	return false, err != nil || expires.After(now)
+}
+
+func normalizeRestoreTier(tier string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(tier)) {
+	case "":
+		return "", nil
+	case "expedited":
+		return string(oss.RestoreExpedited), nil
+	case "standard":
+		return string(oss.RestoreStandard), nil
+	case "bulk":
+		return string(oss.RestoreBulk), nil
+	}
+	return "", fmt.Errorf("unknown restore tier %q; use Expedited, Standard or Bulk", tier)
+}
+
+// RestorePrefix restores every Archive and Cold Archive object under prefix for days days in the
+// background and returns the job ID for CancelOperation. tier (Expedited, Standard or Bulk) applies
+// to Cold Archive objects; empty means Standard. Archive objects have no tier and stay restored
+// at most 7 days. Objects already restored or being restored are skipped. Progress and the final
+// summary are emitted as restore:update.
+func (s *OSSService) RestorePrefix(config OSSConfig, bucketName string, prefix string, days int, tier string) (_ string, err error) {
+	defer s.logOperation("RestorePrefix", bucketName, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return "", err
+	}
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return "", err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+	if days < minRestoreDays || days > maxRestoreDays {
+		return "", s.errorf(msgRestoreDays, minRestoreDays, maxRestoreDays)
+	}
+	tier, err = normalizeRestoreTier(tier)
+	if err != nil {
+		return "", err
+	}
+
+	id, ctx, done := s.startBackgroundOperation("restore " + buildOssPath(bucketName, prefix))
+	update := PrefixRestoreUpdate{
+		ID:          id,
+		Bucket:      bucketName,
+		Prefix:      prefix,
+		Status:      prefixJobRunning,
+		StartedAtMs: time.Now().UnixMilli(),
+	}
+	go func() {
+		defer done()
+		s.runRestoreJob(ctx, config, update, days, tier)
+	}()
+	return id, nil
+}
+
+func (s *OSSService) runRestoreJob(ctx context.Context, config OSSConfig, update PrefixRestoreUpdate, days int, tier string) {
+	emitInterval := s.transferEmitInterval()
+	var (
+		mu       sync.Mutex
+		lastEmit time.Time
+	)
+	// emit is called with mu held.
+	emit := func(force bool) {
+		if !force && time.Since(lastEmit) < emitInterval {
+			return
+		}
+		lastEmit = time.Now()
+		s.emitRestoreUpdate(update)
+	}
+	mu.Lock()
+	emit(true)
+	mu.Unlock()
+
+	result, err := runPrefixWalk(ctx, s.sdkPrefixLister(config, update.Bucket, update.Prefix), prefixWalkWorkers, restoreBatchSize,
+		func(ctx context.Context, batch []oss.ObjectProperties) []prefixObjectError {
+			var failures []prefixObjectError
+			var requested, skipped int64
+			// The listing has learned the bucket's region by now.
+			bucket, bucketErr := s.sdkBucket(config, update.Bucket)
+			now := time.Now()
+			for _, object := range batch {
+				if ctx.Err() != nil {
+					break
+				}
+				if !isArchivedStorageClass(object.StorageClass) {
+					continue
+				}
+				if ongoing, ready := restoreState(object.RestoreInfo, now); ongoing || ready {
+					skipped++
+					continue
+				}
+				err := bucketErr
+				if err == nil {
+					err = s.restoreObject(ctx, bucket, object, days, tier)
+				}
+				switch {
+				case isOSSErrorCode(err, "RestoreAlreadyInProgress"):
+					skipped++
+				case err != nil:
+					failures = append(failures, prefixObjectError{key: object.Key, err: describeSDKError(err)})
+				default:
+					requested++
+				}
+			}
+			mu.Lock()
+			update.Scanned += int64(len(batch))
+			update.Requested += requested
+			update.Skipped += skipped
+			update.Failed += int64(len(failures))
+			emit(false)
+			mu.Unlock()
+			return failures
+		})
+
+	mu.Lock()
+	defer mu.Unlock()
+	update.Scanned = result.Objects
+	update.Failed = result.Failed
+	update.Failures = result.Failures
+	update.FinishedAtMs = time.Now().UnixMilli()
+	level, outcome := logLevelInfo, opOutcomeOK
+	switch {
+	case ctx.Err() != nil:
+		update.Status = prefixJobCanceled
+		update.Message = s.msg(msgRestoreCanceled, update.Requested)
+		err = ctx.Err()
+		level, outcome = logLevelWarn, opOutcomeError
+	case err != nil:
+		update.Status = prefixJobError
+		update.Message = s.errorf(msgListFolderFailed, err).Error()
+		level, outcome = logLevelError, opOutcomeError
+	default:
+		update.Status = prefixJobSuccess
+		update.Message = s.msg(msgRestoreDone, update.Requested, update.Skipped, update.Failed)
+		if update.Failed > 0 {
+			update.Message += ": " + prefixFailureText(update.Failures)
+			level, outcome = logLevelWarn, opOutcomeError
+			err = result.FirstErr
+		}
+	}
+	s.logOperationEvent(level, "RestorePrefix", outcome, buildOssPath(update.Bucket, update.Prefix)+": "+update.Message, err)
+	emit(true)
+}
+
+// restoreRequest is the RestoreObject body. oss.RestoreConfiguration always writes JobParameters,
+// which Archive objects do not take.
+type restoreRequest struct {
+	XMLName       xml.Name              `xml:"RestoreRequest"`
+	Days          int                   `xml:"Days"`
+	JobParameters *restoreJobParameters `xml:"JobParameters,omitempty"`
+}
+
+type restoreJobParameters struct {
+	Tier string `xml:"Tier"`
+}
+
+// restoreObject asks OSS to restore one archived object. Archive objects take no tier and at most
+// maxArchiveRestoreDays days.
+func (s *OSSService) restoreObject(ctx context.Context, bucket *oss.Bucket, object oss.ObjectProperties, days int, tier string) error {
+	request := restoreRequest{Days: days}
+	if oss.StorageClassType(object.StorageClass) == oss.StorageArchive {
+		request.Days = min(days, maxArchiveRestoreDays)
+	} else if tier != "" {
+		request.JobParameters = &restoreJobParameters{Tier: tier}
+	}
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return s.withSDKRetryContext(ctx, "RestoreObject", func() error {
+		return bucket.RestoreObjectXML(object.Key, string(body))
+	})
+}
+
+func (s *OSSService) emitRestoreUpdate(update PrefixRestoreUpdate) {
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.EventsEmit(ctx, "restore:update", update)
+}
+
+// GetPrefixRestoreStatus counts how many archived objects under prefix are restored, being
+// restored or not restored, so a download can wait until everything is ready. It looks at the
+// first 5000 archived objects and sets Sampled when there are more.
+func (s *OSSService) GetPrefixRestoreStatus(config OSSConfig, bucketName string, prefix string) (_ PrefixRestoreStatus, err error) {
+	defer s.logOperation("GetPrefixRestoreStatus", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return PrefixRestoreStatus{}, err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+
+	command := "restore-status " + buildOssPath(bucketName, prefix)
+	ctx, done := s.startLongOperation(s.baseContext(), command)
+	defer done()
+
+	status := PrefixRestoreStatus{Bucket: bucketName, Prefix: prefix}
+	list := s.sdkPrefixLister(config, bucketName, prefix)
+	now := time.Now()
+	marker := ""
+	for {
+		page, err := list(ctx, marker)
+		if err != nil {
+			if ctx.Err() != nil {
+				return PrefixRestoreStatus{}, fmt.Errorf("%w: %s", ErrOperationCanceled, command)
+			}
+			return PrefixRestoreStatus{}, s.errorf(msgListFolderFailed, s.classifyAuthError(config, err))
+		}
+		for _, object := range page.Objects {
+			if !isArchivedStorageClass(object.StorageClass) {
+				continue
+			}
+			if status.Archived == restoreStatusSampleSize {
+				status.Sampled = true
+				return status, nil
+			}
+			status.Archived++
+			switch ongoing, ready := restoreState(object.RestoreInfo, now); {
+			case ready:
+				status.Ready++
+			case ongoing:
+				status.InProgress++
+			default:
+				status.NotRestored++
+			}
+		}
+		if !page.IsTruncated || page.NextMarker == "" {
+			return status, nil
+		}
+		marker = page.NextMarker
+	}
+}
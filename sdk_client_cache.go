@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Clients are cheap to keep but each has its own HTTP transport, so only the few in active use are
+// retained; switching between a handful of profiles and endpoints stays warm.
+const sdkClientCacheSize = 16
+
+type sdkClientCacheEntry struct {
+	client   *oss.Client
+	lastUsed time.Time
+}
+
+// sdkClientCache keeps oss.Client instances so repeated listings and metadata calls reuse their
+// connections. oss.Client is safe for concurrent use; callers must not modify a cached client.
+type sdkClientCache struct {
+	mu      sync.Mutex
+	entries map[string]sdkClientCacheEntry
+}
+
+func newSDKClientCache() *sdkClientCache {
+	return &sdkClientCache{entries: make(map[string]sdkClientCacheEntry)}
+}
+
+// sdkClientCacheKey identifies everything that goes into a client. Credentials are hashed so the
+// cache does not hold another copy of them in plain text.
+func sdkClientCacheKey(endpoint string, cname bool, config OSSConfig, region string, tuning sdkTuning, proxy proxySettings, tlsConfig tlsSettings) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		config.AccessKeyID,
+		config.AccessKeySecret,
+		config.SecurityToken,
+		proxy.url,
+		proxy.user,
+		proxy.password,
+	}, "\x00")))
+	return strings.Join([]string{
+		endpoint,
+		strconv.FormatBool(cname),
+		region,
+		strconv.Itoa(tuning.connectTimeoutSec),
+		strconv.Itoa(tuning.readWriteTimeoutSec),
+		tlsConfig.caCertFile,
+		strconv.FormatBool(tlsConfig.insecureSkipVerify),
+		hex.EncodeToString(sum[:]),
+	}, "|")
+}
+
+// get returns the client cached under key, creating it with newClient on a miss.
+func (c *sdkClientCache) get(key string, newClient func() (*oss.Client, error)) (*oss.Client, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		entry.lastUsed = time.Now()
+		c.entries[key] = entry
+		c.mu.Unlock()
+		return entry.client, nil
+	}
+	c.mu.Unlock()
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		// Another call built the same client meanwhile; keep the first one.
+		return entry.client, nil
+	}
+	if len(c.entries) >= sdkClientCacheSize {
+		c.evictOldestLocked()
+	}
+	c.entries[key] = sdkClientCacheEntry{client: client, lastUsed: time.Now()}
+	return client, nil
+}
+
+func (c *sdkClientCache) evictOldestLocked() {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if oldestKey == "" || entry.lastUsed.Before(oldest) {
+			oldestKey, oldest = key, entry.lastUsed
+		}
+	}
+	delete(c.entries, oldestKey)
+}
+
+// clear drops every cached client, e.g. after the proxy, TLS settings, SDK timeouts or a profile
+// changed. Calls already holding a client finish with it.
+func (c *sdkClientCache) clear() {
+	c.mu.Lock()
+	c.entries = make(map[string]sdkClientCacheEntry)
+	c.mu.Unlock()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func TestSDKClientCacheReusesAndEvicts(t *testing.T) {
+	c := newSDKClientCache()
+	built := 0
+	newClient := func() (*oss.Client, error) {
+		built++
+		return oss.New("https://oss-cn-hangzhou.aliyuncs.com", "id", "secret")
+	}
+
+	first, err := c.get("a", newClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := c.get("a", newClient)
+	if first != again || built != 1 {
+		t.Fatalf("a cached client was rebuilt (built %d)", built)
+	}
+
+	for i := range sdkClientCacheSize {
+		_, _ = c.get(fmt.Sprintf("other-%d", i), newClient)
+	}
+	if len(c.entries) != sdkClientCacheSize {
+		t.Fatalf("cache holds %d clients, want %d", len(c.entries), sdkClientCacheSize)
+	}
+	if _, ok := c.entries["a"]; ok {
+		t.Error("the least recently used client was not evicted")
+	}
+
+	c.clear()
+	_, _ = c.get("a", newClient)
+	if built != sdkClientCacheSize+2 {
+		t.Errorf("built = %d after clear, want a new client", built)
+	}
+}
+
+func TestSDKClientCacheKeySeparatesCredentials(t *testing.T) {
+	config := OSSConfig{AccessKeyID: "id", AccessKeySecret: "one"}
+	other := config
+	other.AccessKeySecret = "two"
+	key := sdkClientCacheKey("https://e", false, config, "cn-hangzhou", sdkTuning{}, proxySettings{}, tlsSettings{})
+	if key == sdkClientCacheKey("https://e", false, other, "cn-hangzhou", sdkTuning{}, proxySettings{}, tlsSettings{}) {
+		t.Fatal("profiles with different secrets share a client")
+	}
+	if key != sdkClientCacheKey("https://e", false, config, "cn-hangzhou", sdkTuning{}, proxySettings{}, tlsSettings{}) {
+		t.Fatal("the same profile got a different key")
+	}
+}
+
+func benchmarkListObjectsPage(b *testing.B, freshClient bool) {
+	s := newTestService(b)
+	config := fakeOSS(b, func(w http.ResponseWriter, r *http.Request) {
+		writeListing(w, []string{"dir/a.txt", "dir/b.txt"}, []string{"dir/sub/"})
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if freshClient {
+			s.sdkClients.clear()
+		}
+		if _, err := s.ListObjectsPage(config, "bucket", "dir/", "", 100); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkListObjectsPage measures repeated listings through the client cache, which keeps the
+// HTTP connection alive between pages.
+func BenchmarkListObjectsPage(b *testing.B) {
+	benchmarkListObjectsPage(b, false)
+}
+
+// BenchmarkListObjectsPageFreshClient builds a client, and so a connection, for every listing as
+// before the cache.
+func BenchmarkListObjectsPageFreshClient(b *testing.B) {
+	benchmarkListObjectsPage(b, true)
+}
+
+// BenchmarkSDKFlightGroup measures the overhead of routing calls through the flight group.
+func BenchmarkSDKFlightGroup(b *testing.B) {
+	g := newSDKFlightGroup()
+	key := sdkFlightKey("ListObjects", OSSConfig{AccessKeyID: "id"}, "bucket", "dir/", "", "100")
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = g.do(key, func() (any, error) { return nil, nil })
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// sdkFlightGroup lets concurrent identical read requests share one SDK call. Several tabs on the
+// same folder otherwise send the same listing to OSS several times over. Only calls in flight are
+// shared; nothing is kept once the call returns.
+//
+// The shared call runs on its own and is not tied to any caller, so a caller giving up does not
+// cancel it for the others. Every caller receives the same value, which must be treated as
+// read-only: callers build their own results from it.
+type sdkFlightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*sdkFlightCall
+}
+
+type sdkFlightCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func newSDKFlightGroup() *sdkFlightGroup {
+	return &sdkFlightGroup{calls: make(map[string]*sdkFlightCall)}
+}
+
+// do runs call unless a call with the same key is already running, in which case it waits for that
+// one and returns its outcome.
+func (g *sdkFlightGroup) do(key string, call func() (any, error)) (any, error) {
+	g.mu.Lock()
+	if running, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-running.done
+		return running.value, running.err
+	}
+	// The error stands if call panics, so waiters are not handed an empty result as a success.
+	running := &sdkFlightCall{done: make(chan struct{}), err: errors.New("shared request did not complete")}
+	g.calls[key] = running
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(running.done)
+	}()
+	running.value, running.err = call()
+	return running.value, running.err
+}
+
+// sdkFlightKey identifies a request by operation, profile and parameters. The whole profile is
+// hashed into the key, secret and token included, so requests only share a call when they would
+// have been sent with the same credentials to the same place.
+func sdkFlightKey(operation string, config OSSConfig, params ...string) string {
+	profile, _ := json.Marshal(config)
+	sum := sha256.Sum256(profile)
+	parts := append([]string{operation, hex.EncodeToString(sum[:])}, params...)
+	return strings.Join(parts, "\x1f")
+}
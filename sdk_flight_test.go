@@ -0,0 +1,360 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// startFlight runs a leader call of g under key that blocks until release is closed, and returns
+// once it is in flight.
+func startFlight(g *sdkFlightGroup, key string, release <-chan struct{}, calls *atomic.Int32, result func() (any, error)) <-chan error {
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.do(key, func() (any, error) {
+			calls.Add(1)
+			close(started)
+			<-release
+			return result()
+		})
+		done <- err
+	}()
+	<-started
+	return done
+}
+
+func TestSDKFlightGroupSharesCallInFlight(t *testing.T) {
+	g := newSDKFlightGroup()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	leader := startFlight(g, "key", release, &calls, func() (any, error) { return "value", nil })
+
+	const waiters = 20
+	var wg sync.WaitGroup
+	values := make([]any, waiters)
+	for i := range waiters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], _ = g.do("key", func() (any, error) {
+				calls.Add(1)
+				return "own", nil
+			})
+		}()
+	}
+	// Give the waiters time to join the call in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if err := <-leader; err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls = %d, want 1", got)
+	}
+	for i, value := range values {
+		if value != "value" {
+			t.Errorf("waiter %d got %v", i, value)
+		}
+	}
+}
+
+func TestSDKFlightGroupErrorReachesEveryWaiter(t *testing.T) {
+	g := newSDKFlightGroup()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	failure := errors.New("listing failed")
+	leader := startFlight(g, "key", release, &calls, func() (any, error) { return nil, failure })
+
+	const waiters = 10
+	errs := make(chan error, waiters)
+	for range waiters {
+		go func() {
+			_, err := g.do("key", func() (any, error) { return nil, nil })
+			errs <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if err := <-leader; !errors.Is(err, failure) {
+		t.Fatalf("leader err = %v", err)
+	}
+	for range waiters {
+		if err := <-errs; !errors.Is(err, failure) {
+			t.Errorf("waiter err = %v, want %v", err, failure)
+		}
+	}
+}
+
+func TestSDKFlightGroupPanicFailsWaiters(t *testing.T) {
+	g := newSDKFlightGroup()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = g.do("key", func() (any, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := g.do("key", func() (any, error) { return "own", nil })
+		errs <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if err := <-errs; err == nil {
+		t.Fatal("a waiter of a panicked call got a success")
+	}
+
+	// The key is free again afterwards.
+	if value, err := g.do("key", func() (any, error) { return "again", nil }); err != nil || value != "again" {
+		t.Fatalf("call after the panic = %v, %v", value, err)
+	}
+}
+
+func TestSDKFlightGroupKeysDoNotShare(t *testing.T) {
+	g := newSDKFlightGroup()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	leader := startFlight(g, "a", release, &calls, func() (any, error) { return "a", nil })
+
+	value, err := g.do("b", func() (any, error) { return "b", nil })
+	if err != nil || value != "b" {
+		t.Fatalf("other key = %v, %v", value, err)
+	}
+	close(release)
+	<-leader
+}
+
+// runConcurrently calls call from n goroutines at once against a server that holds every request
+// until release is closed, and returns the results in start order.
+func runConcurrently[T any](n int, release chan struct{}, call func(i int) (T, error)) ([]T, []error) {
+	results := make([]T, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = call(i)
+		}()
+	}
+	// Give every caller time to join the request in flight.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	return results, errs
+}
+
+// heldServer answers every request with respond once release is closed, counting the requests.
+func heldServer(t *testing.T, respond http.HandlerFunc) (OSSConfig, chan struct{}, *atomic.Int32) {
+	t.Helper()
+	release := make(chan struct{})
+	var requests atomic.Int32
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		respond(w, r)
+	})
+	return config, release, &requests
+}
+
+func requireSameResults[T any](t *testing.T, results []T, errs []error) {
+	t.Helper()
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		if !reflect.DeepEqual(results[i], results[0]) {
+			t.Fatalf("caller %d got %+v, caller 0 got %+v", i, results[i], results[0])
+		}
+	}
+}
+
+const concurrentCallers = 10
+
+func TestListObjectsSharedSendsOneRequest(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeListing(w, []string{"dir/a.txt"}, []string{"dir/sub/"})
+	})
+
+	results, errs := runConcurrently(concurrentCallers, release, func(int) (oss.ListObjectsResult, error) {
+		return s.listObjectsShared(config, "bucket", "dir/", "", 100)
+	})
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+	requireSameResults(t, results, errs)
+	if len(results[0].Objects) != 1 || len(results[0].CommonPrefixes) != 1 {
+		t.Fatalf("listing = %+v", results[0])
+	}
+}
+
+func TestListObjectsSharedErrorReachesEveryCaller(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeOSSError(w, http.StatusForbidden, "AccessDenied", "denied")
+	})
+
+	_, errs := runConcurrently(concurrentCallers, release, func(int) (oss.ListObjectsResult, error) {
+		return s.listObjectsShared(config, "bucket", "", "", 100)
+	})
+	for i, err := range errs {
+		if !isAccessDenied(err) {
+			t.Errorf("caller %d: err = %v, want AccessDenied", i, err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestListObjectsPageSharesOneRequest(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeListing(w, []string{"dir/a.txt", "dir/b.txt"}, []string{"dir/sub/"})
+	})
+
+	results, errs := runConcurrently(concurrentCallers, release, func(int) (ObjectListPageResult, error) {
+		return s.ListObjectsPage(config, "bucket", "dir/", "", 100)
+	})
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+	requireSameResults(t, results, errs)
+	if len(results[0].Items) != 3 {
+		t.Fatalf("items = %+v", results[0].Items)
+	}
+
+	// Every caller owns its result.
+	results[0].Items[0].Name = "changed"
+	results[0].Items = append(results[0].Items[:1], results[0].Items[2:]...)
+	for i := 1; i < concurrentCallers; i++ {
+		if len(results[i].Items) != 3 || results[i].Items[0].Name == "changed" {
+			t.Fatalf("changing caller 0's result changed caller %d's: %+v", i, results[i].Items)
+		}
+	}
+}
+
+func TestGetBucketStatSharesOneRequest(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["stat"]; !ok {
+			writeOSSError(w, http.StatusBadRequest, "InvalidArgument", r.URL.String())
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><BucketStat><Storage>1024</Storage><ObjectCount>7</ObjectCount><MultipartUploadCount>1</MultipartUploadCount><StandardStorage>1024</StandardStorage><StandardObjectCount>7</StandardObjectCount></BucketStat>`))
+	})
+
+	results, errs := runConcurrently(concurrentCallers, release, func(int) (BucketStat, error) {
+		stat, err := s.GetBucketStat(config, "bucket", true)
+		// The fetch time differs per caller.
+		stat.FetchedAtMs = 0
+		return stat, err
+	})
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+	requireSameResults(t, results, errs)
+	if results[0].Storage != 1024 || results[0].ObjectCount != 7 {
+		t.Fatalf("stat = %+v", results[0])
+	}
+	results[0].StorageClasses[0].Storage = -1
+	if results[1].StorageClasses[0].Storage != 1024 {
+		t.Fatal("stats share their storage classes")
+	}
+}
+
+func TestGetBucketInfoSharesOneRequest(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["bucketInfo"]; !ok {
+			writeOSSError(w, http.StatusBadRequest, "InvalidArgument", r.URL.String())
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><BucketInfo><Bucket><Name>bucket</Name><Location>oss-cn-hangzhou</Location><CreationDate>2024-01-01T00:00:00.000Z</CreationDate><StorageClass>Standard</StorageClass><AccessControlList><Grant>private</Grant></AccessControlList><Owner><ID>1</ID><DisplayName>owner</DisplayName></Owner></Bucket></BucketInfo>`))
+	})
+
+	results, errs := runConcurrently(concurrentCallers, release, func(int) (BucketDetail, error) {
+		return s.GetBucketInfo(config, "bucket", BucketInfoOptions{})
+	})
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+	requireSameResults(t, results, errs)
+	if results[0].Name != "bucket" || results[0].Region != "cn-hangzhou" || results[0].ACL != "private" {
+		t.Fatalf("detail = %+v", results[0])
+	}
+}
+
+func TestDifferentListingsDoNotShare(t *testing.T) {
+	s := newTestService(t)
+	config, release, requests := heldServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeListing(w, nil, nil)
+	})
+	other := config
+	other.AccessKeySecret = "other-secret"
+
+	calls := []func() (ObjectListPageResult, error){
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(config, "bucket", "a/", "", 100) },
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(config, "bucket", "b/", "", 100) },
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(config, "bucket", "a/", "a/x", 100) },
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(config, "bucket", "a/", "", 50) },
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(config, "other-bucket", "a/", "", 100) },
+		func() (ObjectListPageResult, error) { return s.ListObjectsPage(other, "bucket", "a/", "", 100) },
+	}
+	_, errs := runConcurrently(len(calls), release, func(i int) (ObjectListPageResult, error) { return calls[i]() })
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if got := requests.Load(); int(got) != len(calls) {
+		t.Fatalf("requests = %d, want %d", got, len(calls))
+	}
+}
+
+func TestSDKFlightKey(t *testing.T) {
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+	base := sdkFlightKey("ListObjects", config, "bucket", "a/")
+	if sdkFlightKey("ListObjects", config, "bucket", "a/") != base {
+		t.Fatal("identical requests have different keys")
+	}
+	if strings.Contains(base, "secret") {
+		t.Fatalf("key %q holds the secret in clear", base)
+	}
+
+	withToken := config
+	withToken.SecurityToken = "token"
+	withSecret := config
+	withSecret.AccessKeySecret = "other"
+	for name, key := range map[string]string{
+		"operation": sdkFlightKey("GetBucketStat", config, "bucket", "a/"),
+		"secret":    sdkFlightKey("ListObjects", withSecret, "bucket", "a/"),
+		"token":     sdkFlightKey("ListObjects", withToken, "bucket", "a/"),
+		"params":    sdkFlightKey("ListObjects", config, "bucket", "a/", ""),
+		"prefix":    sdkFlightKey("ListObjects", config, "bucket", "b/"),
+	} {
+		if key == base {
+			t.Errorf("a different %s shares the key", name)
+		}
+	}
+}
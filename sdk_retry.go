@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	defaultSDKConnectTimeoutSec   = 10
+	defaultSDKReadWriteTimeoutSec = 20
+	defaultSDKMaxRetries          = 2
+
+	maxSDKConnectTimeoutSec   = 300
+	maxSDKReadWriteTimeoutSec = 3600
+	maxSDKMaxRetries          = 10
+
+	sdkRetryBaseDelay = 200 * time.Millisecond
+	sdkRetryMaxDelay  = 5 * time.Second
+)
+
+// OSS answers request-rate limits with these codes, usually on a 503 but not always.
+var ossThrottlingCodes = map[string]bool{
+	"RequestTimeout":   true,
+	"Throttling":       true,
+	"SlowDown":         true,
+	"QpsLimitExceeded": true,
+	"TooManyRequests":  true,
+}
+
+// sdkTuning holds the timeout and retry settings applied to SDK clients.
+type sdkTuning struct {
+	connectTimeoutSec   int
+	readWriteTimeoutSec int
+	maxRetries          int
+}
+
+func validateSDKTuning(settings AppSettings) error {
+	if settings.SDKConnectTimeoutSec < 1 || settings.SDKConnectTimeoutSec > maxSDKConnectTimeoutSec {
+		return fmt.Errorf("SDK connect timeout must be between 1 and %d seconds", maxSDKConnectTimeoutSec)
+	}
+	if settings.SDKReadWriteTimeoutSec < 1 || settings.SDKReadWriteTimeoutSec > maxSDKReadWriteTimeoutSec {
+		return fmt.Errorf("SDK read/write timeout must be between 1 and %d seconds", maxSDKReadWriteTimeoutSec)
+	}
+	if settings.SDKMaxRetries < 0 || settings.SDKMaxRetries > maxSDKMaxRetries {
+		return fmt.Errorf("SDK retries must be between 0 and %d", maxSDKMaxRetries)
+	}
+	return nil
+}
+
+func (s *OSSService) setSDKTuning(settings AppSettings) {
+	tuning := sdkTuning{
+		connectTimeoutSec:   settings.SDKConnectTimeoutSec,
+		readWriteTimeoutSec: settings.SDKReadWriteTimeoutSec,
+		maxRetries:          settings.SDKMaxRetries,
+	}
+	s.sdkTuningMu.Lock()
+	changed := s.sdkTuning != tuning
+	s.sdkTuning = tuning
+	s.sdkTuningMu.Unlock()
+	if changed {
+		s.sdkClients.clear()
+	}
+}
+
+func (s *OSSService) getSDKTuning() sdkTuning {
+	s.sdkTuningMu.RLock()
+	defer s.sdkTuningMu.RUnlock()
+	return s.sdkTuning
+}
+
+func (t sdkTuning) timeoutOption() oss.ClientOption {
+	return oss.Timeout(int64(t.connectTimeoutSec), int64(t.readWriteTimeoutSec))
+}
+
+// isTransientSDKError reports errors worth retrying: throttling, timeouts, dropped connections and
+// 5xx responses. Other 4xx answers (credentials, permissions, bad requests) fail the same way again.
+func isTransientSDKError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.StatusCode >= 500 || serviceErr.StatusCode == http.StatusTooManyRequests || ossThrottlingCodes[serviceErr.Code]
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "connection reset") || strings.Contains(msg, "broken pipe")
+}
+
+func sdkRetryDelay(attempt int) time.Duration {
+	delay := sdkRetryBaseDelay << attempt
+	if delay > sdkRetryMaxDelay || delay <= 0 {
+		delay = sdkRetryMaxDelay
+	}
+	// Full jitter within [delay/2, delay) so parallel callers do not retry in lockstep.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// withSDKRetry runs an idempotent SDK call, retrying transient failures with exponential backoff.
+// Non-idempotent calls (AppendObject, uploads without a fixed body) must not go through here.
+// A call that cannot reach its endpoint at all is not retried; timeouts are.
+func (s *OSSService) withSDKRetry(operation string, call func() error) error {
+	return s.withSDKRetryContext(context.Background(), operation, call)
+}
+
+// withSDKRetryContext is withSDKRetry for jobs that can be canceled: ctx ends the wait between
+// attempts, returning the last error. When every retry fails, the error says how many attempts
+// were made; errors.As still finds the oss.ServiceError underneath.
+func (s *OSSService) withSDKRetryContext(ctx context.Context, operation string, call func() error) error {
+	maxRetries := s.getSDKTuning().maxRetries
+	err := call()
+	attempt := 0
+	for ; attempt < maxRetries && isTransientSDKError(err) && !s.noteNetworkFailure(err); attempt++ {
+		delay := sdkRetryDelay(attempt)
+		s.logSDKRetry(operation, attempt+1, maxRetries, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = call()
+	}
+	s.noteNetworkFailure(err)
+	if err != nil && attempt > 0 {
+		return fmt.Errorf("%w (gave up after %d attempts)", err, attempt+1)
+	}
+	return err
+}
+
+func (s *OSSService) logSDKRetry(operation string, attempt int, maxRetries int, delay time.Duration, err error) {
+	s.logOperationEvent(logLevelWarn, operation, opOutcomeRetry, fmt.Sprintf("retry %d/%d in %s", attempt, maxRetries, delay.Round(time.Millisecond)), err)
+
+	s.transferCtxMu.RLock()
+	ctx := s.transferCtx
+	s.transferCtxMu.RUnlock()
+	if ctx == nil {
+		return
+	}
+	runtime.LogWarningf(ctx, "%s failed (%s); retry %d/%d in %s", operation, s.redact(err.Error()), attempt, maxRetries, delay.Round(time.Millisecond))
+}
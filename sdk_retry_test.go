@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+func TestIsTransientSDKError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"500", oss.ServiceError{StatusCode: 500, Code: "InternalError"}, true},
+		{"503", oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}, true},
+		{"429", oss.ServiceError{StatusCode: http.StatusTooManyRequests}, true},
+		{"throttling on 400", oss.ServiceError{StatusCode: 400, Code: "Throttling"}, true},
+		{"request timeout on 400", oss.ServiceError{StatusCode: 400, Code: "RequestTimeout"}, true},
+		{"qps limit", oss.ServiceError{StatusCode: 403, Code: "QpsLimitExceeded"}, true},
+		{"access denied", oss.ServiceError{StatusCode: 403, Code: "AccessDenied"}, false},
+		{"not found", oss.ServiceError{StatusCode: 404, Code: "NoSuchKey"}, false},
+		{"bad request", oss.ServiceError{StatusCode: 400, Code: "InvalidArgument"}, false},
+		{"wrapped service error", fmt.Errorf("list: %w", oss.ServiceError{StatusCode: 502}), true},
+		{"net timeout", &url.Error{Op: "Get", URL: "https://b.oss-cn-hangzhou.aliyuncs.com/", Err: timeoutError{}}, true},
+		{"dial timeout", &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}, true},
+		{"econnreset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"reset text", errors.New("read tcp: connection reset by peer"), true},
+		{"broken pipe", errors.New("write tcp: broken pipe"), true},
+		{"unexpected eof", fmt.Errorf("body: %w", io.ErrUnexpectedEOF), true},
+		{"refused", &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, false},
+		{"plain", errors.New("invalid bucket name"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientSDKError(tt.err); got != tt.want {
+				t.Fatalf("isTransientSDKError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSDKRetryDelayBounds(t *testing.T) {
+	for attempt := 0; attempt < 80; attempt++ {
+		ceiling := sdkRetryMaxDelay
+		if attempt < 30 && sdkRetryBaseDelay<<attempt < ceiling {
+			ceiling = sdkRetryBaseDelay << attempt
+		}
+		for range 50 {
+			delay := sdkRetryDelay(attempt)
+			if delay < ceiling/2 || delay >= ceiling {
+				t.Fatalf("sdkRetryDelay(%d) = %s, want within [%s, %s)", attempt, delay, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestWithSDKRetryRetriesTimeoutsButNotUnreachable(t *testing.T) {
+	s := newTestService(t)
+	maxRetries := s.getSDKTuning().maxRetries
+
+	calls := 0
+	err := s.withSDKRetry("Test", func() error {
+		calls++
+		return &url.Error{Op: "Get", URL: "https://b.oss-cn-hangzhou.aliyuncs.com/", Err: timeoutError{}}
+	})
+	if err == nil || calls != maxRetries+1 {
+		t.Fatalf("a timing out call ran %d times (err %v), want %d", calls, err, maxRetries+1)
+	}
+
+	calls = 0
+	s.withSDKRetry("Test", func() error {
+		calls++
+		return &url.Error{Op: "Get", URL: "https://b.oss-cn-hangzhou.aliyuncs.com/", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	})
+	if calls != 1 {
+		t.Fatalf("an unreachable endpoint was tried %d times, want 1", calls)
+	}
+
+	calls = 0
+	if err := s.withSDKRetry("Test", func() error {
+		calls++
+		if calls == 1 {
+			return oss.ServiceError{StatusCode: 503}
+		}
+		return nil
+	}); err != nil || calls != 2 {
+		t.Fatalf("a call failing once = %v after %d calls", err, calls)
+	}
+}
+
+func TestWithSDKRetryContextStopsWhenCanceled(t *testing.T) {
+	s := newTestService(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	err := s.withSDKRetryContext(ctx, "Test", func() error {
+		calls++
+		return oss.ServiceError{StatusCode: 503}
+	})
+	if calls != 1 || err == nil {
+		t.Fatalf("a canceled retry ran %d times (err %v)", calls, err)
+	}
+}
+
+// scriptedCall fails with failure for its first n calls, then succeeds.
+func scriptedCall(n int, failure error) (call func() error, calls *int) {
+	calls = new(int)
+	return func() error {
+		*calls++
+		if *calls <= n {
+			return failure
+		}
+		return nil
+	}, calls
+}
+
+func withMaxRetries(s *OSSService, maxRetries int) {
+	tuning := s.getSDKTuning()
+	s.setSDKTuning(AppSettings{
+		SDKConnectTimeoutSec:   tuning.connectTimeoutSec,
+		SDKReadWriteTimeoutSec: tuning.readWriteTimeoutSec,
+		SDKMaxRetries:          maxRetries,
+	})
+}
+
+func TestWithSDKRetrySucceedsAfterTransientFailures(t *testing.T) {
+	s := newTestService(t)
+	withMaxRetries(s, 3)
+	tests := []struct {
+		failures int
+		failure  error
+	}{
+		{0, oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}},
+		{3, oss.ServiceError{StatusCode: 503, Code: "ServiceUnavailable"}},
+		{1, oss.ServiceError{StatusCode: 503, Code: "SlowDown"}},
+		{2, oss.ServiceError{StatusCode: http.StatusTooManyRequests, Code: "TooManyRequests"}},
+		{1, &url.Error{Op: "Get", URL: "https://b.oss-cn-hangzhou.aliyuncs.com/", Err: timeoutError{}}},
+	}
+	for _, tt := range tests {
+		call, calls := scriptedCall(tt.failures, tt.failure)
+		if err := s.withSDKRetry("Test", call); err != nil || *calls != tt.failures+1 {
+			t.Errorf("failing %d times with %v: err %v after %d calls, want success after %d", tt.failures, tt.failure, err, *calls, tt.failures+1)
+		}
+	}
+}
+
+func TestWithSDKRetryGivesUpWithAttemptCount(t *testing.T) {
+	s := newTestService(t)
+	withMaxRetries(s, 2)
+	failure := oss.ServiceError{StatusCode: 503, Code: "SlowDown", Message: "Please reduce your request rate."}
+	call, calls := scriptedCall(3, failure)
+	err := s.withSDKRetry("Test", call)
+	if *calls != 3 {
+		t.Fatalf("ran %d times, want 3", *calls)
+	}
+	if err == nil || !strings.Contains(err.Error(), "gave up after 3 attempts") {
+		t.Fatalf("err = %v, want the attempt count", err)
+	}
+	var serviceErr oss.ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Code != "SlowDown" {
+		t.Fatalf("errors.As lost the service error: %#v", err)
+	}
+}
+
+func TestWithSDKRetryPassesClientErrorsThrough(t *testing.T) {
+	s := newTestService(t)
+	withMaxRetries(s, 5)
+	for _, failure := range []error{
+		oss.ServiceError{StatusCode: 403, Code: "AccessDenied"},
+		oss.ServiceError{StatusCode: 403, Code: "InvalidAccessKeyId"},
+		oss.ServiceError{StatusCode: 403, Code: "SignatureDoesNotMatch"},
+		oss.ServiceError{StatusCode: 400, Code: "InvalidArgument"},
+		oss.ServiceError{StatusCode: 404, Code: "NoSuchKey"},
+		errors.New("invalid bucket name"),
+	} {
+		call, calls := scriptedCall(1, failure)
+		err := s.withSDKRetry("Test", call)
+		if *calls != 1 || !reflect.DeepEqual(err, failure) {
+			t.Errorf("%v: ran %d times and returned %v, want the error unchanged after 1 call", failure, *calls, err)
+		}
+	}
+}
+
+func TestWithSDKRetryZeroRetries(t *testing.T) {
+	s := newTestService(t)
+	withMaxRetries(s, 0)
+	failure := oss.ServiceError{StatusCode: 503}
+	call, calls := scriptedCall(1, failure)
+	if err := s.withSDKRetry("Test", call); *calls != 1 || !reflect.DeepEqual(err, failure) {
+		t.Fatalf("with retries off: %v after %d calls", err, *calls)
+	}
+}
+
+// TestMoveObjectRetriesThrottledCopy moves an object through a server that throttles the copy
+// twice and fails the first delete with a 500.
+func TestMoveObjectRetriesThrottledCopy(t *testing.T) {
+	s := newTestService(t)
+	withMaxRetries(s, 3)
+	var copies, deletes atomic.Int32
+	config := fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.Header.Get("X-Oss-Copy-Source") != "":
+			if copies.Add(1) <= 2 {
+				writeOSSError(w, http.StatusServiceUnavailable, "SlowDown", "Please reduce your request rate.")
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><CopyObjectResult><ETag>"etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></CopyObjectResult>`))
+		case r.Method == http.MethodDelete:
+			if deletes.Add(1) == 1 {
+				writeOSSError(w, http.StatusInternalServerError, "InternalError", "try again")
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeOSSError(w, http.StatusBadRequest, "InvalidArgument", r.Method+" "+r.URL.Path)
+		}
+	})
+
+	if _, err := s.MoveObject(config, "bucket", "a.txt", "bucket", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if copies.Load() != 3 || deletes.Load() != 2 {
+		t.Fatalf("copies %d, deletes %d; want 3 and 2", copies.Load(), deletes.Load())
+	}
+}
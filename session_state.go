@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	sessionStateFileName = "session.json"
+
+	// Navigation saves are coalesced; browsing through folders writes the file once it settles.
+	sessionStateSaveDelay = time.Second
+)
+
+// SessionState is the bucket and prefix a profile was last browsing.
+type SessionState struct {
+	ProfileName string `json:"profileName"`
+	Bucket      string `json:"bucket,omitempty"`
+	Prefix      string `json:"prefix,omitempty"`
+	UpdatedAtMs int64  `json:"updatedAtMs,omitempty"`
+	// Valid is set by GetSessionState when there is a saved location and its bucket still exists.
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type sessionStateEntry struct {
+	Bucket      string `json:"bucket,omitempty"`
+	Prefix      string `json:"prefix,omitempty"`
+	UpdatedAtMs int64  `json:"updatedAtMs"`
+}
+
+func (s *OSSService) sessionStatePath() string {
+	return filepath.Join(s.configDir, sessionStateFileName)
+}
+
+// sessionStatesLocked returns the saved states, reading session.json on first use.
+func (s *OSSService) sessionStatesLocked() map[string]sessionStateEntry {
+	if s.sessionStates != nil {
+		return s.sessionStates
+	}
+	s.sessionStates = make(map[string]sessionStateEntry)
+	if _, err := s.readConfigFile(s.sessionStatePath(), &s.sessionStates); err != nil {
+		s.logOperationEvent(logLevelWarn, "GetSessionState", opOutcomeError, "", err)
+	}
+	if s.sessionStates == nil {
+		s.sessionStates = make(map[string]sessionStateEntry)
+	}
+	return s.sessionStates
+}
+
+func (s *OSSService) writeSessionStatesLocked() error {
+	if s.sessionStateTimer != nil {
+		s.sessionStateTimer.Stop()
+		s.sessionStateTimer = nil
+	}
+	data, err := json.MarshalIndent(s.sessionStatesLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.sessionStatePath(), data)
+}
+
+// flushSessionState writes a pending debounced save right away.
+func (s *OSSService) flushSessionState() error {
+	s.sessionStateMu.Lock()
+	defer s.sessionStateMu.Unlock()
+	if s.sessionStateTimer == nil {
+		return nil
+	}
+	return s.writeSessionStatesLocked()
+}
+
+// SaveSessionState remembers where profileName is browsing. The frontend calls it on every
+// navigation; the file is written once navigation pauses.
+func (s *OSSService) SaveSessionState(profileName string, bucket string, prefix string) error {
+	profileName = strings.TrimSpace(profileName)
+	if profileName == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	bucket = strings.TrimSpace(bucket)
+	if bucket == "" {
+		prefix = ""
+	} else if err := validateBucketName(bucket); err != nil {
+		return err
+	}
+
+	s.sessionStateMu.Lock()
+	defer s.sessionStateMu.Unlock()
+	s.sessionStatesLocked()[profileName] = sessionStateEntry{
+		Bucket:      bucket,
+		Prefix:      normalizeObjectPrefix(prefix),
+		UpdatedAtMs: time.Now().UnixMilli(),
+	}
+	if s.sessionStateTimer == nil {
+		s.sessionStateTimer = time.AfterFunc(sessionStateSaveDelay, func() {
+			if err := s.flushSessionState(); err != nil {
+				s.logOperationEvent(logLevelWarn, "SaveSessionState", opOutcomeError, "", err)
+			}
+		})
+	}
+	return nil
+}
+
+// GetSessionState returns where profileName was last browsing. Valid is false when nothing was
+// saved or the bucket is gone, in which case the UI should open the bucket list.
+func (s *OSSService) GetSessionState(profileName string) (SessionState, error) {
+	profileName = strings.TrimSpace(profileName)
+	s.sessionStateMu.Lock()
+	entry, ok := s.sessionStatesLocked()[profileName]
+	s.sessionStateMu.Unlock()
+
+	state := SessionState{ProfileName: profileName}
+	if !ok {
+		state.Reason = "no saved session"
+		return state, nil
+	}
+	state.Bucket = entry.Bucket
+	state.Prefix = entry.Prefix
+	state.UpdatedAtMs = entry.UpdatedAtMs
+	if entry.Bucket == "" {
+		state.Valid = true
+		return state, nil
+	}
+
+	profile, err := s.GetProfile(profileName)
+	if err != nil {
+		return SessionState{}, err
+	}
+	exists, err := s.bucketExists(profile.Config, entry.Bucket)
+	switch {
+	case err != nil:
+		state.Reason = err.Error()
+	case !exists:
+		state.Reason = fmt.Sprintf("Bucket %s no longer exists.", entry.Bucket)
+	default:
+		state.Valid = true
+	}
+	return state, nil
+}
+
+// renameSessionState moves the saved session of a renamed profile.
+func (s *OSSService) renameSessionState(oldName string, newName string) error {
+	s.sessionStateMu.Lock()
+	defer s.sessionStateMu.Unlock()
+	states := s.sessionStatesLocked()
+	entry, ok := states[oldName]
+	if !ok {
+		return nil
+	}
+	delete(states, oldName)
+	states[newName] = entry
+	return s.writeSessionStatesLocked()
+}
+
+// deleteSessionState forgets the saved session of a deleted profile.
+func (s *OSSService) deleteSessionState(name string) error {
+	s.sessionStateMu.Lock()
+	defer s.sessionStateMu.Unlock()
+	states := s.sessionStatesLocked()
+	if _, ok := states[name]; !ok {
+		return nil
+	}
+	delete(states, name)
+	return s.writeSessionStatesLocked()
+}
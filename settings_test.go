@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestResetInvalidSettingsKeepsValidFields(t *testing.T) {
+	defaults := defaultAppSettings(t.TempDir())
+	settings := defaults
+	settings.Theme = "neon"
+	settings.MaxTransferThreads = 1000
+	settings.SDKMaxRetries = -1
+	settings.Language = "tlh"
+	settings.ProfileLockMinutes = 5
+	settings.NewTabNameRule = "newTab"
+
+	got, reset := resetInvalidSettings(settings, defaults)
+	sort.Strings(reset)
+	if want := []string{"language", "maxTransferThreads", "sdkMaxRetries", "theme"}; !reflect.DeepEqual(reset, want) {
+		t.Errorf("reset fields = %v, want %v", reset, want)
+	}
+	if got.Theme != defaults.Theme || got.MaxTransferThreads != defaults.MaxTransferThreads || got.SDKMaxRetries != defaults.SDKMaxRetries || got.Language != defaults.Language {
+		t.Errorf("invalid fields not reset to their defaults: %+v", got)
+	}
+	if got.ProfileLockMinutes != 5 || got.NewTabNameRule != "newTab" {
+		t.Errorf("valid fields were reset too: %+v", got)
+	}
+
+	if _, reset := resetInvalidSettings(defaults, defaults); len(reset) != 0 {
+		t.Errorf("the defaults themselves were reset: %v", reset)
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	s := newTestService(t)
+	settings, err := s.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Theme = themeLight
+	settings.MaxTransferThreads = 8
+	settings.ProfileLockMinutes = 15
+	if err := s.SaveSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := newServiceIn(t, s).GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Theme != themeLight || got.MaxTransferThreads != 8 || got.ProfileLockMinutes != 15 {
+		t.Errorf("settings after a reload = %+v", got)
+	}
+}
+
+// newServiceIn returns a fresh service reading the config directory of s, as after a restart.
+func newServiceIn(t *testing.T, s *OSSService) *OSSService {
+	t.Helper()
+	fresh := NewOSSService()
+	fresh.configDir = s.configDir
+	return fresh
+}
+
+func writeStateFile(t *testing.T, s *OSSService, content string) {
+	t.Helper()
+	if err := os.MkdirAll(s.configDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.stateFilePathIn(s.configDir), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSettingsMissingFieldsGetDefaults(t *testing.T) {
+	s := newTestService(t)
+	writeStateFile(t, s, `{"schemaVersion": 2, "settings": {"theme": "light"}, "profiles": []}`)
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := defaultAppSettings(s.configDir)
+	if settings.Theme != themeLight {
+		t.Errorf("theme = %q, want the stored light", settings.Theme)
+	}
+	if settings.MaxTransferThreads != defaults.MaxTransferThreads || settings.ProfileLockMinutes != defaults.ProfileLockMinutes || settings.CredentialStorage != defaults.CredentialStorage {
+		t.Errorf("missing fields did not get their defaults: %+v", settings)
+	}
+}
+
+func TestSettingsOutOfRangeFieldFallsBackAlone(t *testing.T) {
+	s := newTestService(t)
+	writeStateFile(t, s, `{"schemaVersion": 2, "settings": {"theme": "light", "maxTransferThreads": 999, "profileLockMinutes": 20}, "profiles": []}`)
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.MaxTransferThreads != defaultAppSettings(s.configDir).MaxTransferThreads {
+		t.Errorf("maxTransferThreads = %d, want the default", settings.MaxTransferThreads)
+	}
+	if settings.Theme != themeLight || settings.ProfileLockMinutes != 20 {
+		t.Errorf("the valid fields were lost: %+v", settings)
+	}
+}
+
+func TestSettingsRecoverFromBackup(t *testing.T) {
+	s := newTestService(t)
+	settings, err := s.GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Theme = themeLight
+	// The second save backs up the first.
+	for range 2 {
+		if err := s.SaveSettings(settings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeStateFile(t, s, `{"schemaVersion": 2, "settings": {"theme": "li`)
+
+	got, err := newServiceIn(t, s).GetSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Theme != themeLight {
+		t.Errorf("theme after recovering = %q, want light from config.json.bak", got.Theme)
+	}
+	data, err := os.ReadFile(s.stateFilePathIn(s.configDir))
+	if err != nil || decodeJSONInto(data, &appState{}) != nil {
+		t.Errorf("config.json was not restored from the backup: %v", err)
+	}
+}
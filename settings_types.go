@@ -6,8 +6,46 @@ type AppSettings struct {
 	WorkDir            string `json:"workDir"`
 	DefaultRegion      string `json:"defaultRegion"`
 	DefaultEndpoint    string `json:"defaultEndpoint"`
-	Theme              string `json:"theme"` // "light" or "dark"
+	Theme              string `json:"theme"` // "light", "dark" or "system"
 	MaxTransferThreads int    `json:"maxTransferThreads"`
-	NewTabNameRule     string `json:"newTabNameRule"`   // "folder" | "newTab"
-	FileListViewMode   string `json:"fileListViewMode"` // "classic" | "finder"
+	NewTabNameRule     string `json:"newTabNameRule"`     // "folder" | "newTab"
+	FileListViewMode   string `json:"fileListViewMode"`   // "classic" | "finder"
+	CredentialStorage  string `json:"credentialStorage"`  // "keychain" | "plaintext"
+	ProfileLockMinutes int    `json:"profileLockMinutes"` // idle minutes before encrypted profiles lock again
+	// ProxyURL is the default proxy (http, https or socks5) for every SDK client and ossutil call;
+	// a profile's own proxy takes precedence.
+	ProxyURL      string `json:"proxyUrl,omitempty"`
+	ProxyUser     string `json:"proxyUser,omitempty"`
+	ProxyPassword string `json:"proxyPassword,omitempty"`
+	// DefaultDownloadDir is used by QuickDownload and as the starting folder of save dialogs.
+	DefaultDownloadDir string `json:"defaultDownloadDir,omitempty"`
+	// OssutilExtraArgs are appended to every ossutil call that talks to OSS, e.g. --loglevel debug.
+	OssutilExtraArgs []string `json:"ossutilExtraArgs,omitempty"`
+	// SDK client timeouts and how often idempotent SDK calls are retried on transient errors.
+	SDKConnectTimeoutSec   int `json:"sdkConnectTimeoutSec"`
+	SDKReadWriteTimeoutSec int `json:"sdkReadWriteTimeoutSec"`
+	SDKMaxRetries          int `json:"sdkMaxRetries"`
+	// LogLevel is the lowest level written to the operation log: "debug", "info", "warn" or "error".
+	LogLevel string `json:"logLevel"`
+	// Language selects the catalog for backend messages, e.g. "en" or "zh-CN"; see GetAvailableLanguages.
+	Language string `json:"language"`
+	// Notifications chooses which events raise a desktop notification.
+	Notifications NotificationSettings `json:"notifications"`
+	// SkipDestructiveConfirmation lets destructive calls run without a RequestDestructiveToken token.
+	SkipDestructiveConfirmation bool `json:"skipDestructiveConfirmation,omitempty"`
+	// ProgressEmitIntervalMs is how often a transfer reports progress, 50–5000 ms.
+	ProgressEmitIntervalMs int `json:"progressEmitIntervalMs"`
+	// SkipDiskSpaceCheck lets downloads start without checking free space, for filesystems whose
+	// free space does not reflect what fits (sparse or compressed ones).
+	SkipDiskSpaceCheck bool `json:"skipDiskSpaceCheck,omitempty"`
+	// OssutilTimeoutSec bounds ossutil commands other than transfers, 5–3600 s.
+	OssutilTimeoutSec int `json:"ossutilTimeoutSec"`
+	// TLSCACertFile is a PEM CA bundle trusted in addition to the system store, for private
+	// deployments with an internal CA. A profile's own CA file takes precedence.
+	TLSCACertFile string `json:"tlsCaCertFile,omitempty"`
+	// TLSInsecureSkipVerify accepts any TLS certificate for every profile except public endpoints.
+	TLSInsecureSkipVerify bool `json:"tlsInsecureSkipVerify,omitempty"`
+	// StoragePrices is the price table of EstimateStorageCost, edited through SaveStoragePrices.
+	// Empty means the shipped prices.
+	StoragePrices []StoragePrice `json:"storagePrices,omitempty"`
 }
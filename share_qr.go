@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultShareQRSize = 256
+	minShareQRSize     = 64
+	maxShareQRSize     = 2048
+
+	// shareQRLevel recovers from 15% damage, enough for a code shown on a screen.
+	shareQRLevel = qrcode.Medium
+	// shareQRMaxBytes is what the largest QR code (version 40) holds in byte mode at shareQRLevel.
+	shareQRMaxBytes = 2331
+)
+
+// shareQRCodePNG renders link as a PNG QR code size pixels wide.
+func (s *OSSService) shareQRCodePNG(link string, size int) ([]byte, error) {
+	link = strings.TrimSpace(link)
+	parsed, err := url.Parse(link)
+	if link == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, s.errorf(msgShareQRInvalidURL)
+	}
+	if size == 0 {
+		size = defaultShareQRSize
+	}
+	if size < minShareQRSize || size > maxShareQRSize {
+		return nil, s.errorf(msgShareQRInvalidSize, minShareQRSize, maxShareQRSize)
+	}
+	if len(link) > shareQRMaxBytes {
+		return nil, s.errorf(msgShareQRTooLong, len(link), shareQRMaxBytes)
+	}
+
+	code, err := qrcode.New(link, shareQRLevel)
+	if err != nil {
+		return nil, s.errorf(msgShareQRTooLong, len(link), shareQRMaxBytes)
+	}
+	return code.PNG(size)
+}
+
+// GenerateShareQRCode renders a share link, typically a presigned URL, as a PNG QR code and returns
+// it as a data: URL for an <img> tag. size is the width in pixels; 0 means 256.
+func (s *OSSService) GenerateShareQRCode(link string, size int) (string, error) {
+	png, err := s.shareQRCodePNG(link, size)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
+
+// SaveShareQRCode writes the QR code of GenerateShareQRCode to a temp file, to be dragged out of
+// the app, and returns its path. The file is removed with the other temp files.
+func (s *OSSService) SaveShareQRCode(link string, size int) (string, error) {
+	png, err := s.shareQRCodePNG(link, size)
+	if err != nil {
+		return "", err
+	}
+	file, err := s.tempFiles.create(tempPurposeShare, "walioss-share-*.png")
+	if err != nil {
+		return "", s.errorf(msgCreateTempFileFailed, err)
+	}
+	_, writeErr := file.Write(png)
+	closeErr := file.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		s.tempFiles.release(file.Name())
+		return "", s.errorf(msgWriteTempFileFailed, err)
+	}
+	s.tempFiles.finish(file.Name())
+	return file.Name(), nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusWarn     = "warn"
+	healthStatusError    = "error"
+	healthStatusTimedOut = "timed out"
+	healthStatusSkipped  = "skipped"
+
+	healthCheckOssutil    = "ossutil"
+	healthCheckProfiles   = "profiles"
+	healthCheckDefault    = "defaultProfile"
+	healthCheckConnection = "connection"
+
+	// Local checks should be instant; the connection test gets long enough for one slow round trip.
+	healthLocalCheckTimeout      = 3 * time.Second
+	healthConnectionCheckTimeout = 6 * time.Second
+)
+
+// HealthCheckResult is the outcome of one startup check.
+type HealthCheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"` // "ok" | "warn" | "error" | "timed out" | "skipped"
+	DurationMs int64  `json:"durationMs"`
+	Message    string `json:"message,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+}
+
+// StartupHealthReport is emitted as "startup:health" by RunStartupHealthCheck.
+type StartupHealthReport struct {
+	Checks         []HealthCheckResult `json:"checks"`
+	DurationMs     int64               `json:"durationMs"`
+	DefaultProfile string              `json:"defaultProfile,omitempty"`
+	OssutilVersion *OssutilVersion     `json:"ossutilVersion,omitempty"`
+}
+
+// runHealthCheck runs check with a deadline. A check that overruns is reported as timed out and
+// left to finish in the background; its result is dropped.
+func runHealthCheck(name string, timeout time.Duration, check func() HealthCheckResult) HealthCheckResult {
+	start := time.Now()
+	done := make(chan HealthCheckResult, 1)
+	go func() {
+		done <- check()
+	}()
+
+	var result HealthCheckResult
+	select {
+	case result = <-done:
+	case <-time.After(timeout):
+		result = HealthCheckResult{
+			Status:  healthStatusTimedOut,
+			Message: fmt.Sprintf("no answer within %s", timeout),
+			Hint:    "Check your network connection and proxy settings.",
+		}
+	}
+	result.Name = name
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+func (s *OSSService) healthCheckOssutil(report *StartupHealthReport) HealthCheckResult {
+	result := s.CheckOssutilInstalled()
+	report.OssutilVersion = result.OssutilVersion
+	switch {
+	case result.Unsupported:
+		return HealthCheckResult{Status: healthStatusError, Message: result.Message, Hint: "Upgrade ossutil or install the bundled version from Settings."}
+	case !result.Success:
+		hint := "Set the ossutil path in Settings."
+		if result.InstallPath != "" {
+			hint = "Install ossutil from Settings or set its path."
+		}
+		return HealthCheckResult{Status: healthStatusError, Message: result.Message, Hint: hint}
+	}
+	return HealthCheckResult{Status: healthStatusOK, Message: result.Message}
+}
+
+func (s *OSSService) healthCheckProfiles() HealthCheckResult {
+	profiles, err := s.storedProfiles()
+	switch {
+	case errors.Is(err, ErrProfilesLocked):
+		return HealthCheckResult{Status: healthStatusWarn, Message: err.Error(), Hint: "Unlock your profiles with the master passphrase."}
+	case err != nil:
+		return HealthCheckResult{Status: healthStatusError, Message: err.Error(), Hint: "Check that the config directory is readable."}
+	case len(profiles) == 0:
+		return HealthCheckResult{Status: healthStatusWarn, Message: "no saved profiles", Hint: "Add a profile to connect to OSS."}
+	}
+	return HealthCheckResult{Status: healthStatusOK, Message: fmt.Sprintf("%d profiles", len(profiles))}
+}
+
+func (s *OSSService) healthCheckDefaultProfile(profile **OSSProfile) HealthCheckResult {
+	defaultProfile, err := s.defaultProfile()
+	if err != nil {
+		return HealthCheckResult{Status: healthStatusError, Message: err.Error()}
+	}
+	if defaultProfile == nil {
+		return HealthCheckResult{Status: healthStatusWarn, Message: "no default profile", Hint: "Mark a profile as default to connect on launch."}
+	}
+	*profile = defaultProfile
+	return HealthCheckResult{Status: healthStatusOK, Message: defaultProfile.Name}
+}
+
+func (s *OSSService) healthCheckConnection(profile OSSProfile) HealthCheckResult {
+	resolved, _, err := s.resolveCredentialsWithSource(profile.Config)
+	if err != nil {
+		return HealthCheckResult{Status: healthStatusError, Message: s.redact(err.Error()), Hint: "Check the credentials of the default profile."}
+	}
+	if err := s.sdkSmokeTestListBuckets(resolved); err != nil {
+		if isAccessDenied(err) {
+			// Credentials scoped to single buckets cannot list buckets but still work.
+			return HealthCheckResult{Status: healthStatusWarn, Message: s.redact(err.Error()), Hint: "These credentials cannot list buckets; pin the buckets you use."}
+		}
+		return HealthCheckResult{Status: healthStatusError, Message: s.redact(err.Error()), Hint: "Check the endpoint, region and network connection."}
+	}
+	return HealthCheckResult{Status: healthStatusOK}
+}
+
+// RunStartupHealthCheck checks ossutil, the saved profiles and the default profile's connection
+// concurrently, emits the report as "startup:health" and returns it. Every check has a deadline, so
+// it finishes in bounded time even when the network is down.
+func (a *App) RunStartupHealthCheck() StartupHealthReport {
+	s := a.OSSService
+	start := time.Now()
+	report := StartupHealthReport{}
+
+	var wg sync.WaitGroup
+	var ossutilResult, profilesResult, defaultResult, connectionResult HealthCheckResult
+
+	// The ossutil check writes report.OssutilVersion, so it gets its own copy of the report.
+	var ossutilReport StartupHealthReport
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		ossutilResult = runHealthCheck(healthCheckOssutil, healthLocalCheckTimeout, func() HealthCheckResult {
+			return s.healthCheckOssutil(&ossutilReport)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		profilesResult = runHealthCheck(healthCheckProfiles, healthLocalCheckTimeout, s.healthCheckProfiles)
+	}()
+	go func() {
+		defer wg.Done()
+		var profile *OSSProfile
+		defaultResult = runHealthCheck(healthCheckDefault, healthLocalCheckTimeout, func() HealthCheckResult {
+			return s.healthCheckDefaultProfile(&profile)
+		})
+		if defaultResult.Status != healthStatusOK || profile == nil {
+			connectionResult = HealthCheckResult{Name: healthCheckConnection, Status: healthStatusSkipped, Message: "no default profile to test"}
+			return
+		}
+		report.DefaultProfile = profile.Name
+		connectionResult = runHealthCheck(healthCheckConnection, healthConnectionCheckTimeout, func() HealthCheckResult {
+			return s.healthCheckConnection(*profile)
+		})
+	}()
+	wg.Wait()
+
+	if ossutilResult.Status != healthStatusTimedOut {
+		report.OssutilVersion = ossutilReport.OssutilVersion
+	}
+	report.Checks = []HealthCheckResult{ossutilResult, profilesResult, defaultResult, connectionResult}
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	for _, check := range report.Checks {
+		level := logLevelInfo
+		if check.Status != healthStatusOK && check.Status != healthStatusSkipped {
+			level = logLevelWarn
+		}
+		s.logOperationEvent(level, "StartupHealthCheck", check.Status, check.Name+": "+check.Message, nil)
+	}
+
+	if ctx := a.runtimeContext(); ctx != nil {
+		runtime.EventsEmit(ctx, "startup:health", report)
+	}
+	return report
+}
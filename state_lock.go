@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const stateLockFileName = ".config.lock"
+
+// lockState guards a read-modify-write cycle of the config files with both the in-process mutex
+// and an OS-level lock, so two walioss processes cannot interleave their saves. The lock file
+// lives in the default config dir, which stays put when the work dir moves. Callers must not
+// nest it.
+func (s *OSSService) lockState() (func(), error) {
+	s.stateMu.Lock()
+
+	if err := os.MkdirAll(s.defaultConfigDir, 0700); err != nil {
+		s.stateMu.Unlock()
+		return nil, err
+	}
+	f, err := os.OpenFile(filepath.Join(s.defaultConfigDir, stateLockFileName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		s.stateMu.Unlock()
+		return nil, fmt.Errorf("failed to open config lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		s.stateMu.Unlock()
+		return nil, fmt.Errorf("failed to lock config: %w", err)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+		s.stateMu.Unlock()
+	}, nil
+}
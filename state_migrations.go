@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrConfigFromNewerVersion is returned when config.json was written by a newer build whose
+// schema this build does not understand. Loading it anyway would silently drop fields.
+var ErrConfigFromNewerVersion = errors.New("config was created by a newer version of walioss")
+
+// appStateMigrations upgrades a raw config.json document one schema version at a time: entry N
+// turns a version N document into version N+1. Add an entry whenever appStateSchemaVersion is
+// bumped.
+var appStateMigrations = map[int]func(doc map[string]json.RawMessage) error{
+	// Files from before schemaVersion existed have the version 1 layout.
+	0: func(doc map[string]json.RawMessage) error { return nil },
+	// Version 2 only added fields with usable zero values.
+	1: func(doc map[string]json.RawMessage) error { return nil },
+	// Version 3 lets secrets leave config.json: profiles may carry a keychain secretRef instead of
+	// the secret, or live in profiles.enc. Version 2 files kept every secret inline and had no
+	// credentialStorage setting; record the keychain storage they now get, so the load moves the
+	// inline secrets out and the file says where they went.
+	2: migrateCredentialStorageSetting,
+}
+
+func migrateCredentialStorageSetting(doc map[string]json.RawMessage) error {
+	settings := map[string]json.RawMessage{}
+	if raw, ok := doc["settings"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return fmt.Errorf("invalid settings: %w", err)
+		}
+	}
+	if _, ok := settings["credentialStorage"]; ok {
+		return nil
+	}
+	settings["credentialStorage"] = json.RawMessage(`"` + credentialStorageKeychain + `"`)
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	doc["settings"] = raw
+	return nil
+}
+
+// migrateAppStateFile upgrades config.json content to appStateSchemaVersion. An upgraded file is
+// written back together with a copy of the original (config.json.v<N>.bak), which later saves
+// scrub of secrets that leave config.json, as they do config.json.bak. Content that is not a
+// JSON object is returned unchanged for the corrupt-file recovery to deal with.
+func (s *OSSService) migrateAppStateFile(dir string, data []byte) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil || doc == nil {
+		return data, nil
+	}
+
+	version := 0
+	if raw, ok := doc["schemaVersion"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid schemaVersion in %s: %w", s.stateFilePathIn(dir), err)
+		}
+	}
+	if version == appStateSchemaVersion {
+		return data, nil
+	}
+	if version > appStateSchemaVersion {
+		return nil, fmt.Errorf("%w: %s has schema version %d, but this build only understands up to %d; please update walioss",
+			ErrConfigFromNewerVersion, s.stateFilePathIn(dir), version, appStateSchemaVersion)
+	}
+
+	for v := version; v < appStateSchemaVersion; v++ {
+		migrate, ok := appStateMigrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from config schema version %d", v)
+		}
+		if err := migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate config from schema version %d: %w", v, err)
+		}
+	}
+	doc["schemaVersion"] = json.RawMessage(fmt.Sprint(appStateSchemaVersion))
+
+	migrated, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	statePath := s.stateFilePathIn(dir)
+	files := []pendingFile{
+		{path: fmt.Sprintf("%s.v%d.bak", statePath, version), data: data},
+		{path: statePath, data: migrated},
+	}
+	s.noteConfigWrite(files)
+	if err := writeFilesTogether(files); err != nil {
+		return nil, fmt.Errorf("failed to save migrated config: %w", err)
+	}
+	return migrated, nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestMigrateAppStateFile(t *testing.T) {
+	for version := 0; version <= appStateSchemaVersion; version++ {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			s := newTestService(t)
+			dir := t.TempDir()
+			original := readFixture(t, fmt.Sprintf("config-v%d.json", version))
+			statePath := s.stateFilePathIn(dir)
+			if err := os.WriteFile(statePath, original, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			migrated, err := s.migrateAppStateFile(dir, original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var state appState
+			if err := json.Unmarshal(migrated, &state); err != nil {
+				t.Fatal(err)
+			}
+			if state.SchemaVersion != appStateSchemaVersion {
+				t.Errorf("schemaVersion = %d, want %d", state.SchemaVersion, appStateSchemaVersion)
+			}
+			if len(state.Profiles) != 1 || state.Profiles[0].Name != "prod" || state.Profiles[0].Config.AccessKeySecret != "fixture-secret" {
+				t.Errorf("profiles did not survive the migration: %+v", state.Profiles)
+			}
+			if state.Settings.DefaultRegion != "cn-hangzhou" || state.Settings.MaxTransferThreads != 3 {
+				t.Errorf("settings did not survive the migration: %+v", state.Settings)
+			}
+
+			backupPath := fmt.Sprintf("%s.v%d.bak", statePath, version)
+			backup, backupErr := os.ReadFile(backupPath)
+			if version == appStateSchemaVersion {
+				if string(migrated) != string(original) {
+					t.Error("a current file was rewritten")
+				}
+				if !errors.Is(backupErr, os.ErrNotExist) {
+					t.Errorf("a current file was backed up: %v", backupErr)
+				}
+				return
+			}
+			if backupErr != nil || string(backup) != string(original) {
+				t.Errorf("backup %s = %q, %v; want the original file", backupPath, backup, backupErr)
+			}
+			onDisk, _ := os.ReadFile(statePath)
+			if string(onDisk) != string(migrated) {
+				t.Error("the migrated file was not written back")
+			}
+
+			// Loading the migrated file again is a no-op.
+			again, err := s.migrateAppStateFile(dir, migrated)
+			if err != nil || string(again) != string(migrated) {
+				t.Errorf("migrating a migrated file = %v, changed %v", err, string(again) != string(migrated))
+			}
+		})
+	}
+}
+
+// TestAppStateMigrationSteps checks each registered step turns the fixture of its version into the
+// fixture of the next one.
+func TestAppStateMigrationSteps(t *testing.T) {
+	for version := 0; version < appStateSchemaVersion; version++ {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			var doc, want map[string]json.RawMessage
+			if err := json.Unmarshal(readFixture(t, fmt.Sprintf("config-v%d.json", version)), &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(readFixture(t, fmt.Sprintf("config-v%d.json", version+1)), &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := appStateMigrations[version](doc); err != nil {
+				t.Fatal(err)
+			}
+			doc["schemaVersion"] = json.RawMessage(fmt.Sprint(version + 1))
+			if got, want := canonicalJSON(t, doc), canonicalJSON(t, want); got != want {
+				t.Errorf("step %d produced\n%s\nwant\n%s", version, got, want)
+			}
+		})
+	}
+}
+
+func TestMigrateCredentialStorageKeepsExplicitSetting(t *testing.T) {
+	for _, tc := range []struct {
+		name, settings, want string
+	}{
+		{"plaintext", `{"credentialStorage": "plaintext"}`, credentialStoragePlaintext},
+		{"keychain", `{"credentialStorage": "keychain"}`, credentialStorageKeychain},
+		{"missing settings", `null`, credentialStorageKeychain},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := map[string]json.RawMessage{"settings": json.RawMessage(tc.settings)}
+			if err := appStateMigrations[2](doc); err != nil {
+				t.Fatal(err)
+			}
+			var settings AppSettings
+			if err := json.Unmarshal(doc["settings"], &settings); err != nil {
+				t.Fatal(err)
+			}
+			if settings.CredentialStorage != tc.want {
+				t.Errorf("credentialStorage = %q, want %q", settings.CredentialStorage, tc.want)
+			}
+		})
+	}
+}
+
+func canonicalJSON(t *testing.T, doc map[string]json.RawMessage) string {
+	t.Helper()
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		t.Fatal(err)
+	}
+	canonical, _ := json.MarshalIndent(value, "", "  ")
+	return string(canonical)
+}
+
+func TestMigrateAppStateFileRefusesNewerVersion(t *testing.T) {
+	s := newTestService(t)
+	dir := t.TempDir()
+	newer := strings.Replace(string(readFixture(t, fmt.Sprintf("config-v%d.json", appStateSchemaVersion))), fmt.Sprintf(`"schemaVersion": %d`, appStateSchemaVersion), fmt.Sprintf(`"schemaVersion": %d`, appStateSchemaVersion+1), 1)
+	statePath := s.stateFilePathIn(dir)
+	if err := os.WriteFile(statePath, []byte(newer), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.migrateAppStateFile(dir, []byte(newer)); !errors.Is(err, ErrConfigFromNewerVersion) {
+		t.Fatalf("err = %v, want ErrConfigFromNewerVersion", err)
+	}
+	if _, err := s.loadAppStateFromDir(dir); !errors.Is(err, ErrConfigFromNewerVersion) {
+		t.Fatalf("loading = %v, want ErrConfigFromNewerVersion", err)
+	}
+	if onDisk, _ := os.ReadFile(statePath); string(onDisk) != newer {
+		t.Error("a file from a newer version was modified")
+	}
+}
+
+func TestMigrationBackupLosesSecretsThatLeaveConfig(t *testing.T) {
+	s := newTestService(t)
+	dir := t.TempDir()
+	if err := os.WriteFile(s.stateFilePathIn(dir), readFixture(t, "config-v1.json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	state, err := s.loadAppStateFromDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state.Profiles[0].Config.AccessKeySecret = ""
+	state.Profiles[0].SecretRef = keychainSecretRef("prod")
+	if err := s.saveAppStateToDir(dir, state); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := os.ReadFile(s.stateFilePathIn(dir) + ".v1.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(backup), "fixture-secret") {
+		t.Fatalf("config.json.v1.bak keeps the secret moved out of config.json:\n%s", backup)
+	}
+	if !strings.Contains(string(backup), `"schemaVersion": 1`) {
+		t.Error("the scrubbed backup lost its schema version")
+	}
+}
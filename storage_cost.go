@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bytesPerBillingGB is the GB storage is billed in.
+const bytesPerBillingGB = 1 << 30
+
+// StoragePrice is the monthly list price of one storage class in one region.
+type StoragePrice struct {
+	Region          string  `json:"region"`
+	StorageClass    string  `json:"storageClass"`
+	PricePerGBMonth float64 `json:"pricePerGbMonth"`
+	Currency        string  `json:"currency"`
+}
+
+// StorageClassCost is the estimated monthly cost of the objects of one storage class.
+type StorageClassCost struct {
+	StorageClass string `json:"storageClass"`
+	Bytes        int64  `json:"bytes"`
+	ObjectCount  int64  `json:"objectCount"`
+	// Priced is false when the price table has no price for the class in the region; the class is
+	// then left out of the total.
+	Priced          bool    `json:"priced"`
+	PricePerGBMonth float64 `json:"pricePerGbMonth"`
+	MonthlyCost     float64 `json:"monthlyCost"`
+}
+
+// CostEstimate is the estimated monthly storage cost of a bucket or prefix.
+type CostEstimate struct {
+	Bucket       string             `json:"bucket"`
+	Prefix       string             `json:"prefix"`
+	Region       string             `json:"region"`
+	Currency     string             `json:"currency"`
+	Classes      []StorageClassCost `json:"classes"`
+	MonthlyTotal float64            `json:"monthlyTotal"`
+	// Disclaimer says what the estimate leaves out; the UI shows it next to the figures.
+	Disclaimer string `json:"disclaimer"`
+}
+
+// defaultStorageClassPrices are the shipped list prices per GB and month, in CNY for mainland
+// China regions and USD elsewhere. They are a starting point for the user's own table.
+var defaultStorageClassPrices = []struct {
+	class    string
+	mainland float64
+	overseas float64
+}{
+	{"Standard", 0.12, 0.0200},
+	{"IA", 0.08, 0.0150},
+	{"Archive", 0.033, 0.0050},
+	{"ColdArchive", 0.015, 0.0025},
+	{"DeepColdArchive", 0.0075, 0.0012},
+}
+
+// defaultStoragePrices is the price table used until the user saves their own: the shipped prices
+// for every public cloud region of the catalog.
+func defaultStoragePrices() []StoragePrice {
+	var prices []StoragePrice
+	for _, region := range knownRegions {
+		if region.Cloud != regionCloudPublic {
+			continue
+		}
+		mainland := strings.HasPrefix(region.ID, "cn-") && region.ID != "cn-hongkong"
+		for _, class := range defaultStorageClassPrices {
+			price := StoragePrice{Region: region.ID, StorageClass: class.class, PricePerGBMonth: class.overseas, Currency: "USD"}
+			if mainland {
+				price.PricePerGBMonth, price.Currency = class.mainland, "CNY"
+			}
+			prices = append(prices, price)
+		}
+	}
+	return prices
+}
+
+// normalizeStoragePrices validates a price table and trims its fields. Prices must be finite and
+// not negative, every region and class may appear once and a region has a single currency.
+func normalizeStoragePrices(prices []StoragePrice) ([]StoragePrice, error) {
+	out := make([]StoragePrice, 0, len(prices))
+	seen := make(map[string]struct{}, len(prices))
+	currencies := make(map[string]string)
+	for i, price := range prices {
+		price.Region = strings.ToLower(normalizeRegion(price.Region))
+		price.StorageClass = strings.TrimSpace(price.StorageClass)
+		price.Currency = strings.ToUpper(strings.TrimSpace(price.Currency))
+		switch {
+		case price.Region == "":
+			return nil, fmt.Errorf("price %d: region is required", i+1)
+		case price.StorageClass == "":
+			return nil, fmt.Errorf("price %d: storage class is required", i+1)
+		case price.Currency == "":
+			return nil, fmt.Errorf("price %d: currency is required", i+1)
+		case math.IsNaN(price.PricePerGBMonth) || math.IsInf(price.PricePerGBMonth, 0) || price.PricePerGBMonth < 0:
+			return nil, fmt.Errorf("price %d: %s %s must be a non-negative number", i+1, price.Region, price.StorageClass)
+		}
+		key := price.Region + "\x00" + strings.ToLower(price.StorageClass)
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("price %d: %s %s is listed twice", i+1, price.Region, price.StorageClass)
+		}
+		seen[key] = struct{}{}
+		if currency, ok := currencies[price.Region]; ok && currency != price.Currency {
+			return nil, fmt.Errorf("price %d: %s mixes currencies %s and %s", i+1, price.Region, currency, price.Currency)
+		}
+		currencies[price.Region] = price.Currency
+		out = append(out, price)
+	}
+	return out, nil
+}
+
+// GetStoragePrices returns the price table EstimateStorageCost uses.
+func (s *OSSService) GetStoragePrices() ([]StoragePrice, error) {
+	unlock, err := s.lockState()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return nil, err
+	}
+	if state.Settings.StoragePrices == nil {
+		return defaultStoragePrices(), nil
+	}
+	return state.Settings.StoragePrices, nil
+}
+
+// SaveStoragePrices replaces the price table. An empty table restores the shipped prices.
+func (s *OSSService) SaveStoragePrices(prices []StoragePrice) error {
+	var normalized []StoragePrice
+	if len(prices) > 0 {
+		var err error
+		if normalized, err = normalizeStoragePrices(prices); err != nil {
+			return err
+		}
+	}
+
+	unlock, err := s.lockState()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.loadAppState()
+	if err != nil {
+		return err
+	}
+	state.Settings.StoragePrices = normalized
+	return s.saveAppStateToDir(s.configDir, state)
+}
+
+// EstimateStorageCost estimates what storing the objects under prefix costs per month at the prices
+// of the price table. A whole bucket is taken from its statistics when OSS reports them; a prefix is
+// listed like GetPrefixStats. Requests, traffic and retrieval are not included.
+func (s *OSSService) EstimateStorageCost(config OSSConfig, bucketName string, prefix string) (_ CostEstimate, err error) {
+	defer s.logOperation("EstimateStorageCost", bucketName, prefix, time.Now(), &err)
+
+	bucketName = strings.TrimSpace(bucketName)
+	if err := validateBucketName(bucketName); err != nil {
+		return CostEstimate{}, err
+	}
+	prefix = normalizeObjectPrefix(prefix)
+
+	var classes []BucketStorageClassStat
+	if prefix == "" {
+		if stat, err := s.GetBucketStat(config, bucketName, false); err == nil && !stat.Unavailable {
+			classes = stat.StorageClasses
+		}
+	}
+	if classes == nil {
+		stats, err := s.GetPrefixStats(config, bucketName, prefix)
+		if err != nil {
+			return CostEstimate{}, err
+		}
+		classes = stats.StorageClasses
+	}
+
+	prices, err := s.GetStoragePrices()
+	if err != nil {
+		return CostEstimate{}, err
+	}
+	region := configRegion(s.configForBucket(config, bucketName))
+	return estimateStorageCost(bucketName, prefix, region, classes, prices, s.msg(msgStorageCostDisclaimer)), nil
+}
+
+func estimateStorageCost(bucketName string, prefix string, region string, classes []BucketStorageClassStat, prices []StoragePrice, disclaimer string) CostEstimate {
+	byClass := make(map[string]StoragePrice)
+	for _, price := range prices {
+		if price.Region == region {
+			byClass[strings.ToLower(price.StorageClass)] = price
+		}
+	}
+
+	estimate := CostEstimate{
+		Bucket:     bucketName,
+		Prefix:     prefix,
+		Region:     region,
+		Classes:    make([]StorageClassCost, 0, len(classes)),
+		Disclaimer: disclaimer,
+	}
+	for _, class := range classes {
+		if class.Storage == 0 && class.ObjectCount == 0 {
+			continue
+		}
+		cost := StorageClassCost{StorageClass: class.StorageClass, Bytes: class.Storage, ObjectCount: class.ObjectCount}
+		if price, ok := byClass[strings.ToLower(class.StorageClass)]; ok {
+			cost.Priced = true
+			cost.PricePerGBMonth = price.PricePerGBMonth
+			cost.MonthlyCost = float64(class.Storage) / bytesPerBillingGB * price.PricePerGBMonth
+			estimate.Currency = price.Currency
+			estimate.MonthlyTotal += cost.MonthlyCost
+		}
+		estimate.Classes = append(estimate.Classes, cost)
+	}
+	sort.Slice(estimate.Classes, func(i, j int) bool {
+		return estimate.Classes[i].MonthlyCost > estimate.Classes[j].MonthlyCost
+	})
+	return estimate
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	tempDirName          = "tmp"
+	tempManifestFileName = "manifest.json"
+
+	// Temp files beyond this total are evicted oldest first.
+	tempFilesMaxBytes = 2 << 30
+
+	// Startup removes what earlier runs left behind; shutdown removes everything not in use.
+	tempFilesStartupMaxAge = 24 * time.Hour
+)
+
+// Temp file purposes; each gets its own subdirectory of ~/.walioss/tmp.
+const (
+	tempPurposeEdit      = "edit"
+	tempPurposePreview   = "preview"
+	tempPurposeOpen      = "open"
+	tempPurposeClipboard = "clipboard"
+	tempPurposeShare     = "share"
+	// tempPurposeCredentials holds the config files that pass credentials to ossutil 1.x.
+	tempPurposeCredentials = "credentials"
+)
+
+type tempFileEntry struct {
+	Path        string `json:"path"`
+	Purpose     string `json:"purpose"`
+	CreatedAtMs int64  `json:"createdAtMs"`
+	SizeBytes   int64  `json:"sizeBytes"`
+}
+
+// TempCleanupResult reports what CleanupTempFiles removed.
+type TempCleanupResult struct {
+	RemovedFiles int   `json:"removedFiles"`
+	FreedBytes   int64 `json:"freedBytes"`
+}
+
+// tempFileManager hands out temp files under one root, remembers them in a manifest so a later run
+// can clean up after a crash, and keeps their total size under tempFilesMaxBytes. Pinned files (open
+// for edit-in-place) are never removed.
+type tempFileManager struct {
+	mu       sync.Mutex
+	root     string
+	maxBytes int64
+	entries  map[string]tempFileEntry
+	pinned   map[string]int
+}
+
+func newTempFileManager(root string) *tempFileManager {
+	m := &tempFileManager{
+		root:     root,
+		maxBytes: tempFilesMaxBytes,
+		entries:  make(map[string]tempFileEntry),
+		pinned:   make(map[string]int),
+	}
+	return m
+}
+
+// loadManifest reads the files an earlier run left behind through read, which moves a damaged
+// manifest aside rather than letting the next save overwrite it.
+func (m *tempFileManager) loadManifest(read configFileReader) error {
+	var entries []tempFileEntry
+	if _, err := read(m.manifestPath(), &entries); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		m.entries[entry.Path] = entry
+	}
+	return nil
+}
+
+func (m *tempFileManager) manifestPath() string {
+	return filepath.Join(m.root, tempManifestFileName)
+}
+
+func (m *tempFileManager) saveManifestLocked() {
+	entries := make([]tempFileEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAtMs < entries[j].CreatedAtMs })
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(m.manifestPath(), data)
+}
+
+// create opens a new empty temp file for purpose. The caller closes it and calls finish once the
+// content is written, or release when done with it.
+func (m *tempFileManager) create(purpose string, pattern string) (*os.File, error) {
+	dir := filepath.Join(m.root, purpose)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.entries[file.Name()] = tempFileEntry{Path: file.Name(), Purpose: purpose, CreatedAtMs: time.Now().UnixMilli()}
+	m.saveManifestLocked()
+	m.mu.Unlock()
+	return file, nil
+}
+
+// finish records the final size of path and evicts old files if the cap is exceeded.
+func (m *tempFileManager) finish(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[path]
+	if !ok {
+		return
+	}
+	entry.SizeBytes = info.Size()
+	m.entries[path] = entry
+	m.enforceCapLocked()
+	m.saveManifestLocked()
+}
+
+// release deletes path and forgets it.
+func (m *tempFileManager) release(path string) {
+	_ = os.Remove(path)
+	m.mu.Lock()
+	delete(m.entries, path)
+	delete(m.pinned, path)
+	m.saveManifestLocked()
+	m.mu.Unlock()
+}
+
+// pin exempts path from cleanup and eviction until the matching unpin.
+func (m *tempFileManager) pin(path string) {
+	m.mu.Lock()
+	m.pinned[path]++
+	m.mu.Unlock()
+}
+
+func (m *tempFileManager) unpin(path string) {
+	m.mu.Lock()
+	if m.pinned[path] <= 1 {
+		delete(m.pinned, path)
+	} else {
+		m.pinned[path]--
+	}
+	m.mu.Unlock()
+}
+
+func (m *tempFileManager) removeLocked(path string, result *TempCleanupResult) {
+	entry := m.entries[path]
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	delete(m.entries, path)
+	result.RemovedFiles++
+	result.FreedBytes += entry.SizeBytes
+}
+
+func (m *tempFileManager) enforceCapLocked() TempCleanupResult {
+	var result TempCleanupResult
+	total := int64(0)
+	entries := make([]tempFileEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		// Credential files are tiny and in use by a running ossutil; evicting one frees nothing.
+		if entry.Purpose == tempPurposeCredentials {
+			continue
+		}
+		total += entry.SizeBytes
+		entries = append(entries, entry)
+	}
+	if total <= m.maxBytes {
+		return result
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAtMs < entries[j].CreatedAtMs })
+	for _, entry := range entries {
+		if total <= m.maxBytes {
+			break
+		}
+		if m.pinned[entry.Path] > 0 {
+			continue
+		}
+		m.removeLocked(entry.Path, &result)
+		total -= entry.SizeBytes
+	}
+	return result
+}
+
+// cleanup removes unpinned temp files created more than olderThan ago, including files a crashed
+// run never recorded, then applies the size cap.
+func (m *tempFileManager) cleanup(olderThan time.Duration) TempCleanupResult {
+	cutoff := time.Now().Add(-olderThan)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result TempCleanupResult
+	for path, entry := range m.entries {
+		if m.pinned[path] > 0 {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			delete(m.entries, path)
+			continue
+		}
+		if time.UnixMilli(entry.CreatedAtMs).Before(cutoff) {
+			m.removeLocked(path, &result)
+		}
+	}
+
+	dirs, _ := os.ReadDir(m.root)
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		files, _ := os.ReadDir(filepath.Join(m.root, dir.Name()))
+		for _, file := range files {
+			path := filepath.Join(m.root, dir.Name(), file.Name())
+			if _, tracked := m.entries[path]; tracked || m.pinned[path] > 0 {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if os.RemoveAll(path) == nil {
+				result.RemovedFiles++
+				result.FreedBytes += info.Size()
+			}
+		}
+	}
+
+	capped := m.enforceCapLocked()
+	result.RemovedFiles += capped.RemovedFiles
+	result.FreedBytes += capped.FreedBytes
+	m.saveManifestLocked()
+	return result
+}
+
+// purge removes every file of purpose whatever its age, tracked or not. Startup purges the ossutil
+// credential files, which hold secrets in plain text and must not outlive a killed run.
+func (m *tempFileManager) purge(purpose string) TempCleanupResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result TempCleanupResult
+	for path, entry := range m.entries {
+		if entry.Purpose == purpose && m.pinned[path] == 0 {
+			m.removeLocked(path, &result)
+		}
+	}
+	dir := filepath.Join(m.root, purpose)
+	files, _ := os.ReadDir(dir)
+	for _, file := range files {
+		path := filepath.Join(dir, file.Name())
+		if m.pinned[path] > 0 {
+			continue
+		}
+		info, err := file.Info()
+		if err == nil && os.RemoveAll(path) == nil {
+			result.RemovedFiles++
+			result.FreedBytes += info.Size()
+		}
+	}
+	m.saveManifestLocked()
+	return result
+}
+
+// CleanupTempFiles deletes the app's temp files older than olderThan, except files open for
+// edit-in-place. It runs at startup and shutdown.
+func (s *OSSService) CleanupTempFiles(olderThan time.Duration) (_ TempCleanupResult, err error) {
+	defer s.logOperation("CleanupTempFiles", "", "", time.Now(), &err)
+	if olderThan < 0 {
+		olderThan = 0
+	}
+	return s.tempFiles.cleanup(olderThan), nil
+}
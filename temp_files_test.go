@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTempFile creates a temp file of purpose holding content and records its size.
+func writeTempFile(t *testing.T, m *tempFileManager, purpose string, content string) string {
+	t.Helper()
+	file, err := m.create(purpose, "test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+	m.finish(file.Name())
+	return file.Name()
+}
+
+func TestPurgeRemovesFreshCredentialFiles(t *testing.T) {
+	root := t.TempDir()
+	m := newTempFileManager(root)
+	tracked := writeTempFile(t, m, tempPurposeCredentials, "accessKeySecret=secret")
+	preview := writeTempFile(t, m, tempPurposePreview, "preview")
+	// A run killed between creating the file and saving the manifest leaves it untracked.
+	untracked := filepath.Join(root, tempPurposeCredentials, "ossutil-killed.conf")
+	if err := os.WriteFile(untracked, []byte("accessKeySecret=secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new run starts from the manifest the killed one left.
+	m = newTempFileManager(root)
+	if err := m.loadManifest(newTestService(t).readConfigFile); err != nil {
+		t.Fatal(err)
+	}
+	result := m.purge(tempPurposeCredentials)
+	if result.RemovedFiles != 2 {
+		t.Fatalf("purge removed %d files, want 2", result.RemovedFiles)
+	}
+	for _, path := range []string{tracked, untracked} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s survived the purge: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(preview); err != nil {
+		t.Errorf("purging credentials removed a preview: %v", err)
+	}
+	if _, tracked := m.entries[tracked]; tracked {
+		t.Error("the purged credential file is still in the manifest")
+	}
+}
+
+func TestSizeCapDoesNotEvictCredentialFiles(t *testing.T) {
+	m := newTempFileManager(t.TempDir())
+	m.maxBytes = 10
+	credentials := writeTempFile(t, m, tempPurposeCredentials, "accessKeySecret=secret")
+	first := writeTempFile(t, m, tempPurposePreview, "12345678")
+	second := writeTempFile(t, m, tempPurposePreview, "12345678")
+
+	if _, err := os.Stat(credentials); err != nil {
+		t.Fatalf("the size cap evicted a credential file in use: %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("the oldest preview was not evicted: %v", err)
+	}
+	if _, err := os.Stat(second); err != nil {
+		t.Errorf("the newest preview was evicted: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	themeLight  = "light"
+	themeDark   = "dark"
+	themeSystem = "system"
+
+	// None of the platforms offers an appearance notification without a native event loop of our
+	// own, so the OS setting is polled.
+	themePollInterval = 3 * time.Second
+)
+
+func validateTheme(theme string) error {
+	switch strings.TrimSpace(theme) {
+	case themeLight, themeDark, themeSystem:
+		return nil
+	}
+	return fmt.Errorf("invalid theme %q: expected light, dark or system", theme)
+}
+
+func (s *OSSService) setTheme(theme string) {
+	s.themeMu.Lock()
+	s.theme = theme
+	s.themeMu.Unlock()
+}
+
+func (s *OSSService) effectiveTheme() string {
+	s.themeMu.RLock()
+	theme := s.theme
+	s.themeMu.RUnlock()
+
+	if theme == themeLight || theme == themeDark {
+		return theme
+	}
+	if osTheme, ok := detectOSTheme(); ok {
+		return osTheme
+	}
+	return themeDark
+}
+
+// GetEffectiveTheme returns "light" or "dark", resolving the "system" setting to the current OS appearance.
+func (s *OSSService) GetEffectiveTheme() string {
+	return s.effectiveTheme()
+}
+
+// watchTheme emits theme:changed with the effective theme whenever it changes, which happens when
+// the setting is "system" and the OS appearance flips.
+func (s *OSSService) watchTheme(ctx context.Context) {
+	last := s.effectiveTheme()
+	ticker := time.NewTicker(themePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := s.effectiveTheme()
+		if current == last {
+			continue
+		}
+		last = current
+		runtime.EventsEmit(ctx, "theme:changed", map[string]string{"theme": current})
+	}
+}
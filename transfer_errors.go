@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Transfer error codes reported in TransferUpdate.ErrorCode, so the frontend can offer the right
+// follow-up: retry, re-enter credentials or check the path.
+const (
+	transferErrAuth         = "auth"
+	transferErrNotFound     = "not-found"
+	transferErrNoPermission = "no-permission"
+	transferErrNetwork      = "network"
+	transferErrDiskFull     = "disk-full"
+	transferErrQuota        = "quota"
+	transferErrCancelled    = "cancelled"
+	transferErrInvalidPath  = "invalid-path"
+	transferErrUnknown      = "unknown"
+)
+
+// transferErrMarkers classify errors that only arrive as ossutil output. OSS error codes come
+// first so a code in the output wins over a generic phrase; matching is case-insensitive.
+var transferErrMarkers = []struct {
+	code    string
+	markers []string
+}{
+	{transferErrAuth, []string{"invalidaccesskeyid", "signaturedoesnotmatch", "securitytokenexpired", "invalidsecuritytoken"}},
+	{transferErrNotFound, []string{"nosuchkey", "nosuchbucket", "no such file or directory", "cannot find the file", "cannot find the path"}},
+	{transferErrNoPermission, []string{"accessdenied", "permission denied", "access is denied"}},
+	{transferErrQuota, []string{"quota", "qpslimitexceeded", "toomanyrequests"}},
+	{transferErrDiskFull, []string{"no space left on device", "not enough space on the disk"}},
+	{transferErrNetwork, []string{"no such host", "connection refused", "connection reset", "network is unreachable", "i/o timeout", "tls handshake timeout"}},
+	{transferErrCancelled, []string{"context canceled", "signal: killed"}},
+}
+
+// classifyTransferError maps a transfer failure to one of the transferErr codes. It understands
+// SDK service errors, the app's own sentinel and validation errors, local file system errors and
+// the text ossutil prints.
+func classifyTransferError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	switch {
+	case errors.Is(err, ErrOperationCanceled) || errors.Is(err, context.Canceled):
+		return transferErrCancelled
+	case errors.Is(err, ErrCredentialsNeedReauth):
+		return transferErrAuth
+	case errors.Is(err, ErrReadOnlyProfile) || errors.Is(err, ErrDirectoryNotWritable) || errors.Is(err, os.ErrPermission):
+		return transferErrNoPermission
+	case errors.Is(err, ErrOffline) || errors.Is(err, ErrOperationTimedOut) || errors.Is(err, ErrInternalEndpointUnreachable):
+		return transferErrNetwork
+	case errors.Is(err, syscall.ENOSPC):
+		return transferErrDiskFull
+	case errors.Is(err, os.ErrNotExist):
+		return transferErrNotFound
+	}
+	var spaceErr *InsufficientDiskSpaceError
+	if errors.As(err, &spaceErr) {
+		return transferErrDiskFull
+	}
+	var pathErr *TransferPathError
+	if errors.As(err, &pathErr) {
+		return transferErrInvalidPath
+	}
+
+	var serviceErr oss.ServiceError
+	if errors.As(err, &serviceErr) {
+		switch {
+		case serviceErr.StatusCode == http.StatusTooManyRequests:
+			return transferErrQuota
+		case serviceErr.StatusCode == http.StatusNotFound:
+			return transferErrNotFound
+		}
+	}
+	if isNetworkError(err) {
+		return transferErrNetwork
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, group := range transferErrMarkers {
+		for _, marker := range group.markers {
+			if strings.Contains(msg, marker) {
+				return group.code
+			}
+		}
+	}
+	if serviceErr.StatusCode == http.StatusForbidden {
+		return transferErrNoPermission
+	}
+	return transferErrUnknown
+}
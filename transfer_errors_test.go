@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"syscall"
+	"testing"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Output captured from failing ossutil 1.x and 2.x runs.
+var ossutilFailureOutputs = []struct {
+	output string
+	want   string
+}{
+	{`Error: oss: service returned error: StatusCode=403, ErrorCode=InvalidAccessKeyId, ErrorMessage="The OSS Access Key Id you provided does not exist in our records.", RequestId=65A1B2C3D4E5F6, Bucket=photos, Object=a.jpg`, transferErrAuth},
+	{`Error: operation error PutObject: Server returned error: StatusCode=403, ErrorCode=SignatureDoesNotMatch, ErrorMessage="The request signature we calculated does not match the signature you provided."`, transferErrAuth},
+	{`Error: oss: service returned error: StatusCode=403, ErrorCode=SecurityTokenExpired, ErrorMessage="The security token you provided has expired."`, transferErrAuth},
+	{`Error: oss: service returned error: StatusCode=404, ErrorCode=NoSuchKey, ErrorMessage="The specified key does not exist.", Bucket=photos, Object=missing.jpg`, transferErrNotFound},
+	{`Error: oss: service returned error: StatusCode=404, ErrorCode=NoSuchBucket, ErrorMessage="The specified bucket does not exist."`, transferErrNotFound},
+	{`Error: stat /home/me/missing.txt: no such file or directory`, transferErrNotFound},
+	{`Error: open C:\Users\me\missing.txt: The system cannot find the file specified.`, transferErrNotFound},
+	{`Error: oss: service returned error: StatusCode=403, ErrorCode=AccessDenied, ErrorMessage="You have no right to access this object because of bucket acl."`, transferErrNoPermission},
+	{`Error: open /root/secret.txt: permission denied`, transferErrNoPermission},
+	{`Error: open C:\Windows\x.txt: Access is denied.`, transferErrNoPermission},
+	{`Error: oss: service returned error: StatusCode=503, ErrorCode=QpsLimitExceeded, ErrorMessage="Please reduce your request rate."`, transferErrQuota},
+	{`Error: oss: service returned error: StatusCode=403, ErrorCode=UserDisable, ErrorMessage="Quota exceeded for this account."`, transferErrQuota},
+	{`Error: write /data/big.iso: no space left on device`, transferErrDiskFull},
+	{`Error: write D:\big.iso: There is not enough space on the disk.`, transferErrDiskFull},
+	{`Error: Put "https://photos.oss-cn-hangzhou.aliyuncs.com/a.jpg": dial tcp: lookup photos.oss-cn-hangzhou.aliyuncs.com: no such host`, transferErrNetwork},
+	{`Error: read tcp 10.0.0.2:51234->47.110.177.60:443: read: connection reset by peer`, transferErrNetwork},
+	{`Error: net/http: TLS handshake timeout`, transferErrNetwork},
+	{`signal: killed`, transferErrCancelled},
+	{`Error: something nobody has seen before`, transferErrUnknown},
+}
+
+func TestClassifyOssutilOutput(t *testing.T) {
+	for _, tt := range ossutilFailureOutputs {
+		if got := classifyTransferError(errors.New(tt.output)); got != tt.want {
+			t.Errorf("classifyTransferError(%q) = %s, want %s", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyTypedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"canceled", fmt.Errorf("%w: cp", ErrOperationCanceled), transferErrCancelled},
+		{"context canceled", context.Canceled, transferErrCancelled},
+		{"reauth", fmt.Errorf("%w: prod", ErrCredentialsNeedReauth), transferErrAuth},
+		{"read-only", fmt.Errorf("%w: support", ErrReadOnlyProfile), transferErrNoPermission},
+		{"local permission", &fs.PathError{Op: "open", Path: "/x", Err: fs.ErrPermission}, transferErrNoPermission},
+		{"offline", fmt.Errorf("%w (oss-cn-hangzhou.aliyuncs.com:443)", ErrOffline), transferErrNetwork},
+		{"timeout", timeoutError{}, transferErrNetwork},
+		{"refused", &fs.PathError{Op: "dial", Path: "x", Err: syscall.ECONNREFUSED}, transferErrNetwork},
+		{"disk full", &fs.PathError{Op: "write", Path: "/x", Err: syscall.ENOSPC}, transferErrDiskFull},
+		{"no room for the download", &InsufficientDiskSpaceError{Path: "/", NeedBytes: 2, HaveBytes: 1}, transferErrDiskFull},
+		{"missing local file", &fs.PathError{Op: "stat", Path: "/x", Err: fs.ErrNotExist}, transferErrNotFound},
+		{"invalid path", &TransferPathError{Field: "prefix", Value: "a/../b", Component: "..", Reason: "contains the segment"}, transferErrInvalidPath},
+		{"throttled", oss.ServiceError{StatusCode: http.StatusTooManyRequests, Code: "TooManyRequests"}, transferErrQuota},
+		{"object gone", oss.ServiceError{StatusCode: http.StatusNotFound, Code: "NoSuchKey"}, transferErrNotFound},
+		{"forbidden without a known code", oss.ServiceError{StatusCode: http.StatusForbidden, Code: "UserDisable"}, transferErrNoPermission},
+		{"server error", oss.ServiceError{StatusCode: http.StatusInternalServerError, Code: "InternalError"}, transferErrUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyTransferError(tt.err); got != tt.want {
+			t.Errorf("%s: classifyTransferError(%v) = %q, want %q", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestFailedTransferCarriesErrorCode(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, `echo 'Error: oss: service returned error: StatusCode=404, ErrorCode=NoSuchKey, ErrorMessage="The specified key does not exist."' >&2
+exit 1`)
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+
+	var last TransferUpdate
+	update := TransferUpdate{ID: "t1", Type: TransferTypeDownload, Bucket: "bucket", Key: "missing.jpg", LocalPath: t.TempDir() + "/missing.jpg"}
+	s.runTransfer(config, update, func(u TransferUpdate) { last = u })
+	if last.Status != TransferStatusError || last.ErrorCode != transferErrNotFound {
+		t.Errorf("final update = %s / %q, want an error with code %s", last.Status, last.ErrorCode, transferErrNotFound)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// TransferPathError reports a destination prefix, object key or local path a transfer refused.
+// Field names what was wrong ("prefix", "key", "remote name" or "local path") and Component the
+// offending segment or character.
+type TransferPathError struct {
+	Field     string
+	Value     string
+	Component string
+	Reason    string
+}
+
+func (e *TransferPathError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s %q", e.Field, e.Value, e.Reason, e.Component)
+}
+
+// checkObjectSegments rejects "." and ".." segments and control characters, which would produce
+// keys the user never meant or that other tools cannot handle.
+func checkObjectSegments(field string, value string, segments []string) error {
+	for _, segment := range segments {
+		if segment == "." || segment == ".." {
+			return &TransferPathError{Field: field, Value: value, Component: segment, Reason: "contains the segment"}
+		}
+		for _, r := range segment {
+			if unicode.IsControl(r) {
+				return &TransferPathError{Field: field, Value: value, Component: fmt.Sprintf("%U", r), Reason: "contains the control character"}
+			}
+		}
+	}
+	return nil
+}
+
+// sanitizeObjectPrefix checks a destination prefix, which is the location the user is browsing.
+// It follows the rule of normalizeObjectPrefix: the prefix is kept as it is, leading slashes,
+// empty segments and surrounding spaces included, since keys written by other tools can hold them
+// and trimming would upload somewhere else than the folder on screen; only a trailing slash is
+// added. Backslashes, "." and ".." segments and control characters are rejected. Names the user
+// types go through joinTypedName instead.
+func sanitizeObjectPrefix(prefix string) (string, error) {
+	if strings.Contains(prefix, "\\") {
+		return "", &TransferPathError{Field: "prefix", Value: prefix, Component: "\\", Reason: "contains the character"}
+	}
+	if err := checkObjectSegments("prefix", prefix, strings.Split(prefix, "/")); err != nil {
+		return "", err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix, nil
+}
+
+// sanitizeRemoteName checks a single file or folder name chosen for an upload.
+func sanitizeRemoteName(name string) error {
+	return checkObjectSegments("remote name", name, []string{name})
+}
+
+// containedLocalPath joins relative onto baseDir and verifies the cleaned result stays inside
+// baseDir, so keys cannot direct a download outside the chosen directory.
+func containedLocalPath(baseDir string, relative string) (string, error) {
+	base := filepath.Clean(baseDir)
+	target := filepath.Clean(filepath.Join(base, relative))
+	rel, err := filepath.Rel(base, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", &TransferPathError{Field: "local path", Value: relative, Component: target, Reason: "leaves the download directory at"}
+	}
+	return target, nil
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeObjectPrefixKeepsLocation(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{"", ""},
+		{"photos", "photos/"},
+		{"photos/", "photos/"},
+		{"photos//2024/", "photos//2024/"},
+		{"/leading/", "/leading/"},
+		{"//", "//"},
+		// Spaces belong to the key, as they do in normalizeObjectPrefix.
+		{" spaced name /", " spaced name /"},
+	}
+	for _, tt := range tests {
+		got, err := sanitizeObjectPrefix(tt.prefix)
+		if err != nil || got != tt.want {
+			t.Errorf("sanitizeObjectPrefix(%q) = %q, %v; want %q", tt.prefix, got, err, tt.want)
+		}
+	}
+}
+
+func TestJoinTypedNameCollapsesSlashesInName(t *testing.T) {
+	tests := []struct {
+		prefix, name, want string
+	}{
+		{"a//b/", "new", "a//b/new"},
+		{"a/", "x//y/", "a/x/y"},
+		{"", "/typed/", "typed"},
+	}
+	for _, tt := range tests {
+		if got := joinTypedName(tt.prefix, tt.name); got != tt.want {
+			t.Errorf("joinTypedName(%q, %q) = %q, want %q", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOssPathKeepsLeadingSlashes(t *testing.T) {
+	for _, key := range []string{"/rooted", "a//b", "//", "plain"} {
+		path := buildOssPath("bucket", key)
+		bucket, got, ok := parseOssPath(path)
+		if !ok || bucket != "bucket" || got != key {
+			t.Errorf("parseOssPath(buildOssPath(%q)) = %q, %q, %v", key, bucket, got, ok)
+		}
+	}
+}
+
+func TestSanitizeObjectPrefixRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		prefix    string
+		component string
+	}{
+		{"backslash", `photos\2024/`, `\`},
+		{"dot dot", "../secret/", ".."},
+		{"dot dot inside", "a/../b/", ".."},
+		{"dot", "a/./b/", "."},
+		{"dot dot after empty segment", "a//../", ".."},
+		{"newline", "a\nb/", "U+000A"},
+		{"nul", "a/\x00/", "U+0000"},
+		{"delete", "a\x7f/", "U+007F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sanitizeObjectPrefix(tt.prefix)
+			var pathErr *TransferPathError
+			if !errors.As(err, &pathErr) {
+				t.Fatalf("sanitizeObjectPrefix(%q) = %v, want a TransferPathError", tt.prefix, err)
+			}
+			if pathErr.Field != "prefix" || pathErr.Value != tt.prefix || pathErr.Component != tt.component {
+				t.Errorf("error = %+v, want component %q", pathErr, tt.component)
+			}
+			if classifyTransferError(err) != transferErrInvalidPath {
+				t.Errorf("classified as %q", classifyTransferError(err))
+			}
+		})
+	}
+}
+
+func TestSanitizeObjectPrefixAllows(t *testing.T) {
+	// Empty segments, dots within names and Unicode are ordinary key characters.
+	for _, prefix := range []string{"a//b/", "..hidden/", "v1.2/", "...", "照片/2024/", "a b/#1/"} {
+		if _, err := sanitizeObjectPrefix(prefix); err != nil {
+			t.Errorf("sanitizeObjectPrefix(%q) = %v", prefix, err)
+		}
+	}
+}
+
+func TestSanitizeRemoteName(t *testing.T) {
+	for _, name := range []string{".", "..", "a\tb"} {
+		if err := sanitizeRemoteName(name); err == nil {
+			t.Errorf("sanitizeRemoteName(%q) accepted", name)
+		}
+	}
+	for _, name := range []string{"report.pdf", "..report", "名前"} {
+		if err := sanitizeRemoteName(name); err != nil {
+			t.Errorf("sanitizeRemoteName(%q) = %v", name, err)
+		}
+	}
+}
+
+func TestContainedLocalPath(t *testing.T) {
+	base := t.TempDir()
+	tests := []struct {
+		relative string
+		want     string
+		ok       bool
+	}{
+		{"a/b.txt", filepath.Join(base, "a", "b.txt"), true},
+		{"a/../b.txt", filepath.Join(base, "b.txt"), true},
+		{"./c.txt", filepath.Join(base, "c.txt"), true},
+		{"../escape.txt", "", false},
+		{"a/../../escape.txt", "", false},
+		{"..", "", false},
+	}
+	for _, tt := range tests {
+		got, err := containedLocalPath(base, tt.relative)
+		if !tt.ok {
+			var pathErr *TransferPathError
+			if !errors.As(err, &pathErr) || pathErr.Field != "local path" {
+				t.Errorf("containedLocalPath(%q) = %q, %v; want a local path error", tt.relative, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("containedLocalPath(%q) = %q, %v; want %q", tt.relative, got, err, tt.want)
+		}
+	}
+}
+
+// uploadKeyTests are the destination keys of uploading db.sql under each prefix. Prefixes are
+// kept as the location the user is browsing, only completed with a trailing slash.
+var uploadKeyTests = []struct {
+	prefix, want string
+}{
+	{"", "db.sql"},
+	{"a", "a/db.sql"},
+	{"a/", "a/db.sql"},
+	{"/a/b", "/a/b/db.sql"},
+}
+
+// uploadFixture returns a local db.sql, a config for a fake bucket without objects and a fake
+// ossutil that appends its arguments to the returned file.
+func uploadFixture(t *testing.T, s *OSSService) (localPath string, config OSSConfig, argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	localPath = filepath.Join(dir, "db.sql")
+	if err := os.WriteFile(localPath, []byte("select 1;"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	argsFile = filepath.Join(dir, "args")
+	fakeOssutil(t, s, `if [ "$1" = "cp" ]; then echo "$@" >> '`+argsFile+`'; fi`)
+	config = fakeOSS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	return localPath, config, argsFile
+}
+
+func TestUploadFileKey(t *testing.T) {
+	for _, tt := range uploadKeyTests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			s := newTestService(t)
+			localPath, config, argsFile := uploadFixture(t, s)
+
+			if err := s.UploadFile(config, "bucket", tt.prefix, localPath); err != nil {
+				t.Fatal(err)
+			}
+			args, _ := os.ReadFile(argsFile)
+			if want := "cp " + localPath + " " + buildOssPath("bucket", tt.want); !strings.HasPrefix(string(args), want+" ") {
+				t.Errorf("ossutil ran %q, want %q", args, want)
+			}
+		})
+	}
+}
+
+func TestEnqueueUploadKey(t *testing.T) {
+	for _, tt := range uploadKeyTests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			s := newTestService(t)
+			localPath, config, _ := uploadFixture(t, s)
+
+			id, err := s.EnqueueUpload(config, "bucket", tt.prefix, localPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			update := waitForTransfer(t, s, id)
+			if update.Key != tt.want {
+				t.Errorf("key = %q, want %q", update.Key, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadsRejectUnsafePrefixes(t *testing.T) {
+	for _, prefix := range []string{`a\b`, "a/../b", "../a"} {
+		s := newTestService(t)
+		localPath, config, argsFile := uploadFixture(t, s)
+
+		var pathErr *TransferPathError
+		if err := s.UploadFile(config, "bucket", prefix, localPath); !errors.As(err, &pathErr) {
+			t.Errorf("UploadFile(prefix %q) = %v, want a TransferPathError", prefix, err)
+		}
+		if _, err := s.EnqueueUpload(config, "bucket", prefix, localPath); !errors.As(err, &pathErr) {
+			t.Errorf("EnqueueUpload(prefix %q) = %v, want a TransferPathError", prefix, err)
+		}
+		if _, err := os.Stat(argsFile); err == nil {
+			t.Errorf("ossutil ran for prefix %q", prefix)
+		}
+	}
+}
+
+func TestListingChild(t *testing.T) {
+	tests := []struct {
+		prefix, key string
+		name        string
+		isFolder    bool
+		ok          bool
+	}{
+		{"", "logs/", "logs", true, true},
+		{"logs/", "logs//", "", true, true},
+		{"logs//", "logs//2024/", "2024", true, true},
+		{"logs//2024/", "logs//2024//", "", true, true},
+		{"logs//2024//", "logs//2024//app.log", "app.log", false, true},
+		{"", "/", "", true, true},
+		{"/", "/rooted.txt", "rooted.txt", false, true},
+		{"/", "//", "", true, true},
+		{"logs/", "logs/", "", false, false},
+		{"logs/", "logs//2024/", "", false, false},
+		{"logs//", "logs/2024/", "", false, false},
+		{"other/", "logs//", "", false, false},
+	}
+	for _, tt := range tests {
+		name, isFolder, ok := listingChild(tt.prefix, tt.key)
+		if name != tt.name || isFolder != tt.isFolder || ok != tt.ok {
+			t.Errorf("listingChild(%q, %q) = %q, %v, %v; want %q, %v, %v", tt.prefix, tt.key, name, isFolder, ok, tt.name, tt.isFolder, tt.ok)
+		}
+	}
+}
+
+// delimitedListing serves keys as OSS lists them with the request's prefix and "/" delimiter.
+func delimitedListing(keys []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		var objects, prefixes []string
+		seen := make(map[string]bool)
+		for _, key := range keys {
+			rest, ok := strings.CutPrefix(key, prefix)
+			if !ok {
+				continue
+			}
+			if i := strings.Index(rest, "/"); i >= 0 && r.URL.Query().Get("delimiter") == "/" {
+				if common := prefix + rest[:i+1]; !seen[common] {
+					seen[common] = true
+					prefixes = append(prefixes, common)
+				}
+				continue
+			}
+			objects = append(objects, key)
+		}
+		writeListing(w, objects, prefixes)
+	}
+}
+
+func TestListObjectsPageEmptySegments(t *testing.T) {
+	s := newTestService(t)
+	config := fakeOSS(t, delimitedListing([]string{"logs//2024//app.log", "logs/top.txt", "/rooted.txt", "//double.txt"}))
+
+	// Each entry is name, type and path; an empty segment is a folder named "" that opens.
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{" Folder oss://bucket//", "logs Folder oss://bucket/logs/"}},
+		{"logs", []string{" Folder oss://bucket/logs//", "top.txt File oss://bucket/logs/top.txt"}},
+		{"logs//", []string{"2024 Folder oss://bucket/logs//2024/"}},
+		{"logs//2024/", []string{" Folder oss://bucket/logs//2024//"}},
+		{"logs//2024//", []string{"app.log File oss://bucket/logs//2024//app.log"}},
+		{"/", []string{" Folder oss://bucket///", "rooted.txt File oss://bucket//rooted.txt"}},
+		{"//", []string{"double.txt File oss://bucket///double.txt"}},
+	}
+	for _, tt := range tests {
+		page, err := s.ListObjectsPage(config, "bucket", tt.prefix, "", 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, item := range page.Items {
+			got = append(got, item.Name+" "+item.Type+" "+item.Path)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("ListObjectsPage(%q) =\n%s\nwant\n%s", tt.prefix, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+		// Opening a listed folder lists what is below it, so every path leads on.
+		for _, item := range page.Items {
+			if _, key, ok := parseOssPath(item.Path); item.Type == "Folder" && (!ok || !strings.HasSuffix(key, "/")) {
+				t.Errorf("folder %+v has no prefix path", item)
+			}
+		}
+	}
+}
+
+func TestMovedKeyKeepsEmptySegments(t *testing.T) {
+	tests := []struct {
+		src, dest, key string
+		want           string
+		ok             bool
+	}{
+		{"logs//", "archive/", "logs//2024//app.log", "archive/2024//app.log", true},
+		{"logs/", "archive/", "logs//2024//app.log", "archive//2024//app.log", true},
+		{"logs//2024//", "flat/", "logs//2024//app.log", "flat/app.log", true},
+		{"logs/", "archive//", "logs/top.txt", "archive//top.txt", true},
+		{"logs//", "archive/", "logs/top.txt", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := movedKey(tt.src, tt.dest, tt.key)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("movedKey(%q, %q, %q) = %q, %v; want %q, %v", tt.src, tt.dest, tt.key, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// transferJob is a transfer waiting for a worker.
+type transferJob struct {
+	config   OSSConfig
+	update   TransferUpdate
+	onUpdate func(TransferUpdate)
+	// probeAddr is the endpoint the transfer talks to; the job is not handed out while it is offline.
+	probeAddr string
+}
+
+// transferQueue runs transfers in the order they were queued on at most max workers. Queuing only
+// appends a record; workers are started as needed and exit when the queue runs dry, so a dropped
+// folder of 50,000 files costs 50,000 records, not 50,000 parked goroutines.
+//
+// Jobs whose endpoint is offline stay queued, where they can still be canceled, and are skipped so
+// transfers to other endpoints keep running. One goroutine per offline endpoint waits for it to
+// come back and then starts workers again.
+type transferQueue struct {
+	mu      sync.Mutex
+	pending []transferJob
+	workers int
+	max     int
+	closed  bool
+	run     func(transferJob)
+	// blockedBy returns a channel that is closed once job may run, or nil when it may run now.
+	blockedBy func(transferJob) <-chan struct{}
+	// watching holds the channels a goroutine already waits on.
+	watching map[<-chan struct{}]bool
+	done     chan struct{}
+}
+
+func newTransferQueue(max int, run func(transferJob)) *transferQueue {
+	if max < 1 {
+		max = 1
+	}
+	return &transferQueue{max: max, run: run, watching: map[<-chan struct{}]bool{}, done: make(chan struct{})}
+}
+
+func (q *transferQueue) push(jobs ...transferJob) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.pending = append(q.pending, jobs...)
+	start := q.claimWorkersLocked()
+	q.mu.Unlock()
+	q.startWorkers(start)
+}
+
+// claimWorkersLocked counts the workers to start so that every pending job up to max has one.
+func (q *transferQueue) claimWorkersLocked() int {
+	start := q.max - q.workers
+	if start > len(q.pending) {
+		start = len(q.pending)
+	}
+	if start < 0 {
+		start = 0
+	}
+	q.workers += start
+	return start
+}
+
+func (q *transferQueue) startWorkers(n int) {
+	for i := 0; i < n; i++ {
+		go q.work()
+	}
+}
+
+func (q *transferQueue) work() {
+	for {
+		job, ok := q.next()
+		if !ok {
+			return
+		}
+		q.run(job)
+	}
+}
+
+// next hands out the oldest pending job that may run. A worker finding the queue closed, itself
+// over max or no job that may run exits.
+func (q *transferQueue) next() (transferJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed && q.workers <= q.max {
+		for i, job := range q.pending {
+			if q.blockedBy != nil {
+				if wait := q.blockedBy(job); wait != nil {
+					q.watchLocked(wait)
+					continue
+				}
+			}
+			q.takeLocked(i)
+			return job, true
+		}
+	}
+	q.workers--
+	return transferJob{}, false
+}
+
+// takeLocked drops pending job i. The oldest job is the common case and costs no copy.
+func (q *transferQueue) takeLocked(i int) {
+	if i == 0 {
+		q.pending[0] = transferJob{}
+		q.pending = q.pending[1:]
+	} else {
+		copy(q.pending[i:], q.pending[i+1:])
+		q.pending[len(q.pending)-1] = transferJob{}
+		q.pending = q.pending[:len(q.pending)-1]
+	}
+	if len(q.pending) == 0 {
+		q.pending = nil
+	}
+}
+
+// watchLocked starts workers again once wait is closed, unless the queue is closed first.
+func (q *transferQueue) watchLocked(wait <-chan struct{}) {
+	if q.watching[wait] {
+		return
+	}
+	q.watching[wait] = true
+	go func() {
+		select {
+		case <-wait:
+		case <-q.done:
+			return
+		}
+		q.mu.Lock()
+		delete(q.watching, wait)
+		start := 0
+		if !q.closed {
+			start = q.claimWorkersLocked()
+		}
+		q.mu.Unlock()
+		q.startWorkers(start)
+	}()
+}
+
+// setMax changes how many transfers run at once. Extra workers start right away; surplus ones
+// exit after their current transfer.
+func (q *transferQueue) setMax(max int) {
+	if max < 1 {
+		max = 1
+	}
+	q.mu.Lock()
+	q.max = max
+	start := 0
+	if !q.closed {
+		start = q.claimWorkersLocked()
+	}
+	q.mu.Unlock()
+	q.startWorkers(start)
+}
+
+func (q *transferQueue) maxWorkers() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.max
+}
+
+// remove takes the pending jobs for transfer id, or the children of group id, off the queue.
+func (q *transferQueue) remove(id string) []transferJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var removed []transferJob
+	kept := q.pending[:0]
+	for _, job := range q.pending {
+		if job.update.ID == id || job.update.ParentID == id {
+			removed = append(removed, job)
+			continue
+		}
+		kept = append(kept, job)
+	}
+	for i := len(kept); i < len(q.pending); i++ {
+		q.pending[i] = transferJob{}
+	}
+	q.pending = kept
+	return removed
+}
+
+// close stops handing out jobs. Running transfers finish; what is still queued is left for the
+// history to mark as interrupted.
+func (q *transferQueue) close() {
+	q.mu.Lock()
+	if !q.closed {
+		close(q.done)
+	}
+	q.closed = true
+	q.pending = nil
+	q.mu.Unlock()
+}
+
+func (s *OSSService) transfers() *transferQueue {
+	s.transferQueueMu.Lock()
+	defer s.transferQueueMu.Unlock()
+	if s.transferQueue == nil {
+		s.transferQueue = newTransferQueue(1, s.runTransferJob)
+		s.transferQueue.blockedBy = s.transferBlockedBy
+	}
+	return s.transferQueue
+}
+
+// transferBlockedBy keeps transfers queued while their endpoint is offline.
+func (s *OSSService) transferBlockedBy(job transferJob) <-chan struct{} {
+	wait := s.onlineSignal(job.probeAddr)
+	select {
+	case <-wait:
+		return nil
+	default:
+		return wait
+	}
+}
+
+func (s *OSSService) runTransferJob(job transferJob) {
+	s.runTransfer(job.config, job.update, job.onUpdate)
+}
+
+// CancelQueuedTransfer cancels a transfer that has not started yet, or every child of a group that
+// has not started. Transfers already running are not affected.
+func (s *OSSService) CancelQueuedTransfer(id string) error {
+	removed := s.transfers().remove(id)
+	if len(removed) == 0 {
+		return fmt.Errorf("transfer %s is not queued", id)
+	}
+	now := time.Now().UnixMilli()
+	for _, job := range removed {
+		update := job.update
+		update.Status = TransferStatusError
+		update.Message = s.msg(msgTransferCanceledQueued)
+		update.ErrorCode = transferErrCancelled
+		update.FinishedAtMs = now
+		update.UpdatedAtMs = now
+		s.emitTransfer(update, job.onUpdate)
+	}
+	return nil
+}
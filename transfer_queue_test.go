@@ -0,0 +1,400 @@
+package main
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func queuedJob(id string) transferJob {
+	return transferJob{update: TransferUpdate{ID: id, Status: TransferStatusQueued}}
+}
+
+// waitFor polls cond for up to five seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransferQueueStressBoundedGoroutines(t *testing.T) {
+	const jobs, workers = 100_000, 4
+	// slack absorbs goroutines other tests leave behind, such as idle HTTP connections; parked
+	// transfers would add tens of thousands.
+	const slack = 10
+	baseline := runtime.NumGoroutine() + slack
+
+	var wg sync.WaitGroup
+	wg.Add(jobs)
+	ran := make([]atomic.Int32, jobs)
+	var peak atomic.Int64
+	q := newTransferQueue(workers, func(job transferJob) {
+		defer wg.Done()
+		i, _ := strconv.Atoi(job.update.ID)
+		ran[i].Add(1)
+		if i%1000 == 0 {
+			n := int64(runtime.NumGoroutine())
+			for old := peak.Load(); n > old && !peak.CompareAndSwap(old, n); old = peak.Load() {
+			}
+		}
+	})
+	for i := range jobs {
+		q.push(queuedJob(strconv.Itoa(i)))
+		if i%1000 == 0 {
+			if n := runtime.NumGoroutine(); n > baseline+workers {
+				t.Fatalf("%d goroutines after queuing %d jobs, want at most %d", n, i+1, baseline+workers)
+			}
+		}
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > int64(baseline+workers) {
+		t.Fatalf("peak of %d goroutines, want at most %d", got, baseline+workers)
+	}
+	for i := range ran {
+		if n := ran[i].Load(); n != 1 {
+			t.Fatalf("job %d ran %d times", i, n)
+		}
+	}
+	// Workers exit once the queue is empty.
+	waitFor(t, "workers to exit", func() bool { return runtime.NumGoroutine() <= baseline })
+}
+
+func TestTransferQueueSingleWorkerRunsInQueueOrder(t *testing.T) {
+	const jobs = 100_000
+	var order []string
+	done := make(chan struct{})
+	q := newTransferQueue(1, func(job transferJob) {
+		order = append(order, job.update.ID)
+		if len(order) == jobs {
+			close(done)
+		}
+	})
+	for i := 0; i < jobs; i += 100 {
+		batch := make([]transferJob, 0, 100)
+		for j := i; j < i+100; j++ {
+			batch = append(batch, queuedJob(strconv.Itoa(j)))
+		}
+		q.push(batch...)
+	}
+	<-done
+	for i, id := range order {
+		if id != strconv.Itoa(i) {
+			t.Fatalf("job %s ran at position %d", id, i)
+		}
+	}
+}
+
+func TestTransferQueueConcurrentPushers(t *testing.T) {
+	const pushers, perPusher, workers = 8, 5000, 3
+	var active, peak atomic.Int32
+	var seen sync.Map
+	var wg sync.WaitGroup
+	wg.Add(pushers * perPusher)
+	q := newTransferQueue(workers, func(job transferJob) {
+		defer wg.Done()
+		n := active.Add(1)
+		for old := peak.Load(); n > old && !peak.CompareAndSwap(old, n); old = peak.Load() {
+		}
+		if _, dup := seen.LoadOrStore(job.update.ID, true); dup {
+			t.Errorf("job %s ran twice", job.update.ID)
+		}
+		active.Add(-1)
+	})
+
+	var pushed sync.WaitGroup
+	for p := range pushers {
+		pushed.Add(1)
+		go func() {
+			defer pushed.Done()
+			for i := range perPusher {
+				q.push(queuedJob(strconv.Itoa(p) + "-" + strconv.Itoa(i)))
+			}
+		}()
+	}
+	pushed.Wait()
+	wg.Wait()
+	if got := peak.Load(); got > workers {
+		t.Fatalf("%d transfers ran at once, want at most %d", got, workers)
+	}
+}
+
+// blockingQueue returns a queue whose jobs block until released, with the number running.
+func blockingQueue(max int) (q *transferQueue, release chan struct{}, running *atomic.Int32, done *sync.Map) {
+	release = make(chan struct{})
+	running = new(atomic.Int32)
+	done = new(sync.Map)
+	q = newTransferQueue(max, func(job transferJob) {
+		running.Add(1)
+		<-release
+		running.Add(-1)
+		done.Store(job.update.ID, true)
+	})
+	return q, release, running, done
+}
+
+func TestTransferQueueSetMax(t *testing.T) {
+	q, release, running, _ := blockingQueue(2)
+	defer close(release)
+	for i := range 10 {
+		q.push(queuedJob(strconv.Itoa(i)))
+	}
+	waitFor(t, "two transfers to run", func() bool { return running.Load() == 2 })
+
+	q.setMax(5)
+	waitFor(t, "five transfers to run", func() bool { return running.Load() == 5 })
+	if q.maxWorkers() != 5 {
+		t.Fatalf("maxWorkers = %d", q.maxWorkers())
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := running.Load(); got != 5 {
+		t.Fatalf("%d transfers running, want 5", got)
+	}
+
+	q.setMax(0)
+	if q.maxWorkers() != 1 {
+		t.Fatalf("maxWorkers = %d after setMax(0), want 1", q.maxWorkers())
+	}
+}
+
+func TestTransferQueueLoweredMaxRetiresWorkers(t *testing.T) {
+	release := make(chan struct{}, 100)
+	var running, peakAfter atomic.Int32
+	var lowered atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(40)
+	q := newTransferQueue(4, func(job transferJob) {
+		defer wg.Done()
+		n := running.Add(1)
+		if lowered.Load() {
+			for old := peakAfter.Load(); n > old && !peakAfter.CompareAndSwap(old, n); old = peakAfter.Load() {
+			}
+		}
+		<-release
+		running.Add(-1)
+	})
+	for i := range 40 {
+		q.push(queuedJob(strconv.Itoa(i)))
+	}
+	waitFor(t, "four transfers to run", func() bool { return running.Load() == 4 })
+	q.setMax(1)
+	// Let the four running finish; from then on one runs at a time.
+	for range 4 {
+		release <- struct{}{}
+	}
+	waitFor(t, "surplus workers to retire", func() bool { return running.Load() <= 1 })
+	lowered.Store(true)
+	for range 36 {
+		release <- struct{}{}
+	}
+	wg.Wait()
+	if got := peakAfter.Load(); got > 1 {
+		t.Fatalf("%d transfers ran at once after lowering the limit to 1", got)
+	}
+}
+
+func TestTransferQueueRemove(t *testing.T) {
+	q, release, running, done := blockingQueue(1)
+	q.push(queuedJob("running"))
+	waitFor(t, "the first transfer to run", func() bool { return running.Load() == 1 })
+
+	group := func(id string) transferJob {
+		job := queuedJob(id)
+		job.update.ParentID = "group"
+		return job
+	}
+	q.push(queuedJob("a"), group("g1"), queuedJob("b"), group("g2"), queuedJob("c"))
+
+	if removed := q.remove("b"); len(removed) != 1 || removed[0].update.ID != "b" {
+		t.Fatalf("remove(b) = %+v", removed)
+	}
+	removed := q.remove("group")
+	if len(removed) != 2 || removed[0].update.ID != "g1" || removed[1].update.ID != "g2" {
+		t.Fatalf("remove(group) = %+v", removed)
+	}
+	if removed := q.remove("running"); len(removed) != 0 {
+		t.Fatalf("a running transfer was removed: %+v", removed)
+	}
+	if removed := q.remove("missing"); len(removed) != 0 {
+		t.Fatalf("remove(missing) = %+v", removed)
+	}
+
+	close(release)
+	waitFor(t, "the queue to drain", func() bool {
+		_, a := done.Load("a")
+		_, c := done.Load("c")
+		return a && c
+	})
+	for _, id := range []string{"b", "g1", "g2"} {
+		if _, ran := done.Load(id); ran {
+			t.Errorf("removed transfer %s ran", id)
+		}
+	}
+}
+
+func TestTransferQueueClose(t *testing.T) {
+	q, release, running, done := blockingQueue(1)
+	q.push(queuedJob("running"), queuedJob("queued"))
+	waitFor(t, "the first transfer to run", func() bool { return running.Load() == 1 })
+
+	q.close()
+	q.push(queuedJob("late"))
+	close(release)
+	waitFor(t, "the running transfer to finish", func() bool {
+		_, ok := done.Load("running")
+		return ok
+	})
+	time.Sleep(20 * time.Millisecond)
+	for _, id := range []string{"queued", "late"} {
+		if _, ran := done.Load(id); ran {
+			t.Errorf("transfer %s ran after close", id)
+		}
+	}
+}
+
+func TestCancelQueuedTransfer(t *testing.T) {
+	s := newTestService(t)
+	q, release, running, done := blockingQueue(1)
+	defer close(release)
+	s.transferQueue = q
+
+	var mu sync.Mutex
+	var updates []TransferUpdate
+	onUpdate := func(update TransferUpdate) {
+		mu.Lock()
+		updates = append(updates, update)
+		mu.Unlock()
+	}
+	q.push(queuedJob("running"))
+	waitFor(t, "the first transfer to run", func() bool { return running.Load() == 1 })
+	job := queuedJob("queued")
+	job.onUpdate = onUpdate
+	q.push(job)
+
+	if err := s.CancelQueuedTransfer("queued"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CancelQueuedTransfer("queued"); err == nil {
+		t.Fatal("canceling twice succeeded")
+	}
+	if err := s.CancelQueuedTransfer("running"); err == nil {
+		t.Fatal("canceling a running transfer succeeded")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates) != 1 {
+		t.Fatalf("updates = %+v", updates)
+	}
+	update := updates[0]
+	if update.ID != "queued" || update.Status != TransferStatusError || update.ErrorCode != transferErrCancelled || update.FinishedAtMs == 0 {
+		t.Fatalf("canceled update = %+v", update)
+	}
+	if _, ran := done.Load("queued"); ran {
+		t.Fatal("the canceled transfer ran")
+	}
+}
+
+// offlineJob is a queued job for the endpoint addr.
+func offlineJob(id string, addr string) transferJob {
+	job := queuedJob(id)
+	job.probeAddr = addr
+	return job
+}
+
+func TestTransferQueueKeepsOfflineJobsQueued(t *testing.T) {
+	s := newTestService(t)
+	s.network.offline["down.example:443"] = make(chan struct{})
+
+	var mu sync.Mutex
+	var order []string
+	q := newTransferQueue(1, func(job transferJob) {
+		mu.Lock()
+		order = append(order, job.update.ID)
+		mu.Unlock()
+	})
+	q.blockedBy = s.transferBlockedBy
+	q.push(offlineJob("down-1", "down.example:443"), offlineJob("up-1", "up.example:443"),
+		offlineJob("down-2", "down.example:443"), offlineJob("up-2", "up.example:443"))
+
+	// The single worker is not parked on the offline endpoint; the healthy transfers run.
+	waitFor(t, "transfers to the healthy endpoint", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 2
+	})
+	waitFor(t, "the worker to exit", func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.workers == 0
+	})
+	if removed := q.remove("down-1"); len(removed) != 1 {
+		t.Fatalf("remove(down-1) = %+v; a job waiting for its endpoint must stay cancelable", removed)
+	}
+
+	s.network.mu.Lock()
+	close(s.network.offline["down.example:443"])
+	delete(s.network.offline, "down.example:443")
+	s.network.mu.Unlock()
+	waitFor(t, "the transfer to come back online", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 3
+	})
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"up-1", "up-2", "down-2"}; strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("ran %v, want %v", order, want)
+	}
+}
+
+func TestTransferQueueCloseStopsOfflineWatch(t *testing.T) {
+	s := newTestService(t)
+	s.network.offline["down.example:443"] = make(chan struct{})
+	baseline := runtime.NumGoroutine()
+
+	var ran atomic.Int32
+	q := newTransferQueue(4, func(transferJob) { ran.Add(1) })
+	q.blockedBy = s.transferBlockedBy
+	for i := range 100 {
+		q.push(offlineJob(strconv.Itoa(i), "down.example:443"))
+	}
+	// One goroutine waits for the endpoint, however many transfers target it.
+	waitFor(t, "workers to exit", func() bool { return runtime.NumGoroutine() <= baseline+1 })
+
+	q.close()
+	waitFor(t, "the watch to stop", func() bool { return runtime.NumGoroutine() <= baseline })
+	if n := ran.Load(); n != 0 {
+		t.Fatalf("%d transfers to an offline endpoint ran", n)
+	}
+}
+
+func TestCancelTransferWaitingForEndpoint(t *testing.T) {
+	s := newTestService(t)
+	s.network.offline["down.example:443"] = make(chan struct{})
+	q := newTransferQueue(1, func(job transferJob) { t.Errorf("transfer %s ran while offline", job.update.ID) })
+	q.blockedBy = s.transferBlockedBy
+	s.transferQueue = q
+
+	var canceled atomic.Value
+	job := offlineJob("waiting", "down.example:443")
+	job.onUpdate = func(update TransferUpdate) { canceled.Store(update) }
+	q.push(job)
+	if err := s.CancelQueuedTransfer("waiting"); err != nil {
+		t.Fatal(err)
+	}
+	update, _ := canceled.Load().(TransferUpdate)
+	if update.ErrorCode != transferErrCancelled {
+		t.Fatalf("canceled update = %+v", update)
+	}
+	q.close()
+}
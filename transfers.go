@@ -46,6 +46,10 @@ const (
 	transferProfileAnonymous       = "__anonymous__"
 	maxTransferHistoryRecords      = 3000
 	transferHistoryPersistInterval = 500 * time.Millisecond
+
+	defaultProgressEmitIntervalMs = 250
+	minProgressEmitIntervalMs     = 50
+	maxProgressEmitIntervalMs     = 5000
 )
 
 type TransferUpdate struct {
@@ -68,9 +72,14 @@ type TransferUpdate struct {
 	SpeedBytesPerSec float64        `json:"speedBytesPerSec,omitempty"`
 	EtaSeconds       int64          `json:"etaSeconds,omitempty"`
 	Message          string         `json:"message,omitempty"`
-	StartedAtMs      int64          `json:"startedAtMs,omitempty"`
-	UpdatedAtMs      int64          `json:"updatedAtMs,omitempty"`
-	FinishedAtMs     int64          `json:"finishedAtMs,omitempty"`
+	// ErrorCode classifies a failure, see classifyTransferError: auth, not-found, no-permission,
+	// network, disk-full, quota, cancelled, invalid-path or unknown.
+	ErrorCode string `json:"errorCode,omitempty"`
+	// Command is the ossutil command line that ran the transfer, with secrets redacted.
+	Command      string `json:"command,omitempty"`
+	StartedAtMs  int64  `json:"startedAtMs,omitempty"`
+	UpdatedAtMs  int64  `json:"updatedAtMs,omitempty"`
+	FinishedAtMs int64  `json:"finishedAtMs,omitempty"`
 }
 
 type transferHistoryStore struct {
@@ -78,50 +87,6 @@ type transferHistoryStore struct {
 	Profiles      map[string][]TransferUpdate `json:"profiles"`
 }
 
-type transferLimiter struct {
-	mu     sync.Mutex
-	cond   *sync.Cond
-	active int
-	max    int
-}
-
-func newTransferLimiter(max int) *transferLimiter {
-	if max < 1 {
-		max = 1
-	}
-	l := &transferLimiter{max: max}
-	l.cond = sync.NewCond(&l.mu)
-	return l
-}
-
-func (l *transferLimiter) Acquire() {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	for l.active >= l.max {
-		l.cond.Wait()
-	}
-	l.active++
-}
-
-func (l *transferLimiter) Release() {
-	l.mu.Lock()
-	if l.active > 0 {
-		l.active--
-	}
-	l.mu.Unlock()
-	l.cond.Broadcast()
-}
-
-func (l *transferLimiter) SetMax(max int) {
-	if max < 1 {
-		max = 1
-	}
-	l.mu.Lock()
-	l.max = max
-	l.mu.Unlock()
-	l.cond.Broadcast()
-}
-
 func (s *OSSService) SetContext(ctx context.Context) {
 	s.transferCtxMu.Lock()
 	s.transferCtx = ctx
@@ -139,6 +104,9 @@ func (s *OSSService) emitTransferUpdate(update TransferUpdate) {
 }
 
 func (s *OSSService) emitTransfer(update TransferUpdate, onUpdate func(TransferUpdate)) {
+	update.Message = s.redact(update.Message)
+	s.logTransferFinished(update)
+	s.notifyTransferFinished(update)
 	s.recordTransferUpdate(update)
 	s.emitTransferUpdate(update)
 	if onUpdate != nil {
@@ -147,22 +115,40 @@ func (s *OSSService) emitTransfer(update TransferUpdate, onUpdate func(TransferU
 }
 
 func (s *OSSService) getMaxTransferThreads() int {
-	s.transferLimiterMu.RLock()
-	defer s.transferLimiterMu.RUnlock()
-	if s.transferLimiter == nil {
-		return 1
+	return s.transfers().maxWorkers()
+}
+func (s *OSSService) setMaxTransferThreads(max int) {
+	s.transfers().setMax(max)
+}
+
+func normalizeProgressEmitIntervalMs(ms int) int {
+	if ms <= 0 {
+		return defaultProgressEmitIntervalMs
+	}
+	if ms < minProgressEmitIntervalMs {
+		return minProgressEmitIntervalMs
 	}
-	return s.transferLimiter.max
+	if ms > maxProgressEmitIntervalMs {
+		return maxProgressEmitIntervalMs
+	}
+	return ms
 }
 
-func (s *OSSService) setMaxTransferThreads(max int) {
-	s.transferLimiterMu.Lock()
-	defer s.transferLimiterMu.Unlock()
-	if s.transferLimiter == nil {
-		s.transferLimiter = newTransferLimiter(max)
-		return
+func (s *OSSService) setProgressEmitInterval(ms int) {
+	s.progressEmitMu.Lock()
+	s.progressEmitInterval = time.Duration(normalizeProgressEmitIntervalMs(ms)) * time.Millisecond
+	s.progressEmitMu.Unlock()
+}
+
+// transferEmitInterval is how often a transfer starting now emits progress. Transfers read it once
+// when they start, so a changed setting applies to the next transfer, not to running ones.
+func (s *OSSService) transferEmitInterval() time.Duration {
+	s.progressEmitMu.RLock()
+	defer s.progressEmitMu.RUnlock()
+	if s.progressEmitInterval <= 0 {
+		return defaultProgressEmitIntervalMs * time.Millisecond
 	}
-	s.transferLimiter.SetMax(max)
+	return s.progressEmitInterval
 }
 
 func transferSortTimestamp(update TransferUpdate) int64 {
@@ -205,7 +191,6 @@ func normalizeTransferDefaultPath(defaultPath string) string {
 func transferConfigSignature(config OSSConfig) string {
 	return strings.Join([]string{
 		strings.TrimSpace(config.AccessKeyID),
-		strings.TrimSpace(config.AccessKeySecret),
 		normalizeRegion(config.Region),
 		normalizeEndpoint(config.Endpoint),
 		normalizeTransferDefaultPath(config.DefaultPath),
@@ -418,7 +403,7 @@ func (s *OSSService) persistTransferHistory(path string, history []TransferUpdat
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o600)
+	return writeFileAtomic(path, data)
 }
 
 func (s *OSSService) ensureTransferHistoryLoadedLocked() {
@@ -454,7 +439,7 @@ func (s *OSSService) ensureTransferHistoryLoadedLocked() {
 		if item.Status == TransferStatusQueued || item.Status == TransferStatusInProgress {
 			item.Status = TransferStatusError
 			if strings.TrimSpace(item.Message) == "" {
-				item.Message = "Interrupted when application exited"
+				item.Message = s.msg(msgTransferInterrupted)
 			}
 			item.SpeedBytesPerSec = 0
 			item.EtaSeconds = 0
@@ -558,23 +543,15 @@ type transferGroupChildState struct {
 	Status           TransferStatus
 	StartedAtMs      int64
 	FinishedAtMs     int64
+	ErrorCode        string
 }
 
 func normalizeTransferBucket(bucket string) string {
 	return strings.Trim(strings.TrimSpace(bucket), "/")
 }
 
-func normalizeTransferPrefix(prefix string) string {
-	prefix = strings.TrimSpace(prefix)
-	prefix = strings.TrimLeft(prefix, "/")
-	if prefix != "" && !strings.HasSuffix(prefix, "/") {
-		prefix += "/"
-	}
-	return prefix
-}
-
 func normalizeTransferObjectKey(key string) string {
-	return strings.TrimLeft(strings.TrimSpace(key), "/")
+	return normalizeObjectKey(key)
 }
 
 func normalizeTransferFolderKey(key string) string {
@@ -585,36 +562,41 @@ func normalizeTransferFolderKey(key string) string {
 	return key
 }
 
-func safeRelativeDownloadPath(relative string) (string, error) {
+func (s *OSSService) safeRelativeDownloadPath(relative string) (string, error) {
 	relative = strings.TrimSpace(relative)
 	relative = strings.TrimLeft(relative, "/")
 	if relative == "" {
-		return "", errors.New("empty relative path")
+		return "", s.errorf(msgTransferEmptyRelativePath)
 	}
 	clean := filepath.Clean(filepath.FromSlash(relative))
 	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" {
-		return "", fmt.Errorf("unsafe relative path: %s", relative)
+		return "", s.errorf(msgTransferUnsafeRelativePath, relative)
 	}
 	if clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
-		return "", fmt.Errorf("unsafe relative path: %s", relative)
+		return "", s.errorf(msgTransferUnsafeRelativePath, relative)
 	}
-	return clean, nil
+	// Keys always use "/"; each segment becomes a name the local OS accepts (no ":" on Windows).
+	segments := strings.Split(clean, string(filepath.Separator))
+	for i, segment := range segments {
+		segments[i] = localFileName(segment)
+	}
+	return filepath.Join(segments...), nil
 }
 
 func (s *OSSService) newTransferID() string {
 	return fmt.Sprintf("tr-%d-%d", time.Now().UnixMilli(), atomic.AddUint64(&s.transferSeq, 1))
 }
 
-func buildUploadPlan(localPath string) (uploadPlan, error) {
+func (s *OSSService) buildUploadPlan(localPath string) (uploadPlan, error) {
 	localPath = strings.TrimSpace(localPath)
 	if localPath == "" {
-		return uploadPlan{}, errors.New("local path is empty")
+		return uploadPlan{}, s.errorf(msgTransferLocalPathEmpty)
 	}
 	localPath = filepath.Clean(localPath)
 
 	stat, err := os.Stat(localPath)
 	if err != nil {
-		return uploadPlan{}, fmt.Errorf("stat local path failed: %w", err)
+		return uploadPlan{}, s.errorf(msgTransferStatLocalPathFail, err)
 	}
 
 	if !stat.IsDir() {
@@ -683,18 +665,18 @@ func buildUploadPlan(localPath string) (uploadPlan, error) {
 		return nil
 	})
 	if err != nil {
-		return uploadPlan{}, fmt.Errorf("walk folder failed: %w", err)
+		return uploadPlan{}, s.errorf(msgTransferWalkFolderFailed, err)
 	}
 
 	if len(plan.Files) == 0 {
-		return uploadPlan{}, errors.New("folder has no files to upload")
+		return uploadPlan{}, s.errorf(msgTransferNoFilesToUpload)
 	}
 
 	return plan, nil
 }
 
-func buildUploadPlanWithRemoteName(localPath string, remoteName string) (uploadPlan, error) {
-	plan, err := buildUploadPlan(localPath)
+func (s *OSSService) buildUploadPlanWithRemoteName(localPath string, remoteName string) (uploadPlan, error) {
+	plan, err := s.buildUploadPlan(localPath)
 	if err != nil {
 		return uploadPlan{}, err
 	}
@@ -707,10 +689,13 @@ func buildUploadPlanWithRemoteName(localPath string, remoteName string) (uploadP
 	remoteName = strings.Trim(remoteName, "\\")
 	remoteName = strings.TrimSpace(remoteName)
 	if remoteName == "" {
-		return uploadPlan{}, errors.New("remote name is empty")
+		return uploadPlan{}, s.errorf(msgTransferRemoteNameEmpty)
 	}
 	if strings.Contains(remoteName, "/") || strings.Contains(remoteName, "\\") {
-		return uploadPlan{}, fmt.Errorf("invalid remote name: %s", remoteName)
+		return uploadPlan{}, s.errorf(msgTransferInvalidRemoteName, remoteName)
+	}
+	if err := sanitizeRemoteName(remoteName); err != nil {
+		return uploadPlan{}, err
 	}
 
 	if !plan.IsDir {
@@ -752,7 +737,7 @@ func (s *OSSService) enqueueTransfer(config OSSConfig, update TransferUpdate, on
 	}
 	update.ProfileName = normalizeTransferProfileName(update.ProfileName)
 	s.emitTransfer(update, onUpdate)
-	go s.runTransfer(config, update, onUpdate)
+	s.transfers().push(transferJob{config: config, update: update, onUpdate: onUpdate, probeAddr: s.ossutilProbeAddr(config, endpointForData)})
 }
 
 func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate, children []TransferUpdate) error {
@@ -804,7 +789,7 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 	}
 
 	var mu sync.Mutex
-	emitInterval := 250 * time.Millisecond
+	emitInterval := s.transferEmitInterval()
 	var lastEmit time.Time
 	currentGroup := group
 
@@ -824,6 +809,8 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 		hasInProgress := false
 		startedAt := int64(0)
 		finishedAt := int64(0)
+		// errorCode is the code shared by every failed child, or unknown when they differ.
+		errorCode := ""
 
 		for _, child := range childStates {
 			if child.TotalBytes > 0 {
@@ -843,6 +830,11 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 			case TransferStatusError:
 				doneCount++
 				errorCount++
+				if errorCode == "" {
+					errorCode = child.ErrorCode
+				} else if errorCode != child.ErrorCode {
+					errorCode = transferErrUnknown
+				}
 			case TransferStatusInProgress:
 				hasInProgress = true
 			}
@@ -876,7 +868,8 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 		if doneCount >= len(childStates) {
 			if errorCount > 0 {
 				next.Status = TransferStatusError
-				next.Message = fmt.Sprintf("%d succeeded, %d failed", successCount, errorCount)
+				next.Message = s.msg(msgTransferGroupSummary, successCount, errorCount)
+				next.ErrorCode = errorCode
 			} else {
 				next.Status = TransferStatusSuccess
 				next.Message = ""
@@ -893,7 +886,7 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 		} else if hasInProgress || doneCount > 0 || startedAt > 0 {
 			next.Status = TransferStatusInProgress
 			if errorCount > 0 {
-				next.Message = fmt.Sprintf("%d failed", errorCount)
+				next.Message = s.msg(msgTransferGroupFailed, errorCount)
 			} else {
 				next.Message = ""
 			}
@@ -916,6 +909,7 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 		}
 		state.SpeedBytesPerSec = child.SpeedBytesPerSec
 		state.Status = child.Status
+		state.ErrorCode = child.ErrorCode
 		if child.StartedAtMs > 0 && (state.StartedAtMs == 0 || child.StartedAtMs < state.StartedAtMs) {
 			state.StartedAtMs = child.StartedAtMs
 		}
@@ -928,10 +922,12 @@ func (s *OSSService) enqueueTransferGroup(config OSSConfig, group TransferUpdate
 		mu.Unlock()
 	}
 
-	for _, child := range children {
-		child := child
-		go s.runTransfer(config, child, onChildUpdate)
+	jobs := make([]transferJob, len(children))
+	probeAddr := s.ossutilProbeAddr(config, endpointForData)
+	for i, child := range children {
+		jobs[i] = transferJob{config: config, update: child, onUpdate: onChildUpdate, probeAddr: probeAddr}
 	}
+	s.transfers().push(jobs...)
 
 	return nil
 }
@@ -994,13 +990,25 @@ func (s *OSSService) enqueueUploadPlan(config OSSConfig, bucket string, prefix s
 	return group.ID, nil
 }
 
-func (s *OSSService) EnqueueUploadPaths(config OSSConfig, bucket string, prefix string, localPaths []string) ([]string, error) {
+func (s *OSSService) EnqueueUploadPaths(config OSSConfig, bucket string, prefix string, localPaths []string) (_ []string, err error) {
+	defer s.logOperation("EnqueueUploadPaths", bucket, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return nil, err
+	}
+
 	bucket = normalizeTransferBucket(bucket)
 	if bucket == "" {
-		return nil, errors.New("bucket is empty")
+		return nil, s.errorf(msgTransferBucketEmpty)
+	}
+	if err := validateBucketName(bucket); err != nil {
+		return nil, err
 	}
 
-	prefix = normalizeTransferPrefix(prefix)
+	prefix, err = sanitizeObjectPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
 
 	plans := make([]uploadPlan, 0, len(localPaths))
 	for _, localPath := range localPaths {
@@ -1008,14 +1016,14 @@ func (s *OSSService) EnqueueUploadPaths(config OSSConfig, bucket string, prefix
 		if localPath == "" {
 			continue
 		}
-		plan, err := buildUploadPlan(localPath)
+		plan, err := s.buildUploadPlan(localPath)
 		if err != nil {
 			return nil, err
 		}
 		plans = append(plans, plan)
 	}
 	if len(plans) == 0 {
-		return nil, errors.New("no local paths to upload")
+		return nil, s.errorf(msgTransferNoLocalPaths)
 	}
 
 	ids := make([]string, 0, len(plans))
@@ -1029,13 +1037,25 @@ func (s *OSSService) EnqueueUploadPaths(config OSSConfig, bucket string, prefix
 	return ids, nil
 }
 
-func (s *OSSService) EnqueueUploadRoots(config OSSConfig, bucket string, prefix string, roots []UploadRootSpec) ([]string, error) {
+func (s *OSSService) EnqueueUploadRoots(config OSSConfig, bucket string, prefix string, roots []UploadRootSpec) (_ []string, err error) {
+	defer s.logOperation("EnqueueUploadRoots", bucket, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return nil, err
+	}
+
 	bucket = normalizeTransferBucket(bucket)
 	if bucket == "" {
-		return nil, errors.New("bucket is empty")
+		return nil, s.errorf(msgTransferBucketEmpty)
+	}
+	if err := validateBucketName(bucket); err != nil {
+		return nil, err
 	}
 
-	prefix = normalizeTransferPrefix(prefix)
+	prefix, err = sanitizeObjectPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
 
 	plans := make([]uploadPlan, 0, len(roots))
 	for _, root := range roots {
@@ -1043,14 +1063,14 @@ func (s *OSSService) EnqueueUploadRoots(config OSSConfig, bucket string, prefix
 		if localPath == "" {
 			continue
 		}
-		plan, err := buildUploadPlanWithRemoteName(localPath, root.RemoteName)
+		plan, err := s.buildUploadPlanWithRemoteName(localPath, root.RemoteName)
 		if err != nil {
 			return nil, err
 		}
 		plans = append(plans, plan)
 	}
 	if len(plans) == 0 {
-		return nil, errors.New("no local paths to upload")
+		return nil, s.errorf(msgTransferNoLocalPaths)
 	}
 
 	ids := make([]string, 0, len(plans))
@@ -1064,38 +1084,64 @@ func (s *OSSService) EnqueueUploadRoots(config OSSConfig, bucket string, prefix
 	return ids, nil
 }
 
-func (s *OSSService) EnqueueUpload(config OSSConfig, bucket string, prefix string, localPath string) (string, error) {
+func (s *OSSService) EnqueueUpload(config OSSConfig, bucket string, prefix string, localPath string) (_ string, err error) {
+	defer s.logOperation("EnqueueUpload", bucket, prefix, time.Now(), &err)
+
+	if err := s.requireWritable(config); err != nil {
+		return "", err
+	}
+
 	ids, err := s.EnqueueUploadPaths(config, bucket, prefix, []string{localPath})
 	if err != nil {
 		return "", err
 	}
 	if len(ids) == 0 {
-		return "", errors.New("no transfer enqueued")
+		return "", s.errorf(msgTransferNothingEnqueued)
 	}
 	return ids[0], nil
 }
 
-func (s *OSSService) EnqueueDownload(config OSSConfig, bucket string, object string, localPath string, totalBytes int64) (string, error) {
+func (s *OSSService) EnqueueDownload(config OSSConfig, bucket string, object string, localPath string, totalBytes int64) (_ string, err error) {
+	defer s.logOperation("EnqueueDownload", bucket, object, time.Now(), &err)
+
 	localPath = strings.TrimSpace(localPath)
 	object = normalizeTransferObjectKey(object)
 	bucket = normalizeTransferBucket(bucket)
 	if localPath == "" {
-		return "", errors.New("local path is empty")
+		return "", s.errorf(msgTransferLocalPathEmpty)
 	}
 	if bucket == "" {
-		return "", errors.New("bucket is empty")
+		return "", s.errorf(msgTransferBucketEmpty)
 	}
 	if object == "" {
-		return "", errors.New("object key is empty")
+		return "", s.errorf(msgTransferObjectKeyEmpty)
+	}
+	if err := validateObjectRef(bucket, object); err != nil {
+		return "", err
+	}
+	target, intoDir, err := downloadTargetPath(localPath, object)
+	if err != nil {
+		return "", err
 	}
 	if strings.HasSuffix(object, "/") {
-		return "", errors.New("object key points to a folder, use EnqueueDownloadFolder")
+		if intoDir {
+			return s.EnqueueDownloadFolder(config, bucket, object, localPath)
+		}
+		return "", s.errorf(msgTransferObjectKeyIsFolder)
 	}
 
 	name := path.Base(object)
 	if name == "." || name == "/" || name == "" {
 		name = object
 	}
+	if intoDir {
+		localPath = target
+		name = filepath.Base(target)
+	}
+
+	if err := s.checkDiskSpace(filepath.Dir(localPath), totalBytes); err != nil {
+		return "", err
+	}
 
 	update := TransferUpdate{
 		ID:          s.newTransferID(),
@@ -1112,39 +1158,40 @@ func (s *OSSService) EnqueueDownload(config OSSConfig, bucket string, object str
 	return update.ID, nil
 }
 
-func (s *OSSService) EnqueueDownloadFolder(config OSSConfig, bucket string, folderKey string, localDir string) (string, error) {
+func (s *OSSService) EnqueueDownloadFolder(config OSSConfig, bucket string, folderKey string, localDir string) (_ string, err error) {
+	defer s.logOperation("EnqueueDownloadFolder", bucket, folderKey, time.Now(), &err)
+
 	bucket = normalizeTransferBucket(bucket)
 	folderKey = normalizeTransferFolderKey(folderKey)
 	localDir = strings.TrimSpace(localDir)
 
 	if bucket == "" {
-		return "", errors.New("bucket is empty")
+		return "", s.errorf(msgTransferBucketEmpty)
 	}
 	if folderKey == "" {
-		return "", errors.New("folder key is empty")
+		return "", s.errorf(msgTransferFolderKeyEmpty)
+	}
+	if err := validateObjectRef(bucket, folderKey); err != nil {
+		return "", err
 	}
 	if localDir == "" {
-		return "", errors.New("local directory is empty")
+		return "", s.errorf(msgTransferLocalDirEmpty)
 	}
 
 	if err := os.MkdirAll(localDir, 0o755); err != nil {
-		return "", fmt.Errorf("create local directory failed: %w", err)
+		return "", s.errorf(msgTransferCreateLocalDir, err)
 	}
 
 	folderName := path.Base(strings.TrimSuffix(folderKey, "/"))
 	if folderName == "" || folderName == "." || folderName == "/" {
-		return "", errors.New("invalid folder key")
+		return "", s.errorf(msgTransferInvalidFolderKey)
 	}
-	localRoot := filepath.Join(localDir, folderName)
+	localRoot := filepath.Join(localDir, localFileName(folderName))
 
-	client, err := sdkClientFromConfig(config)
+	bkt, err := s.sdkBucket(config, bucket)
 	if err != nil {
 		return "", err
 	}
-	bkt, err := client.Bucket(bucket)
-	if err != nil {
-		return "", fmt.Errorf("failed to open bucket: %w", err)
-	}
 
 	children := make([]TransferUpdate, 0, 32)
 	totalBytes := int64(0)
@@ -1156,7 +1203,7 @@ func (s *OSSService) EnqueueDownloadFolder(config OSSConfig, bucket string, fold
 			oss.MaxKeys(1000),
 		)
 		if listErr != nil {
-			return "", fmt.Errorf("failed to list folder objects: %w", listErr)
+			return "", s.errorf(msgListFolderFailed, listErr)
 		}
 
 		for _, object := range lor.Objects {
@@ -1171,14 +1218,17 @@ func (s *OSSService) EnqueueDownloadFolder(config OSSConfig, bucket string, fold
 				continue
 			}
 
-			relativeLocal, relErr := safeRelativeDownloadPath(relative)
+			relativeLocal, relErr := s.safeRelativeDownloadPath(relative)
 			if relErr != nil {
 				return "", relErr
 			}
 
-			localPath := filepath.Join(localRoot, relativeLocal)
+			localPath, pathErr := containedLocalPath(localRoot, relativeLocal)
+			if pathErr != nil {
+				return "", pathErr
+			}
 			if mkdirErr := os.MkdirAll(filepath.Dir(localPath), 0o755); mkdirErr != nil {
-				return "", fmt.Errorf("prepare local folder failed: %w", mkdirErr)
+				return "", s.errorf(msgTransferPrepareLocalDir, mkdirErr)
 			}
 
 			displayName := path.Join(folderName, strings.ReplaceAll(relativeLocal, string(filepath.Separator), "/"))
@@ -1205,7 +1255,10 @@ func (s *OSSService) EnqueueDownloadFolder(config OSSConfig, bucket string, fold
 	}
 
 	if len(children) == 0 {
-		return "", errors.New("folder has no files to download")
+		return "", s.errorf(msgTransferNoFilesToDownload)
+	}
+	if err := s.checkDiskSpace(localRoot, totalBytes); err != nil {
+		return "", err
 	}
 
 	group := TransferUpdate{
@@ -1321,34 +1374,43 @@ func parseProgressSegment(seg string) parsedProgress {
 	return out
 }
 
-func splitOnCRLF(r io.Reader, emit func(string)) error {
+// maxProgressSegmentBytes bounds how much output splitOnCRLF holds while waiting for a line break.
+// A progress line is far shorter, so a forced chunk still contains whole ones for the regexes.
+const maxProgressSegmentBytes = 64 * 1024
+
+// splitOnCRLF emits r's output one line at a time, treating \r like \n so progress redraws become
+// separate segments. Output without line breaks is emitted in chunks of maxPending bytes.
+func splitOnCRLF(r io.Reader, maxPending int, emit func(string)) error {
 	buf := make([]byte, 4096)
-	var pending []byte
+	pending := make([]byte, 0, maxPending+len(buf))
+	flush := func(b []byte) {
+		if seg := strings.TrimSpace(string(b)); seg != "" {
+			emit(seg)
+		}
+	}
 	for {
 		n, err := r.Read(buf)
 		if n > 0 {
 			pending = append(pending, buf[:n]...)
+			start := 0
 			for {
-				idx := bytes.IndexAny(pending, "\r\n")
+				idx := bytes.IndexAny(pending[start:], "\r\n")
 				if idx == -1 {
 					break
 				}
-				seg := string(pending[:idx])
-				pending = pending[idx+1:]
-				seg = strings.TrimSpace(seg)
-				if seg != "" {
-					emit(seg)
-				}
+				flush(pending[start : start+idx])
+				start += idx + 1
+			}
+			// Keep the unterminated rest at the front so the buffer's capacity is reused.
+			pending = append(pending[:0], pending[start:]...)
+			if len(pending) >= maxPending {
+				flush(pending)
+				pending = pending[:0]
 			}
 		}
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				if len(pending) > 0 {
-					seg := strings.TrimSpace(string(pending))
-					if seg != "" {
-						emit(seg)
-					}
-				}
+				flush(pending)
 				return nil
 			}
 			return err
@@ -1396,86 +1458,72 @@ func (b *ringBuffer) String() string {
 	return strings.TrimSpace(string(b.data))
 }
 
+// runTransfer runs one transfer on a transferQueue worker.
 func (s *OSSService) runTransfer(config OSSConfig, update TransferUpdate, onUpdate func(TransferUpdate)) {
-	s.transferLimiterMu.RLock()
-	limiter := s.transferLimiter
-	s.transferLimiterMu.RUnlock()
-	if limiter == nil {
-		limiter = newTransferLimiter(1)
-		s.transferLimiterMu.Lock()
-		if s.transferLimiter == nil {
-			s.transferLimiter = limiter
-		} else {
-			limiter = s.transferLimiter
-		}
-		s.transferLimiterMu.Unlock()
-	}
-
-	limiter.Acquire()
-	defer limiter.Release()
-
 	update.Status = TransferStatusInProgress
 	update.StartedAtMs = time.Now().UnixMilli()
 	update.UpdatedAtMs = update.StartedAtMs
 	s.emitTransfer(update, onUpdate)
 
 	var args []string
-	region := normalizeRegion(config.Region)
-	endpoint := normalizeEndpoint(config.Endpoint)
 
 	switch update.Type {
 	case TransferTypeDownload:
 		if dir := filepath.Dir(update.LocalPath); dir != "" && dir != "." {
 			if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
 				update.Status = TransferStatusError
-				update.Message = fmt.Sprintf("create local directory failed: %v", mkErr)
+				update.Message = s.errorf(msgTransferCreateLocalDir, mkErr).Error()
+				update.ErrorCode = classifyTransferError(mkErr)
 				update.FinishedAtMs = time.Now().UnixMilli()
 				update.UpdatedAtMs = update.FinishedAtMs
 				s.emitTransfer(update, onUpdate)
 				return
 			}
 		}
-		cloudURL := fmt.Sprintf("oss://%s/%s", update.Bucket, update.Key)
-		args = []string{
-			"cp",
-			cloudURL,
-			update.LocalPath,
-			"--access-key-id", config.AccessKeyID,
-			"--access-key-secret", config.AccessKeySecret,
-			"--region", region,
-			"-f",
-		}
+		cloudURL := buildOssPath(update.Bucket, update.Key)
+		args = []string{"cp", cloudURL, update.LocalPath}
 	case TransferTypeUpload:
-		cloudURL := fmt.Sprintf("oss://%s/%s", update.Bucket, update.Key)
-		args = []string{
-			"cp",
-			update.LocalPath,
-			cloudURL,
-			"--access-key-id", config.AccessKeyID,
-			"--access-key-secret", config.AccessKeySecret,
-			"--region", region,
-			"-f",
-		}
+		cloudURL := buildOssPath(update.Bucket, update.Key)
+		args = []string{"cp", update.LocalPath, cloudURL}
 	default:
 		update.Status = TransferStatusError
-		update.Message = "unknown transfer type"
+		update.Message = s.msg(msgTransferUnknownType)
 		update.FinishedAtMs = time.Now().UnixMilli()
 		update.UpdatedAtMs = update.FinishedAtMs
 		s.emitTransfer(update, onUpdate)
 		return
 	}
 
-	if endpoint != "" {
-		args = append(args, "--endpoint", endpoint)
+	conn, err := s.ossutilConn(config, endpointForData)
+	if err != nil {
+		update.Status = TransferStatusError
+		update.Message = err.Error()
+		update.ErrorCode = classifyTransferError(err)
+		update.FinishedAtMs = time.Now().UnixMilli()
+		update.UpdatedAtMs = update.FinishedAtMs
+		s.emitTransfer(update, onUpdate)
+		return
 	}
+	defer conn.close()
+	args = append(args, conn.args...)
+	args = append(args, conn.dialect.force())
+
+	// Uploads replace objects silently; the audit log records the ones that did.
+	overwrites := update.Type == TransferTypeUpload && s.objectExists(config, update.Bucket, update.Key)
 
-	err := s.runOssutilWithProgress(args, &update, onUpdate)
+	// Transfers run as long as they need; only the app shutting down stops them.
+	err = s.runOssutilWithProgress(s.baseContext(), args, conn, &update, onUpdate)
 	update.FinishedAtMs = time.Now().UnixMilli()
 	update.UpdatedAtMs = update.FinishedAtMs
+	if overwrites {
+		s.recordAudit(config, "Upload", update.Bucket, buildOssPath(update.Bucket, update.Key), auditCount(err), err)
+	}
 
 	if err != nil {
+		s.noteEndpointFailure(conn.probeAddr, err)
 		update.Status = TransferStatusError
 		update.Message = err.Error()
+		update.ErrorCode = classifyTransferError(err)
 		s.emitTransfer(update, onUpdate)
 		return
 	}
@@ -1485,15 +1533,19 @@ func (s *OSSService) runTransfer(config OSSConfig, update TransferUpdate, onUpda
 		update.DoneBytes = update.TotalBytes
 	}
 	s.emitTransfer(update, onUpdate)
+	s.markProfileUsed(config)
 }
 
-func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdate, onUpdate func(TransferUpdate)) error {
+func (s *OSSService) runOssutilWithProgress(ctx context.Context, args []string, conn ossutilConnection, update *TransferUpdate, onUpdate func(TransferUpdate)) error {
 	if update == nil {
 		return errors.New("internal error: missing transfer update")
 	}
 
+	args = s.withOssutilExtraArgs(args)
 	startCmd := func(binary string) (*exec.Cmd, io.ReadCloser, io.ReadCloser, error) {
-		cmd := exec.Command(binary, args...)
+		update.Command = s.ossutilCommandLine(binary, args)
+		s.logOperationEvent(logLevelDebug, "ossutil", opOutcomeStart, update.Command, nil)
+		cmd := ossutilCommand(ctx, binary, conn.env, args...)
 		stdout, err := cmd.StdoutPipe()
 		if err != nil {
 			return nil, nil, nil, err
@@ -1505,28 +1557,30 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 		return cmd, stdout, stderr, cmd.Start()
 	}
 
-	primary := strings.TrimSpace(s.ossutilPath)
-	fallback := strings.TrimSpace(s.defaultOssutilPath)
-	if primary == "" {
-		primary = fallback
-	}
-	if primary == "" {
-		primary = "ossutil"
+	// ossutil echoes the paths; a key such as "50%.csv" must not read as progress.
+	var echoedPaths []string
+	for _, p := range []string{buildOssPath(update.Bucket, update.Key), update.LocalPath} {
+		if p != "" {
+			echoedPaths = append(echoedPaths, p, "")
+		}
 	}
+	withoutPaths := strings.NewReplacer(echoedPaths...)
+
+	primary, fallback := s.ossutilBinaries()
 
 	cmd, stdout, stderr, err := startCmd(primary)
 	if err != nil && ossutilStartFailed(err) && fallback != "" && fallback != primary {
 		cmd, stdout, stderr, err = startCmd(fallback)
 		if err == nil {
-			s.ossutilPath = fallback
+			s.useOssutilFallback(primary, fallback)
 		}
 	}
 	if err != nil {
-		return fmt.Errorf("failed to start ossutil: %w", err)
+		return s.errorf(msgTransferStartFailed, s.redactError(err))
 	}
 
 	outputTail := newRingBuffer(16 * 1024)
-	emitInterval := 250 * time.Millisecond
+	emitInterval := s.transferEmitInterval()
 	staleSpeedAfter := 2 * time.Second
 	var lastEmit time.Time
 
@@ -1537,6 +1591,22 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 	lastDoneSampleBytes := doneBytes
 	var lastDoneSampleAt time.Time
 	var lastProgressAt time.Time
+	// Bytes already added to the usage counters; resumed transfers only count what they move now.
+	countedBytes := doneBytes
+	// countUsage is called with mu held. DoneBytes can drop when ossutil restarts a part; only
+	// growth past the high-water mark counts, so re-sent bytes are not counted twice.
+	countUsage := func() {
+		if doneBytes > countedBytes {
+			s.usage.add(update.ProfileName, update.Type, doneBytes-countedBytes)
+			countedBytes = doneBytes
+		}
+	}
+	// Bytes moved before a failure were transferred all the same.
+	defer func() {
+		mu.Lock()
+		countUsage()
+		mu.Unlock()
+	}()
 
 	emit := func(force bool) {
 		now := time.Now()
@@ -1546,6 +1616,7 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 		lastEmit = now
 
 		mu.Lock()
+		countUsage()
 		update.DoneBytes = doneBytes
 		effectiveSpeedBps := derivedSpeedBps
 		if effectiveSpeedBps <= 0 && cliSpeedBps > 0 {
@@ -1572,11 +1643,11 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		_ = splitOnCRLF(stdout, func(seg string) { segments <- seg })
+		_ = splitOnCRLF(stdout, maxProgressSegmentBytes, func(seg string) { segments <- seg })
 	}()
 	go func() {
 		defer wg.Done()
-		_ = splitOnCRLF(stderr, func(seg string) { segments <- seg })
+		_ = splitOnCRLF(stderr, maxProgressSegmentBytes, func(seg string) { segments <- seg })
 	}()
 	go func() {
 		wg.Wait()
@@ -1584,11 +1655,17 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 	}()
 
 	waitCh := make(chan error, 1)
-	go func() { waitCh <- cmd.Wait() }()
+	go func() {
+		// Wait closes the pipes, so output of a command that exits quickly would be lost.
+		wg.Wait()
+		waitCh <- cmd.Wait()
+	}()
 
 	ticker := time.NewTicker(emitInterval)
 	defer ticker.Stop()
 
+	_, expiryTracked := staticTokenKey(conn.config)
+	tokenExpired := false
 	segmentsOpen := true
 	waitDone := false
 	for segmentsOpen || !waitDone {
@@ -1600,7 +1677,7 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 			}
 
 			seg = stripANSI(seg)
-			p := parseProgressSegment(seg)
+			p := parseProgressSegment(withoutPaths.Replace(seg))
 			if p.hasDone || p.hasSpeed || p.hasPercent {
 				now := time.Now()
 
@@ -1647,6 +1724,12 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 
 			// Non-progress output for debugging/errors.
 			outputTail.AppendLine(strings.TrimSpace(seg))
+			if !tokenExpired && expiryTracked && ossutilOutputTokenExpired([]byte(seg)) {
+				// Every retry would fail the same way; stop now and ask for new credentials.
+				tokenExpired = true
+				s.markNeedsReauth(conn.config)
+				_ = cmd.Process.Kill()
+			}
 		case <-ticker.C:
 			// Heartbeat: keep transfer card updates alive even when ossutil output is sparse.
 			emit(false)
@@ -1657,13 +1740,22 @@ func (s *OSSService) runOssutilWithProgress(args []string, update *TransferUpdat
 	}
 
 	if err != nil {
-		tail := outputTail.String()
+		if tokenExpired {
+			return s.checkNeedsReauth(conn.config)
+		}
+		tail := s.redact(outputTail.String())
 		if tail != "" {
 			return fmt.Errorf("%w: %s", err, tail)
 		}
 		return err
 	}
 
+	// Small files often finish before ossutil prints any progress.
+	mu.Lock()
+	if update.TotalBytes > doneBytes {
+		doneBytes = update.TotalBytes
+	}
+	mu.Unlock()
 	emit(true)
 	return nil
 }
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOssutil installs a shell script as the ossutil binary of s.
+func fakeOssutil(t *testing.T, s *OSSService, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ossutil is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "ossutil")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s.SetOssutilPath(path)
+}
+
+func pendingUsage(s *OSSService, profile string) ProfileDailyUsage {
+	s.usage.pendingMu.Lock()
+	defer s.usage.pendingMu.Unlock()
+	return s.usage.pending[usageKey{date: time.Now().Format(usageDateLayout), profile: profile}]
+}
+
+// waitForTransfer waits until the transfer id has finished and returns its last update.
+func waitForTransfer(t *testing.T, s *OSSService, id string) TransferUpdate {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		history, err := s.GetTransferHistory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, update := range history {
+			if update.ID == id && isTransferFinalStatus(update.Status) {
+				return update
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("transfer %s did not finish", id)
+	return TransferUpdate{}
+}
+
+func TestRunOssutilWithProgressCountsUsageOfFailedTransfer(t *testing.T) {
+	s := newTestService(t)
+	s.progressEmitInterval = time.Hour
+	// The second progress line falls within the emit interval, so only the deferred count sees it.
+	fakeOssutil(t, s, "echo 'OK size: 1000'\necho 'OK size: 5000'\necho 'connection reset by peer' >&2\nexit 1")
+
+	update := TransferUpdate{ProfileName: "prod", Type: TransferTypeDownload, Bucket: "bucket", Key: "key", TotalBytes: 10000}
+	err := s.runOssutilWithProgress(context.Background(), []string{"cp"}, ossutilConnection{}, &update, func(TransferUpdate) {})
+	if err == nil {
+		t.Fatal("the failing ossutil run reported success")
+	}
+	if got := pendingUsage(s, "prod").DownloadedBytes; got != 5000 {
+		t.Fatalf("downloaded bytes = %d, want 5000", got)
+	}
+}
+
+func TestRunOssutilWithProgressCountsResumedBytesOnce(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, "echo 'OK size: 3000'")
+
+	// 2000 bytes were moved, and counted, by an earlier attempt.
+	update := TransferUpdate{ProfileName: "prod", Type: TransferTypeUpload, Bucket: "bucket", Key: "key", TotalBytes: 4000, DoneBytes: 2000}
+	if err := s.runOssutilWithProgress(context.Background(), []string{"cp"}, ossutilConnection{}, &update, func(TransferUpdate) {}); err != nil {
+		t.Fatal(err)
+	}
+	if got := pendingUsage(s, "prod").UploadedBytes; got != 2000 {
+		t.Fatalf("uploaded bytes = %d, want 2000", got)
+	}
+}
+
+// progressScript prints ten progress lines 40 ms apart.
+const progressScript = `i=1
+while [ $i -le 10 ]; do echo "OK size: ${i}000"; i=$((i+1)); sleep 0.04; done`
+
+func countProgressEmits(t *testing.T, s *OSSService, onEach func()) int {
+	t.Helper()
+	emitted := 0
+	update := TransferUpdate{ProfileName: "prod", Type: TransferTypeDownload, Bucket: "bucket", Key: "key", TotalBytes: 10000}
+	err := s.runOssutilWithProgress(context.Background(), []string{"cp"}, ossutilConnection{}, &update, func(TransferUpdate) {
+		emitted++
+		if onEach != nil {
+			onEach()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return emitted
+}
+
+func TestProgressEmitIntervalSetting(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, progressScript)
+
+	s.setProgressEmitInterval(minProgressEmitIntervalMs)
+	fast := countProgressEmits(t, s, nil)
+	s.setProgressEmitInterval(maxProgressEmitIntervalMs)
+	slow := countProgressEmits(t, s, nil)
+
+	// 400 ms of output at most emits once at the start of a 5 s interval.
+	if slow > 2 {
+		t.Errorf("%d emits with a %d ms interval, want at most 2", slow, maxProgressEmitIntervalMs)
+	}
+	if fast < 5 || fast <= slow {
+		t.Errorf("%d emits with a %d ms interval against %d with %d ms", fast, minProgressEmitIntervalMs, slow, maxProgressEmitIntervalMs)
+	}
+}
+
+func TestRunningTransferKeepsEmitInterval(t *testing.T) {
+	s := newTestService(t)
+	fakeOssutil(t, s, progressScript)
+
+	s.setProgressEmitInterval(maxProgressEmitIntervalMs)
+	emitted := countProgressEmits(t, s, func() { s.setProgressEmitInterval(minProgressEmitIntervalMs) })
+	if emitted > 2 {
+		t.Errorf("%d emits after shortening the interval mid-transfer, want the running transfer to keep 5 s", emitted)
+	}
+	if got := s.transferEmitInterval(); got != minProgressEmitIntervalMs*time.Millisecond {
+		t.Errorf("interval for the next transfer = %v, want %d ms", got, minProgressEmitIntervalMs)
+	}
+}
+
+func TestNormalizeProgressEmitIntervalMs(t *testing.T) {
+	tests := map[int]int{
+		0:     defaultProgressEmitIntervalMs,
+		-5:    defaultProgressEmitIntervalMs,
+		10:    minProgressEmitIntervalMs,
+		100:   100,
+		1000:  1000,
+		60000: maxProgressEmitIntervalMs,
+	}
+	for ms, want := range tests {
+		if got := normalizeProgressEmitIntervalMs(ms); got != want {
+			t.Errorf("normalizeProgressEmitIntervalMs(%d) = %d, want %d", ms, got, want)
+		}
+	}
+}
+
+// countingReader serves n bytes of filler without a line break and counts what was read.
+type countingReader struct {
+	remaining int
+	read      int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	n := min(len(p), r.remaining)
+	for i := range n {
+		p[i] = 'x'
+	}
+	r.remaining -= n
+	r.read += n
+	return n, nil
+}
+
+func TestSplitOnCRLFBoundsOutputWithoutLineBreaks(t *testing.T) {
+	const streamBytes = 10 << 20
+	const maxPending = 64 << 10
+	reader := &countingReader{remaining: streamBytes}
+
+	var segments, total, longest, readAtFirst int
+	err := splitOnCRLF(reader, maxPending, func(seg string) {
+		if segments == 0 {
+			readAtFirst = reader.read
+		}
+		segments++
+		total += len(seg)
+		longest = max(longest, len(seg))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segments < streamBytes/(maxPending+4096) {
+		t.Errorf("%d segments for %d bytes, want chunks of about %d", segments, streamBytes, maxPending)
+	}
+	if total != streamBytes {
+		t.Errorf("segments hold %d bytes, want all %d", total, streamBytes)
+	}
+	// One read past the cap at most is buffered before it is flushed.
+	if longest > maxPending+4096 || readAtFirst > maxPending+4096 {
+		t.Errorf("longest segment %d bytes, first emitted after %d; want at most %d buffered", longest, readAtFirst, maxPending+4096)
+	}
+}
+
+func TestSplitOnCRLFSplitsProgressRedraws(t *testing.T) {
+	var got []string
+	input := "OK size: 10\rOK size: 20\r\n\nError: done\npartial"
+	if err := splitOnCRLF(strings.NewReader(input), maxProgressSegmentBytes, func(seg string) { got = append(got, seg) }); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"OK size: 10", "OK size: 20", "Error: done", "partial"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("segments = %q, want %q", got, want)
+	}
+}
+
+// TestSetOssutilPathDuringTransfers toggles the ossutil path, including to a binary that does not
+// exist and makes transfers fall back to the auto-discovered one, while transfers run. Run it with
+// -race.
+func TestSetOssutilPathDuringTransfers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake ossutil is a shell script")
+	}
+	s := newTestService(t)
+	dir := t.TempDir()
+	script := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n[ \"$1\" = cp ] && printf data > \"$3\"\nexit 0\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	discovered, custom := script("ossutil-discovered"), script("ossutil-custom")
+	s.ossutilPathMu.Lock()
+	s.defaultOssutilPath = discovered
+	s.ossutilPathMu.Unlock()
+	s.SetOssutilPath(custom)
+	s.transfers().setMax(4)
+
+	stop := make(chan struct{})
+	toggled := make(chan struct{})
+	go func() {
+		defer close(toggled)
+		paths := []string{custom, filepath.Join(dir, "missing-ossutil"), "", discovered}
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.SetOssutilPath(paths[i%len(paths)])
+			_ = s.GetOssutilPath()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	config := OSSConfig{AccessKeyID: "LTAI-test", AccessKeySecret: "secret", Region: "cn-hangzhou"}
+	const transfers = 40
+	ids := make([]string, transfers)
+	for i := range transfers {
+		id, err := s.EnqueueDownload(config, "bucket", fmt.Sprintf("key-%d", i), filepath.Join(dir, fmt.Sprintf("file-%d", i)), 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+	final := make(map[string]TransferUpdate)
+	waitFor(t, "every transfer to finish", func() bool {
+		history, err := s.GetTransferHistory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, update := range history {
+			if isTransferFinalStatus(update.Status) {
+				final[update.ID] = update
+			}
+		}
+		return len(final) == transfers
+	})
+	for i, id := range ids {
+		if update := final[id]; update.Status != TransferStatusSuccess {
+			t.Errorf("transfer %d = %s: %s", i, update.Status, update.Message)
+		}
+	}
+	close(stop)
+	<-toggled
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+const (
+	// undoStackSize is how many moves UndoLastOperation can walk back.
+	undoStackSize = 20
+	// undoMaxObjects bounds the keys remembered for one move; bigger folder moves are not undoable.
+	undoMaxObjects = 100000
+)
+
+// UndoConflict is a key that changed after the operation being undone, so undoing it would destroy
+// data.
+type UndoConflict struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// UndoResult reports what UndoLastOperation did. When Conflicts is set nothing was changed and the
+// operation stays on the undo stack.
+type UndoResult struct {
+	Operation  string         `json:"operation"`
+	SrcBucket  string         `json:"srcBucket"`
+	SrcPrefix  string         `json:"srcPrefix"`
+	DestBucket string         `json:"destBucket"`
+	DestPrefix string         `json:"destPrefix"`
+	Undone     bool           `json:"undone"`
+	Restored   int            `json:"restored"`
+	Removed    int            `json:"removed"`
+	Conflicts  []UndoConflict `json:"conflicts,omitempty"`
+	// Remaining is how many earlier operations can still be undone.
+	Remaining int    `json:"remaining"`
+	Message   string `json:"message,omitempty"`
+}
+
+// undoObject is one key moved by a reversible operation.
+type undoObject struct {
+	srcKey  string
+	destKey string
+	// etag is the ETag of the copy at destKey right after it was made.
+	etag string
+	// sourceKept is set when the source could not be deleted, leaving a copy; undoing deletes it.
+	sourceKept bool
+}
+
+// undoOperation is a move or rename with what it takes to invert it. srcPrefix and destPrefix
+// bound the keys involved, so the check before undoing lists them instead of asking per key.
+// Deletes never get here: the trash and bucket versioning cover those.
+type undoOperation struct {
+	id          uint64
+	config      OSSConfig
+	description string
+	srcBucket   string
+	srcPrefix   string
+	destBucket  string
+	destPrefix  string
+	objects     []undoObject
+}
+
+type undoStack struct {
+	mu  sync.Mutex
+	ops []undoOperation
+	seq uint64
+}
+
+func newUndoStack() *undoStack {
+	return &undoStack{}
+}
+
+// push remembers op, forgetting the oldest operation when the stack is full.
+func (u *undoStack) push(op undoOperation) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.seq++
+	op.id = u.seq
+	u.ops = append(u.ops, op)
+	if len(u.ops) > undoStackSize {
+		u.ops = append([]undoOperation(nil), u.ops[len(u.ops)-undoStackSize:]...)
+	}
+}
+
+func (u *undoStack) last() (undoOperation, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.ops) == 0 {
+		return undoOperation{}, false
+	}
+	return u.ops[len(u.ops)-1], true
+}
+
+// finish removes operation id, or keeps only the objects still to undo when some are left.
+// It returns how many operations remain.
+func (u *undoStack) finish(id uint64, left []undoObject) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for i := range u.ops {
+		if u.ops[i].id != id {
+			continue
+		}
+		if len(left) > 0 {
+			u.ops[i].objects = left
+		} else {
+			u.ops = append(u.ops[:i], u.ops[i+1:]...)
+		}
+		break
+	}
+	return len(u.ops)
+}
+
+func (u *undoStack) size() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.ops)
+}
+
+// rememberMove puts a move on the undo stack.
+func (s *OSSService) rememberMove(config OSSConfig, srcBucket string, srcPrefix string, destBucket string, destPrefix string, objects []undoObject) {
+	if len(objects) == 0 {
+		return
+	}
+	description := buildOssPath(srcBucket, srcPrefix) + " -> " + buildOssPath(destBucket, destPrefix)
+	if len(objects) > undoMaxObjects {
+		s.logOperationEvent(logLevelWarn, "UndoLastOperation", opOutcomeError, description, fmt.Errorf("%d objects are too many to undo", len(objects)))
+		return
+	}
+	s.undo.push(undoOperation{
+		config:      config,
+		description: description,
+		srcBucket:   srcBucket,
+		srcPrefix:   srcPrefix,
+		destBucket:  destBucket,
+		destPrefix:  destPrefix,
+		objects:     objects,
+	})
+}
+
+// CanUndo returns what UndoLastOperation would undo, or "" when there is nothing to undo.
+func (s *OSSService) CanUndo() string {
+	op, ok := s.undo.last()
+	if !ok {
+		return ""
+	}
+	return op.description
+}
+
+// UndoLastOperation reverts the most recent move or rename: moved objects are moved back and copies
+// left by a failed source delete are deleted. It first checks that every copy still has the ETag it
+// had after the move and that nothing took the place of a moved source; if anything changed it
+// refuses and lists the conflicting keys. It can be canceled through CancelOperation.
+func (s *OSSService) UndoLastOperation() (_ UndoResult, err error) {
+	s.undoRunMu.Lock()
+	defer s.undoRunMu.Unlock()
+
+	op, ok := s.undo.last()
+	if !ok {
+		return UndoResult{}, s.errorf(msgUndoNothing)
+	}
+	defer s.logOperation("UndoLastOperation", op.srcBucket, op.srcPrefix, time.Now(), &err)
+
+	if err := s.requireWritable(op.config); err != nil {
+		return UndoResult{}, err
+	}
+
+	ctx, done := s.startLongOperation(s.baseContext(), "undo "+op.description)
+	defer done()
+
+	srcBucket, err := s.sdkBucket(op.config, op.srcBucket)
+	if err != nil {
+		return UndoResult{}, s.errorf(msgOpenSourceBucketFailed, err)
+	}
+	destBucket, err := s.sdkBucket(op.config, op.destBucket)
+	if err != nil {
+		return UndoResult{}, s.errorf(msgOpenDestBucketFailed, err)
+	}
+
+	result := UndoResult{
+		Operation:  op.description,
+		SrcBucket:  op.srcBucket,
+		SrcPrefix:  op.srcPrefix,
+		DestBucket: op.destBucket,
+		DestPrefix: op.destPrefix,
+	}
+	conflicts, err := s.undoConflicts(ctx, op, srcBucket, destBucket)
+	if err != nil {
+		return UndoResult{}, err
+	}
+	if len(conflicts) > 0 {
+		result.Conflicts = conflicts
+		result.Remaining = s.undo.size()
+		result.Message = s.msg(msgUndoConflicts, len(conflicts))
+		return result, nil
+	}
+
+	for i, object := range op.objects {
+		restores := !object.sourceKept
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%w: %s", ErrOperationCanceled, s.msg(msgUndoCanceled, result.Restored+result.Removed))
+		} else {
+			err = s.undoObject(ctx, op, srcBucket, destBucket, &object)
+		}
+		if err != nil {
+			left := append([]undoObject{object}, op.objects[i+1:]...)
+			s.undo.finish(op.id, left)
+			s.recordAudit(op.config, "Undo", op.srcBucket, op.description, int64(result.Restored+result.Removed), err)
+			return UndoResult{}, err
+		}
+		if restores {
+			result.Restored++
+		} else {
+			result.Removed++
+		}
+	}
+
+	result.Undone = true
+	result.Remaining = s.undo.finish(op.id, nil)
+	result.Message = s.msg(msgUndoDone, result.Restored+result.Removed)
+	s.recordAudit(op.config, "Undo", op.srcBucket, op.description, int64(result.Restored+result.Removed), nil)
+	return result, nil
+}
+
+// undoConflicts lists the keys of op that changed since it ran.
+func (s *OSSService) undoConflicts(ctx context.Context, op undoOperation, srcBucket *oss.Bucket, destBucket *oss.Bucket) ([]UndoConflict, error) {
+	copies, err := s.listUndoETags(ctx, destBucket, op.destPrefix)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := s.listUndoETags(ctx, srcBucket, op.srcPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []UndoConflict
+	for _, object := range op.objects {
+		etag, ok := copies[object.destKey]
+		switch {
+		case !ok:
+			conflicts = append(conflicts, UndoConflict{Key: buildOssPath(op.destBucket, object.destKey), Reason: s.msg(msgUndoCopyMissing)})
+		case !sameETag(etag, object.etag):
+			conflicts = append(conflicts, UndoConflict{Key: buildOssPath(op.destBucket, object.destKey), Reason: s.msg(msgUndoCopyModified)})
+		}
+		if _, taken := sources[object.srcKey]; taken && !object.sourceKept {
+			conflicts = append(conflicts, UndoConflict{Key: buildOssPath(op.srcBucket, object.srcKey), Reason: s.msg(msgUndoSourceTaken)})
+		}
+	}
+	return conflicts, nil
+}
+
+// listUndoETags maps every key under prefix to its ETag.
+func (s *OSSService) listUndoETags(ctx context.Context, bucket *oss.Bucket, prefix string) (map[string]string, error) {
+	etags := make(map[string]string)
+	marker := ""
+	for {
+		var lor oss.ListObjectsResult
+		err := s.withSDKRetryContext(ctx, "ListObjects", func() error {
+			var listErr error
+			lor, listErr = bucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker), oss.MaxKeys(1000))
+			return listErr
+		})
+		if err != nil {
+			return nil, s.errorf(msgListFolderFailed, err)
+		}
+		for _, object := range lor.Objects {
+			etags[normalizeObjectKey(object.Key)] = object.ETag
+		}
+		if !lor.IsTruncated {
+			return etags, nil
+		}
+		marker = lor.NextMarker
+	}
+}
+
+// undoObject moves one object back, or deletes the copy when the source was never deleted. Once
+// the object is back at its source, object is marked so that a retry only deletes the copy.
+func (s *OSSService) undoObject(ctx context.Context, op undoOperation, srcBucket *oss.Bucket, destBucket *oss.Bucket, object *undoObject) error {
+	if !object.sourceKept {
+		err := s.withSDKRetryContext(ctx, "CopyObject", func() error {
+			_, err := s.copyObjectForMove(op.destBucket, srcBucket, object.destKey, object.srcKey)
+			return err
+		})
+		if err != nil {
+			return s.errorf(msgCopyFailed, err)
+		}
+		object.sourceKept = true
+	}
+	if err := s.withSDKRetryContext(ctx, "DeleteObject", func() error { return destBucket.DeleteObject(object.destKey) }); err != nil {
+		return s.deleteSourceError(op.config, op.destBucket, err)
+	}
+	return nil
+}
+
+func sameETag(a string, b string) bool {
+	return strings.EqualFold(strings.Trim(a, `"`), strings.Trim(b, `"`))
+}
@@ -4,35 +4,39 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	oss "github.com/aliyun/aliyun-oss-go-sdk/oss"
 )
 
+// Destinations with more children than this are not listed in full; names the partial listing did
+// not settle are probed one by one instead.
+const uploadCollisionListLimit = 10000
+
 type UploadNameCollision struct {
 	Name         string `json:"name"`
 	FileExists   bool   `json:"fileExists"`
 	FolderExists bool   `json:"folderExists"`
 }
 
-func (s *OSSService) CheckUploadNameCollisions(config OSSConfig, bucket string, prefix string, names []string) ([]UploadNameCollision, error) {
+func (s *OSSService) CheckUploadNameCollisions(config OSSConfig, bucket string, prefix string, names []string) (_ []UploadNameCollision, err error) {
+	defer s.logOperation("CheckUploadNameCollisions", bucket, prefix, time.Now(), &err)
+
 	bucket = normalizeTransferBucket(bucket)
 	if bucket == "" {
 		return nil, errors.New("bucket is empty")
 	}
-
-	prefix = normalizeTransferPrefix(prefix)
-
-	client, err := sdkClientFromConfig(config)
-	if err != nil {
+	if err := validateBucketName(bucket); err != nil {
 		return nil, err
 	}
-	bkt, err := client.Bucket(bucket)
+
+	prefix, err = sanitizeObjectPrefix(prefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open bucket: %w", err)
+		return nil, err
 	}
 
 	seen := map[string]struct{}{}
-	out := make([]UploadNameCollision, 0, len(names))
+	cleaned := make([]string, 0, len(names))
 	for _, name := range names {
 		name = strings.TrimSpace(name)
 		name = strings.Trim(name, "/")
@@ -48,19 +52,44 @@ func (s *OSSService) CheckUploadNameCollisions(config OSSConfig, bucket string,
 			continue
 		}
 		seen[name] = struct{}{}
+		cleaned = append(cleaned, name)
+	}
+	if len(cleaned) == 0 {
+		return []UploadNameCollision{}, nil
+	}
 
-		fileKey := prefix + name
-		fileExists, err := bkt.IsObjectExist(fileKey)
-		if err != nil {
-			return nil, fmt.Errorf("check object existence failed: %w", err)
-		}
+	var children prefixChildren
+	err = s.withBucketRegion(config, bucket, "ListObjects", func(bkt *oss.Bucket) error {
+		var err error
+		children, err = s.listPrefixChildren(bkt, prefix, uploadCollisionListLimit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	bkt, err := s.sdkBucket(config, bucket)
+	if err != nil {
+		return nil, err
+	}
 
-		folderPrefix := prefix + name + "/"
-		lor, err := bkt.ListObjects(oss.Prefix(folderPrefix), oss.MaxKeys(1))
-		if err != nil {
-			return nil, fmt.Errorf("check folder existence failed: %w", err)
+	out := make([]UploadNameCollision, 0, len(cleaned))
+	for _, name := range cleaned {
+		_, fileExists := children.files[name]
+		_, folderExists := children.folders[name]
+
+		// A destination with more children than the listing covers may still hold the name.
+		if !children.complete && !fileExists {
+			if fileExists, err = bkt.IsObjectExist(prefix + name); err != nil {
+				return nil, fmt.Errorf("check object existence failed: %w", err)
+			}
+		}
+		if !children.complete && !folderExists {
+			lor, err := bkt.ListObjects(oss.Prefix(prefix+name+"/"), oss.MaxKeys(1))
+			if err != nil {
+				return nil, fmt.Errorf("check folder existence failed: %w", err)
+			}
+			folderExists = len(lor.Objects) > 0
 		}
-		folderExists := len(lor.Objects) > 0
 
 		out = append(out, UploadNameCollision{
 			Name:         name,
@@ -71,3 +100,45 @@ func (s *OSSService) CheckUploadNameCollisions(config OSSConfig, bucket string,
 
 	return out, nil
 }
+
+// prefixChildren are the immediate file and folder names under a prefix. complete is false when
+// the listing stopped at its limit, in which case names missing from the sets are unknown.
+type prefixChildren struct {
+	files    map[string]struct{}
+	folders  map[string]struct{}
+	complete bool
+}
+
+// listPrefixChildren lists the immediate children of prefix with delimiter listings, stopping
+// after limit entries.
+func (s *OSSService) listPrefixChildren(bkt *oss.Bucket, prefix string, limit int) (prefixChildren, error) {
+	children := prefixChildren{files: map[string]struct{}{}, folders: map[string]struct{}{}}
+	marker := ""
+	for len(children.files)+len(children.folders) < limit {
+		var lor oss.ListObjectsResult
+		err := s.withSDKRetry("ListObjects", func() error {
+			var err error
+			lor, err = bkt.ListObjects(oss.Prefix(prefix), oss.Delimiter("/"), oss.Marker(marker), oss.MaxKeys(1000))
+			return err
+		})
+		if err != nil {
+			return prefixChildren{}, fmt.Errorf("list destination failed: %w", err)
+		}
+		for _, obj := range lor.Objects {
+			if name := strings.TrimPrefix(obj.Key, prefix); name != "" {
+				children.files[name] = struct{}{}
+			}
+		}
+		for _, common := range lor.CommonPrefixes {
+			if name := strings.TrimSuffix(strings.TrimPrefix(common, prefix), "/"); name != "" {
+				children.folders[name] = struct{}{}
+			}
+		}
+		if !lor.IsTruncated || lor.NextMarker == "" {
+			children.complete = true
+			return children, nil
+		}
+		marker = lor.NextMarker
+	}
+	return children, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// childKeys returns n file keys directly under prefix.
+func childKeys(prefix string, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%sfile-%05d.txt", prefix, i)
+	}
+	return keys
+}
+
+func TestCheckUploadNameCollisionsListsOnce(t *testing.T) {
+	s := newTestService(t)
+	bucket := newFakeBucket(append(childKeys("dest/", 300), "dest/photos/a.jpg", "elsewhere/new.txt")...)
+	config := fakeOSS(t, bucket.serve)
+
+	names := []string{"file-00000.txt", "file-00299.txt", "photos", "new.txt", " file-00000.txt "}
+	for i := range 300 {
+		names = append(names, fmt.Sprintf("file-%05d.txt", i))
+	}
+	got, err := s.CheckUploadNameCollisions(config, "bucket", "dest/", names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]UploadNameCollision{}
+	for _, collision := range got {
+		byName[collision.Name] = collision
+	}
+	if len(got) != 302 {
+		t.Fatalf("got %d results, want one per distinct name (302)", len(got))
+	}
+	if !byName["file-00299.txt"].FileExists || byName["file-00299.txt"].FolderExists {
+		t.Errorf("file-00299.txt = %+v", byName["file-00299.txt"])
+	}
+	if !byName["photos"].FolderExists || byName["photos"].FileExists {
+		t.Errorf("photos = %+v", byName["photos"])
+	}
+	if c := byName["new.txt"]; c.FileExists || c.FolderExists {
+		t.Errorf("new.txt = %+v", c)
+	}
+	if lists, heads := bucket.requests(); lists != 1 || heads != 0 {
+		t.Errorf("requests: %d lists, %d heads; want one listing", lists, heads)
+	}
+}
+
+func TestCheckUploadNameCollisionsAtThreshold(t *testing.T) {
+	pages := uploadCollisionListLimit / 1000
+	for _, tt := range []struct {
+		name     string
+		children int
+		// missing names are probed once the listing is cut off at the threshold.
+		wantProbes bool
+	}{
+		{"at threshold", uploadCollisionListLimit, false},
+		{"over threshold", uploadCollisionListLimit + 1, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			keys := childKeys("dest/", tt.children)
+			bucket := newFakeBucket(keys...)
+			config := fakeOSS(t, bucket.serve)
+
+			// The last key sorts after everything the cut-off listing saw.
+			last := keys[len(keys)-1][len("dest/"):]
+			got, err := s.CheckUploadNameCollisions(config, "bucket", "dest/", []string{"file-00000.txt", last, "missing.txt"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got[0].FileExists || !got[1].FileExists || got[2].FileExists || got[2].FolderExists {
+				t.Fatalf("collisions = %+v", got)
+			}
+
+			lists, heads := bucket.requests()
+			if !tt.wantProbes {
+				if lists != pages || heads != 0 {
+					t.Errorf("requests: %d lists, %d heads; want %d listing pages only", lists, heads, pages)
+				}
+				return
+			}
+			// Every name the listing did not show as a folder gets a folder probe, and every name it
+			// did not show as a file a HEAD.
+			if lists != pages+3 || heads != 2 {
+				t.Errorf("requests: %d lists, %d heads; want %d pages plus 3 folder probes and 2 HEADs", lists, heads, pages)
+			}
+		})
+	}
+}
+
+// BenchmarkCheckUploadNameCollisions drops 300 names onto a folder of 300 files. It reports the
+// requests per check, one listing instead of the two probes per name it used to take.
+func BenchmarkCheckUploadNameCollisions(b *testing.B) {
+	s := newTestService(b)
+	bucket := newFakeBucket(childKeys("dest/", 300)...)
+	config := fakeOSS(b, bucket.serve)
+	names := make([]string, 300)
+	for i := range names {
+		names[i] = fmt.Sprintf("file-%05d.txt", i*2)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.CheckUploadNameCollisions(config, "bucket", "dest/", names); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	lists, heads := bucket.requests()
+	b.ReportMetric(float64(lists+heads)/float64(b.N), "requests/op")
+}
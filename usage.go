@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	usageFileName      = "usage.json"
+	usageDateLayout    = "2006-01-02"
+	usageFlushInterval = 30 * time.Second
+	usageRetentionDays = 400
+	maxUsageStatsDays  = 366
+)
+
+// DailyUsage is the number of bytes transferred on one local calendar day.
+type DailyUsage struct {
+	Date            string `json:"date"` // YYYY-MM-DD, local time
+	UploadedBytes   int64  `json:"uploadedBytes"`
+	DownloadedBytes int64  `json:"downloadedBytes"`
+}
+
+// ProfileDailyUsage is the number of bytes one profile transferred on one day. It is a row of
+// usage.json; rows written before usage was kept per profile have no profile.
+type ProfileDailyUsage struct {
+	Date            string `json:"date"`
+	Profile         string `json:"profile,omitempty"`
+	UploadedBytes   int64  `json:"uploadedBytes"`
+	DownloadedBytes int64  `json:"downloadedBytes"`
+}
+
+// UsageReport totals the bytes a profile transferred between two dates, both included.
+type UsageReport struct {
+	Profile         string `json:"profile"`
+	FromDate        string `json:"fromDate"`
+	ToDate          string `json:"toDate"`
+	UploadedBytes   int64  `json:"uploadedBytes"`
+	DownloadedBytes int64  `json:"downloadedBytes"`
+	// Days lists the days with transfers, oldest first.
+	Days []ProfileDailyUsage `json:"days"`
+}
+
+type usageKey struct {
+	date    string
+	profile string
+}
+
+// usageTracker counts transferred bytes per day and profile. Transfers add to pending counters
+// under a short lock, booked on the day the bytes moved, so a flush after midnight does not shift
+// them to the next day; flush folds them into the totals and writes usage.json.
+type usageTracker struct {
+	pendingMu sync.Mutex
+	pending   map[usageKey]ProfileDailyUsage
+
+	mu   sync.Mutex
+	path string
+	days map[usageKey]ProfileDailyUsage
+}
+
+func newUsageTracker(path string) *usageTracker {
+	return &usageTracker{path: path, pending: make(map[usageKey]ProfileDailyUsage), days: make(map[usageKey]ProfileDailyUsage)}
+}
+
+// load reads the counters saved in usage.json through read, which moves a damaged file aside
+// rather than letting the next flush overwrite it.
+func (t *usageTracker) load(read configFileReader) error {
+	var days []ProfileDailyUsage
+	if _, err := read(t.path, &days); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, day := range days {
+		t.days[usageKey{date: day.Date, profile: day.Profile}] = day
+	}
+	return nil
+}
+
+// add counts bytes moved by a transfer of profile. Callers pass deltas of DoneBytes, so canceled
+// and failed transfers count what they actually moved.
+func (t *usageTracker) add(profile string, transferType TransferType, bytes int64) {
+	if bytes <= 0 {
+		return
+	}
+	key := usageKey{date: time.Now().Format(usageDateLayout), profile: profile}
+	t.pendingMu.Lock()
+	defer t.pendingMu.Unlock()
+	row := t.pending[key]
+	switch transferType {
+	case TransferTypeUpload:
+		row.UploadedBytes += bytes
+	case TransferTypeDownload:
+		row.DownloadedBytes += bytes
+	default:
+		return
+	}
+	t.pending[key] = row
+}
+
+// flush books the pending bytes and persists the counters when anything changed.
+func (t *usageTracker) flush() error {
+	t.pendingMu.Lock()
+	pending := t.pending
+	t.pending = make(map[usageKey]ProfileDailyUsage)
+	t.pendingMu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, delta := range pending {
+		day := t.days[key]
+		day.Date, day.Profile = key.date, key.profile
+		day.UploadedBytes += delta.UploadedBytes
+		day.DownloadedBytes += delta.DownloadedBytes
+		t.days[key] = day
+	}
+
+	oldest := time.Now().AddDate(0, 0, -usageRetentionDays).Format(usageDateLayout)
+	for key := range t.days {
+		if key.date < oldest {
+			delete(t.days, key)
+		}
+	}
+	return t.saveLocked()
+}
+
+// rowsLocked returns the stored rows ordered by date, then profile.
+func (t *usageTracker) rowsLocked() []ProfileDailyUsage {
+	rows := make([]ProfileDailyUsage, 0, len(t.days))
+	for _, day := range t.days {
+		rows = append(rows, day)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Date != rows[j].Date {
+			return rows[i].Date < rows[j].Date
+		}
+		return rows[i].Profile < rows[j].Profile
+	})
+	return rows
+}
+
+// renameProfile moves the rows of profile oldName to newName.
+func (t *usageTracker) renameProfile(oldName string, newName string) error {
+	if err := t.flush(); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	changed := false
+	for key, day := range t.days {
+		if key.profile != oldName {
+			continue
+		}
+		delete(t.days, key)
+		key.profile, day.Profile = newName, newName
+		merged := t.days[key]
+		day.UploadedBytes += merged.UploadedBytes
+		day.DownloadedBytes += merged.DownloadedBytes
+		t.days[key] = day
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return t.saveLocked()
+}
+
+func (t *usageTracker) saveLocked() error {
+	data, err := json.MarshalIndent(t.rowsLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(t.path, data)
+}
+
+// run flushes the counters periodically until ctx ends, then once more.
+func (t *usageTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = t.flush()
+			return
+		case <-ticker.C:
+			_ = t.flush()
+		}
+	}
+}
+
+// GetUsageStats returns the bytes uploaded and downloaded on each of the last days days, oldest
+// first and including today; days without transfers are reported as zero.
+func (s *OSSService) GetUsageStats(days int) ([]DailyUsage, error) {
+	if days <= 0 {
+		days = 1
+	}
+	if days > maxUsageStatsDays {
+		return nil, fmt.Errorf("days must be at most %d", maxUsageStatsDays)
+	}
+	if err := s.usage.flush(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.usage.mu.Lock()
+	byDate := make(map[string]DailyUsage, len(s.usage.days))
+	for key, row := range s.usage.days {
+		day := byDate[key.date]
+		day.UploadedBytes += row.UploadedBytes
+		day.DownloadedBytes += row.DownloadedBytes
+		byDate[key.date] = day
+	}
+	s.usage.mu.Unlock()
+
+	out := make([]DailyUsage, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		date := now.AddDate(0, 0, -i).Format(usageDateLayout)
+		day := byDate[date]
+		day.Date = date
+		out = append(out, day)
+	}
+	return out, nil
+}
+
+// GetProfileUsage totals what profileName transferred from fromDate to toDate (YYYY-MM-DD, both
+// included). An empty fromDate means the first of the current month, an empty toDate today.
+func (s *OSSService) GetProfileUsage(profileName string, fromDate string, toDate string) (UsageReport, error) {
+	profileName = normalizeTransferProfileName(profileName)
+	now := time.Now()
+	fromDate, toDate = strings.TrimSpace(fromDate), strings.TrimSpace(toDate)
+	if fromDate == "" {
+		fromDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format(usageDateLayout)
+	}
+	if toDate == "" {
+		toDate = now.Format(usageDateLayout)
+	}
+	for _, date := range []string{fromDate, toDate} {
+		if _, err := time.Parse(usageDateLayout, date); err != nil {
+			return UsageReport{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", date)
+		}
+	}
+	if fromDate > toDate {
+		return UsageReport{}, fmt.Errorf("from date %s is after to date %s", fromDate, toDate)
+	}
+	if err := s.usage.flush(); err != nil {
+		return UsageReport{}, err
+	}
+
+	report := UsageReport{Profile: profileName, FromDate: fromDate, ToDate: toDate, Days: []ProfileDailyUsage{}}
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+	for _, row := range s.usage.rowsLocked() {
+		if row.Profile != profileName || row.Date < fromDate || row.Date > toDate {
+			continue
+		}
+		report.UploadedBytes += row.UploadedBytes
+		report.DownloadedBytes += row.DownloadedBytes
+		report.Days = append(report.Days, row)
+	}
+	return report, nil
+}
+
+// ExportUsageCSV writes every stored daily row, all profiles, to path as CSV.
+func (s *OSSService) ExportUsageCSV(path string) error {
+	path = strings.TrimSpace(expandLeadingTilde(path))
+	if path == "" {
+		return fmt.Errorf("export path is required")
+	}
+	if err := s.usage.flush(); err != nil {
+		return err
+	}
+
+	s.usage.mu.Lock()
+	rows := s.usage.rowsLocked()
+	s.usage.mu.Unlock()
+
+	var out strings.Builder
+	w := csv.NewWriter(&out)
+	_ = w.Write([]string{"date", "profile", "uploaded_bytes", "downloaded_bytes"})
+	for _, row := range rows {
+		_ = w.Write([]string{row.Date, row.Profile, strconv.FormatInt(row.UploadedBytes, 10), strconv.FormatInt(row.DownloadedBytes, 10)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(out.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// ResetUsageStats clears all usage counters.
+func (s *OSSService) ResetUsageStats() error {
+	s.usage.pendingMu.Lock()
+	s.usage.pending = make(map[usageKey]ProfileDailyUsage)
+	s.usage.pendingMu.Unlock()
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+	s.usage.days = make(map[usageKey]ProfileDailyUsage)
+	return s.usage.saveLocked()
+}